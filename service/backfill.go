@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"radiocast/internal/backfill"
+	"radiocast/internal/config"
+	"radiocast/internal/logger"
+	"radiocast/internal/storage"
+	"radiocast/internal/timeseries"
+)
+
+// runBackfill implements "radiocast backfill": it loads the history of every stored report
+// into the time-series store and prints a summary
+func runBackfill(ctx context.Context, args []string, defaultDeployment string) error {
+	flags := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	deploymentFlag := flags.String("deployment", defaultDeployment, "Deployment mode: local, gcs, s3 or memory")
+	dryRun := flags.Bool("dry-run", false, "Read the reports and report gaps without writing history")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	deploymentMode, err := parseDeploymentMode(*deploymentFlag)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadOffline(ctx)
+	if err != nil {
+		return err
+	}
+	initializeLogger(cfg, deploymentMode)
+
+	client, err := storage.NewStorageClient(ctx, deploymentMode, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create storage client: %w", err)
+	}
+	defer client.Close()
+
+	summary, err := backfill.Run(ctx, client, timeseries.NewStore(client), backfill.Options{DryRun: *dryRun, Progress: os.Stdout})
+	if err != nil {
+		return err
+	}
+	logger.Info("Backfill completed", map[string]interface{}{"reports": summary.Reports, "loaded": summary.Loaded, "dry_run": *dryRun})
+
+	fmt.Printf("\nReports: %d found, %d loaded, %d skipped\n", summary.Reports, summary.Loaded, len(summary.Skipped))
+	for _, skipped := range summary.Skipped {
+		fmt.Printf("  skipped %s\n", skipped)
+	}
+	fmt.Printf("Points: %d read in %d series, %d new or changed\n", summary.TotalPoints(), len(summary.Points), summary.PointsChanged)
+	if *dryRun {
+		fmt.Println("Dry run: nothing was written")
+	}
+	fmt.Printf("Gaps: %d\n", len(summary.Gaps))
+	for _, gap := range summary.Gaps {
+		fmt.Printf("  %s: %s to %s\n", gap.Metric, gap.From.Format("2006-01-02 15:04"), gap.To.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ require (
 	github.com/sashabaranov/go-openai v1.17.9
 	github.com/sethvargo/go-envconfig v0.9.0
 	github.com/yuin/goldmark v1.6.0
+	golang.org/x/image v0.18.0
 	google.golang.org/api v0.152.0
 )
 
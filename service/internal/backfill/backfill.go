@@ -0,0 +1,220 @@
+package backfill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"radiocast/internal/fetchers"
+	"radiocast/internal/models"
+	"radiocast/internal/reports"
+	"radiocast/internal/storage"
+	"radiocast/internal/timeseries"
+)
+
+// Report artifacts read by the backfill
+const (
+	kIndexFile     = "noaa_k_index.json"
+	solarFile      = "noaa_solar.json"
+	n0nbhFile      = "n0nbh_data.json"
+	normalizedFile = "normalized_data.json"
+)
+
+// Gap tolerances: Kp is published every 3 hours, and reports are expected at least daily
+const (
+	kpStep     = 3 * time.Hour
+	reportStep = 36 * time.Hour
+)
+
+// Options configures a backfill run
+type Options struct {
+	DryRun   bool      // parse everything but write nothing
+	Progress io.Writer // receives one line per report; nil disables progress output
+}
+
+// Summary describes a backfill run
+type Summary struct {
+	Reports       int                       `json:"reports"`        // report folders found
+	Loaded        int                       `json:"loaded"`         // reports that contributed points
+	Skipped       []string                  `json:"skipped"`        // folders without usable artifacts, with the reason
+	Points        map[timeseries.Metric]int `json:"points"`         // points read per metric
+	PointsChanged int                       `json:"points_changed"` // new or changed points in the store; 0 when rerun
+	Gaps          []timeseries.Gap          `json:"gaps"`           // gaps in the K-index and report history
+}
+
+// Run loads the JSON artifacts of every published report into store. Raw source files are
+// preferred; a metric missing from them is taken from normalized_data.json, which is all that
+// archived reports keep. Points are upserted, so running it again changes nothing.
+func Run(ctx context.Context, client storage.StorageClient, store *timeseries.Store, opts Options) (*Summary, error) {
+	progress := opts.Progress
+	if progress == nil {
+		progress = io.Discard
+	}
+
+	folders, err := reports.ListReportFolders(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{Points: make(map[timeseries.Metric]int)}
+	series := make(map[timeseries.Metric][]timeseries.Point)
+	for i, folder := range folders {
+		timestamp, ok := storage.ParseReportFolderPath(folder.Path)
+		if !ok {
+			continue // not a report folder
+		}
+		summary.Reports++
+		if folder.Pending && !folder.Committed {
+			summary.Skipped = append(summary.Skipped, folder.Path+": not committed")
+			fmt.Fprintf(progress, "[%d/%d] %s: skipped, not committed\n", i+1, len(folders), folder.Path)
+			continue
+		}
+
+		points, err := readReport(ctx, client, folder, timestamp)
+		if errors.Is(err, errMalformedArtifact) {
+			summary.Skipped = append(summary.Skipped, folder.Path+": "+err.Error())
+			fmt.Fprintf(progress, "[%d/%d] %s: skipped, %v\n", i+1, len(folders), folder.Path, err)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read report %s: %w", folder.Path, err)
+		}
+		if len(points) == 0 {
+			summary.Skipped = append(summary.Skipped, folder.Path+": no history artifacts")
+			fmt.Fprintf(progress, "[%d/%d] %s: skipped, no history artifacts\n", i+1, len(folders), folder.Path)
+			continue
+		}
+
+		count := 0
+		for metric, p := range points {
+			series[metric] = append(series[metric], p...)
+			summary.Points[metric] += len(p)
+			count += len(p)
+		}
+		summary.Loaded++
+		fmt.Fprintf(progress, "[%d/%d] %s: %d series, %d points\n", i+1, len(folders), folder.Path, len(points), count)
+	}
+
+	// One upsert per metric, in a stable order
+	metrics := make([]timeseries.Metric, 0, len(series))
+	for metric := range series {
+		metrics = append(metrics, metric)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i] < metrics[j] })
+	if !opts.DryRun {
+		for _, metric := range metrics {
+			changed, err := store.Upsert(ctx, metric, series[metric])
+			if err != nil {
+				return summary, err
+			}
+			summary.PointsChanged += changed
+		}
+	}
+
+	summary.Gaps, err = findGaps(ctx, store, series, opts.DryRun)
+	if err != nil {
+		return summary, err
+	}
+	return summary, nil
+}
+
+// readReport returns the points of one report folder
+func readReport(ctx context.Context, client storage.StorageClient, folder *reports.ReportFolder, timestamp time.Time) (map[timeseries.Metric][]timeseries.Point, error) {
+	files := make(map[string]string)
+	for _, file := range folder.Files {
+		files[path.Base(file)] = file
+	}
+
+	var kIndex []models.NOAAKIndexResponse
+	var solar []models.NOAASolarResponse
+	var n0nbh *models.N0NBHResponse
+	if err := readJSON(ctx, client, files[kIndexFile], &kIndex); err != nil {
+		return nil, err
+	}
+	if err := readJSON(ctx, client, files[solarFile], &solar); err != nil {
+		return nil, err
+	}
+	if err := readJSON(ctx, client, files[n0nbhFile], &n0nbh); err != nil {
+		return nil, err
+	}
+	points := fetchers.HistoryPoints(kIndex, solar, n0nbh, timestamp)
+
+	var normalized *models.PropagationData
+	if err := readJSON(ctx, client, files[normalizedFile], &normalized); err != nil {
+		return nil, err
+	}
+	if normalized != nil {
+		if normalized.Timestamp.IsZero() {
+			normalized.Timestamp = timestamp
+		}
+		for metric, p := range fetchers.PropagationHistoryPoints(normalized) {
+			if _, ok := points[metric]; !ok {
+				points[metric] = p
+			}
+		}
+	}
+	return points, nil
+}
+
+// errMalformedArtifact wraps the decode error of a report artifact; Run skips the report, so one
+// broken report does not stop the backfill
+var errMalformedArtifact = errors.New("malformed artifact")
+
+// readJSON decodes a report artifact into v; an empty path (artifact not in the folder) is skipped
+func readJSON(ctx context.Context, client storage.StorageClient, filePath string, v interface{}) error {
+	if filePath == "" {
+		return nil
+	}
+	data, err := client.GetFile(ctx, filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w %s: %v", errMalformedArtifact, path.Base(filePath), err)
+	}
+	return nil
+}
+
+// findGaps reports gaps in the K-index and in the per-report snapshots. After a dry run the store
+// is not updated, so the gaps are those of what was read.
+func findGaps(ctx context.Context, store *timeseries.Store, series map[timeseries.Metric][]timeseries.Point, dryRun bool) ([]timeseries.Gap, error) {
+	var gaps []timeseries.Gap
+	for _, check := range []struct {
+		metric  timeseries.Metric
+		maxStep time.Duration
+	}{
+		{timeseries.MetricKp, kpStep},
+		{timeseries.MetricSFI, reportStep},
+	} {
+		var points []timeseries.Point
+		if dryRun {
+			points = append(points, series[check.metric]...)
+			sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+		} else {
+			var err error
+			if points, err = store.Query(ctx, check.metric, time.Time{}, time.Time{}); err != nil {
+				return nil, err
+			}
+		}
+		gaps = append(gaps, timeseries.FindGaps(check.metric, points, check.maxStep)...)
+	}
+	return gaps, nil
+}
+
+// TotalPoints returns the number of points read across all metrics
+func (s *Summary) TotalPoints() int {
+	total := 0
+	for _, n := range s.Points {
+		total += n
+	}
+	return total
+}
@@ -0,0 +1,126 @@
+package backfill
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+	"radiocast/internal/storage"
+	"radiocast/internal/timeseries"
+)
+
+func storeJSON(t *testing.T, client storage.StorageClient, filePath string, v interface{}) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.StoreFile(context.Background(), filePath, data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// kIndexDay returns the 3-hourly K-index points of one day of September 2025
+func kIndexDay(day int) []models.NOAAKIndexResponse {
+	var points []models.NOAAKIndexResponse
+	for hour := 0; hour < 24; hour += 3 {
+		points = append(points, models.NOAAKIndexResponse{
+			TimeTag: fmt.Sprintf("2025-09-%02dT%02d:00:00", day, hour),
+			KpIndex: 2,
+			Source:  "NOAA SWPC",
+		})
+	}
+	return points
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	client := storage.NewMemoryStorageClient()
+	store := timeseries.NewStore(client)
+
+	// A full report with raw source files
+	full := "reports/2025/09/10/PropagationReport-2025-09-10-12-00-00"
+	client.StoreFile(ctx, full+"/index.html", []byte("<html></html>"))
+	client.StoreFile(ctx, full+"/_COMPLETE", []byte("{}"))
+	storeJSON(t, client, full+"/noaa_k_index.json", kIndexDay(10))
+	storeJSON(t, client, full+"/noaa_solar.json", []models.NOAASolarResponse{{TimeTag: "2025-08", SolarFlux: 150, SunspotNumber: 120, Source: "NOAA SWPC"}})
+	n0nbh := &models.N0NBHResponse{Source: "N0NBH"}
+	n0nbh.SolarData.SolarFlux = "155"
+	storeJSON(t, client, full+"/n0nbh_data.json", n0nbh)
+
+	// An archived report that only kept normalized data, three days later
+	archived := "reports/2025/09/13/PropagationReport-2025-09-13-12-00-00"
+	data := &models.PropagationData{Timestamp: time.Date(2025, 9, 13, 12, 0, 0, 0, time.UTC)}
+	data.SolarData.SolarFluxIndex = 160
+	data.SolarData.SolarFluxDataSource = "N0NBH"
+	data.BandData.Band10m = models.BandCondition{Day: "Good", Night: "Poor"}
+	data.BandData.BandDataSource = "N0NBH"
+	for hour := 0; hour < 24; hour += 3 {
+		data.HistoricalKIndex = append(data.HistoricalKIndex, models.KIndexPoint{Timestamp: time.Date(2025, 9, 13, hour, 0, 0, 0, time.UTC), KIndex: 3, Source: "NOAA SWPC"})
+	}
+	client.StoreFile(ctx, archived+"/index.html", []byte("<html></html>"))
+	storeJSON(t, client, archived+"/normalized_data.json", data)
+
+	// A report still being generated, and a folder without artifacts
+	client.StoreFile(ctx, "reports/2025/09/14/PropagationReport-2025-09-14-12-00-00/_PENDING", []byte{})
+	client.StoreFile(ctx, "reports/2025/09/15/PropagationReport-2025-09-15-12-00-00/index.html", []byte("<html></html>"))
+
+	// A report with a corrupt artifact is skipped with the decode error as the reason
+	corrupt := "reports/2025/09/16/PropagationReport-2025-09-16-12-00-00"
+	client.StoreFile(ctx, corrupt+"/index.html", []byte("<html></html>"))
+	client.StoreFile(ctx, corrupt+"/normalized_data.json", []byte(`{"timestamp": `))
+
+	// A dry run reads everything but writes nothing
+	summary, err := Run(ctx, client, store, Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("Run(dry run) error = %v", err)
+	}
+	if summary.Loaded != 2 || summary.PointsChanged != 0 {
+		t.Errorf("Run(dry run) loaded %d reports and changed %d points, want 2 and 0", summary.Loaded, summary.PointsChanged)
+	}
+	if metrics, _ := store.Metrics(ctx); len(metrics) != 0 {
+		t.Errorf("Run(dry run) wrote series %v", metrics)
+	}
+
+	var progress bytes.Buffer
+	summary, err = Run(ctx, client, store, Options{Progress: &progress})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if summary.Reports != 5 || summary.Loaded != 2 || len(summary.Skipped) != 3 {
+		t.Errorf("Run() = %d reports, %d loaded, skipped %v, want 5, 2 and 3 skipped", summary.Reports, summary.Loaded, summary.Skipped)
+	}
+	if len(summary.Skipped) == 3 && !strings.HasPrefix(summary.Skipped[2], "2025/09/16/PropagationReport-2025-09-16-12-00-00: malformed artifact normalized_data.json: ") {
+		t.Errorf("Skipped[2] = %q, want the decode error of normalized_data.json", summary.Skipped[2])
+	}
+	if !strings.Contains(progress.String(), "[1/5] 2025/09/10/PropagationReport-2025-09-10-12-00-00: ") {
+		t.Errorf("Progress output = %q, want a line per report", progress.String())
+	}
+
+	if kp, _ := store.Query(ctx, timeseries.MetricKp, time.Time{}, time.Time{}); len(kp) != 16 {
+		t.Errorf("Loaded %d K-index points, want 16", len(kp))
+	}
+	sfi, _ := store.Query(ctx, timeseries.MetricSFI, time.Time{}, time.Time{})
+	if len(sfi) != 2 || sfi[0].Value != 155 || sfi[1].Value != 160 {
+		t.Errorf("Loaded flux snapshots %v, want 155 and 160", sfi)
+	}
+	if band, _ := store.Query(ctx, timeseries.BandMetric("12m-10m", "night"), time.Time{}, time.Time{}); len(band) != 1 || band[0].Value != 1 {
+		t.Errorf("Loaded band conditions %v, want Poor (1)", band)
+	}
+
+	// Days 11 and 12 are missing from both series
+	if len(summary.Gaps) != 2 || summary.Gaps[0].Metric != timeseries.MetricKp || summary.Gaps[1].Metric != timeseries.MetricSFI {
+		t.Errorf("Gaps = %+v, want one K-index and one flux gap", summary.Gaps)
+	}
+
+	// Running it again changes nothing
+	summary, err = Run(ctx, client, store, Options{})
+	if err != nil || summary.PointsChanged != 0 || summary.Loaded != 2 {
+		t.Errorf("Second Run() = %+v, %v, want no changes", summary, err)
+	}
+}
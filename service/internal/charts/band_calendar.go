@@ -0,0 +1,199 @@
+package charts
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"radiocast/internal/models"
+)
+
+// Calendar layout: one calendar per band group and period, day on the left and night on the right
+const (
+	bandCalendarCell = 13 // px per day
+	bandCalendarRow  = 7*bandCalendarCell + 48
+	bandCalendarTop  = 40
+)
+
+// conditionLevels colors daily mean conditions like the report's band table
+var conditionLevels = []StaticLevel{
+	{Min: 0, Label: "Closed", Color: "#000000"},
+	{Min: 0.5, Label: "Poor", Color: "#dc3545"},
+	{Min: 1.5, Label: "Fair", Color: "#fd7e14"},
+	{Min: 2.5, Label: "Good", Color: "#ffc107"},
+	{Min: 3.5, Label: "Excellent", Color: "#28a745"},
+}
+
+// conditionPieces are conditionLevels as ECharts visualMap pieces
+var conditionPieces = func() []map[string]interface{} {
+	pieces := make([]map[string]interface{}, len(conditionLevels))
+	for i, level := range conditionLevels {
+		pieces[i] = map[string]interface{}{"gte": level.Min, "label": level.Label, "color": level.Color}
+		if i+1 < len(conditionLevels) {
+			pieces[i]["lt"] = conditionLevels[i+1].Min
+		}
+	}
+	return pieces
+}()
+
+// generateBandCalendarSnippet builds the band condition calendar of a report from its band
+// history; without recorded history it shows the report's own conditions for the report day
+func (cg *ChartGenerator) generateBandCalendarSnippet(data *models.PropagationData) (ChartSnippet, error) {
+	if data == nil {
+		return ChartSnippet{}, fmt.Errorf("data cannot be nil")
+	}
+	calendar := data.BandHistory
+	if calendar == nil {
+		calendar = cg.currentBandCalendar(data)
+	}
+	return cg.GenerateBandCalendarSnippet(calendar)
+}
+
+// currentBandCalendar is a one-day calendar of the report's band conditions
+func (cg *ChartGenerator) currentBandCalendar(data *models.PropagationData) *models.BandCalendar {
+	calendar := &models.BandCalendar{Days: []string{data.Timestamp.UTC().Format("2006-01-02")}}
+	for _, group := range data.BandData.Groups() {
+		for _, period := range []struct{ name, condition string }{{"day", group.Day}, {"night", group.Night}} {
+			if normalize(period.condition) == "" {
+				continue
+			}
+			value := float64(cg.conditionToValue(period.condition))
+			row := models.BandCalendarRow{Band: group.Group, Period: period.name, Values: []*float64{&value}}
+			row.Days[int(value)]++
+			calendar.Rows = append(calendar.Rows, row)
+		}
+	}
+	return calendar
+}
+
+// GenerateBandCalendarSnippet builds a calendar heatmap per band group and period
+func (cg *ChartGenerator) GenerateBandCalendarSnippet(calendar *models.BandCalendar) (ChartSnippet, error) {
+	if calendar == nil || len(calendar.Rows) == 0 || len(calendar.Days) == 0 {
+		return ChartSnippet{}, fmt.Errorf("no band condition history")
+	}
+
+	// One line of calendars per band group, in row order
+	var bands []string
+	line := make(map[string]int)
+	for _, row := range calendar.Rows {
+		if _, ok := line[row.Band]; !ok {
+			line[row.Band] = len(bands)
+			bands = append(bands, row.Band)
+		}
+	}
+
+	dateRange := []string{calendar.Days[0], calendar.Days[len(calendar.Days)-1]}
+	var calendars, titles, series []interface{}
+	for _, row := range calendar.Rows {
+		left := "4%"
+		if row.Period == "night" {
+			left = "52%"
+		}
+		top := bandCalendarTop + line[row.Band]*bandCalendarRow
+		calendars = append(calendars, map[string]interface{}{
+			"top":        top + 24,
+			"left":       left,
+			"width":      "44%",
+			"cellSize":   []interface{}{"auto", bandCalendarCell},
+			"range":      dateRange,
+			"yearLabel":  map[string]interface{}{"show": false},
+			"dayLabel":   map[string]interface{}{"firstDay": 1, "nameMap": []string{"S", "M", "T", "W", "T", "F", "S"}, "fontSize": 9},
+			"monthLabel": map[string]interface{}{"fontSize": 10},
+			"splitLine":  map[string]interface{}{"show": false},
+			"itemStyle":  map[string]interface{}{"borderColor": "#fff", "borderWidth": 1, "color": "#f0f0f0"},
+		})
+		good := row.Days[3] + row.Days[4]
+		titles = append(titles, map[string]interface{}{
+			"text":         fmt.Sprintf("%s %s", row.Band, row.Period),
+			"subtext":      fmt.Sprintf("Good or better on %d of %d days", good, countDays(row)),
+			"left":         left,
+			"top":          top - 14,
+			"textStyle":    map[string]interface{}{"fontSize": 12},
+			"subtextStyle": map[string]interface{}{"fontSize": 10},
+			"itemGap":      2,
+		})
+
+		var cells [][]interface{}
+		for i, value := range row.Values {
+			if value != nil {
+				cells = append(cells, []interface{}{calendar.Days[i], *value})
+			}
+		}
+		series = append(series, map[string]interface{}{
+			"name":             row.Band + " " + row.Period,
+			"type":             "heatmap",
+			"coordinateSystem": "calendar",
+			"calendarIndex":    len(calendars) - 1,
+			"data":             cells,
+		})
+	}
+
+	option := map[string]interface{}{
+		"title":   titles,
+		"tooltip": map[string]interface{}{"position": "top"},
+		"visualMap": map[string]interface{}{
+			"type":       "piecewise",
+			"orient":     "horizontal",
+			"left":       "center",
+			"bottom":     0,
+			"pieces":     conditionPieces,
+			"textStyle":  map[string]interface{}{"fontSize": 11},
+			"itemWidth":  14,
+			"itemHeight": 14,
+		},
+		"calendar": calendars,
+		"series":   series,
+	}
+	optJSON, err := json.Marshal(option)
+	if err != nil {
+		return ChartSnippet{}, err
+	}
+
+	id := "chart-band-calendar"
+	height := bandCalendarTop + len(bands)*bandCalendarRow + 40
+	div := fmt.Sprintf("<div id=\"%s\" style=\"width:100%%;height:%dpx;\"></div>", id, height)
+	script := fmt.Sprintf(`<script>(function(){var el=document.getElementById('%s');if(!el)return;var c=echarts.init(el);var option=%s;c.setOption(option);window.addEventListener('resize',function(){c.resize();});})();</script>`, id, string(optJSON))
+
+	title := fmt.Sprintf("Band Conditions by Day (%d Days)", len(calendar.Days))
+	completeHTML := fmt.Sprintf(`<div class="chart-container">
+	<h3>%s</h3>
+	%s
+</div>
+%s`, title, div, script)
+
+	return ChartSnippet{ID: id, Title: title, Div: div, Script: script, HTML: completeHTML,
+		Static: staticBandCalendar(title, calendar)}, nil
+}
+
+// staticBandCalendar lays the calendar out as one heatmap row per band group and period
+func staticBandCalendar(title string, calendar *models.BandCalendar) *StaticChart {
+	heatmap := &StaticHeatmap{Levels: conditionLevels}
+	for _, day := range calendar.Days {
+		label := day
+		if t, err := time.Parse("2006-01-02", day); err == nil {
+			label = t.Format("01/02")
+		}
+		heatmap.Columns = append(heatmap.Columns, label)
+	}
+	for _, row := range calendar.Rows {
+		values := make([]float64, len(calendar.Days))
+		for i := range values {
+			values[i] = math.NaN()
+			if i < len(row.Values) && row.Values[i] != nil {
+				values[i] = *row.Values[i]
+			}
+		}
+		heatmap.Rows = append(heatmap.Rows, StaticHeatmapRow{Label: row.Band + " " + row.Period, Values: values})
+	}
+	return &StaticChart{Kind: StaticHeatmapKind, Title: title, Heatmap: heatmap}
+}
+
+// countDays returns the number of days of a row with data
+func countDays(row models.BandCalendarRow) int {
+	n := 0
+	for _, count := range row.Days {
+		n += count
+	}
+	return n
+}
@@ -0,0 +1,171 @@
+package charts
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+)
+
+// bandCalendarOption is the part of the band calendar option the tests check
+type bandCalendarOption struct {
+	Title []struct {
+		Text    string `json:"text"`
+		Subtext string `json:"subtext"`
+	} `json:"title"`
+	Calendar []struct {
+		Range []string `json:"range"`
+	} `json:"calendar"`
+	Series []struct {
+		Name          string          `json:"name"`
+		Type          string          `json:"type"`
+		CalendarIndex int             `json:"calendarIndex"`
+		Data          [][]interface{} `json:"data"`
+	} `json:"series"`
+}
+
+func TestGenerateBandCalendarSnippet(t *testing.T) {
+	cg := NewChartGenerator("")
+	if _, err := cg.GenerateBandCalendarSnippet(&models.BandCalendar{Days: []string{"2025-03-01"}}); err == nil {
+		t.Error("Expected an error without band rows")
+	}
+
+	good, poor := 3.0, 1.25
+	calendar := &models.BandCalendar{
+		Days: []string{"2025-03-01", "2025-03-02", "2025-03-03"},
+		Rows: []models.BandCalendarRow{
+			{Band: "80m-40m", Period: "day", Values: []*float64{&good, nil, &poor}, Days: [5]int{0, 1, 0, 1, 0}},
+			{Band: "80m-40m", Period: "night", Values: []*float64{&good, &good, &good}, Days: [5]int{0, 0, 0, 3, 0}},
+			{Band: "12m-10m", Period: "day", Values: []*float64{nil, nil, &poor}, Days: [5]int{0, 1, 0, 0, 0}},
+		},
+	}
+	sn, err := cg.GenerateBandCalendarSnippet(calendar)
+	if err != nil {
+		t.Fatalf("GenerateBandCalendarSnippet() error = %v", err)
+	}
+	if sn.ID != "chart-band-calendar" || !strings.Contains(sn.HTML, sn.Div) {
+		t.Errorf("Unexpected snippet %s: %s", sn.ID, sn.HTML)
+	}
+	// Two lines of calendars, one per band group
+	if !strings.Contains(sn.Div, "height:358px") {
+		t.Errorf("Unexpected chart height: %s", sn.Div)
+	}
+
+	var option bandCalendarOption
+	decodeOption(t, sn.Script, &option)
+	if len(option.Calendar) != 3 || len(option.Title) != 3 || len(option.Series) != 3 {
+		t.Fatalf("Expected a calendar, title and series per row, got %d, %d and %d", len(option.Calendar), len(option.Title), len(option.Series))
+	}
+	for i, cal := range option.Calendar {
+		if !reflect.DeepEqual(cal.Range, []string{"2025-03-01", "2025-03-03"}) {
+			t.Errorf("calendar[%d].range = %v, want the calendar days", i, cal.Range)
+		}
+	}
+	wantTitles := []struct{ text, subtext string }{
+		{"80m-40m day", "Good or better on 1 of 2 days"},
+		{"80m-40m night", "Good or better on 3 of 3 days"},
+		{"12m-10m day", "Good or better on 0 of 1 days"},
+	}
+	for i, want := range wantTitles {
+		if got := option.Title[i]; got.Text != want.text || got.Subtext != want.subtext {
+			t.Errorf("title[%d] = %q / %q, want %q / %q", i, got.Text, got.Subtext, want.text, want.subtext)
+		}
+	}
+	// Days without a value are left out of the heatmap
+	wantData := [][][]interface{}{
+		{{"2025-03-01", 3.0}, {"2025-03-03", 1.25}},
+		{{"2025-03-01", 3.0}, {"2025-03-02", 3.0}, {"2025-03-03", 3.0}},
+		{{"2025-03-03", 1.25}},
+	}
+	for i, series := range option.Series {
+		if series.Name != wantTitles[i].text || series.Type != "heatmap" || series.CalendarIndex != i {
+			t.Errorf("series[%d] = %s %s on calendar %d", i, series.Name, series.Type, series.CalendarIndex)
+		}
+		if !reflect.DeepEqual(series.Data, wantData[i]) {
+			t.Errorf("series[%d].data = %v, want %v", i, series.Data, wantData[i])
+		}
+	}
+}
+
+func TestGenerateBandCalendarSnippet_Static(t *testing.T) {
+	good, poor := 3.0, 1.25
+	calendar := &models.BandCalendar{
+		Days: []string{"2025-03-01", "2025-03-02"},
+		Rows: []models.BandCalendarRow{
+			{Band: "80m-40m", Period: "day", Values: []*float64{&good, nil}},
+			{Band: "80m-40m", Period: "night", Values: []*float64{&poor, &good}},
+		},
+	}
+	sn, err := NewChartGenerator("").GenerateBandCalendarSnippet(calendar)
+	if err != nil {
+		t.Fatalf("GenerateBandCalendarSnippet() error = %v", err)
+	}
+	if sn.Static == nil || sn.Static.Kind != StaticHeatmapKind || sn.Static.Heatmap == nil {
+		t.Fatalf("Expected a static heatmap, got %+v", sn.Static)
+	}
+
+	heatmap := sn.Static.Heatmap
+	if !reflect.DeepEqual(heatmap.Columns, []string{"03/01", "03/02"}) {
+		t.Errorf("Columns = %v", heatmap.Columns)
+	}
+	if len(heatmap.Rows) != 2 || heatmap.Rows[0].Label != "80m-40m day" || heatmap.Rows[1].Label != "80m-40m night" {
+		t.Fatalf("Unexpected rows %+v", heatmap.Rows)
+	}
+	if v := heatmap.Rows[0].Values; v[0] != 3 || !math.IsNaN(v[1]) {
+		t.Errorf("day values = %v, want [3 NaN]", v)
+	}
+	if got := heatmapColor(heatmap.Levels, 1.25); got != "#dc3545" {
+		t.Errorf("1.25 is colored %s, want Poor", got)
+	}
+
+	// Static reports, the PDF and the digest all draw the heatmap instead of dropping it
+	if html := StaticHTML(sn, StaticFileName(sn.ID, "svg")); !strings.Contains(html, "chart-band-calendar.svg") {
+		t.Errorf("StaticHTML() = %q", html)
+	}
+	files, err := RenderStaticFiles([]ChartSnippet{sn})
+	if err != nil {
+		t.Fatalf("RenderStaticFiles() error = %v", err)
+	}
+	if len(files["chart-band-calendar.png"]) == 0 || !strings.Contains(string(files["chart-band-calendar.svg"]), "80m-40m night") {
+		t.Errorf("Expected rendered PNG and SVG with the row labels, got %d files", len(files))
+	}
+}
+
+func TestGenerateBandCalendarSnippet_CurrentConditions(t *testing.T) {
+	cg := NewChartGenerator("")
+	data := &models.PropagationData{
+		Timestamp: time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC),
+		BandData: models.BandData{
+			Band80m: models.BandCondition{Day: "Fair", Night: "Good"},
+			Band10m: models.BandCondition{Day: "Excellent"},
+		},
+	}
+	sn, err := cg.generateBandCalendarSnippet(data)
+	if err != nil {
+		t.Fatalf("generateBandCalendarSnippet() error = %v", err)
+	}
+
+	// One calendar per reported condition, holding the report day only
+	var option bandCalendarOption
+	decodeOption(t, sn.Script, &option)
+	want := map[string][][]interface{}{
+		"80m-40m day":   {{"2025-09-17", 2.0}},
+		"80m-40m night": {{"2025-09-17", 3.0}},
+		"12m-10m day":   {{"2025-09-17", 4.0}},
+	}
+	if len(option.Series) != len(want) {
+		t.Fatalf("Expected %d series, got %d", len(want), len(option.Series))
+	}
+	for _, series := range option.Series {
+		if data, ok := want[series.Name]; !ok || !reflect.DeepEqual(series.Data, data) {
+			t.Errorf("series %s data = %v, want %v", series.Name, series.Data, data)
+		}
+	}
+
+	if _, err := cg.generateBandCalendarSnippet(&models.PropagationData{}); err == nil {
+		t.Error("Expected an error without band conditions")
+	}
+}
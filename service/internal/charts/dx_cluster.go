@@ -0,0 +1,89 @@
+package charts
+
+import (
+	"fmt"
+	"math"
+
+	"radiocast/internal/geo"
+	"radiocast/internal/models"
+)
+
+// continentPalette extends defaultPalette with the next ECharts colors, one per continent and
+// one for DX stations of unknown continent
+var continentPalette = append(append([]string{}, defaultPalette...), "#3ba272", "#9a60b4")
+
+// generateDXClusterSnippet stacks the DX cluster spots of each band by continent of the DX station
+func (cg *ChartGenerator) generateDXClusterSnippet(data *models.PropagationData) (ChartSnippet, error) {
+	if data == nil || data.DXCluster == nil || len(data.DXCluster.Bands) == 0 {
+		return ChartSnippet{}, fmt.Errorf("no DX cluster spots")
+	}
+	summary := data.DXCluster
+
+	labels := make([]string, len(summary.Bands))
+	for i, b := range summary.Bands {
+		labels[i] = b.Band
+	}
+
+	// One series per continent with spots, in geo.Continents order, unknown continents last
+	var echartsSeries []interface{}
+	var staticSeries []StaticSeries
+	for i, continent := range append(append([]string{}, geo.Continents...), "") {
+		values := make([]float64, len(summary.Bands))
+		total := 0.0
+		for j, b := range summary.Bands {
+			for _, c := range b.Continents {
+				if c.Continent == continent {
+					values[j] = float64(c.Spots)
+					total += values[j]
+				}
+			}
+		}
+		if total == 0 {
+			continue
+		}
+		name := geo.ContinentName(continent)
+		if continent == "" {
+			name = "Unknown"
+		}
+		color := continentPalette[i%len(continentPalette)]
+		echartsSeries = append(echartsSeries, map[string]interface{}{
+			"name":      name,
+			"type":      "bar",
+			"stack":     "spots",
+			"data":      values,
+			"itemStyle": map[string]interface{}{"color": color},
+		})
+		staticSeries = append(staticSeries, StaticSeries{Name: name, Values: values, Color: color})
+	}
+
+	// Busiest band rounded up for even axis ticks
+	maxSpots := 0.0
+	for _, b := range summary.Bands {
+		maxSpots = math.Max(maxSpots, float64(b.Spots))
+	}
+	axisMax := math.Max(5, math.Ceil(maxSpots/5)*5)
+	option := map[string]interface{}{
+		"tooltip": map[string]interface{}{"trigger": "axis", "axisPointer": map[string]interface{}{"type": "shadow"}},
+		"legend":  map[string]interface{}{"bottom": 0},
+		"grid":    map[string]interface{}{"left": "8%", "right": "5%", "bottom": "14%", "containLabel": true},
+		"xAxis":   map[string]interface{}{"type": "category", "data": labels},
+		"yAxis":   map[string]interface{}{"type": "value", "name": "Spots", "min": 0, "max": axisMax},
+		"series":  echartsSeries,
+	}
+
+	minutes := summary.To.Sub(summary.From).Minutes()
+	title := fmt.Sprintf("DX Cluster Spots by Band and Continent (Last %.0f min)", minutes)
+	sn, err := trendSnippet("chart-dx-cluster", title, 400, option)
+	if err != nil {
+		return ChartSnippet{}, err
+	}
+	sn.Static = &StaticChart{
+		Kind:    StaticBarKind,
+		Title:   title,
+		Labels:  labels,
+		Axes:    []StaticAxis{{Name: "Spots", Min: 0, Max: axisMax}},
+		Series:  staticSeries,
+		Stacked: true,
+	}
+	return sn, nil
+}
@@ -0,0 +1,93 @@
+package charts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+)
+
+func TestGenerateDXClusterSnippet(t *testing.T) {
+	cg := NewChartGenerator("")
+	if _, err := cg.generateDXClusterSnippet(&models.PropagationData{}); err == nil {
+		t.Error("Expected an error without DX cluster spots")
+	}
+
+	to := time.Date(2025, 10, 18, 14, 0, 0, 0, time.UTC)
+	data := &models.PropagationData{
+		DXCluster: &models.DXClusterSummary{
+			From:  to.Add(-time.Hour),
+			To:    to,
+			Spots: 31,
+			Bands: []models.DXClusterBand{
+				{Band: "40m", Spots: 6, Continents: []models.DXClusterCount{{Continent: "EU", Spots: 6}}},
+				{Band: "20m", Spots: 22, Continents: []models.DXClusterCount{{Continent: "EU", Spots: 12}, {Continent: "AS", Spots: 8}, {Continent: "", Spots: 2}}},
+				{Band: "15m", Spots: 3, Continents: []models.DXClusterCount{{Continent: "AS", Spots: 3}}},
+			},
+		},
+	}
+	sn, err := cg.generateDXClusterSnippet(data)
+	if err != nil {
+		t.Fatalf("generateDXClusterSnippet() error = %v", err)
+	}
+	if sn.ID != "chart-dx-cluster" || sn.Title != "DX Cluster Spots by Band and Continent (Last 60 min)" || !strings.Contains(sn.HTML, sn.Div) {
+		t.Errorf("Unexpected snippet %s %q", sn.ID, sn.Title)
+	}
+
+	var option struct {
+		XAxis struct {
+			Data []string `json:"data"`
+		} `json:"xAxis"`
+		YAxis struct {
+			Min, Max float64
+		} `json:"yAxis"`
+		Series []struct {
+			Name  string    `json:"name"`
+			Type  string    `json:"type"`
+			Stack string    `json:"stack"`
+			Data  []float64 `json:"data"`
+		} `json:"series"`
+	}
+	decodeOption(t, sn.Script, &option)
+	if want := []string{"40m", "20m", "15m"}; !reflect.DeepEqual(option.XAxis.Data, want) {
+		t.Errorf("xAxis = %v, want %v", option.XAxis.Data, want)
+	}
+	if option.YAxis.Min != 0 || option.YAxis.Max != 25 {
+		t.Errorf("yAxis = %v to %v, want 0 to 25", option.YAxis.Min, option.YAxis.Max)
+	}
+	// Only continents with spots, in geo.Continents order, unknown last
+	want := []struct {
+		name string
+		data []float64
+	}{
+		{"Europe", []float64{6, 12, 0}},
+		{"Asia", []float64{0, 8, 3}},
+		{"Unknown", []float64{0, 2, 0}},
+	}
+	if len(option.Series) != len(want) {
+		t.Fatalf("Expected %d series, got %+v", len(want), option.Series)
+	}
+	for i, w := range want {
+		if s := option.Series[i]; s.Name != w.name || s.Type != "bar" || s.Stack != "spots" || !reflect.DeepEqual(s.Data, w.data) {
+			t.Errorf("series[%d] = %+v, want %s stacked with %v", i, s, w.name, w.data)
+		}
+	}
+
+	if sn.Static == nil || !sn.Static.Stacked || len(sn.Static.Series) != 3 || sn.Static.Axes[0].Max != 25 {
+		t.Fatalf("Unexpected static chart %+v", sn.Static)
+	}
+	if s := sn.Static.Series[0]; s.Name != "Europe" || s.Values[1] != 12 {
+		t.Errorf("Unexpected first series %+v", s)
+	}
+	for _, render := range []func(*StaticChart, int, int) ([]byte, error){RenderPNG, RenderSVG} {
+		if out, err := render(sn.Static, 900, 400); err != nil || len(out) == 0 {
+			t.Errorf("Rendering the chart failed: %v", err)
+		}
+	}
+	svg, _ := RenderSVG(sn.Static, 900, 400)
+	if !strings.Contains(string(svg), "Unknown") {
+		t.Error("Expected a legend for the stacked bars")
+	}
+}
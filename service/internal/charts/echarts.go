@@ -0,0 +1,10 @@
+package charts
+
+// ECharts bundle the chart snippets are written for. Snippets only contain the chart
+// initialisation; pages load the bundle once, preferring the vendored copy in /static/.
+const (
+	EChartsVersion    = "5.4.3"
+	EChartsStaticFile = "echarts.min.js"
+	EChartsStaticURL  = "/static/" + EChartsStaticFile
+	EChartsCDNURL     = "https://cdn.jsdelivr.net/npm/echarts@" + EChartsVersion + "/dist/echarts.min.js"
+)
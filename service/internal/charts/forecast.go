@@ -108,8 +108,7 @@ window.addEventListener('resize', function(){ c.resize(); });
 `, id, string(optJSON))
 
 	// Create complete HTML snippet with div and script
-	completeHTML := fmt.Sprintf(`<script src="https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js"></script>
-<div class="chart-container">
+	completeHTML := fmt.Sprintf(`<div class="chart-container">
 	<h3>3-Day K-index Forecast</h3>
 	%s
 </div>
@@ -121,6 +120,13 @@ window.addEventListener('resize', function(){ c.resize(); });
 		Div:    div,
 		Script: script,
 		HTML:   completeHTML,
+		Static: &StaticChart{
+			Kind:   StaticBarKind,
+			Title:  "3-Day K-index Forecast",
+			Labels: labels,
+			Axes:   []StaticAxis{{Name: "K-index", Min: 0, Max: 6}},
+			Series: []StaticSeries{{Name: "K-index", Values: kIndexValues, Colors: colors}},
+		},
 	}, nil
 }
 
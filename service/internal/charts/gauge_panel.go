@@ -50,8 +50,7 @@ func (cg *ChartGenerator) generateGaugePanelSnippet(data *models.PropagationData
 	}
 
 	// Create combined HTML with responsive layout
-	completeHTML := fmt.Sprintf(`<script src="https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js"></script>
-<div class="gauge-panel">
+	completeHTML := fmt.Sprintf(`<div class="gauge-panel">
 	<h3>Solar Activity Metrics</h3>
 	<div class="gauge-container">
 		%s
@@ -89,6 +88,7 @@ func (cg *ChartGenerator) generateGaugePanelSnippet(data *models.PropagationData
 		Div:    combinedDiv, 
 		Script: combinedScript, 
 		HTML:   completeHTML,
+		Static: combineStaticGauges("Solar Activity Metrics", kIndexGauge, solarFluxGauge, sunspotGauge),
 	}, nil
 }
 
@@ -43,5 +43,25 @@ func (cg *ChartGenerator) GenerateEChartsSnippetsWithSources(data *models.Propag
     if sn, err := cg.generatePropagationTimelineSnippet(data, sourceData); err == nil {
         snippets = append(snippets, sn)
     }
+    // Band Conditions Calendar (daily heatmap per band group, day and night)
+    if sn, err := cg.generateBandCalendarSnippet(data); err == nil {
+        snippets = append(snippets, sn)
+    }
+    // Gray Line (day/night terminator at the report time, with the configured QTHs)
+    if sn, err := cg.generateGrayLineSnippet(data); err == nil {
+        snippets = append(snippets, sn)
+    }
+    // Ionosonde MUF(3000)F2 (measured, hourly per station over the last 24 hours)
+    if sn, err := cg.generateIonosondeSnippet(data); err == nil {
+        snippets = append(snippets, sn)
+    }
+    // Observed Openings (WSPR/PSKReporter continent pairs per band group vs. N0NBH predictions)
+    if sn, err := cg.generateObservedBandsSnippet(data); err == nil {
+        snippets = append(snippets, sn)
+    }
+    // DX Cluster Spots (live spots per band, stacked by continent of the DX station)
+    if sn, err := cg.generateDXClusterSnippet(data); err == nil {
+        snippets = append(snippets, sn)
+    }
     return snippets, nil
 }
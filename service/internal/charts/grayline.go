@@ -0,0 +1,151 @@
+package charts
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"radiocast/internal/models"
+	"radiocast/internal/sun"
+)
+
+// terminatorStep is the longitude step of the terminator line, in degrees
+const terminatorStep = 2.0
+
+// generateGrayLineSnippet maps the day/night terminator at the report time, with the subsolar
+// point and the QTHs whose gray-line windows the report carries
+func (cg *ChartGenerator) generateGrayLineSnippet(data *models.PropagationData) (ChartSnippet, error) {
+	if data == nil || data.Timestamp.IsZero() {
+		return ChartSnippet{}, fmt.Errorf("no report time")
+	}
+	at := data.Timestamp.UTC()
+	subsolar := sun.Subsolar(at)
+	terminator := sun.Terminator(at, terminatorStep)
+
+	// The night side lies towards the pole the sun is away from
+	origin := "start"
+	if subsolar.Latitude < 0 {
+		origin = "end"
+	}
+	line := make([][2]float64, len(terminator))
+	for i, p := range terminator {
+		line[i] = [2]float64{round1(p.Longitude), round1(p.Latitude)}
+	}
+	night := make([][2]float64, 0, len(terminator)+2)
+	for _, p := range sun.NightPolygon(at, terminatorStep) {
+		night = append(night, [2]float64{round1(p.Longitude), round1(p.Latitude)})
+	}
+
+	var land []interface{}
+	for _, outline := range worldOutlines {
+		ring := append(append([][2]float64{}, outline...), outline[0])
+		land = append(land, map[string]interface{}{"coords": ring})
+	}
+
+	markers := []StaticMarker{{Label: "Sun", Latitude: subsolar.Latitude, Longitude: subsolar.Longitude, Color: "#ffc107"}}
+	qths := []interface{}{}
+	for _, qth := range data.GrayLine {
+		markers = append(markers, StaticMarker{Label: qth.Grid, Latitude: qth.Latitude, Longitude: qth.Longitude, Color: "#dc3545"})
+		qths = append(qths, map[string]interface{}{
+			"name":    qth.Grid,
+			"value":   []float64{qth.Longitude, qth.Latitude},
+			"tooltip": map[string]interface{}{"formatter": grayLineTooltip(qth)},
+		})
+	}
+
+	axis := func(min, max int) map[string]interface{} {
+		return map[string]interface{}{
+			"type":      "value",
+			"min":       min,
+			"max":       max,
+			"interval":  30,
+			"axisLabel": map[string]interface{}{"formatter": "{value}°"},
+			"splitLine": map[string]interface{}{"lineStyle": map[string]interface{}{"color": staticAxisLine}},
+		}
+	}
+	option := map[string]interface{}{
+		"tooltip": map[string]interface{}{"trigger": "item"},
+		"legend":  map[string]interface{}{"bottom": 0, "data": []string{"Gray line", "Sun", "QTH"}},
+		"grid": map[string]interface{}{
+			"left":            50,
+			"right":           20,
+			"top":             20,
+			"bottom":          60,
+			"show":            true,
+			"backgroundColor": staticOcean,
+		},
+		"xAxis": axis(-180, 180),
+		"yAxis": axis(-90, 90),
+		"series": []interface{}{
+			map[string]interface{}{
+				"name":             "Land",
+				"type":             "lines",
+				"coordinateSystem": "cartesian2d",
+				"polyline":         true,
+				"silent":           true,
+				"lineStyle":        map[string]interface{}{"color": "#6b8e5a", "width": 1, "opacity": 1},
+				"data":             land,
+			},
+			map[string]interface{}{
+				"name":       "Gray line",
+				"type":       "line",
+				"showSymbol": false,
+				"silent":     true,
+				"lineStyle":  map[string]interface{}{"color": staticGrayLine, "width": 2},
+				"itemStyle":  map[string]interface{}{"color": staticGrayLine},
+				"areaStyle":  map[string]interface{}{"color": "rgba(0,0,0,0.35)", "origin": origin},
+				"data":       line,
+			},
+			map[string]interface{}{
+				"name":       "Sun",
+				"type":       "scatter",
+				"symbolSize": 18,
+				"itemStyle":  map[string]interface{}{"color": "#ffc107", "borderColor": "#ff6b35"},
+				"tooltip":    map[string]interface{}{"formatter": fmt.Sprintf("Sun overhead at %.1f°, %.1f°", subsolar.Latitude, subsolar.Longitude)},
+				"data":       [][]float64{{round1(subsolar.Longitude), round1(subsolar.Latitude)}},
+			},
+			map[string]interface{}{
+				"name":       "QTH",
+				"type":       "scatter",
+				"symbolSize": 10,
+				"itemStyle":  map[string]interface{}{"color": "#dc3545", "borderColor": "#ffffff"},
+				"label":      map[string]interface{}{"show": true, "position": "right", "formatter": "{b}"},
+				"data":       qths,
+			},
+		},
+	}
+
+	title := fmt.Sprintf("Gray Line at %s", at.Format("Jan 2, 15:04 UTC"))
+	sn, err := trendSnippet("chart-gray-line", title, 480, option)
+	if err != nil {
+		return ChartSnippet{}, err
+	}
+	sn.Static = &StaticChart{
+		Kind:  StaticMapKind,
+		Title: title,
+		Map:   &StaticMap{Night: night, Terminator: line, Markers: markers},
+	}
+	return sn, nil
+}
+
+// grayLineTooltip lists the gray-line windows of a QTH
+func grayLineTooltip(qth models.GrayLine) string {
+	lines := []string{qth.Grid}
+	for _, w := range qth.Windows {
+		lines = append(lines, fmt.Sprintf("%s: %s–%s UTC", w.Event, clock(w.Start), clock(w.End)))
+	}
+	if len(qth.Windows) == 0 {
+		lines = append(lines, "No gray line in the next 24 hours")
+	}
+	return strings.Join(lines, "<br/>")
+}
+
+// clock formats a time as UTC hours and minutes
+func clock(t time.Time) string {
+	return t.UTC().Format("15:04")
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,108 @@
+package charts
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+)
+
+func TestGenerateGrayLineSnippet(t *testing.T) {
+	cg := NewChartGenerator("")
+	if _, err := cg.generateGrayLineSnippet(&models.PropagationData{}); err == nil {
+		t.Error("Expected an error without a report time")
+	}
+
+	at := time.Date(2025, 10, 18, 14, 0, 0, 0, time.UTC)
+	data := &models.PropagationData{
+		Timestamp: at,
+		GrayLine: []models.GrayLine{{
+			Grid:      "FN31pr",
+			Latitude:  41.7292,
+			Longitude: -72.7083,
+			Windows: []models.GrayLineWindow{
+				{Start: time.Date(2025, 10, 18, 21, 39, 0, 0, time.UTC), End: time.Date(2025, 10, 18, 22, 37, 0, 0, time.UTC), Event: "sunset"},
+			},
+		}},
+	}
+	sn, err := cg.generateGrayLineSnippet(data)
+	if err != nil {
+		t.Fatalf("generateGrayLineSnippet() error = %v", err)
+	}
+	if sn.ID != "chart-gray-line" || sn.Title != "Gray Line at Oct 18, 14:00 UTC" || !strings.Contains(sn.HTML, sn.Div) {
+		t.Errorf("Unexpected snippet %s %q: %s", sn.ID, sn.Title, sn.HTML)
+	}
+	if sn.Static == nil || sn.Static.Kind != StaticMapKind || len(sn.Static.Map.Markers) != 2 {
+		t.Fatalf("Unexpected static chart %+v", sn.Static)
+	}
+
+	var option struct {
+		XAxis, YAxis struct {
+			Min, Max float64
+		}
+		Series []struct {
+			Name             string                  `json:"name"`
+			Type             string                  `json:"type"`
+			CoordinateSystem string                  `json:"coordinateSystem"`
+			AreaStyle        struct{ Origin string } `json:"areaStyle"`
+			Data             json.RawMessage         `json:"data"`
+		} `json:"series"`
+	}
+	decodeOption(t, sn.Script, &option)
+	if option.XAxis.Min != -180 || option.XAxis.Max != 180 || option.YAxis.Min != -90 || option.YAxis.Max != 90 {
+		t.Errorf("Axes = %+v / %+v, want longitude and latitude", option.XAxis, option.YAxis)
+	}
+	var names []string
+	for _, series := range option.Series {
+		names = append(names, series.Name+" "+series.Type)
+	}
+	if want := []string{"Land lines", "Gray line line", "Sun scatter", "QTH scatter"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("series = %v, want %v", names, want)
+	}
+	if land := option.Series[0]; land.CoordinateSystem != "cartesian2d" {
+		t.Errorf("Land outlines drawn on %q, want the cartesian grid", land.CoordinateSystem)
+	}
+
+	// The sun is south of the equator in October: night over the north pole
+	var line [][2]float64
+	if err := json.Unmarshal(option.Series[1].Data, &line); err != nil {
+		t.Fatal(err)
+	}
+	if option.Series[1].AreaStyle.Origin != "end" || !reflect.DeepEqual(line, sn.Static.Map.Terminator) {
+		t.Errorf("Gray line origin %q with %d points, want end and the %d terminator points", option.Series[1].AreaStyle.Origin, len(line), len(sn.Static.Map.Terminator))
+	}
+	var subsolar [][2]float64
+	if err := json.Unmarshal(option.Series[2].Data, &subsolar); err != nil {
+		t.Fatal(err)
+	}
+	if len(subsolar) != 1 || subsolar[0][1] >= 0 {
+		t.Errorf("Sun data = %v, want one point south of the equator", subsolar)
+	}
+	var qths []struct {
+		Name    string    `json:"name"`
+		Value   []float64 `json:"value"`
+		Tooltip struct{ Formatter string }
+	}
+	if err := json.Unmarshal(option.Series[3].Data, &qths); err != nil {
+		t.Fatal(err)
+	}
+	if len(qths) != 1 || qths[0].Name != "FN31pr" || !reflect.DeepEqual(qths[0].Value, []float64{-72.7083, 41.7292}) || qths[0].Tooltip.Formatter != "FN31pr<br/>sunset: 21:39–22:37 UTC" {
+		t.Errorf("QTH data = %+v", qths)
+	}
+
+	if m := sn.Static.Map; len(m.Night) != len(m.Terminator)+2 || m.Night[len(m.Night)-1] != [2]float64{-180, 90} {
+		t.Errorf("Night side should close over the north pole, got %v", m.Night[len(m.Night)-2:])
+	}
+	for _, render := range []func(*StaticChart, int, int) ([]byte, error){RenderPNG, RenderSVG} {
+		if out, err := render(sn.Static, 900, 480); err != nil || len(out) == 0 {
+			t.Errorf("Rendering the map failed: %v", err)
+		}
+	}
+	svg, _ := RenderSVG(sn.Static, 900, 480)
+	if !strings.Contains(string(svg), `fill-opacity="0.35"`) {
+		t.Error("Expected a translucent night side in the SVG")
+	}
+}
@@ -113,12 +113,22 @@ func (cg *ChartGenerator) generateHistoricalSolarTrendSnippet(data *models.Propa
 	script := fmt.Sprintf(`<script>(function(){var el=document.getElementById('%s');if(!el)return;var c=echarts.init(el);var option=%s;c.setOption(option);window.addEventListener('resize',function(){c.resize();});})();</script>`, id, string(optJSON))
 
 	// Create complete HTML snippet with div and script
-	completeHTML := fmt.Sprintf(`<script src="https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js"></script>
-<div class="chart-container">
+	completeHTML := fmt.Sprintf(`<div class="chart-container">
 	<h3>Solar Activity Trends (6 Months)</h3>
 	%s
 </div>
 %s`, div, script)
 
-	return ChartSnippet{ID: id, Title: "Solar Activity Trends (6 Months)", Div: div, Script: script, HTML: completeHTML}, nil
+	static := &StaticChart{
+		Kind:   StaticLineKind,
+		Title:  "Solar Activity Trends (6 Months)",
+		Labels: xdata,
+		Axes:   []StaticAxis{{Name: "Solar Flux", Min: 80, Max: 250}, {Name: "Sunspot Number", Min: 0, Max: 200}},
+		Series: []StaticSeries{
+			{Name: "Solar Flux (10.7cm)", Values: solarFluxValues, Color: "#ff6b35"},
+			{Name: "Sunspot Number", Values: sunspotValues, Color: "#4ecdc4", Axis: 1},
+		},
+	}
+
+	return ChartSnippet{ID: id, Title: "Solar Activity Trends (6 Months)", Div: div, Script: script, HTML: completeHTML, Static: static}, nil
 }
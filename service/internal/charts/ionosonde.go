@@ -0,0 +1,121 @@
+package charts
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"radiocast/internal/models"
+)
+
+// ionosondeHours is the width of the ionosonde chart
+const ionosondeHours = 24
+
+// ionosondeBands mark the MUF(3000) above which a band opens on 3000 km F2 hops
+var ionosondeBands = []struct {
+	Name         string
+	FrequencyMHz float64
+}{
+	{"20m", 14}, {"15m", 21}, {"10m", 28},
+}
+
+// generateIonosondeSnippet plots the hourly measured MUF(3000)F2 of every ionosonde over the 24
+// hours up to the newest reading
+func (cg *ChartGenerator) generateIonosondeSnippet(data *models.PropagationData) (ChartSnippet, error) {
+	if data == nil || len(data.Ionosondes) == 0 {
+		return ChartSnippet{}, fmt.Errorf("no ionosonde readings")
+	}
+
+	var last time.Time
+	for _, station := range data.Ionosondes {
+		if t := station.Latest.Time.UTC().Truncate(time.Hour); t.After(last) {
+			last = t
+		}
+	}
+	hours := make([]time.Time, ionosondeHours)
+	for i := range hours {
+		hours[i] = last.Add(time.Duration(i-ionosondeHours+1) * time.Hour)
+	}
+	labels := staticTimeLabels(hours)
+
+	top := 30.0
+	var series []interface{}
+	var static []StaticSeries
+	var legend []string
+	for i, station := range data.Ionosondes {
+		name := fmt.Sprintf("%s (%s)", station.Name, station.Code)
+		values := make([]float64, ionosondeHours)
+		points := make([]interface{}, ionosondeHours) // nil leaves a gap
+		for j := range values {
+			values[j] = math.NaN()
+		}
+		for _, r := range station.Hourly {
+			j := int(r.Time.UTC().Truncate(time.Hour).Sub(hours[0]) / time.Hour)
+			if j < 0 || j >= ionosondeHours {
+				continue
+			}
+			values[j], points[j] = r.MUF3000, r.MUF3000
+			top = math.Max(top, r.MUF3000)
+		}
+
+		color := defaultPalette[i%len(defaultPalette)]
+		s := map[string]interface{}{
+			"name":       name,
+			"type":       "line",
+			"data":       points,
+			"showSymbol": true,
+			"symbolSize": 5,
+			"itemStyle":  map[string]interface{}{"color": color},
+		}
+		if i == 0 {
+			var marks []interface{}
+			for _, band := range ionosondeBands {
+				marks = append(marks, map[string]interface{}{"yAxis": band.FrequencyMHz, "name": band.Name})
+			}
+			s["markLine"] = map[string]interface{}{
+				"silent":    true,
+				"symbol":    "none",
+				"lineStyle": map[string]interface{}{"color": "#adb5bd", "type": "dashed"},
+				"label":     map[string]interface{}{"formatter": "{b}", "position": "end"},
+				"data":      marks,
+			}
+		}
+		series = append(series, s)
+		static = append(static, StaticSeries{Name: name, Values: values, Color: color})
+		legend = append(legend, name)
+	}
+	axisMax := math.Ceil(top/5) * 5
+
+	option := map[string]interface{}{
+		"tooltip": map[string]interface{}{"trigger": "axis"},
+		"legend":  map[string]interface{}{"data": legend, "bottom": 0},
+		"grid":    map[string]interface{}{"left": "8%", "right": "8%", "bottom": "14%", "containLabel": true},
+		"xAxis":   map[string]interface{}{"type": "category", "data": labels},
+		"yAxis": map[string]interface{}{
+			"type": "value",
+			"name": "MUF(3000)F2 (MHz)",
+			"min":  0,
+			"max":  axisMax,
+		},
+		"series": series,
+	}
+
+	title := "Ionosonde MUF(3000)F2, Last 24 Hours"
+	sn, err := trendSnippet("chart-ionosonde-muf", title, 420, option)
+	if err != nil {
+		return ChartSnippet{}, err
+	}
+	guides := make([]float64, len(ionosondeBands))
+	for i, band := range ionosondeBands {
+		guides[i] = band.FrequencyMHz
+	}
+	sn.Static = &StaticChart{
+		Kind:       StaticLineKind,
+		Title:      title,
+		Labels:     labels,
+		Axes:       []StaticAxis{{Name: "MUF(3000)F2 (MHz)", Min: 0, Max: axisMax}},
+		Series:     static,
+		GuideLines: guides,
+	}
+	return sn, nil
+}
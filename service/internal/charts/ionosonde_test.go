@@ -0,0 +1,107 @@
+package charts
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+)
+
+func TestGenerateIonosondeSnippet(t *testing.T) {
+	cg := NewChartGenerator("")
+	if _, err := cg.generateIonosondeSnippet(&models.PropagationData{}); err == nil {
+		t.Error("Expected an error without ionosondes")
+	}
+
+	end := time.Date(2025, 10, 18, 13, 45, 0, 0, time.UTC)
+	var hourly []models.IonosondeReading
+	for i := 23; i >= 0; i-- {
+		if i == 5 {
+			continue // a missing hour
+		}
+		hourly = append(hourly, models.IonosondeReading{Time: end.Add(time.Duration(-i) * time.Hour), Confidence: 90, FoF2: 6, MUF3000: 20 + float64(i%4)})
+	}
+	data := &models.PropagationData{Ionosondes: []models.Ionosonde{
+		{Code: "BC840", Name: "Boulder", Latest: hourly[len(hourly)-1], Hourly: hourly},
+		{Code: "JR055", Name: "Juliusruh", Latest: models.IonosondeReading{Time: end.Add(-2 * time.Hour), MUF3000: 34.2},
+			Hourly: []models.IonosondeReading{{Time: end.Add(-2 * time.Hour), MUF3000: 34.2}}},
+	}}
+
+	sn, err := cg.generateIonosondeSnippet(data)
+	if err != nil {
+		t.Fatalf("generateIonosondeSnippet() error = %v", err)
+	}
+	if sn.ID != "chart-ionosonde-muf" || sn.Title != "Ionosonde MUF(3000)F2, Last 24 Hours" || !strings.Contains(sn.HTML, sn.Div) {
+		t.Errorf("Unexpected snippet %s %q", sn.ID, sn.Title)
+	}
+	if sn.Static == nil || sn.Static.Kind != StaticLineKind || len(sn.Static.Series) != 2 || len(sn.Static.Labels) != 24 {
+		t.Fatalf("Unexpected static chart %+v", sn.Static)
+	}
+
+	var option struct {
+		XAxis struct {
+			Data []string `json:"data"`
+		} `json:"xAxis"`
+		YAxis struct {
+			Min, Max float64
+		} `json:"yAxis"`
+		Series []struct {
+			Name     string     `json:"name"`
+			Data     []*float64 `json:"data"`
+			MarkLine struct {
+				Data []struct {
+					Name  string  `json:"name"`
+					YAxis float64 `json:"yAxis"`
+				} `json:"data"`
+			} `json:"markLine"`
+		} `json:"series"`
+	}
+	decodeOption(t, sn.Script, &option)
+	if !reflect.DeepEqual(option.XAxis.Data, sn.Static.Labels) {
+		t.Errorf("xAxis = %v, want the hourly labels %v", option.XAxis.Data, sn.Static.Labels)
+	}
+	// The axis rounds the highest reading up to 5 MHz
+	if option.YAxis.Min != 0 || option.YAxis.Max != 35 {
+		t.Errorf("yAxis = %v to %v, want 0 to 35", option.YAxis.Min, option.YAxis.Max)
+	}
+	if len(option.Series) != 2 || option.Series[0].Name != "Boulder (BC840)" || option.Series[1].Name != "Juliusruh (JR055)" {
+		t.Fatalf("Unexpected series %+v", option.Series)
+	}
+	for i, series := range option.Series {
+		if len(series.Data) != 24 {
+			t.Fatalf("series[%d] has %d points, want 24", i, len(series.Data))
+		}
+	}
+	// Missing hours are gaps in the line
+	if b := option.Series[0].Data; b[18] != nil || b[23] == nil || *b[23] != 20 || b[0] == nil || *b[0] != 23 {
+		t.Errorf("Unexpected Boulder points %v", b)
+	}
+	if j := option.Series[1].Data; j[21] == nil || *j[21] != 34.2 || j[23] != nil {
+		t.Errorf("Unexpected Juliusruh points %v", j)
+	}
+	marks := option.Series[0].MarkLine.Data
+	if len(marks) != 3 || marks[0].Name != "20m" || marks[0].YAxis != 14 || marks[2].Name != "10m" || marks[2].YAxis != 28 {
+		t.Errorf("Unexpected band marks %+v", marks)
+	}
+	if len(option.Series[1].MarkLine.Data) != 0 {
+		t.Error("Band marks should be drawn once, on the first series")
+	}
+	if got := sn.Static.GuideLines; len(got) != 3 || got[0] != 14 || got[2] != 28 {
+		t.Errorf("Unexpected guide lines %v", got)
+	}
+	boulder, juliusruh := sn.Static.Series[0].Values, sn.Static.Series[1].Values
+	if !math.IsNaN(boulder[18]) || boulder[23] != 20 || boulder[0] != 23 {
+		t.Errorf("Unexpected Boulder values %v", boulder)
+	}
+	if juliusruh[21] != 34.2 || !math.IsNaN(juliusruh[23]) {
+		t.Errorf("Unexpected Juliusruh values %v", juliusruh)
+	}
+	for _, render := range []func(*StaticChart, int, int) ([]byte, error){RenderPNG, RenderSVG} {
+		if out, err := render(sn.Static, 900, 420); err != nil || len(out) == 0 {
+			t.Errorf("Rendering the chart failed: %v", err)
+		}
+	}
+}
@@ -108,12 +108,20 @@ func (cg *ChartGenerator) generateKIndexGaugeSnippet(data *models.PropagationDat
 	script := fmt.Sprintf(`<script>(function(){var el=document.getElementById('%s');if(!el)return;var c=echarts.init(el);var option=%s;c.setOption(option);window.addEventListener('resize',function(){c.resize();});})();</script>`, id, string(optJSON))
 
 	// Create complete HTML snippet with div and script
-	completeHTML := fmt.Sprintf(`<script src="https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js"></script>
-<div class="gauge-item">
+	completeHTML := fmt.Sprintf(`<div class="gauge-item">
 	<h4>K-index</h4>
 	%s
 </div>
 %s`, div, script)
 
-	return ChartSnippet{ID: id, Title: "Solar K-index Gauge", Div: div, Script: script, HTML: completeHTML}, nil
+	static := newStaticGauge("Solar K-index Gauge", StaticGauge{
+		Label:  "K-index",
+		Value:  kIndex,
+		Min:    0,
+		Max:    9,
+		Stops:  []ColorStop{{0.22, "#28a745"}, {0.44, "#ffc107"}, {0.67, "#fd7e14"}, {0.89, "#dc3545"}, {1.0, "#6f42c1"}},
+		Detail: fmt.Sprintf("%.1f %s", kIndex, statusText),
+	})
+
+	return ChartSnippet{ID: id, Title: "Solar K-index Gauge", Div: div, Script: script, HTML: completeHTML, Static: static}, nil
 }
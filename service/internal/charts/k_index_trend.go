@@ -70,14 +70,25 @@ func (cg *ChartGenerator) generateKIndexTrendSnippet(data *models.PropagationDat
 	script := fmt.Sprintf(`<script>(function(){var el=document.getElementById('%s');if(!el)return;var c=echarts.init(el);var option=%s;c.setOption(option);window.addEventListener('resize',function(){c.resize();});})();</script>`, id, string(optJSON))
 
 	// Create complete HTML snippet with div and script
-	completeHTML := fmt.Sprintf(`<script src="https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js"></script>
-<div class="chart-container">
+	completeHTML := fmt.Sprintf(`<div class="chart-container">
 	<h3>K-index Trend (72 Hours)</h3>
 	%s
 </div>
 %s`, div, script)
 
-	return ChartSnippet{ID: id, Title: "K-index Trend (72 Hours)", Div: div, Script: script, HTML: completeHTML}, nil
+	static := &StaticChart{
+		Kind:   StaticLineKind,
+		Title:  "K-index Trend (72 Hours)",
+		Labels: staticTimeLabels(times),
+		Axes:   []StaticAxis{{Name: "K-index", Min: 0, Max: 9}},
+		Series: []StaticSeries{
+			{Name: "K-index", Values: values, Color: defaultPalette[0]},
+			{Name: "EMA(5)", Values: ema, Color: defaultPalette[1]},
+		},
+		GuideLines: []float64{2, 3, 4},
+	}
+
+	return ChartSnippet{ID: id, Title: "K-index Trend (72 Hours)", Div: div, Script: script, HTML: completeHTML, Static: static}, nil
 }
 
 func emaSeries(vals []float64, period int) []float64 {
@@ -0,0 +1,76 @@
+package charts
+
+import (
+	"fmt"
+	"math"
+
+	"radiocast/internal/geo"
+	"radiocast/internal/models"
+)
+
+// generateObservedBandsSnippet compares the continent pairs each N0NBH band group was open between,
+// from the spots, with the band group's predicted day and night conditions
+func (cg *ChartGenerator) generateObservedBandsSnippet(data *models.PropagationData) (ChartSnippet, error) {
+	if data == nil || data.ObservedBands == nil || len(data.ObservedBands.Bands) == 0 {
+		return ChartSnippet{}, fmt.Errorf("no observed band activity")
+	}
+
+	// Every continent with every other one and itself, rounded up for even axis ticks
+	maxPairs := math.Ceil(float64(len(geo.Continents)*(len(geo.Continents)+1)/2)/5) * 5
+
+	groups := data.BandData.Groups()
+	labels := make([]string, len(groups))
+	open := make([]float64, len(groups))
+	day := make([]float64, len(groups))
+	night := make([]float64, len(groups))
+	for i, g := range groups {
+		labels[i] = g.Group
+		open[i] = float64(len(data.ObservedBands.Group(g.Group)))
+		day[i] = float64(cg.conditionToValue(g.Day))
+		night[i] = float64(cg.conditionToValue(g.Night))
+	}
+
+	bar := func(name string, values []float64, axis int, color string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":       name,
+			"type":       "bar",
+			"data":       values,
+			"yAxisIndex": axis,
+			"itemStyle":  map[string]interface{}{"color": color},
+		}
+	}
+	option := map[string]interface{}{
+		"tooltip": map[string]interface{}{"trigger": "axis"},
+		"legend":  map[string]interface{}{"bottom": 0},
+		"grid":    map[string]interface{}{"left": "8%", "right": "8%", "bottom": "14%", "containLabel": true},
+		"xAxis":   map[string]interface{}{"type": "category", "data": labels},
+		"yAxis": []interface{}{
+			map[string]interface{}{"type": "value", "name": "Open continent pairs", "min": 0, "max": maxPairs},
+			map[string]interface{}{"type": "value", "name": "Predicted (0 Closed, 4 Excellent)", "min": 0, "max": 4, "interval": 1},
+		},
+		"series": []interface{}{
+			bar("Observed", open, 0, defaultPalette[0]),
+			bar("Predicted day", day, 1, defaultPalette[2]),
+			bar("Predicted night", night, 1, defaultPalette[4]),
+		},
+	}
+
+	hours := data.ObservedBands.To.Sub(data.ObservedBands.From).Hours()
+	title := fmt.Sprintf("Observed Openings (Last %.0fh) vs. Predicted Conditions", hours)
+	sn, err := trendSnippet("chart-observed-bands", title, 400, option)
+	if err != nil {
+		return ChartSnippet{}, err
+	}
+	sn.Static = &StaticChart{
+		Kind:   StaticBarKind,
+		Title:  title,
+		Labels: labels,
+		Axes:   []StaticAxis{{Name: "Open continent pairs", Min: 0, Max: maxPairs}, {Name: "Predicted", Min: 0, Max: 4}},
+		Series: []StaticSeries{
+			{Name: "Observed", Values: open, Color: defaultPalette[0]},
+			{Name: "Predicted day", Values: day, Color: defaultPalette[2], Axis: 1},
+			{Name: "Predicted night", Values: night, Color: defaultPalette[4], Axis: 1},
+		},
+	}
+	return sn, nil
+}
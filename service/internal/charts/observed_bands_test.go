@@ -0,0 +1,101 @@
+package charts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+)
+
+func TestGenerateObservedBandsSnippet(t *testing.T) {
+	cg := NewChartGenerator("")
+	if _, err := cg.generateObservedBandsSnippet(&models.PropagationData{}); err == nil {
+		t.Error("Expected an error without observed bands")
+	}
+
+	to := time.Date(2025, 10, 18, 14, 0, 0, 0, time.UTC)
+	data := &models.PropagationData{
+		BandData: models.BandData{
+			Band80m: models.BandCondition{Day: "Poor", Night: "Good"},
+			Band20m: models.BandCondition{Day: "Good", Night: "Fair"},
+			Band15m: models.BandCondition{Day: "Excellent", Night: "Poor"},
+			Band10m: models.BandCondition{Day: "Fair", Night: "Closed"},
+		},
+		ObservedBands: &models.ObservedBands{
+			From: to.Add(-6 * time.Hour),
+			To:   to,
+			Bands: []models.ObservedBand{
+				{Band: "40m", Group: "80m-40m", Spots: 355, Openings: []models.ObservedOpening{{From: "EU", To: "EU", Spots: 300}}},
+				{Band: "30m", Group: "30m-20m", Spots: 20, Openings: []models.ObservedOpening{{From: "NA", To: "EU", Spots: 20}}},
+				{Band: "20m", Group: "30m-20m", Spots: 180, Openings: []models.ObservedOpening{
+					{From: "EU", To: "EU", Spots: 120}, {From: "NA", To: "EU", Spots: 60}, {From: "NA", To: "AS", Spots: 3},
+				}},
+			},
+		},
+	}
+	sn, err := cg.generateObservedBandsSnippet(data)
+	if err != nil {
+		t.Fatalf("generateObservedBandsSnippet() error = %v", err)
+	}
+	if sn.ID != "chart-observed-bands" || sn.Title != "Observed Openings (Last 6h) vs. Predicted Conditions" || !strings.Contains(sn.HTML, sn.Div) {
+		t.Errorf("Unexpected snippet %s %q", sn.ID, sn.Title)
+	}
+
+	var option struct {
+		XAxis struct {
+			Data []string `json:"data"`
+		} `json:"xAxis"`
+		YAxis []struct {
+			Min, Max float64
+		} `json:"yAxis"`
+		Series []struct {
+			Name       string    `json:"name"`
+			Type       string    `json:"type"`
+			Data       []float64 `json:"data"`
+			YAxisIndex int       `json:"yAxisIndex"`
+		} `json:"series"`
+	}
+	decodeOption(t, sn.Script, &option)
+	if want := []string{"80m-40m", "30m-20m", "17m-15m", "12m-10m", "6m"}; !reflect.DeepEqual(option.XAxis.Data, want) {
+		t.Errorf("xAxis = %v, want the band groups %v", option.XAxis.Data, want)
+	}
+	// Open continent pairs on the left axis, predicted conditions on the right one
+	if len(option.YAxis) != 2 || option.YAxis[0].Max != 25 || option.YAxis[1].Max != 4 {
+		t.Errorf("Unexpected y axes %+v", option.YAxis)
+	}
+	want := []struct {
+		name string
+		axis int
+		data []float64
+	}{
+		{"Observed", 0, []float64{1, 3, 0, 0, 0}},
+		{"Predicted day", 1, []float64{1, 3, 4, 2, 0}},
+		{"Predicted night", 1, []float64{3, 2, 1, 0, 0}},
+	}
+	if len(option.Series) != len(want) {
+		t.Fatalf("Expected %d series, got %d", len(want), len(option.Series))
+	}
+	for i, w := range want {
+		if s := option.Series[i]; s.Name != w.name || s.Type != "bar" || s.YAxisIndex != w.axis || !reflect.DeepEqual(s.Data, w.data) {
+			t.Errorf("series[%d] = %+v, want %s on axis %d with %v", i, s, w.name, w.axis, w.data)
+		}
+	}
+
+	if sn.Static == nil || sn.Static.Kind != StaticBarKind || len(sn.Static.Series) != 3 || len(sn.Static.Axes) != 2 {
+		t.Fatalf("Unexpected static chart %+v", sn.Static)
+	}
+	if s := sn.Static.Series[2]; s.Axis != 1 || s.Values[0] != 3 {
+		t.Errorf("Unexpected predicted night series %+v", s)
+	}
+	for _, render := range []func(*StaticChart, int, int) ([]byte, error){RenderPNG, RenderSVG} {
+		if out, err := render(sn.Static, 900, 400); err != nil || len(out) == 0 {
+			t.Errorf("Rendering the chart failed: %v", err)
+		}
+	}
+	svg, _ := RenderSVG(sn.Static, 900, 400)
+	if !strings.Contains(string(svg), "Predicted night") {
+		t.Error("Expected a legend for the grouped bars")
+	}
+}
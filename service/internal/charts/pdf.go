@@ -0,0 +1,110 @@
+package charts
+
+import (
+	"fmt"
+	"math"
+
+	"radiocast/internal/pdf"
+	"radiocast/internal/raster"
+)
+
+// pdfLayoutWidth caps the layout width so labels stay legible once scaled to a printed page
+const pdfLayoutWidth = 700.0
+
+// DrawPDF draws a static chart as vector graphics at (x, y), scaled to the given width with
+// the aspect ratio of StaticImageSize, and returns the height used on the page
+func DrawPDF(page *pdf.Page, chart *StaticChart, x, y, width float64) (float64, error) {
+	if chart == nil {
+		return 0, fmt.Errorf("chart cannot be nil")
+	}
+	w, h := StaticImageSize(chart)
+	layoutW := math.Min(float64(w), pdfLayoutWidth)
+	layoutH := float64(h) * layoutW / float64(w)
+	s := &pdfSurface{page: page, x: x, y: y, scale: width / layoutW}
+	if err := drawStatic(s, chart, layoutW, layoutH); err != nil {
+		return 0, err
+	}
+	return layoutH * s.scale, nil
+}
+
+// PDFHeight returns the height DrawPDF will use for the chart at the given width
+func PDFHeight(chart *StaticChart, width float64) float64 {
+	w, h := StaticImageSize(chart)
+	return float64(h) / float64(w) * width
+}
+
+// pdfSurface maps chart pixel coordinates onto a PDF page
+type pdfSurface struct {
+	page  *pdf.Page
+	x, y  float64
+	scale float64
+}
+
+func (s *pdfSurface) pt(x, y float64) pdf.Point {
+	return pdf.Point{X: s.x + x*s.scale, Y: s.y + y*s.scale}
+}
+
+func (s *pdfSurface) Rect(x, y, w, h float64, fill string) {
+	p := s.pt(x, y)
+	s.page.FillRect(p.X, p.Y, w*s.scale, h*s.scale, raster.ParseHexColor(fill))
+}
+
+func (s *pdfSurface) Line(x0, y0, x1, y1, width float64, stroke string) {
+	a, b := s.pt(x0, y0), s.pt(x1, y1)
+	s.page.Line(a.X, a.Y, b.X, b.Y, width*s.scale, raster.ParseHexColor(stroke))
+}
+
+func (s *pdfSurface) Polyline(points []raster.Point, width float64, stroke string) {
+	pts := make([]pdf.Point, len(points))
+	for i, p := range points {
+		pts[i] = s.pt(p.X, p.Y)
+	}
+	s.page.Polyline(pts, width*s.scale, raster.ParseHexColor(stroke))
+}
+
+func (s *pdfSurface) Circle(cx, cy, r float64, fill string) {
+	c := s.pt(cx, cy)
+	s.page.FillCircle(c.X, c.Y, r*s.scale, raster.ParseHexColor(fill))
+}
+
+func (s *pdfSurface) Polygon(points []raster.Point, fill string, opacity float64) {
+	pts := make([]pdf.Point, len(points))
+	for i, p := range points {
+		pts[i] = s.pt(p.X, p.Y)
+	}
+	s.page.FillPolygonAlpha(pts, raster.ParseHexColor(fill), opacity)
+}
+
+// Arc fills an annulus sector approximated by short segments (angles counter-clockwise from +x)
+func (s *pdfSurface) Arc(cx, cy, rInner, rOuter, startDeg, endDeg float64, fill string) {
+	if endDeg < startDeg {
+		startDeg, endDeg = endDeg, startDeg
+	}
+	steps := int(math.Max(2, math.Ceil((endDeg-startDeg)/3)))
+	pts := make([]pdf.Point, 0, 2*(steps+1))
+	for i := 0; i <= steps; i++ {
+		rad := (startDeg + (endDeg-startDeg)*float64(i)/float64(steps)) * math.Pi / 180
+		pts = append(pts, s.pt(cx+rOuter*math.Cos(rad), cy-rOuter*math.Sin(rad)))
+	}
+	for i := steps; i >= 0; i-- {
+		rad := (startDeg + (endDeg-startDeg)*float64(i)/float64(steps)) * math.Pi / 180
+		pts = append(pts, s.pt(cx+rInner*math.Cos(rad), cy-rInner*math.Sin(rad)))
+	}
+	s.page.FillPolygon(pts, raster.ParseHexColor(fill))
+}
+
+// Text uses Helvetica at a size comparable to the bitmap font scale; y is the top of the text
+func (s *pdfSurface) Text(x, y float64, text string, size int, fill string, align raster.Align) {
+	if size < 1 {
+		size = 1
+	}
+	fontSize := float64(10*size) * s.scale
+	switch align {
+	case raster.AlignCenter:
+		x -= pdf.TextWidth(pdf.Helvetica, fontSize, text) / 2 / s.scale
+	case raster.AlignRight:
+		x -= pdf.TextWidth(pdf.Helvetica, fontSize, text) / s.scale
+	}
+	p := s.pt(x, y+float64(raster.TextHeight(size)))
+	s.page.Text(p.X, p.Y, text, pdf.Helvetica, fontSize, raster.ParseHexColor(fill))
+}
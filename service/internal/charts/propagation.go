@@ -0,0 +1,110 @@
+package charts
+
+import (
+	"fmt"
+	"strings"
+
+	"radiocast/internal/propagation"
+	"radiocast/internal/sun"
+)
+
+// GeneratePropagationSnippet builds the hourly band-opening heatmap of a QTH prediction, with a
+// timeline to step through the DX regions
+func (cg *ChartGenerator) GeneratePropagationSnippet(p *propagation.Prediction) (ChartSnippet, error) {
+	if p == nil || len(p.Paths) == 0 {
+		return ChartSnippet{}, fmt.Errorf("no propagation paths")
+	}
+
+	hours := make([]string, 24)
+	for h := range hours {
+		hours[h] = fmt.Sprintf("%02d", h)
+	}
+	bands := make([]string, len(p.Bands))
+	for i, band := range p.Bands {
+		bands[i] = band.Name
+	}
+
+	var regions []string
+	var options []interface{}
+	for _, path := range p.Paths {
+		regions = append(regions, path.Region)
+		var cells [][]interface{}
+		for h, hour := range path.Hours {
+			for b, open := range hour.Open {
+				cells = append(cells, []interface{}{h, b, int(open*100 + 0.5)})
+			}
+		}
+		options = append(options, map[string]interface{}{
+			"title": map[string]interface{}{
+				"text": fmt.Sprintf("%s (%s)", path.Region, path.Location.Grid),
+				"subtext": fmt.Sprintf("%.0f km at %.0f°, %d hop(s) · %s there",
+					path.DistanceKm, path.Bearing, path.Hops, sunText(path.Sun)),
+			},
+			"series": []interface{}{map[string]interface{}{"data": cells}},
+		})
+	}
+
+	option := map[string]interface{}{
+		"baseOption": map[string]interface{}{
+			"timeline": map[string]interface{}{
+				"axisType": "category",
+				"data":     regions,
+				"autoPlay": false,
+				"left":     "5%",
+				"right":    "5%",
+				"bottom":   0,
+				"label":    map[string]interface{}{"fontSize": 10},
+			},
+			"title": map[string]interface{}{
+				"left":         "center",
+				"textStyle":    map[string]interface{}{"fontSize": 14},
+				"subtextStyle": map[string]interface{}{"fontSize": 11},
+			},
+			"tooltip": map[string]interface{}{"position": "top"},
+			"grid":    map[string]interface{}{"top": 60, "left": 45, "right": 20, "bottom": 130},
+			"xAxis": map[string]interface{}{
+				"type":      "category",
+				"data":      hours,
+				"name":      "UTC",
+				"splitArea": map[string]interface{}{"show": true},
+			},
+			"yAxis": map[string]interface{}{
+				"type":      "category",
+				"data":      bands,
+				"splitArea": map[string]interface{}{"show": true},
+			},
+			"visualMap": map[string]interface{}{
+				"min":        0,
+				"max":        100,
+				"calculable": true,
+				"orient":     "horizontal",
+				"left":       "center",
+				"bottom":     65,
+				"text":       []string{"100% open", "closed"},
+				"inRange":    map[string]interface{}{"color": []string{"#000000", "#dc3545", "#fd7e14", "#ffc107", "#28a745"}},
+			},
+			"series": []interface{}{map[string]interface{}{
+				"name": "Open %",
+				"type": "heatmap",
+			}},
+		},
+		"options": options,
+	}
+
+	id := "chart-propagation-" + strings.ToLower(p.Location.Grid)
+	title := fmt.Sprintf("Band Openings from %s on %s · %s", p.Location.Grid, p.Date, sunText(p.Sun))
+	return trendSnippet(id, title, 520, option)
+}
+
+// sunText describes the sunrise and sunset of a day
+func sunText(times sun.Times) string {
+	switch {
+	case times.PolarDay:
+		return "polar day"
+	case times.PolarNight:
+		return "polar night"
+	case times.Sunrise == nil || times.Sunset == nil:
+		return "no sun times"
+	}
+	return fmt.Sprintf("sunrise %s, sunset %s UTC", times.Sunrise.Format("15:04"), times.Sunset.Format("15:04"))
+}
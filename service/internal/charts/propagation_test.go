@@ -0,0 +1,46 @@
+package charts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/geo"
+	"radiocast/internal/propagation"
+)
+
+func TestGeneratePropagationSnippet(t *testing.T) {
+	cg := NewChartGenerator("")
+	qth, err := geo.ParseGrid("FN31pr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cg.GeneratePropagationSnippet(&propagation.Prediction{Location: qth}); err == nil {
+		t.Error("Expected an error without paths")
+	}
+
+	p := propagation.Predict(qth, propagation.Indices{SSN: 137, SFI: 180, Kp: 2}, time.Date(2025, 10, 18, 0, 0, 0, 0, time.UTC))
+	sn, err := cg.GeneratePropagationSnippet(p)
+	if err != nil {
+		t.Fatalf("GeneratePropagationSnippet() error = %v", err)
+	}
+	if sn.ID != "chart-propagation-fn31pr" || !strings.Contains(sn.HTML, sn.Div) || sn.Static != nil {
+		t.Errorf("Unexpected snippet %s: %s", sn.ID, sn.HTML)
+	}
+	if !strings.Contains(sn.Title, "FN31pr on 2025-10-18 · sunrise 11:04, sunset 22:06 UTC") {
+		t.Errorf("Title = %s", sn.Title)
+	}
+	for _, want := range []string{
+		`"data":["Europe","North America West",`,
+		`"text":"Europe (JO40ic)"`,
+		`"data":["80m","40m","30m","20m","17m","15m","12m","10m","6m"]`,
+		`[0,0,100]`, // 80m is open to Europe at midnight UTC
+	} {
+		if !strings.Contains(sn.Script, want) {
+			t.Errorf("Script missing %s", want)
+		}
+	}
+	if got := strings.Count(sn.Script, `"series":[{"data":`); got != len(p.Paths) {
+		t.Errorf("Expected %d timeline options, got %d", len(p.Paths), got)
+	}
+}
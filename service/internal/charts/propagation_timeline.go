@@ -65,12 +65,22 @@ func (cg *ChartGenerator) generatePropagationTimelineSnippet(data *models.Propag
 	script := fmt.Sprintf(`<script>(function(){var el=document.getElementById('%s');if(!el)return;var c=echarts.init(el);var option=%s;c.setOption(option);window.addEventListener('resize',function(){c.resize();});})();</script>`, id, string(optJSON))
 
 	// Create complete HTML snippet with div and script
-	completeHTML := fmt.Sprintf(`<script src="https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js"></script>
-<div class="chart-container">
+	completeHTML := fmt.Sprintf(`<div class="chart-container">
 	<h3>Propagation Quality Timeline (24 Hours)</h3>
 	%s
 </div>
 %s`, div, script)
 
-	return ChartSnippet{ID: id, Title: "Propagation Quality Timeline (24 Hours)", Div: div, Script: script, HTML: completeHTML}, nil
+	static := &StaticChart{
+		Kind:   StaticLineKind,
+		Title:  "Propagation Quality Timeline (24 Hours)",
+		Labels: staticTimeLabels(times),
+		Axes:   []StaticAxis{{Name: "K-index", Min: 0, Max: 9}, {Name: "Solar Flux (SFU)", Min: 50, Max: 300}},
+		Series: []StaticSeries{
+			{Name: "K-index", Values: kValues, Color: defaultPalette[0]},
+			{Name: "Solar Flux", Values: sfiValues, Color: defaultPalette[1], Axis: 1},
+		},
+	}
+
+	return ChartSnippet{ID: id, Title: "Propagation Quality Timeline (24 Hours)", Div: div, Script: script, HTML: completeHTML, Static: static}, nil
 }
@@ -0,0 +1,413 @@
+package charts
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"radiocast/internal/raster"
+)
+
+const (
+	staticBackground = "#ffffff"
+	staticText       = "#343a40"
+	staticMuted      = "#6c757d"
+	staticGrid       = "#e9ecef"
+	staticAxisLine   = "#ced4da"
+	staticOcean      = "#dbe9f6"
+	staticLand       = "#c9d6b8"
+	staticGrayLine   = "#495057"
+)
+
+// surface is the drawing target used by the static chart layout code
+type surface interface {
+	Rect(x, y, w, h float64, fill string)
+	Line(x0, y0, x1, y1, width float64, stroke string)
+	Polyline(points []raster.Point, width float64, stroke string)
+	Circle(cx, cy, r float64, fill string)
+	Polygon(points []raster.Point, fill string, opacity float64)
+	Arc(cx, cy, rInner, rOuter, startDeg, endDeg float64, fill string)
+	Text(x, y float64, s string, size int, fill string, align raster.Align)
+}
+
+// RenderPNG draws a static chart as a PNG image of the given size
+func RenderPNG(chart *StaticChart, width, height int) ([]byte, error) {
+	if chart == nil {
+		return nil, fmt.Errorf("chart cannot be nil")
+	}
+	canvas := raster.NewCanvas(width, height, raster.ParseHexColor(staticBackground))
+	if err := drawStatic(&rasterSurface{canvas: canvas}, chart, float64(width), float64(height)); err != nil {
+		return nil, err
+	}
+	return canvas.EncodePNG()
+}
+
+// drawStatic lays out a chart on any surface
+func drawStatic(s surface, chart *StaticChart, width, height float64) error {
+	s.Rect(0, 0, width, height, staticBackground)
+	top := 8.0
+	if chart.Title != "" {
+		s.Text(width/2, top, chart.Title, 2, staticText, raster.AlignCenter)
+		top += 24
+	}
+
+	switch chart.Kind {
+	case StaticGaugeKind:
+		drawGauges(s, chart.Gauges, 0, top, width, height-top)
+	case StaticLineKind, StaticBarKind:
+		drawCartesian(s, chart, top, width, height)
+	case StaticMapKind:
+		drawMap(s, chart.Map, top, width, height)
+	case StaticHeatmapKind:
+		drawHeatmap(s, chart.Heatmap, top, width, height)
+	default:
+		return fmt.Errorf("unsupported static chart kind %q", chart.Kind)
+	}
+	return nil
+}
+
+// gaugeAngle maps a 0-1 fraction onto the ECharts default 225°..-45° sweep
+func gaugeAngle(frac float64) float64 {
+	return 225 - 270*math.Max(0, math.Min(1, frac))
+}
+
+// drawGauges places gauges side by side in the given area
+func drawGauges(s surface, gauges []StaticGauge, x, y, width, height float64) {
+	if len(gauges) == 0 {
+		return
+	}
+	cellWidth := width / float64(len(gauges))
+	for i, g := range gauges {
+		cellX := x + float64(i)*cellWidth
+		cx := cellX + cellWidth/2
+		radius := math.Min(cellWidth*0.38, (height-56)/1.71)
+		cy := y + 18 + radius
+		thickness := math.Max(6, radius*0.16)
+
+		s.Text(cx, y, g.Label, 1, staticText, raster.AlignCenter)
+
+		prev := 0.0
+		for _, stop := range g.Stops {
+			s.Arc(cx, cy, radius-thickness, radius, gaugeAngle(stop.Offset), gaugeAngle(prev), stop.Color)
+			prev = stop.Offset
+		}
+
+		frac := 0.0
+		if g.Max > g.Min {
+			frac = (g.Value - g.Min) / (g.Max - g.Min)
+		}
+		angle := gaugeAngle(frac) * math.Pi / 180
+		needle := radius - thickness - 4
+		pointerColor := gaugeColor(g.Stops, frac)
+		s.Line(cx, cy, cx+needle*math.Cos(angle), cy-needle*math.Sin(angle), 3, pointerColor)
+		s.Circle(cx, cy, 5, pointerColor)
+
+		minAngle, maxAngle := gaugeAngle(0)*math.Pi/180, gaugeAngle(1)*math.Pi/180
+		s.Text(cx+radius*math.Cos(minAngle), cy-radius*math.Sin(minAngle)+6, formatAxisValue(g.Min), 1, staticMuted, raster.AlignCenter)
+		s.Text(cx+radius*math.Cos(maxAngle), cy-radius*math.Sin(maxAngle)+6, formatAxisValue(g.Max), 1, staticMuted, raster.AlignCenter)
+
+		s.Text(cx, cy+radius*0.71+20, g.Detail, 2, pointerColor, raster.AlignCenter)
+	}
+}
+
+// gaugeColor returns the stop color covering frac
+func gaugeColor(stops []ColorStop, frac float64) string {
+	for _, stop := range stops {
+		if frac <= stop.Offset {
+			return stop.Color
+		}
+	}
+	if len(stops) > 0 {
+		return stops[len(stops)-1].Color
+	}
+	return staticText
+}
+
+// drawCartesian draws line and bar charts with one or two value axes and a legend
+func drawCartesian(s surface, chart *StaticChart, top, width, height float64) {
+	if len(chart.Axes) == 0 {
+		return
+	}
+	left, right := 44.0, 16.0
+	if len(chart.Axes) > 1 {
+		right = 44
+	}
+	bottom := height - 48
+	plotWidth, plotHeight := width-left-right, bottom-top-8
+	if plotWidth <= 0 || plotHeight <= 0 {
+		return
+	}
+
+	yFor := func(axis int, v float64) float64 {
+		a := chart.Axes[0]
+		if axis > 0 && axis < len(chart.Axes) {
+			a = chart.Axes[axis]
+		}
+		if a.Max <= a.Min {
+			return bottom
+		}
+		frac := math.Max(0, math.Min(1, (v-a.Min)/(a.Max-a.Min)))
+		return bottom - frac*plotHeight
+	}
+
+	// Grid and axis labels
+	const divisions = 5
+	for i := 0; i <= divisions; i++ {
+		gy := bottom - float64(i)*plotHeight/divisions
+		s.Line(left, gy, left+plotWidth, gy, 1, staticGrid)
+		a := chart.Axes[0]
+		s.Text(left-4, gy-3, formatAxisValue(a.Min+float64(i)*(a.Max-a.Min)/divisions), 1, staticMuted, raster.AlignRight)
+		if len(chart.Axes) > 1 {
+			b := chart.Axes[1]
+			s.Text(left+plotWidth+4, gy-3, formatAxisValue(b.Min+float64(i)*(b.Max-b.Min)/divisions), 1, staticMuted, raster.AlignLeft)
+		}
+	}
+	s.Line(left, bottom, left+plotWidth, bottom, 1, staticAxisLine)
+	for _, guide := range chart.GuideLines {
+		gy := yFor(0, guide)
+		s.Line(left, gy, left+plotWidth, gy, 1, "#adb5bd")
+	}
+
+	n := len(chart.Labels)
+	for _, series := range chart.Series {
+		if len(series.Values) > n {
+			n = len(series.Values)
+		}
+	}
+	if n == 0 {
+		return
+	}
+	slot := plotWidth / float64(n)
+	xFor := func(i int) float64 { return left + slot*(float64(i)+0.5) }
+
+	// X labels, thinned so they do not overlap
+	step := 1
+	for step*int(slot) < 70 && step < n {
+		step++
+	}
+	for i := 0; i < len(chart.Labels); i += step {
+		for j, line := range strings.Split(chart.Labels[i], "\n") {
+			s.Text(xFor(i), bottom+6+float64(j)*10, line, 1, staticMuted, raster.AlignCenter)
+		}
+	}
+
+	stack := make([]float64, n) // tops of the stacked bars so far
+	for si, series := range chart.Series {
+		color := series.Color
+		if color == "" {
+			color = defaultPalette[si%len(defaultPalette)]
+		}
+		if chart.Kind == StaticBarKind && chart.Stacked {
+			for i, v := range series.Values {
+				base := yFor(series.Axis, stack[i])
+				stack[i] += v
+				y := yFor(series.Axis, stack[i])
+				s.Rect(xFor(i)-slot*0.3, y, slot*0.6, base-y, color)
+			}
+			continue
+		}
+		if chart.Kind == StaticBarKind {
+			// Several bar series are drawn side by side within each slot
+			barWidth := slot * 0.4
+			offset := 0.0
+			if k := len(chart.Series); k > 1 {
+				barWidth = slot * 0.8 / float64(k)
+				offset = barWidth * (float64(si) - float64(k-1)/2)
+			}
+			for i, v := range series.Values {
+				barColor := color
+				if i < len(series.Colors) {
+					barColor = series.Colors[i]
+				}
+				y := yFor(series.Axis, v)
+				s.Rect(xFor(i)+offset-barWidth/2, y, barWidth, bottom-y, barColor)
+			}
+			continue
+		}
+		// NaN values are gaps: the line is drawn in runs of known values
+		var points, run []raster.Point
+		for i, v := range series.Values {
+			if math.IsNaN(v) {
+				if len(run) > 1 {
+					s.Polyline(run, 2, color)
+				}
+				run = nil
+				continue
+			}
+			p := raster.Point{X: xFor(i), Y: yFor(series.Axis, v)}
+			run = append(run, p)
+			points = append(points, p)
+		}
+		if len(run) > 1 {
+			s.Polyline(run, 2, color)
+		}
+		if len(series.Values) <= 40 {
+			for _, p := range points {
+				s.Circle(p.X, p.Y, 3, color)
+			}
+		}
+	}
+
+	// Legend (bar charts with per-point colors have no meaningful legend)
+	for _, series := range chart.Series {
+		if chart.Kind == StaticBarKind && len(series.Colors) > 0 {
+			return
+		}
+	}
+	legendX := left
+	for si, series := range chart.Series {
+		color := series.Color
+		if color == "" {
+			color = defaultPalette[si%len(defaultPalette)]
+		}
+		s.Rect(legendX, height-14, 16, 6, color)
+		s.Text(legendX+20, height-15, series.Name, 1, staticText, raster.AlignLeft)
+		legendX += 32 + float64(raster.TextWidth(series.Name, 1))
+	}
+}
+
+// drawMap draws the world outlines, the night side and the markers on an equirectangular map
+func drawMap(s surface, m *StaticMap, top, width, height float64) {
+	scale := math.Min((width-32)/360, (height-top-8)/180)
+	left := (width - 360*scale) / 2
+	project := func(lon, lat float64) raster.Point {
+		return raster.Point{X: left + (lon+180)*scale, Y: top + (90-lat)*scale}
+	}
+	ring := func(points [][2]float64) []raster.Point {
+		out := make([]raster.Point, len(points))
+		for i, p := range points {
+			out[i] = project(p[0], p[1])
+		}
+		return out
+	}
+
+	s.Rect(left, top, 360*scale, 180*scale, staticOcean)
+	for _, outline := range worldOutlines {
+		s.Polygon(ring(outline), staticLand, 1)
+	}
+	for lon := -150.0; lon <= 150; lon += 30 {
+		a, b := project(lon, 90), project(lon, -90)
+		s.Line(a.X, a.Y, b.X, b.Y, 1, staticAxisLine)
+	}
+	for lat := -60.0; lat <= 60; lat += 30 {
+		a, b := project(-180, lat), project(180, lat)
+		s.Line(a.X, a.Y, b.X, b.Y, 1, staticAxisLine)
+	}
+	if m == nil {
+		return
+	}
+
+	s.Polygon(ring(m.Night), "#000000", 0.35)
+	s.Polyline(ring(m.Terminator), 2, staticGrayLine)
+	for _, marker := range m.Markers {
+		p := project(marker.Longitude, marker.Latitude)
+		s.Circle(p.X, p.Y, 5, "#ffffff")
+		s.Circle(p.X, p.Y, 4, marker.Color)
+		s.Text(p.X+7, p.Y-4, marker.Label, 1, staticText, raster.AlignLeft)
+	}
+}
+
+// drawHeatmap draws a grid of cells colored by level, with row labels on the left, thinned
+// column labels below and the levels as the legend
+func drawHeatmap(s surface, h *StaticHeatmap, top, width, height float64) {
+	if h == nil || len(h.Rows) == 0 || len(h.Columns) == 0 {
+		return
+	}
+	left := 16.0
+	for _, row := range h.Rows {
+		left = math.Max(left, float64(raster.TextWidth(row.Label, 1))+24)
+	}
+	gridWidth := width - left - 16
+	rowHeight := math.Min(20, (height-top-56)/float64(len(h.Rows)))
+	cellWidth := gridWidth / float64(len(h.Columns))
+	if gridWidth <= 0 || rowHeight <= 0 {
+		return
+	}
+	gap := 0.0
+	if cellWidth >= 4 {
+		gap = 1
+	}
+
+	for r, row := range h.Rows {
+		y := top + float64(r)*rowHeight
+		s.Text(left-8, y+rowHeight/2-3, row.Label, 1, staticText, raster.AlignRight)
+		for c := range h.Columns {
+			color := "#f0f0f0"
+			if c < len(row.Values) && !math.IsNaN(row.Values[c]) {
+				color = heatmapColor(h.Levels, row.Values[c])
+			}
+			s.Rect(left+float64(c)*cellWidth, y, cellWidth-gap, rowHeight-1, color)
+		}
+	}
+
+	// Column labels, thinned so they do not overlap
+	labelY := top + float64(len(h.Rows))*rowHeight + 6
+	step := 1
+	for float64(step)*cellWidth < 48 && step < len(h.Columns) {
+		step++
+	}
+	for c := 0; c < len(h.Columns); c += step {
+		s.Text(left+(float64(c)+0.5)*cellWidth, labelY, h.Columns[c], 1, staticMuted, raster.AlignCenter)
+	}
+
+	legendX := left
+	for _, level := range h.Levels {
+		s.Rect(legendX, height-16, 10, 10, level.Color)
+		s.Text(legendX+14, height-15, level.Label, 1, staticText, raster.AlignLeft)
+		legendX += 28 + float64(raster.TextWidth(level.Label, 1))
+	}
+}
+
+// heatmapColor returns the color of the highest level at or below v
+func heatmapColor(levels []StaticLevel, v float64) string {
+	color := staticGrid
+	for _, level := range levels {
+		if v >= level.Min {
+			color = level.Color
+		}
+	}
+	return color
+}
+
+// formatAxisValue prints integers without decimals
+func formatAxisValue(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// rasterSurface adapts raster.Canvas to the surface interface
+type rasterSurface struct {
+	canvas *raster.Canvas
+}
+
+func (r *rasterSurface) Rect(x, y, w, h float64, fill string) {
+	r.canvas.FillRect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+w)), int(math.Round(y+h)), raster.ParseHexColor(fill))
+}
+
+func (r *rasterSurface) Line(x0, y0, x1, y1, width float64, stroke string) {
+	r.canvas.Line(x0, y0, x1, y1, width, raster.ParseHexColor(stroke))
+}
+
+func (r *rasterSurface) Polyline(points []raster.Point, width float64, stroke string) {
+	r.canvas.Polyline(points, width, raster.ParseHexColor(stroke))
+}
+
+func (r *rasterSurface) Circle(cx, cy, radius float64, fill string) {
+	r.canvas.FillCircle(cx, cy, radius, raster.ParseHexColor(fill))
+}
+
+func (r *rasterSurface) Polygon(points []raster.Point, fill string, opacity float64) {
+	c := raster.ParseHexColor(fill)
+	r.canvas.FillPolygon(points, color.NRGBA{R: c.R, G: c.G, B: c.B, A: uint8(math.Round(255 * math.Max(0, math.Min(1, opacity))))})
+}
+
+func (r *rasterSurface) Arc(cx, cy, rInner, rOuter, startDeg, endDeg float64, fill string) {
+	r.canvas.FillArc(cx, cy, rInner, rOuter, startDeg, endDeg, raster.ParseHexColor(fill))
+}
+
+func (r *rasterSurface) Text(x, y float64, s string, size int, fill string, align raster.Align) {
+	r.canvas.Text(int(math.Round(x)), int(math.Round(y)), s, size, raster.ParseHexColor(fill), align)
+}
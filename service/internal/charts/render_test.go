@@ -0,0 +1,134 @@
+package charts
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+	"radiocast/internal/pdf"
+)
+
+func TestStaticChartsPopulated(t *testing.T) {
+	generator := NewChartGenerator("/test")
+	data := &models.PropagationData{
+		Timestamp:  time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC),
+		SolarData:  models.SolarData{SolarFluxIndex: 150, SunspotNumber: 120, XRayFlux: "C2.1", SolarWindSpeed: 450, Aurora: "3"},
+		GeomagData: models.GeomagData{KIndex: 3.5},
+	}
+
+	snippets, err := generator.GenerateEChartsSnippetsWithSources(data, nil)
+	if err != nil {
+		t.Fatalf("GenerateEChartsSnippetsWithSources failed: %v", err)
+	}
+	for _, sn := range snippets {
+		if sn.Static == nil {
+			t.Errorf("snippet %s has no static chart", sn.ID)
+			continue
+		}
+		img, err := RenderPNG(sn.Static, 640, 320)
+		if err != nil {
+			t.Fatalf("RenderPNG(%s) failed: %v", sn.ID, err)
+		}
+		if dir := os.Getenv("RENDER_DUMP"); dir != "" {
+			os.WriteFile(dir+"/"+sn.ID+".png", img, 0644)
+		}
+		decoded, err := png.Decode(bytes.NewReader(img))
+		if err != nil {
+			t.Fatalf("RenderPNG(%s) produced invalid PNG: %v", sn.ID, err)
+		}
+		if b := decoded.Bounds(); b.Dx() != 640 || b.Dy() != 320 {
+			t.Errorf("RenderPNG(%s) size = %dx%d, want 640x320", sn.ID, b.Dx(), b.Dy())
+		}
+	}
+
+	panel := snippets[0]
+	if panel.ID != "chart-gauge-panel" || len(panel.Static.Gauges) != 3 {
+		t.Errorf("expected gauge panel with 3 gauges, got %s with %d", panel.ID, len(panel.Static.Gauges))
+	}
+}
+
+func TestRenderPNGRejectsUnknownKind(t *testing.T) {
+	if _, err := RenderPNG(&StaticChart{Kind: "pie"}, 100, 100); err == nil {
+		t.Error("expected error for unsupported kind")
+	}
+	if _, err := RenderPNG(nil, 100, 100); err == nil {
+		t.Error("expected error for nil chart")
+	}
+}
+
+func TestRenderStaticFiles(t *testing.T) {
+	generator := NewChartGenerator("/test")
+	data := &models.PropagationData{
+		Timestamp:  time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC),
+		SolarData:  models.SolarData{SolarFluxIndex: 150, SunspotNumber: 120, XRayFlux: "M1.0"},
+		GeomagData: models.GeomagData{KIndex: 5},
+	}
+	snippets, err := generator.GenerateEChartsSnippetsWithSources(data, nil)
+	if err != nil {
+		t.Fatalf("GenerateEChartsSnippetsWithSources failed: %v", err)
+	}
+
+	files, err := RenderStaticFiles(snippets)
+	if err != nil {
+		t.Fatalf("RenderStaticFiles failed: %v", err)
+	}
+	for _, sn := range snippets {
+		svg, ok := files[StaticFileName(sn.ID, "svg")]
+		if !ok {
+			t.Fatalf("missing SVG for %s", sn.ID)
+		}
+		if err := xml.Unmarshal(svg, new(struct{ XMLName xml.Name })); err != nil {
+			t.Errorf("SVG for %s is not well-formed XML: %v", sn.ID, err)
+		}
+		if _, ok := files[StaticFileName(sn.ID, "png")]; !ok {
+			t.Errorf("missing PNG for %s", sn.ID)
+		}
+	}
+
+	panel := snippets[0]
+	withFallback := WithNoscriptFallback(panel, "/reports/x/chart-gauge-panel.svg")
+	if !strings.HasPrefix(withFallback, panel.HTML) || !strings.Contains(withFallback, `<noscript><img src="/reports/x/chart-gauge-panel.svg"`) {
+		t.Errorf("unexpected noscript fallback: %s", withFallback)
+	}
+	static := StaticHTML(panel, "chart-gauge-panel.svg")
+	if strings.Contains(static, "<script") || !strings.Contains(static, `src="chart-gauge-panel.svg"`) {
+		t.Errorf("static HTML should only contain the image: %s", static)
+	}
+}
+
+func TestDrawPDF(t *testing.T) {
+	generator := NewChartGenerator("/test")
+	data := &models.PropagationData{
+		Timestamp:  time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC),
+		SolarData:  models.SolarData{SolarFluxIndex: 150, SunspotNumber: 120, XRayFlux: "C2.1"},
+		GeomagData: models.GeomagData{KIndex: 3},
+	}
+	snippets, err := generator.GenerateEChartsSnippetsWithSources(data, nil)
+	if err != nil {
+		t.Fatalf("GenerateEChartsSnippetsWithSources failed: %v", err)
+	}
+
+	doc := pdf.New(pdf.Letter)
+	for _, sn := range snippets {
+		page := doc.AddPage()
+		h, err := DrawPDF(page, sn.Static, 54, 54, 504)
+		if err != nil {
+			t.Fatalf("DrawPDF(%s) failed: %v", sn.ID, err)
+		}
+		if want := PDFHeight(sn.Static, 504); math.Abs(h-want) > 0.01 {
+			t.Errorf("DrawPDF(%s) height = %.2f, PDFHeight = %.2f", sn.ID, h, want)
+		}
+	}
+	if _, err := doc.Bytes(); err != nil {
+		t.Fatalf("failed to write PDF: %v", err)
+	}
+	if _, err := DrawPDF(doc.AddPage(), nil, 0, 0, 100); err == nil {
+		t.Error("expected error for nil chart")
+	}
+}
@@ -1,5 +1,7 @@
 package charts
 
+import "radiocast/internal/models"
+
 // ChartSnippet represents an embeddable go-echarts chart fragment.
 // Div should contain a single root <div id="..." style="..."></div>
 // Script should contain the <script>...</script> block that initializes the chart in that div.
@@ -12,26 +14,16 @@ type ChartSnippet struct {
     HTML   string
     Width  string
     Height string
+    // Static describes the chart for server-side rendering (PNG/SVG); nil if unsupported
+    Static *StaticChart
 }
 
 
 // conditionToValue maps band condition text to a numeric bucket for heatmap coloring.
 // Returns: 0 Closed, 1 Poor, 2 Fair, 3 Good, 4 Excellent.
 func (cg *ChartGenerator) conditionToValue(cond string) int {
-    switch normalize(cond) {
-    case "closed":
-        return 0
-    case "poor":
-        return 1
-    case "fair":
-        return 2
-    case "good":
-        return 3
-    case "excellent":
-        return 4
-    default:
-        return 0
-    }
+    value, _ := models.ConditionValue(cond) // unknown conditions count as closed
+    return value
 }
 
 // normalize trims and lowercases a string.
@@ -1,9 +1,23 @@
 package charts
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 )
 
+// decodeOption decodes the ECharts option a snippet script passes to setOption into v
+func decodeOption(t *testing.T, script string, v interface{}) {
+	t.Helper()
+	start := strings.Index(script, "var option=")
+	if start == -1 {
+		t.Fatalf("Could not find option JSON in script: %s", script)
+	}
+	if err := json.NewDecoder(strings.NewReader(script[start+len("var option="):])).Decode(v); err != nil {
+		t.Fatalf("Failed to parse option JSON: %v", err)
+	}
+}
+
 func TestChartSnippet(t *testing.T) {
 	// Test ChartSnippet struct creation and field access
 	snippet := ChartSnippet{
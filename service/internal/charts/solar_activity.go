@@ -39,8 +39,7 @@ func (cg *ChartGenerator) generateSolarActivitySnippet(data *models.PropagationD
 	script := fmt.Sprintf(`<script>(function(){var el=document.getElementById('%s');if(!el)return;var c=echarts.init(el);var option=%s;c.setOption(option);window.addEventListener('resize',function(){c.resize();});})();</script>`, id, string(optJSON))
 
 	// Create complete HTML snippet with div and script
-	completeHTML := fmt.Sprintf(`<script src="https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js"></script>
-<div class="chart-container">
+	completeHTML := fmt.Sprintf(`<div class="chart-container">
 	<h3>Current Solar Activity</h3>
 	%s
 </div>
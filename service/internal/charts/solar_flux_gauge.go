@@ -107,12 +107,20 @@ func (cg *ChartGenerator) generateSolarFluxGaugeSnippet(data *models.Propagation
 	script := fmt.Sprintf(`<script>(function(){var el=document.getElementById('%s');if(!el)return;var c=echarts.init(el);var option=%s;c.setOption(option);window.addEventListener('resize',function(){c.resize();});})();</script>`, id, string(optJSON))
 
 	// Create complete HTML snippet with div and script
-	completeHTML := fmt.Sprintf(`<script src="https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js"></script>
-<div class="gauge-item">
+	completeHTML := fmt.Sprintf(`<div class="gauge-item">
 	<h4>Solar Flux (10.7cm)</h4>
 	%s
 </div>
 %s`, div, script)
 
-	return ChartSnippet{ID: id, Title: "Solar Flux Gauge", Div: div, Script: script, HTML: completeHTML}, nil
+	static := newStaticGauge("Solar Flux Gauge", StaticGauge{
+		Label:  "Solar Flux (10.7cm)",
+		Value:  solarFlux,
+		Min:    50,
+		Max:    300,
+		Stops:  []ColorStop{{0.2, "#dc3545"}, {0.4, "#fd7e14"}, {0.7, "#ffc107"}, {1.0, "#28a745"}},
+		Detail: fmt.Sprintf("%.0f %s", solarFlux, statusText),
+	})
+
+	return ChartSnippet{ID: id, Title: "Solar Flux Gauge", Div: div, Script: script, HTML: completeHTML, Static: static}, nil
 }
@@ -105,12 +105,20 @@ func (cg *ChartGenerator) generateSolarWindGaugeSnippet(data *models.Propagation
 	script := fmt.Sprintf(`<script>(function(){var el=document.getElementById('%s');if(!el)return;var c=echarts.init(el);var option=%s;c.setOption(option);window.addEventListener('resize',function(){c.resize();});})();</script>`, id, string(optJSON))
 
 	// Create complete HTML snippet with div and script
-	completeHTML := fmt.Sprintf(`<script src="https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js"></script>
-<div class="gauge-item">
+	completeHTML := fmt.Sprintf(`<div class="gauge-item">
 	<h4>Solar Wind Speed</h4>
 	%s
 </div>
 %s`, div, script)
 
-	return ChartSnippet{ID: id, Title: "Solar Wind", Div: div, Script: script, HTML: completeHTML}, nil
+	static := newStaticGauge("Solar Wind", StaticGauge{
+		Label:  "Solar Wind Speed",
+		Value:  solarWindSpeed,
+		Min:    200,
+		Max:    800,
+		Stops:  []ColorStop{{0.25, "#28a745"}, {0.5, "#ffc107"}, {0.75, "#fd7e14"}, {1.0, "#dc3545"}},
+		Detail: fmt.Sprintf("%.0f km/s %s", solarWindSpeed, statusText),
+	})
+
+	return ChartSnippet{ID: id, Title: "Solar Wind", Div: div, Script: script, HTML: completeHTML, Static: static}, nil
 }
@@ -50,8 +50,7 @@ func (cg *ChartGenerator) generateSpaceWeatherDashboardSnippet(data *models.Prop
 	}
 
 	// Create combined HTML with responsive layout using gauge panel style
-	completeHTML := fmt.Sprintf(`<script src="https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js"></script>
-<div class="gauge-panel">
+	completeHTML := fmt.Sprintf(`<div class="gauge-panel">
 	<h3>Space Weather Dashboard</h3>
 	<div class="gauge-container">
 		%s
@@ -83,6 +82,8 @@ func (cg *ChartGenerator) generateSpaceWeatherDashboardSnippet(data *models.Prop
 	// Combine all scripts
 	combinedScript := fmt.Sprintf("<script>\n%s\n</script>", strings.Join(allScripts, "\n"))
 
-	return ChartSnippet{ID: id, Title: "Space Weather Dashboard", Div: combinedDiv, Script: combinedScript, HTML: completeHTML}, nil
+	static := combineStaticGauges("Space Weather Dashboard", xrayGauge, solarWindGauge, auroraGauge)
+
+	return ChartSnippet{ID: id, Title: "Space Weather Dashboard", Div: combinedDiv, Script: combinedScript, HTML: completeHTML, Static: static}, nil
 }
 
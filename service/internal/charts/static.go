@@ -0,0 +1,197 @@
+package charts
+
+import (
+	"fmt"
+	"html"
+	"time"
+)
+
+// StaticKind identifies how a StaticChart is drawn
+type StaticKind string
+
+const (
+	StaticGaugeKind   StaticKind = "gauge"
+	StaticLineKind    StaticKind = "line"
+	StaticBarKind     StaticKind = "bar"
+	StaticMapKind     StaticKind = "map"
+	StaticHeatmapKind StaticKind = "heatmap"
+)
+
+// ColorStop colors the gauge band up to Offset (0-1 fraction of the range)
+type ColorStop struct {
+	Offset float64
+	Color  string
+}
+
+// StaticGauge describes one gauge dial
+type StaticGauge struct {
+	Label  string
+	Value  float64
+	Min    float64
+	Max    float64
+	Stops  []ColorStop
+	Detail string // Value/status text drawn below the dial
+}
+
+// StaticAxis describes a value axis; Axes[0] is drawn on the left, Axes[1] on the right
+type StaticAxis struct {
+	Name string
+	Min  float64
+	Max  float64
+}
+
+// StaticSeries is one line or bar series
+type StaticSeries struct {
+	Name   string
+	Values []float64 // NaN leaves a gap in a line
+	Color  string
+	Axis   int      // Index into StaticChart.Axes
+	Colors []string // Per-point colors for bar series (optional)
+}
+
+// StaticMarker is a labelled point on a map
+type StaticMarker struct {
+	Label     string
+	Latitude  float64
+	Longitude float64
+	Color     string
+}
+
+// StaticMap is a world map in an equirectangular projection, with the night side shaded
+type StaticMap struct {
+	Night      [][2]float64 // lon/lat outline of the night side
+	Terminator [][2]float64 // lon/lat day/night line, drawn as the gray line
+	Markers    []StaticMarker
+}
+
+// StaticLevel colors heatmap values from Min up to the next level
+type StaticLevel struct {
+	Min   float64
+	Label string
+	Color string
+}
+
+// StaticHeatmapRow is one labelled row of heatmap cells
+type StaticHeatmapRow struct {
+	Label  string
+	Values []float64 // NaN leaves the cell empty
+}
+
+// StaticHeatmap is a grid of colored cells, one column per label in Columns
+type StaticHeatmap struct {
+	Columns []string
+	Rows    []StaticHeatmapRow
+	Levels  []StaticLevel // Ascending by Min, shown as the legend
+}
+
+// StaticChart is a renderer-independent description of a chart, used to draw
+// PNG images where JavaScript is unavailable (email, PDF, static reports)
+type StaticChart struct {
+	Kind       StaticKind
+	Title      string
+	Gauges     []StaticGauge
+	Labels     []string
+	Axes       []StaticAxis
+	Series     []StaticSeries
+	GuideLines []float64 // Horizontal reference values on the first axis
+	Map        *StaticMap
+	Heatmap    *StaticHeatmap
+	Stacked    bool // Bar series are stacked instead of side by side
+}
+
+// defaultPalette matches the ECharts default series colors
+var defaultPalette = []string{"#5470c6", "#91cc75", "#fac858", "#ee6666", "#73c0de"}
+
+// newStaticGauge wraps a single gauge in a StaticChart
+func newStaticGauge(title string, gauge StaticGauge) *StaticChart {
+	return &StaticChart{Kind: StaticGaugeKind, Title: title, Gauges: []StaticGauge{gauge}}
+}
+
+// combineStaticGauges merges the gauges of several snippets into one panel chart
+func combineStaticGauges(title string, snippets ...ChartSnippet) *StaticChart {
+	chart := &StaticChart{Kind: StaticGaugeKind, Title: title}
+	for _, sn := range snippets {
+		if sn.Static != nil {
+			chart.Gauges = append(chart.Gauges, sn.Static.Gauges...)
+		}
+	}
+	return chart
+}
+
+// staticTimeLabels formats timestamps as short axis labels
+func staticTimeLabels(times []time.Time) []string {
+	labels := make([]string, len(times))
+	for i, t := range times {
+		labels[i] = t.UTC().Format("01/02 15:04")
+	}
+	return labels
+}
+
+// StaticImageSize returns the rendered image size for a chart
+func StaticImageSize(chart *StaticChart) (int, int) {
+	switch chart.Kind {
+	case StaticGaugeKind:
+		return 300 * max(1, len(chart.Gauges)), 280
+	case StaticBarKind:
+		return 900, 360
+	case StaticMapKind:
+		return 900, 480
+	case StaticHeatmapKind:
+		rows := 0
+		if chart.Heatmap != nil {
+			rows = len(chart.Heatmap.Rows)
+		}
+		return 900, 96 + 20*max(1, rows)
+	default:
+		return 900, 420
+	}
+}
+
+// StaticFileName returns the file name of a chart image stored next to index.html
+func StaticFileName(id, ext string) string {
+	return id + "." + ext
+}
+
+// RenderStaticFiles renders every snippet with a static model to SVG and PNG, keyed by file name
+func RenderStaticFiles(snippets []ChartSnippet) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for _, sn := range snippets {
+		if sn.Static == nil {
+			continue
+		}
+		width, height := StaticImageSize(sn.Static)
+		svg, err := RenderSVG(sn.Static, width, height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s as SVG: %w", sn.ID, err)
+		}
+		png, err := RenderPNG(sn.Static, width, height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s as PNG: %w", sn.ID, err)
+		}
+		files[StaticFileName(sn.ID, "svg")] = svg
+		files[StaticFileName(sn.ID, "png")] = png
+	}
+	return files, nil
+}
+
+// staticImageTag returns an <img> tag for a rendered chart
+func staticImageTag(sn ChartSnippet, src string) string {
+	return fmt.Sprintf(`<img src="%s" alt="%s" class="chart-static" style="max-width:100%%;height:auto;" />`,
+		html.EscapeString(src), html.EscapeString(sn.Title))
+}
+
+// WithNoscriptFallback appends a <noscript> image to the interactive snippet HTML
+func WithNoscriptFallback(sn ChartSnippet, src string) string {
+	if sn.Static == nil {
+		return sn.HTML
+	}
+	return sn.HTML + "\n<noscript>" + staticImageTag(sn, src) + "</noscript>"
+}
+
+// StaticHTML returns a script-free replacement for the snippet HTML (the image carries its own title)
+func StaticHTML(sn ChartSnippet, src string) string {
+	if sn.Static == nil {
+		return ""
+	}
+	return fmt.Sprintf("<div class=\"chart-container\">\n\t%s\n</div>", staticImageTag(sn, src))
+}
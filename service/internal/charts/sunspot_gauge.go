@@ -110,12 +110,20 @@ func (cg *ChartGenerator) generateSunspotGaugeSnippet(data *models.PropagationDa
 	script := fmt.Sprintf(`<script>(function(){var el=document.getElementById('%s');if(!el)return;var c=echarts.init(el);var option=%s;c.setOption(option);window.addEventListener('resize',function(){c.resize();});})();</script>`, id, string(optJSON))
 
 	// Create complete HTML snippet with div and script
-	completeHTML := fmt.Sprintf(`<script src="https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js"></script>
-<div class="gauge-item">
+	completeHTML := fmt.Sprintf(`<div class="gauge-item">
 	<h4>Sunspot Number</h4>
 	%s
 </div>
 %s`, div, script)
 
-	return ChartSnippet{ID: id, Title: "Sunspot Number Gauge", Div: div, Script: script, HTML: completeHTML}, nil
+	static := newStaticGauge("Sunspot Number Gauge", StaticGauge{
+		Label:  "Sunspot Number",
+		Value:  sunspotNumber,
+		Min:    0,
+		Max:    200,
+		Stops:  []ColorStop{{0.1, "#6c757d"}, {0.25, "#dc3545"}, {0.5, "#fd7e14"}, {0.75, "#ffc107"}, {1.0, "#28a745"}},
+		Detail: fmt.Sprintf("%.0f %s", sunspotNumber, statusText),
+	})
+
+	return ChartSnippet{ID: id, Title: "Sunspot Number Gauge", Div: div, Script: script, HTML: completeHTML, Static: static}, nil
 }
@@ -0,0 +1,108 @@
+package charts
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"radiocast/internal/raster"
+)
+
+// RenderSVG draws a static chart as a standalone SVG document of the given size
+func RenderSVG(chart *StaticChart, width, height int) ([]byte, error) {
+	if chart == nil {
+		return nil, fmt.Errorf("chart cannot be nil")
+	}
+	s := &svgSurface{}
+	fmt.Fprintf(&s.buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif">`+"\n",
+		width, height, width, height)
+	if chart.Title != "" {
+		fmt.Fprintf(&s.buf, "<title>%s</title>\n", html.EscapeString(chart.Title))
+	}
+	if err := drawStatic(s, chart, float64(width), float64(height)); err != nil {
+		return nil, err
+	}
+	s.buf.WriteString("</svg>\n")
+	return s.buf.Bytes(), nil
+}
+
+// svgSurface writes SVG elements for the surface interface
+type svgSurface struct {
+	buf bytes.Buffer
+}
+
+func (s *svgSurface) Rect(x, y, w, h float64, fill string) {
+	fmt.Fprintf(&s.buf, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n", num(x), num(y), num(w), num(h), fill)
+}
+
+func (s *svgSurface) Line(x0, y0, x1, y1, width float64, stroke string) {
+	fmt.Fprintf(&s.buf, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="%s" stroke-linecap="round"/>`+"\n",
+		num(x0), num(y0), num(x1), num(y1), stroke, num(width))
+}
+
+func (s *svgSurface) Polyline(points []raster.Point, width float64, stroke string) {
+	coords := make([]string, len(points))
+	for i, p := range points {
+		coords[i] = num(p.X) + "," + num(p.Y)
+	}
+	fmt.Fprintf(&s.buf, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%s" stroke-linejoin="round"/>`+"\n",
+		strings.Join(coords, " "), stroke, num(width))
+}
+
+func (s *svgSurface) Circle(cx, cy, r float64, fill string) {
+	fmt.Fprintf(&s.buf, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n", num(cx), num(cy), num(r), fill)
+}
+
+func (s *svgSurface) Polygon(points []raster.Point, fill string, opacity float64) {
+	coords := make([]string, len(points))
+	for i, p := range points {
+		coords[i] = num(p.X) + "," + num(p.Y)
+	}
+	fmt.Fprintf(&s.buf, `<polygon points="%s" fill="%s" fill-opacity="%g"/>`+"\n", strings.Join(coords, " "), fill, opacity)
+}
+
+// Arc draws an annulus sector as a closed path (angles counter-clockwise from +x, screen y down)
+func (s *svgSurface) Arc(cx, cy, rInner, rOuter, startDeg, endDeg float64, fill string) {
+	if endDeg < startDeg {
+		startDeg, endDeg = endDeg, startDeg
+	}
+	point := func(r, deg float64) (float64, float64) {
+		rad := deg * math.Pi / 180
+		return cx + r*math.Cos(rad), cy - r*math.Sin(rad)
+	}
+	large := 0
+	if endDeg-startDeg > 180 {
+		large = 1
+	}
+	ox0, oy0 := point(rOuter, startDeg)
+	ox1, oy1 := point(rOuter, endDeg)
+	ix1, iy1 := point(rInner, endDeg)
+	ix0, iy0 := point(rInner, startDeg)
+	fmt.Fprintf(&s.buf, `<path d="M%s %s A%s %s 0 %d 0 %s %s L%s %s A%s %s 0 %d 1 %s %s Z" fill="%s"/>`+"\n",
+		num(ox0), num(oy0), num(rOuter), num(rOuter), large, num(ox1), num(oy1),
+		num(ix1), num(iy1), num(rInner), num(rInner), large, num(ix0), num(iy0), fill)
+}
+
+// Text maps the bitmap font scale to a comparable font size; y is the top of the text
+func (s *svgSurface) Text(x, y float64, text string, size int, fill string, align raster.Align) {
+	if size < 1 {
+		size = 1
+	}
+	anchor := "start"
+	switch align {
+	case raster.AlignCenter:
+		anchor = "middle"
+	case raster.AlignRight:
+		anchor = "end"
+	}
+	baseline := y + float64(raster.TextHeight(size))
+	fmt.Fprintf(&s.buf, `<text x="%s" y="%s" font-size="%d" fill="%s" text-anchor="%s">%s</text>`+"\n",
+		num(x), num(baseline), 10*size, fill, anchor, html.EscapeString(text))
+}
+
+// num formats a coordinate compactly
+func num(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.1f", v), "0"), ".")
+}
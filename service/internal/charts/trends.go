@@ -0,0 +1,229 @@
+package charts
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"radiocast/internal/trends"
+)
+
+// gLevelColors colors G1 to G5 like the NOAA space weather scales
+var gLevelColors = []string{"#f6eb14", "#ffc800", "#ff9600", "#ff0000", "#c80000"}
+
+// GenerateTrendSnippets builds the charts of the /trends page; charts without data are left out
+func (cg *ChartGenerator) GenerateTrendSnippets(t *trends.Trends) []ChartSnippet {
+	var snippets []ChartSnippet
+	for _, generate := range []func(*trends.Trends) (ChartSnippet, error){
+		cg.generateSolarCycleSnippet,
+		cg.generateKpDistributionSnippet,
+		cg.generateStormDaysSnippet,
+		cg.generateBandTrendSnippet,
+	} {
+		if sn, err := generate(t); err == nil {
+			snippets = append(snippets, sn)
+		}
+	}
+	return snippets
+}
+
+// generateSolarCycleSnippet plots the monthly and 13-month smoothed SFI and SSN of the current
+// cycle, with NOAA's prediction
+func (cg *ChartGenerator) generateSolarCycleSnippet(t *trends.Trends) (ChartSnippet, error) {
+	if len(t.Cycle) == 0 {
+		return ChartSnippet{}, fmt.Errorf("no solar cycle data")
+	}
+
+	months := make([]string, len(t.Cycle))
+	var sfi, ssn, sfiSmoothed, ssnSmoothed, predictedSFI, predictedSSN []*float64
+	for i, m := range t.Cycle {
+		months[i] = m.Month.Format("2006-01")
+		sfi = append(sfi, m.SFI)
+		ssn = append(ssn, m.SSN)
+		sfiSmoothed = append(sfiSmoothed, m.SFISmoothed)
+		ssnSmoothed = append(ssnSmoothed, m.SSNSmoothed)
+		predictedSFI = append(predictedSFI, m.PredictedSFI)
+		predictedSSN = append(predictedSSN, m.PredictedSSN)
+	}
+
+	line := func(name string, axis int, color string, width int, dashed bool, data []*float64) map[string]interface{} {
+		style := map[string]interface{}{"width": width, "color": color}
+		if dashed {
+			style["type"] = "dashed"
+		}
+		return map[string]interface{}{
+			"name":       name,
+			"type":       "line",
+			"yAxisIndex": axis,
+			"showSymbol": false,
+			"lineStyle":  style,
+			"itemStyle":  map[string]interface{}{"color": color},
+			"data":       data,
+		}
+	}
+	series := []interface{}{
+		line("Solar Flux (monthly)", 0, "#ffb399", 1, false, sfi),
+		line("Solar Flux (smoothed)", 0, "#ff6b35", 3, false, sfiSmoothed),
+		line("Solar Flux (predicted)", 0, "#ff6b35", 2, true, predictedSFI),
+		line("Sunspot Number (monthly)", 1, "#a8e6e1", 1, false, ssn),
+		line("Sunspot Number (smoothed)", 1, "#4ecdc4", 3, false, ssnSmoothed),
+		line("Sunspot Number (predicted)", 1, "#4ecdc4", 2, true, predictedSSN),
+	}
+
+	option := map[string]interface{}{
+		"tooltip": map[string]interface{}{"trigger": "axis"},
+		"grid":    map[string]interface{}{"left": "6%", "right": "6%", "bottom": "18%", "containLabel": true},
+		"xAxis":   map[string]interface{}{"type": "category", "data": months},
+		"yAxis": []interface{}{
+			map[string]interface{}{"type": "value", "name": "Solar Flux", "position": "left"},
+			map[string]interface{}{"type": "value", "name": "Sunspot Number", "position": "right"},
+		},
+		"series":   series,
+		"legend":   map[string]interface{}{"bottom": 0},
+		"dataZoom": []interface{}{map[string]interface{}{"type": "inside"}},
+	}
+	return trendSnippet("chart-trend-solar-cycle", "Solar Cycle 25: Solar Flux and Sunspot Number", 460, option)
+}
+
+// generateKpDistributionSnippet compares the Kp distribution of every year
+func (cg *ChartGenerator) generateKpDistributionSnippet(t *trends.Trends) (ChartSnippet, error) {
+	if len(t.KpByYear) == 0 {
+		return ChartSnippet{}, fmt.Errorf("no K-index data")
+	}
+
+	categories := make([]string, 10)
+	for k := range categories {
+		categories[k] = "Kp " + strconv.Itoa(k)
+	}
+	var series []interface{}
+	for _, year := range t.KpByYear {
+		series = append(series, map[string]interface{}{
+			"name": strconv.Itoa(year.Year),
+			"type": "bar",
+			"data": year.Percent,
+		})
+	}
+
+	option := map[string]interface{}{
+		"tooltip": map[string]interface{}{"trigger": "axis"},
+		"grid":    map[string]interface{}{"left": "6%", "right": "4%", "bottom": "12%", "containLabel": true},
+		"xAxis":   map[string]interface{}{"type": "category", "data": categories},
+		"yAxis":   map[string]interface{}{"type": "value", "name": "% of 3-hour periods"},
+		"series":  series,
+		"legend":  map[string]interface{}{"bottom": 0},
+	}
+	return trendSnippet("chart-trend-kp-distribution", "K-index Distribution by Year", 380, option)
+}
+
+// generateStormDaysSnippet stacks the geomagnetic storm days of every month by G-level
+func (cg *ChartGenerator) generateStormDaysSnippet(t *trends.Trends) (ChartSnippet, error) {
+	if len(t.StormDays) == 0 {
+		return ChartSnippet{}, fmt.Errorf("no K-index data")
+	}
+
+	months := make([]string, len(t.StormDays))
+	levels := make([][]int, 5)
+	for i, m := range t.StormDays {
+		months[i] = m.Month.Format("2006-01")
+		for g := 1; g <= 5; g++ {
+			levels[g-1] = append(levels[g-1], m.GLevels[g])
+		}
+	}
+	var series []interface{}
+	for g, days := range levels {
+		series = append(series, map[string]interface{}{
+			"name":      fmt.Sprintf("G%d", g+1),
+			"type":      "bar",
+			"stack":     "storms",
+			"itemStyle": map[string]interface{}{"color": gLevelColors[g]},
+			"data":      days,
+		})
+	}
+
+	option := map[string]interface{}{
+		"tooltip": map[string]interface{}{"trigger": "axis"},
+		"grid":    map[string]interface{}{"left": "6%", "right": "4%", "bottom": "12%", "containLabel": true},
+		"xAxis":   map[string]interface{}{"type": "category", "data": months},
+		"yAxis":   map[string]interface{}{"type": "value", "name": "Days", "minInterval": 1},
+		"series":  series,
+		"legend":  map[string]interface{}{"bottom": 0},
+	}
+	return trendSnippet("chart-trend-storm-days", "Geomagnetic Storm Days per Month", 380, option)
+}
+
+// generateBandTrendSnippet plots the monthly average condition of every band
+func (cg *ChartGenerator) generateBandTrendSnippet(t *trends.Trends) (ChartSnippet, error) {
+	if len(t.Bands) == 0 {
+		return ChartSnippet{}, fmt.Errorf("no band condition data")
+	}
+
+	// The bands may cover different months; align them on one axis
+	seen := make(map[string]bool)
+	var months []string
+	for _, band := range t.Bands {
+		for _, m := range band.Months {
+			if label := m.Month.Format("2006-01"); !seen[label] {
+				seen[label] = true
+				months = append(months, label)
+			}
+		}
+	}
+	sort.Strings(months)
+	index := make(map[string]int, len(months))
+	for i, label := range months {
+		index[label] = i
+	}
+
+	var series []interface{}
+	for _, band := range t.Bands {
+		data := make([]*float64, len(months))
+		for _, m := range band.Months {
+			v := m.Value
+			data[index[m.Month.Format("2006-01")]] = &v
+		}
+		lineStyle := map[string]interface{}{"width": 2}
+		if band.Period == "night" {
+			lineStyle["type"] = "dashed"
+		}
+		series = append(series, map[string]interface{}{
+			"name":         band.Band + " " + band.Period,
+			"type":         "line",
+			"connectNulls": true,
+			"lineStyle":    lineStyle,
+			"data":         data,
+		})
+	}
+
+	option := map[string]interface{}{
+		"tooltip": map[string]interface{}{"trigger": "axis"},
+		"grid":    map[string]interface{}{"left": "6%", "right": "4%", "bottom": "15%", "containLabel": true},
+		"xAxis":   map[string]interface{}{"type": "category", "data": months},
+		"yAxis": map[string]interface{}{
+			"type": "value", "min": 0, "max": 4, "interval": 1,
+			"axisLabel": map[string]interface{}{"formatter": "{value}"},
+			"name":      "0 Closed - 4 Excellent",
+		},
+		"series": series,
+		"legend": map[string]interface{}{"bottom": 0},
+	}
+	return trendSnippet("chart-trend-band-conditions", "Monthly Average Band Conditions", 400, option)
+}
+
+// trendSnippet wraps an ECharts option in a chart container
+func trendSnippet(id, title string, height int, option map[string]interface{}) (ChartSnippet, error) {
+	optJSON, err := json.Marshal(option)
+	if err != nil {
+		return ChartSnippet{}, err
+	}
+
+	div := fmt.Sprintf("<div id=\"%s\" style=\"width:100%%;height:%dpx;\"></div>", id, height)
+	script := fmt.Sprintf(`<script>(function(){var el=document.getElementById('%s');if(!el)return;var c=echarts.init(el);var option=%s;c.setOption(option);window.addEventListener('resize',function(){c.resize();});})();</script>`, id, string(optJSON))
+	completeHTML := fmt.Sprintf(`<div class="chart-container">
+	<h3>%s</h3>
+	%s
+</div>
+%s`, title, div, script)
+
+	return ChartSnippet{ID: id, Title: title, Div: div, Script: script, HTML: completeHTML}, nil
+}
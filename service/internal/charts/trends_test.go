@@ -0,0 +1,41 @@
+package charts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/trends"
+)
+
+func TestGenerateTrendSnippets(t *testing.T) {
+	cg := NewChartGenerator("")
+	if snippets := cg.GenerateTrendSnippets(&trends.Trends{}); len(snippets) != 0 {
+		t.Errorf("Expected no charts without data, got %d", len(snippets))
+	}
+
+	month := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	ssn := 150.0
+	data := &trends.Trends{
+		Cycle:     []trends.CycleMonth{{Month: month, SSN: &ssn}},
+		KpByYear:  []trends.KpDistribution{{Year: 2025, Samples: 8}},
+		StormDays: []trends.StormMonth{{Month: month, Days: 31, GLevels: [6]int{28, 2, 1}}},
+		Bands: []trends.BandTrend{
+			{Band: "12m-10m", Period: "day", Months: []trends.MonthValue{{Month: month.AddDate(0, 1, 0), Value: 3}}},
+			{Band: "12m-10m", Period: "night", Months: []trends.MonthValue{{Month: month, Value: 1}}},
+		},
+	}
+	snippets := cg.GenerateTrendSnippets(data)
+	if len(snippets) != 4 {
+		t.Fatalf("Expected 4 charts, got %d", len(snippets))
+	}
+	for _, sn := range snippets {
+		if !strings.Contains(sn.HTML, sn.Div) || !strings.Contains(sn.Script, "echarts.init") {
+			t.Errorf("Chart %s is not a complete snippet", sn.ID)
+		}
+	}
+	// Band months are aligned on one axis: the night series has no April value
+	if !strings.Contains(snippets[3].Script, `"data":["2025-03","2025-04"]`) || !strings.Contains(snippets[3].Script, `"data":[1,null]`) {
+		t.Errorf("Band chart not aligned by month: %s", snippets[3].Script)
+	}
+}
@@ -0,0 +1,111 @@
+package charts
+
+// worldOutlines are coarse coastlines as closed lon/lat rings, accurate to a few degrees: enough
+// to place the terminator and the QTHs on a world map without a map dataset
+var worldOutlines = [][][2]float64{
+	// North America
+	{{-168, 66}, {-162, 70}, {-156, 71.3}, {-141, 69.6}, {-128, 70}, {-115, 68.5}, {-95, 68}, {-82, 69.5},
+		{-80, 63}, {-94, 59}, {-92, 57}, {-82, 55}, {-79, 51.5}, {-77, 56}, {-77, 60}, {-70, 61}, {-65, 60},
+		{-61, 56}, {-56, 52}, {-59, 48}, {-65, 45}, {-70, 43.5}, {-70, 41.7}, {-74, 40.5}, {-76, 37},
+		{-75.5, 35.2}, {-81, 31.5}, {-80, 27}, {-80.2, 25.2}, {-81.7, 25.9}, {-83, 29}, {-85, 29.7},
+		{-89, 30.2}, {-94, 29.6}, {-97.3, 27.5}, {-97.5, 22}, {-96, 19}, {-91, 18.5}, {-90.5, 21}, {-87, 21.5},
+		{-88, 16}, {-83.5, 15}, {-83.5, 11}, {-79.5, 9}, {-77.5, 8.5}, {-80, 7.5}, {-85.7, 10}, {-87.5, 13},
+		{-92, 14.6}, {-96, 15.7}, {-105, 19.5}, {-105.7, 22.5}, {-109, 25.5}, {-114.7, 31.7}, {-112, 28},
+		{-109.9, 22.9}, {-112, 25}, {-114, 28}, {-117, 32.5}, {-120.5, 34.5}, {-122.5, 37.8}, {-124.2, 40.4},
+		{-124, 46.2}, {-124.7, 48.4}, {-127.5, 50.5}, {-130.5, 54.5}, {-134, 58}, {-139.5, 59.6}, {-146, 60.5},
+		{-152, 59}, {-158, 56.5}, {-164.5, 54.5}, {-158, 58.5}, {-162, 60}, {-165.5, 62}, {-164.5, 63.5}},
+	// Baffin Island and the Canadian Arctic
+	{{-88, 73.5}, {-78, 72.5}, {-70, 70.5}, {-62, 66.8}, {-65, 63}, {-72, 64}, {-76, 66}, {-78, 69.5}, {-88, 70.5}},
+	{{-118, 69}, {-102, 68.5}, {-101, 71}, {-110, 73}, {-118, 72}},
+	{{-90, 76.5}, {-75, 78.5}, {-62, 82}, {-75, 83}, {-90, 81.5}},
+	// Greenland
+	{{-73, 78}, {-66, 81}, {-45, 82.5}, {-20, 82}, {-18, 77}, {-22, 72}, {-27, 68.5}, {-40, 65}, {-43, 60},
+		{-49, 61.5}, {-53, 66}, {-54, 70.5}, {-58, 75.5}},
+	// Iceland
+	{{-24, 65.5}, {-22, 66.4}, {-16, 66.5}, {-13.6, 65}, {-15, 64.2}, {-18.7, 63.4}, {-22.7, 63.8}},
+	// Cuba
+	{{-85, 21.9}, {-80.5, 23.1}, {-77, 21.5}, {-74.2, 20.2}, {-77.6, 19.9}, {-81, 21.7}},
+	// South America
+	{{-77.5, 8.5}, {-72, 12}, {-64, 10.7}, {-60, 8.5}, {-52, 5}, {-50, 0}, {-44, -2.5}, {-35, -5.5}, {-35, -9},
+		{-39, -14}, {-39.5, -18}, {-42, -22.8}, {-48.5, -26}, {-51, -30.5}, {-53, -33.8}, {-58, -34.5}, {-57, -38},
+		{-62, -39}, {-65, -42}, {-67.5, -46.5}, {-69, -51}, {-68.5, -54.8}, {-72, -53.5}, {-75.5, -50}, {-74, -43},
+		{-73.5, -37}, {-71.5, -30}, {-70.3, -18.5}, {-75.5, -15}, {-79, -8}, {-81, -5}, {-80, -1}, {-79, 1.5},
+		{-77.5, 4}},
+	// Great Britain and Ireland
+	{{-5.7, 50}, {1.4, 51.2}, {1.7, 52.7}, {0.2, 53.5}, {-1.6, 55.6}, {-2, 57.7}, {-3.1, 58.6}, {-5, 58.6},
+		{-6.2, 56.8}, {-4.8, 55.5}, {-3, 54.9}, {-3.3, 53.4}, {-4.6, 53.3}, {-4.2, 52.2}, {-5.2, 51.7}, {-3.2, 51.4}},
+	{{-6, 52.2}, {-6.2, 53.9}, {-5.5, 55.3}, {-8, 55.3}, {-10, 54}, {-10.3, 51.9}, {-8.3, 51.6}},
+	// Eurasia
+	{{-9.5, 37}, {-9, 43}, {-1.5, 43.5}, {-1.2, 46}, {-4.5, 48.5}, {-1, 49.5}, {2, 51}, {4.5, 52.5}, {8.5, 54},
+		{8.5, 57}, {10.5, 57.7}, {10.5, 54.5}, {14, 54}, {19, 54.5}, {21, 57}, {24, 57.5}, {23.5, 59.2}, {30, 60},
+		{23, 60}, {21.5, 61.5}, {21.5, 63.5}, {25, 65.5}, {21.5, 65.5}, {17.5, 62}, {18.8, 60}, {16.5, 57},
+		{14.5, 56}, {12.5, 56}, {11, 59}, {8, 58}, {5.5, 58.8}, {5, 62}, {10, 64}, {14, 67}, {16, 68.5}, {20, 70},
+		{25.5, 71}, {31, 70}, {33, 69}, {41, 67}, {44, 68.5}, {54, 68.5}, {59, 69.5}, {67, 69}, {69, 73},
+		{73, 72.5}, {80, 73.5}, {87, 75}, {100, 76}, {105, 77.7}, {113, 73.5}, {127, 73.5}, {140, 72.5},
+		{150, 71.5}, {160, 70}, {170, 70}, {180, 66}, {178, 62.5}, {170, 60}, {163, 59.5}, {163, 56},
+		{156.5, 51}, {155.5, 57.5}, {160, 61.5}, {151, 59}, {143, 59.3}, {137, 54}, {141, 52}, {140, 48},
+		{135, 43.5}, {129.5, 42.5}, {128, 39}, {129.4, 36}, {126.5, 34.5}, {126.3, 37.5}, {124.7, 40},
+		{121.5, 40.8}, {122, 39}, {117.7, 38.9}, {119, 37.2}, {122.5, 37}, {120, 34.5}, {121.8, 31}, {122, 29},
+		{119.5, 25.5}, {114, 22.2}, {109.5, 21.5}, {108, 21.5}, {106, 19}, {109, 15}, {109, 11.5}, {105, 8.6},
+		{104.8, 10.5}, {100.8, 13.5}, {99.2, 10}, {100.3, 6}, {103.4, 3.5}, {104.2, 1.4}, {101, 2.5}, {98.3, 8},
+		{98.5, 13}, {97.5, 16.5}, {94.5, 16}, {94, 19}, {91.8, 22.4}, {88, 21.7}, {86.8, 20.8}, {84.5, 19},
+		{80.3, 15.5}, {80.2, 13}, {79.8, 10.3}, {77.5, 8.1}, {76.3, 10}, {74.8, 13}, {73, 17}, {72.8, 21},
+		{70, 21}, {68.5, 23.5}, {67, 24.8}, {62, 25.2}, {57.5, 25.7}, {56.4, 27}, {54, 26.7}, {51.5, 27.9},
+		{48.8, 30}, {47.7, 28.5}, {50, 26.5}, {51.5, 25.2}, {51.6, 24}, {54, 24.2}, {56.3, 26.2}, {56.4, 24.2},
+		{59.8, 22.4}, {58.5, 20.4}, {55, 17}, {52, 15.6}, {48, 14}, {45, 12.8}, {43.3, 12.7}, {42.7, 15.5},
+		{41, 18.5}, {39, 21.5}, {37.2, 25}, {35, 28}, {35, 29.5}, {34.3, 27.8}, {32.6, 29.9}, {32.3, 31.3},
+		{34.3, 31.3}, {35, 33}, {36, 35.8}, {32.5, 36.1}, {30.5, 36.3}, {27.3, 37}, {26.2, 39.4}, {26.2, 40.3},
+		{23, 40.3}, {22.5, 37}, {21, 38.3}, {19.4, 40.4}, {19.4, 42}, {16, 43.5}, {13.6, 45.6}, {12.3, 45},
+		{13.7, 43.5}, {16, 41.5}, {18.5, 40.2}, {16, 38}, {15.7, 40}, {12.5, 41.8}, {10.5, 43}, {8.7, 44.4},
+		{6.5, 43.1}, {3.2, 43.2}, {3.2, 41.9}, {0.9, 41}, {-0.3, 39.4}, {0, 38.8}, {-0.7, 37.6}, {-2.1, 36.7},
+		{-5.6, 36}, {-6.5, 36.9}},
+	// Sri Lanka
+	{{79.8, 9.8}, {81.9, 7.5}, {81.4, 6.2}, {80.1, 6}, {79.8, 8}},
+	// Japan
+	{{130, 31.3}, {130.9, 34}, {133, 35.5}, {136, 36}, {137, 37}, {139.9, 40}, {140, 41.4}, {141.5, 41.3},
+		{141, 38.3}, {140.9, 36.9}, {140.9, 35.7}, {139.8, 35}, {138.6, 34.6}, {136.8, 34.3}, {135.2, 33.8},
+		{132.5, 33.3}, {131.6, 31.4}},
+	{{140, 41.5}, {141.7, 42.6}, {143.3, 42}, {145.5, 43.3}, {144.8, 44}, {141.8, 45.5}, {141.5, 43.3}, {140, 42.7}},
+	// Philippines
+	{{120, 18.5}, {122.2, 18.5}, {122, 16}, {124, 13}, {121.5, 13.8}, {120.6, 14.5}, {120, 16}},
+	{{122, 7}, {125.4, 9.8}, {126.6, 7.3}, {125.5, 5.8}, {123.5, 7.7}},
+	// Indonesia and New Guinea
+	{{95.3, 5.6}, {97.5, 5.2}, {100.3, 2.4}, {104, -1}, {106, -3.5}, {105.8, -5.8}, {104.5, -5.9}, {101.5, -3},
+		{98.6, 1.7}},
+	{{105.2, -6.8}, {108, -6.2}, {112.6, -6.9}, {114.5, -7.8}, {111, -8.3}, {106.3, -7.4}},
+	{{109, 1.5}, {111, 1.8}, {113.5, 3.5}, {116, 6.9}, {119, 5}, {118, 1}, {117.3, -0.5}, {116.5, -3},
+		{114.5, -3.9}, {111, -3}, {110, -1.5}},
+	{{131, -1.4}, {134.2, -0.9}, {138, -1.7}, {141, -2.6}, {145, -4.3}, {147.5, -6}, {147.9, -8}, {150.3, -10.5},
+		{146.3, -8.5}, {143.5, -9}, {142.5, -9.3}, {141, -9.1}, {138.5, -8.3}, {137.8, -5.3}, {134.5, -4},
+		{132.3, -3.6}},
+	// Africa
+	{{-5.9, 35.8}, {-10, 29}, {-13, 27.6}, {-17, 21}, {-17.5, 14.7}, {-16.7, 12.4}, {-13.3, 9}, {-11, 6.8},
+		{-7.5, 4.4}, {-2, 4.8}, {2, 6.3}, {4.5, 6.3}, {6, 4.3}, {8.5, 4.5}, {9.5, 3}, {9.3, -0.5}, {12, -5},
+		{13.3, -8.5}, {13.8, -11}, {11.8, -17}, {14.5, -22.8}, {16.5, -28.6}, {18.4, -33.9}, {20, -34.8},
+		{25.6, -33.9}, {30, -31.2}, {32.5, -28.5}, {32.7, -26}, {35.5, -24}, {35.3, -22}, {34.7, -19.8},
+		{36.8, -17.7}, {40.5, -15}, {40.5, -10.5}, {39.3, -7}, {39.2, -4.6}, {41.6, -1.6}, {43.5, 0.5}, {46, 2.5},
+		{49, 6}, {51.2, 10.4}, {51.3, 11.8}, {48.5, 11.2}, {44.5, 10.4}, {43.2, 11.5}, {42.5, 12.5}, {39.5, 15.5},
+		{38.5, 18}, {37.2, 21}, {35.5, 23.5}, {33.5, 27.5}, {32.5, 30}, {32.3, 31.3}, {29, 30.9}, {25, 31.6},
+		{20, 30.9}, {19.8, 32.2}, {15.2, 32.3}, {11.5, 33.2}, {10.2, 35.5}, {11.1, 37}, {9.7, 37.3}, {3, 36.8},
+		{-2, 35.1}},
+	// Madagascar
+	{{49.3, -12}, {50.5, -15.5}, {49.5, -17.5}, {47, -25}, {45, -25.5}, {43.6, -23.5}, {43.5, -21}, {44.4, -16.2},
+		{47, -15}},
+	// Australia
+	{{113.5, -22}, {114, -26.5}, {115, -30}, {115, -34.2}, {118, -35}, {123.5, -33.9}, {129, -31.6}, {134, -32.6},
+		{136, -34.9}, {137.8, -32.9}, {138.5, -35.6}, {140.5, -38}, {144, -38.3}, {146.3, -39}, {150, -37.5},
+		{151.2, -33.9}, {153.1, -30.5}, {153.5, -28}, {152.9, -25.3}, {150.8, -22.6}, {148.7, -20.3},
+		{145.5, -14.9}, {143.5, -12.6}, {142.5, -10.7}, {141.6, -12.9}, {141.6, -16.8}, {140.5, -17.6},
+		{139.2, -17.3}, {136.6, -15.9}, {135.7, -14.8}, {136.9, -12.3}, {132.6, -11.5}, {130, -12.8},
+		{129.5, -14.9}, {127, -13.8}, {125.1, -14.6}, {122.2, -17.3}, {121, -19.5}, {117.5, -20.7}},
+	// New Zealand
+	{{172.7, -34.5}, {174.6, -36.8}, {178.5, -37.7}, {177, -39.6}, {175, -41.5}, {174.6, -39.8}, {173.8, -39.1},
+		{174.6, -37.8}},
+	{{172.7, -40.5}, {174.3, -41.7}, {172.8, -43.8}, {171, -45.4}, {169, -46.7}, {166.5, -46}, {168, -44},
+		{171.3, -42}},
+	// Antarctica, closed along the bottom edge of the map
+	{{-180, -78}, {-160, -78}, {-150, -76}, {-135, -74.5}, {-120, -73.5}, {-100, -73}, {-75, -72.5}, {-63, -65},
+		{-57, -63.4}, {-60, -66}, {-62, -70}, {-60, -75}, {-45, -78}, {-35, -77.5}, {-25, -75}, {-10, -71.5},
+		{0, -70}, {20, -70}, {40, -69}, {55, -66.5}, {70, -68}, {78, -69}, {90, -66.5}, {110, -66}, {130, -66.2},
+		{145, -67}, {160, -70}, {167, -73}, {165, -78}, {180, -78}, {180, -90}, {-180, -90}},
+}
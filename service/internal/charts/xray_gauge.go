@@ -109,13 +109,21 @@ func (cg *ChartGenerator) generateXRayGaugeSnippet(data *models.PropagationData)
 	script := fmt.Sprintf(`<script>(function(){var el=document.getElementById('%s');if(!el)return;var c=echarts.init(el);var option=%s;c.setOption(option);window.addEventListener('resize',function(){c.resize();});})();</script>`, id, string(optJSON))
 
 	// Create complete HTML snippet with div and script
-	completeHTML := fmt.Sprintf(`<script src="https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js"></script>
-<div class="gauge-item">
+	completeHTML := fmt.Sprintf(`<div class="gauge-item">
 	<h4>X-ray Activity</h4>
 	%s
 </div>
 %s`, div, script)
 
-	return ChartSnippet{ID: id, Title: "X-ray Activity", Div: div, Script: script, HTML: completeHTML}, nil
+	static := newStaticGauge("X-ray Activity", StaticGauge{
+		Label:  "X-ray Activity",
+		Value:  xrayValue,
+		Min:    0,
+		Max:    10,
+		Stops:  []ColorStop{{0.2, "#28a745"}, {0.4, "#ffc107"}, {0.6, "#fd7e14"}, {0.8, "#dc3545"}, {1.0, "#6f42c1"}},
+		Detail: fmt.Sprintf("%s %s", xrayFlux, statusText),
+	})
+
+	return ChartSnippet{ID: id, Title: "X-ray Activity", Div: div, Script: script, HTML: completeHTML, Static: static}, nil
 }
 
@@ -20,15 +20,66 @@ type Config struct {
 	GCPProjectID string `env:"GCP_PROJECT_ID"`
 	GCSBucket    string `env:"GCS_BUCKET"`
 	
+	// S3-compatible storage for -deployment=s3 (AWS, MinIO, Backblaze B2, Wasabi...)
+	S3Endpoint         string `env:"S3_ENDPOINT"` // empty for AWS
+	S3Region           string `env:"S3_REGION,default=us-east-1"`
+	S3Bucket           string `env:"S3_BUCKET"`
+	S3PathStyle        bool   `env:"S3_PATH_STYLE,default=false"` // bucket in the URL path, as MinIO expects
+	AWSAccessKeyID     string `env:"AWS_ACCESS_KEY_ID"`
+	AWSSecretAccessKey string `env:"AWS_SECRET_ACCESS_KEY"`
+	AWSSessionToken    string `env:"AWS_SESSION_TOKEN"`
+	
 	// Local testing configuration
 	LocalReportsDir string `env:"LOCAL_REPORTS_DIR,default=./reports"`
 	MockupMode      bool   `env:"MOCKUP_MODE,default=false"`
 	
+	// Report rendering: embed pre-rendered chart images instead of ECharts scripts
+	StaticReports bool `env:"STATIC_REPORTS,default=false"`
+	PDFReports    bool `env:"PDF_REPORTS,default=false"` // Also write a printable report.pdf
+	
+	// Helioviewer imagery products animated in each report (aia171, aia193, aia304, hmi, lasco_c2, lasco_c3)
+	SunImagery         []string `env:"SUN_IMAGERY,default=aia171"`
+	HelioviewerWorkers int      `env:"HELIOVIEWER_WORKERS,default=6"` // concurrent frame requests
+	SunImageryFormats  []string `env:"SUN_IMAGERY_FORMATS"`           // extra animation formats (webp); GIF is always produced
+	
+	// History windows handed to reports from the time-series store (every fetch is recorded in full)
+	HistoryKIndexHours int `env:"HISTORY_K_INDEX_HOURS,default=72"`
+	HistorySolarMonths int `env:"HISTORY_SOLAR_MONTHS,default=6"`
+	HistoryBandDays    int `env:"HISTORY_BAND_DAYS,default=30"`
+	
+	// Club QTHs (Maidenhead grids) predicted on /propagation and /api/v1/grayline when no ?grid= is
+	// given, and whose gray-line windows are added to reports
+	QTHGrids []string `env:"QTH_GRIDS"`
+	
+	// Ionosondes (URSI codes, e.g. BC840,JR055) whose measured foF2, hmF2 and MUF(3000) are added to
+	// reports. IONOSONDE_SOURCE is giro (DIDBase, the last 24 hours of each station) or kc2g (a
+	// prop.kc2g.com-style stations.json with the latest reading of each station).
+	IonosondeStations []string `env:"IONOSONDE_STATIONS"`
+	IonosondeSource   string   `env:"IONOSONDE_SOURCE,default=giro"`
+	IonosondeURL      string   `env:"IONOSONDE_URL"` // defaults to the source's public endpoint
+	
+	// Observed band activity: spots of the last SPOTS_HOURS from SPOTS_SOURCES (wspr, pskreporter),
+	// aggregated by band and continent pair. No spots are fetched when SPOTS_SOURCES is empty.
+	SpotsSources   []string `env:"SPOTS_SOURCES"`
+	SpotsHours     int      `env:"SPOTS_HOURS,default=6"`
+	WSPRURL        string   `env:"WSPR_URL,default=https://db1.wspr.live/"`
+	PSKReporterURL string   `env:"PSKREPORTER_URL,default=https://retrieve.pskreporter.info/query"`
+	
+	// DX cluster (host:port, DXSpider or AR-Cluster telnet) logged in to as DXCLUSTER_CALLSIGN for
+	// live spot statistics over the last DXCLUSTER_WINDOW_MINUTES. DXCLUSTER_WATCH lists calls, e.g.
+	// DXpeditions, that are always reported when spotted. No cluster is used when the address is empty.
+	// The statistics live in memory: the instance has to stay up with CPU between requests.
+	DXClusterAddr          string   `env:"DXCLUSTER_ADDR"`
+	DXClusterCallsign      string   `env:"DXCLUSTER_CALLSIGN"`
+	DXClusterWindowMinutes int      `env:"DXCLUSTER_WINDOW_MINUTES,default=60"`
+	DXClusterWatch         []string `env:"DXCLUSTER_WATCH"`
+	
 	// Data source URLs
-	NOAAKIndexURL string `env:"NOAA_K_INDEX_URL,default=https://services.swpc.noaa.gov/products/noaa-planetary-k-index.json"`
-	NOAASolarURL  string `env:"NOAA_SOLAR_URL,default=https://services.swpc.noaa.gov/json/solar-cycle/observed-solar-cycle-indices.json"`
-	N0NBHSolarURL string `env:"N0NBH_SOLAR_URL,default=https://www.hamqsl.com/solarapi.php?format=json"`
-	SIDCRSSURL    string `env:"SIDC_RSS_URL,default=https://www.sidc.be/products/meu"`
+	NOAAKIndexURL         string `env:"NOAA_K_INDEX_URL,default=https://services.swpc.noaa.gov/products/noaa-planetary-k-index.json"`
+	NOAASolarURL          string `env:"NOAA_SOLAR_URL,default=https://services.swpc.noaa.gov/json/solar-cycle/observed-solar-cycle-indices.json"`
+	N0NBHSolarURL         string `env:"N0NBH_SOLAR_URL,default=https://www.hamqsl.com/solarapi.php?format=json"`
+	SIDCRSSURL            string `env:"SIDC_RSS_URL,default=https://www.sidc.be/products/meu"`
+	NOAAPredictedCycleURL string `env:"NOAA_PREDICTED_CYCLE_URL,default=https://services.swpc.noaa.gov/json/solar-cycle/predicted-solar-cycle.json"`
 	
 	// Service configuration
 	Environment string `env:"ENVIRONMENT,default=development"`
@@ -37,6 +88,33 @@ type Config struct {
 	
 	// API Security
 	RadiocastAPIKey string `env:"RADIOCAST_API_KEY"`
+
+	// Public URL of the service used to build absolute report links (e.g. https://radio-propagation.net)
+	PublicBaseURL string `env:"PUBLIC_BASE_URL"`
+
+	// Webhook notifications (entries are "kind:url", kind is one of json, discord, slack, matrix, ntfy)
+	NotifyWebhooks      []string `env:"NOTIFY_WEBHOOKS"`
+	NotifyWebhookSecret string   `env:"NOTIFY_WEBHOOK_SECRET"`
+	NotifyMaxRetries    int      `env:"NOTIFY_MAX_RETRIES,default=3"`
+	NotifyKpThreshold   float64  `env:"NOTIFY_KP_THRESHOLD,default=5"`
+	NotifyFlareClass    string   `env:"NOTIFY_FLARE_CLASS,default=X"`
+	NotifyGoodBands     []string `env:"NOTIFY_GOOD_BANDS,default=10m"`
+
+	// Report retention: all artifacts for RETENTION_FULL_DAYS, then one report per day until
+	// RETENTION_DAILY_DAYS, then only normalized_data.json and llm_response.md of that report
+	RetentionEnabled   bool `env:"RETENTION_ENABLED,default=false"` // apply after every report
+	RetentionFullDays  int  `env:"RETENTION_FULL_DAYS,default=30"`
+	RetentionDailyDays int  `env:"RETENTION_DAILY_DAYS,default=365"`
+	RetentionDryRun    bool `env:"RETENTION_DRY_RUN,default=false"` // only log what would be deleted
+
+	// Email digest via SMTP (SMTP_TLS_MODE is one of none, starttls, tls)
+	EmailDigestEnabled bool   `env:"EMAIL_DIGEST_ENABLED,default=false"`
+	SMTPHost           string `env:"SMTP_HOST"`
+	SMTPPort           int    `env:"SMTP_PORT,default=587"`
+	SMTPTLSMode        string `env:"SMTP_TLS_MODE,default=starttls"`
+	SMTPUsername       string `env:"SMTP_USERNAME"`
+	SMTPPassword       string `env:"SMTP_PASSWORD"`
+	SMTPFrom           string `env:"SMTP_FROM"`
 }
 
 // Load loads configuration from environment variables
@@ -47,3 +125,14 @@ func Load(ctx context.Context) (*Config, error) {
 	}
 	return &cfg, nil
 }
+
+// LoadOffline loads configuration for commands that do not generate reports, such as backfill,
+// so OPENAI_API_KEY is not required
+func LoadOffline(ctx context.Context) (*Config, error) {
+	var cfg Config
+	lookuper := envconfig.MultiLookuper(envconfig.OsLookuper(), envconfig.MapLookuper(map[string]string{"OPENAI_API_KEY": ""}))
+	if err := envconfig.ProcessWith(ctx, &cfg, lookuper); err != nil {
+		return nil, fmt.Errorf("failed to process config: %w", err)
+	}
+	return &cfg, nil
+}
@@ -44,6 +44,30 @@ func TestLoad(t *testing.T) {
 				if cfg.LogFormat != "auto" {
 					t.Errorf("Expected default LogFormat to be 'auto', got '%s'", cfg.LogFormat)
 				}
+				if len(cfg.SunImagery) != 1 || cfg.SunImagery[0] != "aia171" {
+					t.Errorf("Expected default SunImagery to be [aia171], got %v", cfg.SunImagery)
+				}
+				if cfg.HelioviewerWorkers != 6 {
+					t.Errorf("Expected default HelioviewerWorkers to be 6, got %d", cfg.HelioviewerWorkers)
+				}
+				if len(cfg.SunImageryFormats) != 0 {
+					t.Errorf("Expected no extra SunImageryFormats by default, got %v", cfg.SunImageryFormats)
+				}
+				if cfg.HistoryKIndexHours != 72 || cfg.HistorySolarMonths != 6 || cfg.HistoryBandDays != 30 {
+					t.Errorf("Expected default history windows 72h/6 months/30 days, got %dh/%d months/%d days", cfg.HistoryKIndexHours, cfg.HistorySolarMonths, cfg.HistoryBandDays)
+				}
+				if len(cfg.QTHGrids) != 0 {
+					t.Errorf("Expected no QTHGrids by default, got %v", cfg.QTHGrids)
+				}
+				if len(cfg.IonosondeStations) != 0 || cfg.IonosondeSource != "giro" || cfg.IonosondeURL != "" {
+					t.Errorf("Expected no ionosondes from giro by default, got %v from %q %q", cfg.IonosondeStations, cfg.IonosondeSource, cfg.IonosondeURL)
+				}
+				if len(cfg.SpotsSources) != 0 || cfg.SpotsHours != 6 || cfg.WSPRURL != "https://db1.wspr.live/" || cfg.PSKReporterURL != "https://retrieve.pskreporter.info/query" {
+					t.Errorf("Expected no spot sources over 6 hours by default, got %v over %d hours", cfg.SpotsSources, cfg.SpotsHours)
+				}
+				if cfg.DXClusterAddr != "" || cfg.DXClusterCallsign != "" || cfg.DXClusterWindowMinutes != 60 || len(cfg.DXClusterWatch) != 0 {
+					t.Errorf("Expected no DX cluster with a 60 minute window by default, got %q as %q over %d minutes", cfg.DXClusterAddr, cfg.DXClusterCallsign, cfg.DXClusterWindowMinutes)
+				}
 				return nil
 			},
 		},
@@ -122,6 +146,162 @@ func TestLoad(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "notification settings",
+			envVars: map[string]string{
+				"OPENAI_API_KEY":        "test-key",
+				"NOTIFY_WEBHOOKS":       "discord:https://discord.example/hook,ntfy:https://ntfy.example/radio",
+				"NOTIFY_WEBHOOK_SECRET": "s3cret",
+				"NOTIFY_KP_THRESHOLD":   "6",
+				"NOTIFY_GOOD_BANDS":     "10m,12m",
+			},
+			expectError: false,
+			validate: func(cfg *Config) error {
+				if len(cfg.NotifyWebhooks) != 2 || cfg.NotifyWebhooks[0] != "discord:https://discord.example/hook" {
+					t.Errorf("Expected 2 webhook entries, got %v", cfg.NotifyWebhooks)
+				}
+				if cfg.NotifyWebhookSecret != "s3cret" {
+					t.Errorf("Expected NotifyWebhookSecret to be 's3cret', got '%s'", cfg.NotifyWebhookSecret)
+				}
+				if cfg.NotifyKpThreshold != 6 {
+					t.Errorf("Expected NotifyKpThreshold to be 6, got %v", cfg.NotifyKpThreshold)
+				}
+				if cfg.NotifyFlareClass != "X" {
+					t.Errorf("Expected default NotifyFlareClass to be 'X', got '%s'", cfg.NotifyFlareClass)
+				}
+				if cfg.NotifyMaxRetries != 3 {
+					t.Errorf("Expected default NotifyMaxRetries to be 3, got %d", cfg.NotifyMaxRetries)
+				}
+				if len(cfg.NotifyGoodBands) != 2 || cfg.NotifyGoodBands[1] != "12m" {
+					t.Errorf("Expected NotifyGoodBands [10m 12m], got %v", cfg.NotifyGoodBands)
+				}
+				return nil
+			},
+		},
+		{
+			name: "email digest settings",
+			envVars: map[string]string{
+				"OPENAI_API_KEY":       "test-key",
+				"EMAIL_DIGEST_ENABLED": "true",
+				"SMTP_HOST":            "smtp.example.org",
+				"SMTP_TLS_MODE":        "tls",
+				"SMTP_PORT":            "465",
+				"SMTP_FROM":            "Radiocast <reports@example.org>",
+			},
+			expectError: false,
+			validate: func(cfg *Config) error {
+				if !cfg.EmailDigestEnabled {
+					t.Error("Expected EmailDigestEnabled to be true")
+				}
+				if cfg.SMTPHost != "smtp.example.org" || cfg.SMTPPort != 465 || cfg.SMTPTLSMode != "tls" {
+					t.Errorf("Unexpected SMTP settings: host=%s port=%d tls=%s", cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPTLSMode)
+				}
+				if cfg.SMTPFrom != "Radiocast <reports@example.org>" {
+					t.Errorf("Expected SMTPFrom to be set, got '%s'", cfg.SMTPFrom)
+				}
+				return nil
+			},
+		},
+		{
+			name: "retention settings",
+			envVars: map[string]string{
+				"OPENAI_API_KEY":      "test-key",
+				"RETENTION_ENABLED":   "true",
+				"RETENTION_FULL_DAYS": "14",
+				"RETENTION_DRY_RUN":   "true",
+			},
+			expectError: false,
+			validate: func(cfg *Config) error {
+				if !cfg.RetentionEnabled || !cfg.RetentionDryRun {
+					t.Errorf("Expected retention enabled in dry-run mode, got enabled=%v dry_run=%v", cfg.RetentionEnabled, cfg.RetentionDryRun)
+				}
+				if cfg.RetentionFullDays != 14 {
+					t.Errorf("Expected RetentionFullDays to be 14, got %d", cfg.RetentionFullDays)
+				}
+				if cfg.RetentionDailyDays != 365 {
+					t.Errorf("Expected default RetentionDailyDays to be 365, got %d", cfg.RetentionDailyDays)
+				}
+				return nil
+			},
+		},
+		{
+			name: "static and PDF reports",
+			envVars: map[string]string{
+				"OPENAI_API_KEY": "test-key",
+				"STATIC_REPORTS": "true",
+				"PDF_REPORTS":    "true",
+			},
+			expectError: false,
+			validate: func(cfg *Config) error {
+				if !cfg.StaticReports {
+					t.Error("Expected StaticReports to be true")
+				}
+				if !cfg.PDFReports {
+					t.Error("Expected PDFReports to be true")
+				}
+				return nil
+			},
+		},
+		{
+			name: "sun imagery products",
+			envVars: map[string]string{
+				"OPENAI_API_KEY":      "test-key",
+				"SUN_IMAGERY":         "aia304,lasco_c2",
+				"HELIOVIEWER_WORKERS": "3",
+				"SUN_IMAGERY_FORMATS": "gif,webp",
+			},
+			expectError: false,
+			validate: func(cfg *Config) error {
+				if len(cfg.SunImagery) != 2 || cfg.SunImagery[0] != "aia304" || cfg.SunImagery[1] != "lasco_c2" {
+					t.Errorf("Expected 2 imagery products, got %v", cfg.SunImagery)
+				}
+				if cfg.HelioviewerWorkers != 3 {
+					t.Errorf("Expected HelioviewerWorkers to be 3, got %d", cfg.HelioviewerWorkers)
+				}
+				if len(cfg.SunImageryFormats) != 2 || cfg.SunImageryFormats[0] != "gif" || cfg.SunImageryFormats[1] != "webp" {
+					t.Errorf("Expected [gif webp] imagery formats, got %v", cfg.SunImageryFormats)
+				}
+				return nil
+			},
+		},
+		{
+			name: "club QTHs",
+			envVars: map[string]string{
+				"OPENAI_API_KEY": "test-key",
+				"QTH_GRIDS":      "FN31pr,JO60",
+			},
+			expectError: false,
+			validate: func(cfg *Config) error {
+				if len(cfg.QTHGrids) != 2 || cfg.QTHGrids[0] != "FN31pr" || cfg.QTHGrids[1] != "JO60" {
+					t.Errorf("Expected QTHGrids [FN31pr JO60], got %v", cfg.QTHGrids)
+				}
+				return nil
+			},
+		},
+		{
+			name: "S3 storage",
+			envVars: map[string]string{
+				"OPENAI_API_KEY":        "test-key",
+				"S3_ENDPOINT":           "http://localhost:9000",
+				"S3_BUCKET":             "radiocast",
+				"S3_PATH_STYLE":         "true",
+				"AWS_ACCESS_KEY_ID":     "minioadmin",
+				"AWS_SECRET_ACCESS_KEY": "minioadmin",
+			},
+			expectError: false,
+			validate: func(cfg *Config) error {
+				if cfg.S3Endpoint != "http://localhost:9000" || cfg.S3Bucket != "radiocast" || !cfg.S3PathStyle {
+					t.Errorf("Unexpected S3 config: %q %q %v", cfg.S3Endpoint, cfg.S3Bucket, cfg.S3PathStyle)
+				}
+				if cfg.S3Region != "us-east-1" {
+					t.Errorf("Expected default S3Region to be 'us-east-1', got '%s'", cfg.S3Region)
+				}
+				if cfg.AWSAccessKeyID != "minioadmin" || cfg.AWSSecretAccessKey != "minioadmin" || cfg.AWSSessionToken != "" {
+					t.Errorf("Unexpected AWS credentials: %q %q %q", cfg.AWSAccessKeyID, cfg.AWSSecretAccessKey, cfg.AWSSessionToken)
+				}
+				return nil
+			},
+		},
 		{
 			name:        "missing required OpenAI API key",
 			envVars:     map[string]string{},
@@ -178,10 +358,11 @@ func TestLoadDefaultURLs(t *testing.T) {
 
 	// Test default URLs
 	expectedURLs := map[string]string{
-		"NOAAKIndexURL": "https://services.swpc.noaa.gov/products/noaa-planetary-k-index.json",
-		"NOAASolarURL":  "https://services.swpc.noaa.gov/json/solar-cycle/observed-solar-cycle-indices.json",
-		"N0NBHSolarURL": "https://www.hamqsl.com/solarapi.php?format=json",
-		"SIDCRSSURL":    "https://www.sidc.be/products/meu",
+		"NOAAKIndexURL":         "https://services.swpc.noaa.gov/products/noaa-planetary-k-index.json",
+		"NOAASolarURL":          "https://services.swpc.noaa.gov/json/solar-cycle/observed-solar-cycle-indices.json",
+		"N0NBHSolarURL":         "https://www.hamqsl.com/solarapi.php?format=json",
+		"SIDCRSSURL":            "https://www.sidc.be/products/meu",
+		"NOAAPredictedCycleURL": "https://services.swpc.noaa.gov/json/solar-cycle/predicted-solar-cycle.json",
 	}
 
 	if cfg.NOAAKIndexURL != expectedURLs["NOAAKIndexURL"] {
@@ -196,6 +377,9 @@ func TestLoadDefaultURLs(t *testing.T) {
 	if cfg.SIDCRSSURL != expectedURLs["SIDCRSSURL"] {
 		t.Errorf("Expected SIDCRSSURL to be '%s', got '%s'", expectedURLs["SIDCRSSURL"], cfg.SIDCRSSURL)
 	}
+	if cfg.NOAAPredictedCycleURL != expectedURLs["NOAAPredictedCycleURL"] {
+		t.Errorf("Expected NOAAPredictedCycleURL to be '%s', got '%s'", expectedURLs["NOAAPredictedCycleURL"], cfg.NOAAPredictedCycleURL)
+	}
 
 	clearEnv()
 }
@@ -219,12 +403,43 @@ func TestLoadWithContext(t *testing.T) {
 	clearEnv()
 }
 
+func TestLoadOffline(t *testing.T) {
+	clearEnv()
+	os.Setenv("GCS_BUCKET", "offline-bucket")
+
+	// The OpenAI key is not required offline, but is still read when set
+	cfg, err := LoadOffline(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error without OPENAI_API_KEY, got: %v", err)
+	}
+	if cfg.GCSBucket != "offline-bucket" || cfg.OpenAIAPIKey != "" {
+		t.Errorf("Expected bucket 'offline-bucket' and no API key, got '%s' and '%s'", cfg.GCSBucket, cfg.OpenAIAPIKey)
+	}
+
+	os.Setenv("OPENAI_API_KEY", "test-key")
+	if cfg, _ := LoadOffline(context.Background()); cfg.OpenAIAPIKey != "test-key" {
+		t.Errorf("Expected OpenAIAPIKey 'test-key', got '%s'", cfg.OpenAIAPIKey)
+	}
+
+	clearEnv()
+}
+
 // Helper function to clear relevant environment variables
 func clearEnv() {
 	envVars := []string{
 		"PORT", "OPENAI_API_KEY", "OPENAI_MODEL", "GCP_PROJECT_ID", "GCS_BUCKET",
 		"LOCAL_REPORTS_DIR", "MOCKUP_MODE", "NOAA_K_INDEX_URL", "NOAA_SOLAR_URL",
 		"N0NBH_SOLAR_URL", "SIDC_RSS_URL", "ENVIRONMENT", "LOG_LEVEL", "LOG_FORMAT",
+		"PUBLIC_BASE_URL", "NOTIFY_WEBHOOKS", "NOTIFY_WEBHOOK_SECRET", "NOTIFY_MAX_RETRIES",
+		"NOTIFY_KP_THRESHOLD", "NOTIFY_FLARE_CLASS", "NOTIFY_GOOD_BANDS",
+		"EMAIL_DIGEST_ENABLED", "SMTP_HOST", "SMTP_PORT", "SMTP_TLS_MODE", "SMTP_USERNAME",
+		"SMTP_PASSWORD", "SMTP_FROM", "STATIC_REPORTS", "PDF_REPORTS", "SUN_IMAGERY", "HELIOVIEWER_WORKERS", "SUN_IMAGERY_FORMATS",
+		"S3_ENDPOINT", "S3_REGION", "S3_BUCKET", "S3_PATH_STYLE", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN",
+		"RETENTION_ENABLED", "RETENTION_FULL_DAYS", "RETENTION_DAILY_DAYS", "RETENTION_DRY_RUN",
+		"HISTORY_K_INDEX_HOURS", "HISTORY_SOLAR_MONTHS", "HISTORY_BAND_DAYS", "QTH_GRIDS",
+		"IONOSONDE_STATIONS", "IONOSONDE_SOURCE", "IONOSONDE_URL",
+		"SPOTS_SOURCES", "SPOTS_HOURS", "WSPR_URL", "PSKREPORTER_URL",
+		"DXCLUSTER_ADDR", "DXCLUSTER_CALLSIGN", "DXCLUSTER_WINDOW_MINUTES", "DXCLUSTER_WATCH",
 	}
 	for _, env := range envVars {
 		os.Unsetenv(env)
@@ -0,0 +1,267 @@
+package dxcluster
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"radiocast/internal/logger"
+	"radiocast/internal/models"
+)
+
+const (
+	// DefaultWindow is the default width of the rolling spot statistics
+	DefaultWindow = time.Hour
+
+	dialTimeout  = 15 * time.Second
+	loginTimeout = 30 * time.Second
+	// idleTimeout drops a connection that stayed silent, busy clusters send spots every few seconds
+	idleTimeout = 10 * time.Minute
+)
+
+// telnet protocol bytes
+const (
+	iac  = 255
+	sb   = 250
+	se   = 240
+	will = 251
+	wont = 252
+	do   = 253
+	dont = 254
+)
+
+// Client keeps a telnet connection to a DX cluster (DXSpider, AR-Cluster, CC Cluster) and
+// feeds the spots it receives into Stats
+type Client struct {
+	addr     string
+	callsign string
+	stats    *Stats
+
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu        sync.Mutex
+	connected bool
+}
+
+// NewClient creates a client logging in to the cluster at addr (host:port) as callsign
+func NewClient(addr, callsign string, stats *Stats) *Client {
+	return &Client{
+		addr:       addr,
+		callsign:   strings.ToUpper(strings.TrimSpace(callsign)),
+		stats:      stats,
+		minBackoff: 5 * time.Second,
+		maxBackoff: 5 * time.Minute,
+	}
+}
+
+// Connected reports whether the client is logged in right now
+func (c *Client) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+// Summary returns the spot statistics of the window before now along with the connection state
+func (c *Client) Summary(now time.Time) *models.DXClusterSummary {
+	summary := c.stats.Summary(now)
+	summary.Host = c.addr
+	summary.Connected = c.Connected()
+	return summary
+}
+
+// Run connects to the cluster and reads spots until ctx is done. Lost connections are retried
+// with exponential backoff, reset after a session that received spots.
+func (c *Client) Run(ctx context.Context) {
+	backoff := c.minBackoff
+	for {
+		spots, err := c.session(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if spots > 0 {
+			backoff = c.minBackoff
+		}
+		logger.Warn("DX cluster connection lost", map[string]interface{}{
+			"host":  c.addr,
+			"spots": spots,
+			"error": fmt.Sprint(err),
+			"retry": backoff.String(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+// session runs one connection and returns how many spots it received
+func (c *Client) session(ctx context.Context) (int, error) {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	// Unblock reads when the context ends
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	r := bufio.NewReader(&telnetReader{r: conn, w: conn})
+	if err := c.login(conn, r); err != nil {
+		return 0, err
+	}
+	c.setConnected(true)
+	defer c.setConnected(false)
+	logger.Info("Connected to DX cluster", map[string]interface{}{"host": c.addr, "callsign": c.callsign})
+
+	spots := 0
+	for {
+		conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = errors.New("closed by cluster")
+			}
+			return spots, err
+		}
+		spot, err := ParseSpot(line, time.Now())
+		if err != nil {
+			continue
+		}
+		c.stats.Add(spot)
+		spots++
+	}
+}
+
+// login waits for the callsign prompt and answers it. Clusters that do not prompt within
+// loginTimeout get the callsign anyway.
+func (c *Client) login(conn net.Conn, r *bufio.Reader) error {
+	conn.SetReadDeadline(time.Now().Add(loginTimeout))
+	var seen strings.Builder
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return fmt.Errorf("failed to read login prompt: %w", err)
+		}
+		seen.WriteByte(b)
+		if isLoginPrompt(seen.String()) {
+			break
+		}
+		if b == '\n' {
+			seen.Reset()
+		}
+	}
+	conn.SetWriteDeadline(time.Now().Add(dialTimeout))
+	if _, err := conn.Write([]byte(c.callsign + "\r\n")); err != nil {
+		return fmt.Errorf("failed to send callsign: %w", err)
+	}
+	conn.SetWriteDeadline(time.Time{})
+	return nil
+}
+
+func (c *Client) setConnected(connected bool) {
+	c.mu.Lock()
+	c.connected = connected
+	c.mu.Unlock()
+}
+
+// isLoginPrompt recognizes "login:", "Please enter your call:" and "enter your callsign" prompts
+func isLoginPrompt(s string) bool {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.HasSuffix(s, "login:") || strings.HasSuffix(s, "call:") ||
+		strings.HasSuffix(s, "callsign:") || strings.HasSuffix(s, "callsign")
+}
+
+// cleanLine drops the carriage returns and bells clusters send along with spots
+func cleanLine(line string) string {
+	return strings.TrimSpace(strings.NewReplacer("\r", "", "\a", "").Replace(line))
+}
+
+// telnetReader strips telnet commands from the stream and refuses every option the cluster
+// offers or asks for
+type telnetReader struct {
+	r     io.Reader
+	w     io.Writer
+	state int // bytes of the pending command seen so far; -1 inside a subnegotiation
+	cmd   byte
+}
+
+func (t *telnetReader) Read(p []byte) (int, error) {
+	buf := make([]byte, len(p))
+	for {
+		n, err := t.r.Read(buf)
+		out := 0
+		for _, b := range buf[:n] {
+			switch {
+			case t.state == -1: // subnegotiation, until IAC SE
+				if b == iac {
+					t.state = -2
+				}
+			case t.state == -2:
+				t.state = -1
+				if b == se {
+					t.state = 0
+				}
+			case t.state == 0 && b == iac:
+				t.state = 1
+			case t.state == 1:
+				switch {
+				case b == iac: // escaped 255
+					p[out] = b
+					out++
+					t.state = 0
+				case b == sb:
+					t.state = -1
+				case b >= will && b <= dont:
+					t.cmd = b
+					t.state = 2
+				default:
+					t.state = 0
+				}
+			case t.state == 2:
+				t.refuse(b)
+				t.state = 0
+			default:
+				p[out] = b
+				out++
+			}
+		}
+		if out > 0 || err != nil {
+			return out, err
+		}
+	}
+}
+
+// refuse answers WILL with DONT and DO with WONT; WONT and DONT need no answer
+func (t *telnetReader) refuse(option byte) {
+	switch t.cmd {
+	case will:
+		t.w.Write([]byte{iac, dont, option})
+	case do:
+		t.w.Write([]byte{iac, wont, option})
+	}
+}
@@ -0,0 +1,171 @@
+package dxcluster
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeCluster is a local telnet server standing in for a DX cluster. Each accepted connection
+// is handed to the next session function.
+type fakeCluster struct {
+	listener net.Listener
+	logins   chan string
+}
+
+func newFakeCluster(t *testing.T, sessions ...func(conn net.Conn)) *fakeCluster {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	f := &fakeCluster{listener: listener, logins: make(chan string, len(sessions))}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for _, session := range sessions {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(session func(net.Conn)) {
+				defer conn.Close()
+				// Offer echo, then prompt for the callsign like DXSpider does
+				conn.Write([]byte{iac, will, 1})
+				conn.Write([]byte("\r\nPlease enter your call: "))
+				login, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil {
+					return
+				}
+				f.logins <- login
+				session(conn)
+			}(session)
+		}
+	}()
+	return f
+}
+
+func spotLines(calls ...string) string {
+	now := time.Now().UTC().Format("1504")
+	var lines strings.Builder
+	for i, call := range calls {
+		fmt.Fprintf(&lines, "DX de W%dAB:     14025.0  %-12s CW 599                          %sZ\a\a\r\n", i, call, now)
+	}
+	return lines.String()
+}
+
+func TestClientReconnects(t *testing.T) {
+	stay := make(chan struct{})
+	cluster := newFakeCluster(t,
+		func(conn net.Conn) {
+			conn.Write([]byte("Hello N0CALL, this is DXSPIDER\r\n"))
+			conn.Write([]byte(spotLines("JA1ABC", "3Y0J")))
+			conn.Write([]byte("WWV de W0MU <18>:   SFI=120, A=8, K=2\r\n"))
+			// dropped by the cluster
+		},
+		func(conn net.Conn) {
+			conn.Write([]byte(spotLines("3Y0J")))
+			<-stay
+		},
+	)
+	defer close(stay)
+
+	client := NewClient(cluster.listener.Addr().String(), " n0call ", NewStats(time.Hour, []string{"3Y0J"}))
+	client.minBackoff = 10 * time.Millisecond
+	client.maxBackoff = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		client.Run(ctx)
+		close(done)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case login := <-cluster.logins:
+			want := string([]byte{iac, dont, 1}) + "N0CALL\r\n"
+			if login != want {
+				t.Errorf("login %d = %q, want %q", i, login, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no login %d", i)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for client.stats.Summary(time.Now().Add(time.Minute)).Spots < 3 || !client.Connected() {
+		if time.Now().After(deadline) {
+			t.Fatalf("summary = %+v, connected = %v", client.stats.Summary(time.Now()), client.Connected())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	summary := client.Summary(time.Now().Add(time.Minute))
+	if summary.Host != cluster.listener.Addr().String() || !summary.Connected || summary.Spots != 3 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if len(summary.Notable) != 1 || summary.Notable[0].Call != "3Y0J" || summary.Notable[0].Spots != 2 {
+		t.Errorf("notable = %+v", summary.Notable)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+	if client.Connected() {
+		t.Error("still connected after cancel")
+	}
+}
+
+func TestClientBacksOff(t *testing.T) {
+	// Nothing listens on a closed listener's port
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	client := NewClient(addr, "N0CALL", NewStats(time.Hour, nil))
+	client.minBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	client.Run(ctx)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run took %v after the context ended", elapsed)
+	}
+	if client.Connected() {
+		t.Error("connected without a cluster")
+	}
+}
+
+func TestTelnetReader(t *testing.T) {
+	var replies strings.Builder
+	input := []byte{iac, do, 24} // DO terminal type
+	input = append(input, "a"...)
+	input = append(input, iac, sb, 24, 1, iac, se) // subnegotiation
+	input = append(input, "b"...)
+	input = append(input, iac, iac, iac, 241) // escaped 255, NOP
+	input = append(input, "c\r\n"...)
+
+	r := bufio.NewReader(&telnetReader{r: strings.NewReader(string(input)), w: &replies})
+	line, err := r.ReadString('\n')
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if want := "ab\xffc\r\n"; line != want {
+		t.Errorf("line = %q, want %q", line, want)
+	}
+	if want := string([]byte{iac, wont, 24}); replies.String() != want {
+		t.Errorf("replies = %q, want %q", replies.String(), want)
+	}
+}
@@ -0,0 +1,64 @@
+package dxcluster
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"radiocast/internal/geo"
+	"radiocast/internal/models"
+)
+
+// ErrNotSpot is returned by ParseSpot for cluster lines that are not DX spots (announcements,
+// WWV, talk, prompts)
+var ErrNotSpot = errors.New("not a DX spot")
+
+// spotLine matches the spot format shared by DXSpider, AR-Cluster and CC Cluster, e.g.
+// "DX de W3LPL-#:    14025.0  JA1ABC       CW 24 dB 28 WPM CQ              1234Z FM19"
+var spotLine = regexp.MustCompile(`^DX DE\s+([A-Z0-9/#-]+):\s*(\d+(?:\.\d+)?)\s+([A-Z0-9/]+)\s+(.*?)\s*(\d{4})Z(?:\s+\S+)?\s*$`)
+
+// Spot is one DX spot: Spotter heard DXCall on FrequencyKHz
+type Spot struct {
+	Time         time.Time
+	Spotter      string
+	FrequencyKHz float64
+	DXCall       string
+	Comment      string
+	Band         string // empty outside the amateur bands
+	Continent    string // of the DX station, empty if unknown
+}
+
+// ParseSpot parses a spot line. The line only carries the time of day, which is placed on the
+// UTC day of now, or the day before if that would be more than an hour ahead.
+func ParseSpot(line string, now time.Time) (Spot, error) {
+	m := spotLine.FindStringSubmatch(strings.ToUpper(cleanLine(line)))
+	if m == nil {
+		return Spot{}, ErrNotSpot
+	}
+	khz, err := strconv.ParseFloat(m[2], 64)
+	if err != nil {
+		return Spot{}, ErrNotSpot
+	}
+	hour, _ := strconv.Atoi(m[5][:2])
+	minute, _ := strconv.Atoi(m[5][2:])
+	if hour > 23 || minute > 59 {
+		return Spot{}, ErrNotSpot
+	}
+
+	now = now.UTC()
+	t := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, time.UTC)
+	if t.After(now.Add(time.Hour)) {
+		t = t.AddDate(0, 0, -1)
+	}
+	return Spot{
+		Time:         t,
+		Spotter:      strings.TrimSuffix(m[1], "-#"),
+		FrequencyKHz: khz,
+		DXCall:       m[3],
+		Comment:      m[4],
+		Band:         models.BandOf(khz / 1000),
+		Continent:    geo.ContinentOfCall(m[3]),
+	}, nil
+}
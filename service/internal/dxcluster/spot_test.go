@@ -0,0 +1,67 @@
+package dxcluster
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseSpot(t *testing.T) {
+	now := time.Date(2025, 9, 23, 19, 30, 0, 0, time.UTC)
+
+	spot, err := ParseSpot("DX de W3LPL-#:    14025.0  JA1ABC       CW 24 dB 28 WPM CQ              1912Z FM19\a\a", now)
+	if err != nil {
+		t.Fatalf("ParseSpot failed: %v", err)
+	}
+	want := Spot{
+		Time:         time.Date(2025, 9, 23, 19, 12, 0, 0, time.UTC),
+		Spotter:      "W3LPL",
+		FrequencyKHz: 14025,
+		DXCall:       "JA1ABC",
+		Comment:      "CW 24 DB 28 WPM CQ",
+		Band:         "20m",
+		Continent:    "AS",
+	}
+	if spot != want {
+		t.Errorf("ParseSpot = %+v, want %+v", spot, want)
+	}
+
+	// AR-Cluster lines have no locator, portable calls keep their suffix
+	spot, err = ParseSpot("DX de dl1abc:     50313.0  3Y0J/p       FT8 -12dB                      1905Z", now)
+	if err != nil {
+		t.Fatalf("ParseSpot failed: %v", err)
+	}
+	if spot.Spotter != "DL1ABC" || spot.DXCall != "3Y0J/P" || spot.Band != "6m" || spot.Comment != "FT8 -12DB" {
+		t.Errorf("ParseSpot = %+v", spot)
+	}
+
+	// A time more than an hour ahead belongs to the previous day
+	spot, err = ParseSpot("DX de G4ABC:       7010.0  VK2XX        2355Z", now)
+	if err != nil {
+		t.Fatalf("ParseSpot failed: %v", err)
+	}
+	if wantTime := time.Date(2025, 9, 22, 23, 55, 0, 0, time.UTC); !spot.Time.Equal(wantTime) {
+		t.Errorf("time = %v, want %v", spot.Time, wantTime)
+	}
+	if spot.Comment != "" || spot.Band != "40m" || spot.Continent != "OC" {
+		t.Errorf("ParseSpot = %+v", spot)
+	}
+
+	// Outside the amateur bands
+	spot, err = ParseSpot("DX de G4ABC:       5000.0  WWV          1900Z", now)
+	if err != nil || spot.Band != "" {
+		t.Errorf("ParseSpot(5000 kHz) = %+v, %v", spot, err)
+	}
+
+	for _, line := range []string{
+		"",
+		"WWV de W0MU <18>:   SFI=120, A=8, K=2, No Storms -> No Storms",
+		"To ALL de DL1ABC: 3Y0J is loud on 20m",
+		"W3LPL de DXSPIDER 23-Sep-2025 1930Z dxspider >",
+		"DX de W3LPL:    14025.0  JA1ABC       CW                             2575Z",
+	} {
+		if _, err := ParseSpot(line, now); !errors.Is(err, ErrNotSpot) {
+			t.Errorf("ParseSpot(%q) error = %v, want ErrNotSpot", line, err)
+		}
+	}
+}
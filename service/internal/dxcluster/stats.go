@@ -0,0 +1,181 @@
+package dxcluster
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"radiocast/internal/geo"
+	"radiocast/internal/models"
+)
+
+const (
+	// NotableSpotters is how many distinct spotters make a call notable without being watched
+	NotableSpotters = 3
+
+	// notableLimit caps the notable calls of a summary
+	notableLimit = 10
+
+	// maxSpots bounds the spots kept in memory, whatever the window
+	maxSpots = 50000
+)
+
+// Stats keeps the spots of a rolling window and summarizes them
+type Stats struct {
+	window time.Duration
+	watch  map[string]bool
+
+	mu    sync.Mutex
+	spots []Spot // oldest first
+}
+
+// NewStats creates rolling statistics over window; calls in watch, e.g. announced DXpeditions,
+// are always notable when spotted
+func NewStats(window time.Duration, watch []string) *Stats {
+	watched := make(map[string]bool, len(watch))
+	for _, call := range watch {
+		if call = strings.ToUpper(strings.TrimSpace(call)); call != "" {
+			watched[call] = true
+		}
+	}
+	return &Stats{window: window, watch: watched}
+}
+
+// Window returns the width of the rolling window
+func (s *Stats) Window() time.Duration {
+	return s.window
+}
+
+// Add records a spot and drops the spots that left the window
+func (s *Stats) Add(spot Spot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.spots = append(s.spots, spot)
+	for i := len(s.spots) - 1; i > 0 && s.spots[i].Time.Before(s.spots[i-1].Time); i-- {
+		s.spots[i], s.spots[i-1] = s.spots[i-1], s.spots[i]
+	}
+	latest := s.spots[len(s.spots)-1].Time
+	drop := 0
+	for drop < len(s.spots) && (!s.spots[drop].Time.After(latest.Add(-s.window)) || len(s.spots)-drop > maxSpots) {
+		drop++
+	}
+	s.spots = append(s.spots[:0], s.spots[drop:]...)
+}
+
+// Summary counts the spots of the window before now by band and continent of the DX station,
+// and lists the watched calls and those heard by at least NotableSpotters spotters
+func (s *Stats) Summary(now time.Time) *models.DXClusterSummary {
+	from := now.Add(-s.window)
+	summary := &models.DXClusterSummary{
+		From:       from,
+		To:         now,
+		Bands:      []models.DXClusterBand{},
+		Continents: []models.DXClusterCount{},
+		Notable:    []models.DXClusterCall{},
+	}
+
+	type callStats struct {
+		call     models.DXClusterCall
+		spotters map[string]bool
+		bands    map[string]bool
+	}
+	bands := make(map[string]map[string]int)
+	continents := make(map[string]int)
+	calls := make(map[string]*callStats)
+
+	s.mu.Lock()
+	for _, spot := range s.spots {
+		if !spot.Time.After(from) || spot.Time.After(now) || spot.Band == "" {
+			continue
+		}
+		summary.Spots++
+		if bands[spot.Band] == nil {
+			bands[spot.Band] = make(map[string]int)
+		}
+		bands[spot.Band][spot.Continent]++
+		continents[spot.Continent]++
+
+		c, ok := calls[spot.DXCall]
+		if !ok {
+			c = &callStats{
+				call:     models.DXClusterCall{Call: spot.DXCall, Continent: spot.Continent, Watched: s.watch[spot.DXCall]},
+				spotters: make(map[string]bool),
+				bands:    make(map[string]bool),
+			}
+			calls[spot.DXCall] = c
+		}
+		c.call.Spots++
+		c.spotters[spot.Spotter] = true
+		c.bands[spot.Band] = true
+		c.call.FrequencyKHz, c.call.Comment, c.call.LastSpot = spot.FrequencyKHz, spot.Comment, spot.Time
+	}
+	s.mu.Unlock()
+
+	for _, band := range models.Bands {
+		if len(bands[band.Name]) == 0 {
+			continue
+		}
+		b := models.DXClusterBand{Band: band.Name, Continents: counts(bands[band.Name])}
+		for _, c := range b.Continents {
+			b.Spots += c.Spots
+		}
+		summary.Bands = append(summary.Bands, b)
+	}
+	summary.Continents = counts(continents)
+
+	for _, c := range calls {
+		c.call.Spotters = len(c.spotters)
+		if !c.call.Watched && c.call.Spotters < NotableSpotters {
+			continue
+		}
+		for _, band := range models.Bands {
+			if c.bands[band.Name] {
+				c.call.Bands = append(c.call.Bands, band.Name)
+			}
+		}
+		summary.Notable = append(summary.Notable, c.call)
+	}
+	sort.Slice(summary.Notable, func(i, j int) bool {
+		a, b := summary.Notable[i], summary.Notable[j]
+		switch {
+		case a.Watched != b.Watched:
+			return a.Watched
+		case a.Spotters != b.Spotters:
+			return a.Spotters > b.Spotters
+		case a.Spots != b.Spots:
+			return a.Spots > b.Spots
+		}
+		return a.Call < b.Call
+	})
+	if len(summary.Notable) > notableLimit {
+		summary.Notable = summary.Notable[:notableLimit]
+	}
+	return summary
+}
+
+// counts lists spot counts by continent, most first, in geo.Continents order on ties
+func counts(byContinent map[string]int) []models.DXClusterCount {
+	order := make(map[string]int, len(geo.Continents))
+	for i, c := range geo.Continents {
+		order[c] = i
+	}
+	result := make([]models.DXClusterCount, 0, len(byContinent))
+	for continent, spots := range byContinent {
+		result = append(result, models.DXClusterCount{Continent: continent, Spots: spots})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Spots != b.Spots {
+			return a.Spots > b.Spots
+		}
+		oa, knownA := order[a.Continent]
+		ob, knownB := order[b.Continent]
+		if knownA != knownB {
+			return knownA
+		}
+		return oa < ob
+	})
+	return result
+}
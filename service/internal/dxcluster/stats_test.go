@@ -0,0 +1,89 @@
+package dxcluster
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+)
+
+func TestStatsSummary(t *testing.T) {
+	now := time.Date(2025, 9, 23, 19, 30, 0, 0, time.UTC)
+	stats := NewStats(time.Hour, []string{" 3y0j ", ""})
+
+	add := func(minutesAgo int, spotter string, khz float64, call string) {
+		t.Helper()
+		stats.Add(Spot{
+			Time:         now.Add(-time.Duration(minutesAgo) * time.Minute),
+			Spotter:      spotter,
+			FrequencyKHz: khz,
+			DXCall:       call,
+			Band:         models.BandOf(khz / 1000),
+			Continent:    map[string]string{"JA1ABC": "AS", "DL1ABC": "EU", "3Y0J": "AF", "PY2XB": "SA"}[call],
+		})
+	}
+	add(90, "W3LPL", 14025, "JA1ABC") // before the window
+	add(50, "W3LPL", 14025, "JA1ABC")
+	add(40, "K1TTT", 14026, "JA1ABC")
+	add(30, "N2IC", 21025, "JA1ABC")
+	add(20, "G4ABC", 14074, "DL1ABC")
+	add(25, "VK2XX", 28074, "DL1ABC") // out of order
+	add(10, "W3LPL", 18100, "3Y0J")   // watched, one spotter
+	add(5, "W3LPL", 5000, "WWV")      // outside the bands
+	add(1, "N2IC", 14200, "PY2XB")
+	add(-5, "N2IC", 14200, "PY2XB") // after now
+
+	summary := stats.Summary(now)
+	if summary.Spots != 7 {
+		t.Errorf("spots = %d, want 7", summary.Spots)
+	}
+	if !summary.From.Equal(now.Add(-time.Hour)) || !summary.To.Equal(now) {
+		t.Errorf("window = %v - %v", summary.From, summary.To)
+	}
+
+	wantBands := []models.DXClusterBand{
+		{Band: "20m", Spots: 4, Continents: []models.DXClusterCount{{Continent: "AS", Spots: 2}, {Continent: "SA", Spots: 1}, {Continent: "EU", Spots: 1}}},
+		{Band: "17m", Spots: 1, Continents: []models.DXClusterCount{{Continent: "AF", Spots: 1}}},
+		{Band: "15m", Spots: 1, Continents: []models.DXClusterCount{{Continent: "AS", Spots: 1}}},
+		{Band: "10m", Spots: 1, Continents: []models.DXClusterCount{{Continent: "EU", Spots: 1}}},
+	}
+	if !reflect.DeepEqual(summary.Bands, wantBands) {
+		t.Errorf("bands = %+v, want %+v", summary.Bands, wantBands)
+	}
+	wantContinents := []models.DXClusterCount{{Continent: "AS", Spots: 3}, {Continent: "EU", Spots: 2}, {Continent: "SA", Spots: 1}, {Continent: "AF", Spots: 1}}
+	if !reflect.DeepEqual(summary.Continents, wantContinents) {
+		t.Errorf("continents = %+v, want %+v", summary.Continents, wantContinents)
+	}
+
+	if len(summary.Notable) != 2 {
+		t.Fatalf("notable = %+v, want 3Y0J and JA1ABC", summary.Notable)
+	}
+	if n := summary.Notable[0]; n.Call != "3Y0J" || !n.Watched || n.Spotters != 1 || n.FrequencyKHz != 18100 {
+		t.Errorf("notable[0] = %+v", n)
+	}
+	n := summary.Notable[1]
+	if n.Call != "JA1ABC" || n.Watched || n.Spots != 3 || n.Spotters != 3 || n.FrequencyKHz != 21025 ||
+		!reflect.DeepEqual(n.Bands, []string{"20m", "15m"}) || !n.LastSpot.Equal(now.Add(-30*time.Minute)) {
+		t.Errorf("notable[1] = %+v", n)
+	}
+}
+
+func TestStatsPrunesWindow(t *testing.T) {
+	start := time.Date(2025, 9, 23, 12, 0, 0, 0, time.UTC)
+	stats := NewStats(time.Hour, nil)
+	for i := 0; i < 180; i++ {
+		stats.Add(Spot{Time: start.Add(time.Duration(i) * time.Minute), DXCall: "JA1ABC", Band: "20m"})
+	}
+	if len(stats.spots) != 60 {
+		t.Errorf("kept %d spots, want 60", len(stats.spots))
+	}
+	if summary := stats.Summary(start.Add(179 * time.Minute)); summary.Spots != 60 {
+		t.Errorf("spots = %d, want 60", summary.Spots)
+	}
+
+	empty := NewStats(time.Hour, nil).Summary(start)
+	if empty.Spots != 0 || empty.Bands == nil || empty.Continents == nil || empty.Notable == nil {
+		t.Errorf("empty summary = %+v", empty)
+	}
+}
@@ -0,0 +1,160 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"radiocast/internal/charts"
+	"radiocast/internal/imagery"
+	"radiocast/internal/logger"
+	"radiocast/internal/reports"
+)
+
+const (
+	// Chart image size in the HTML email; most clients render at most ~640px wide
+	chartImageWidth  = 640
+	chartImageHeight = 320
+	gaugeImageHeight = 240
+
+	// unsubscribeMarker is replaced with the recipient's unsubscribe link
+	unsubscribeMarker = "__UNSUBSCRIBE_URL__"
+)
+
+var htmlPlaceholderPattern = regexp.MustCompile(`(?:<p>\s*)?\{\{\s*\.([A-Za-z0-9]+)\s*\}\}(?:\s*</p>)?`)
+
+// Digest is a rendered report email shared by all recipients
+type Digest struct {
+	Subject string
+	Text    string
+	HTML    string
+	Inline  []InlineImage
+}
+
+// MessageFor personalises the digest for one recipient
+func (d *Digest) MessageFor(to, unsubscribeURL string) *Message {
+	return &Message{
+		To:      []string{to},
+		Subject: d.Subject,
+		Text:    strings.ReplaceAll(d.Text, unsubscribeMarker, unsubscribeURL),
+		HTML:    strings.ReplaceAll(d.HTML, unsubscribeMarker, template.HTMLEscapeString(unsubscribeURL)),
+		Inline:  d.Inline,
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}
+}
+
+// BuildDigest converts a published report into a digest email. Chart placeholders in the
+// markdown become inline PNG images in the HTML part and are dropped from the plaintext part.
+func BuildDigest(report *reports.PublishedReport, baseURL string) (*Digest, error) {
+	if report == nil || report.Data == nil {
+		return nil, fmt.Errorf("report data cannot be nil")
+	}
+	reportURL := strings.TrimSuffix(baseURL, "/") + report.URLPath()
+	date := report.Data.Timestamp.UTC().Format("2006-01-02")
+
+	snippets, err := charts.NewChartGenerator(report.FolderPath).GenerateEChartsSnippetsWithSources(report.Data, report.SourceData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate charts: %w", err)
+	}
+	snippetByID := make(map[string]charts.ChartSnippet, len(snippets))
+	for _, sn := range snippets {
+		snippetByID[sn.ID] = sn
+	}
+
+	body, err := reports.NewHTMLBuilder().ConvertMarkdownToHTML(report.Markdown)
+	if err != nil {
+		return nil, err
+	}
+
+	var inline []InlineImage
+	body = htmlPlaceholderPattern.ReplaceAllStringFunc(body, func(match string) string {
+		name := htmlPlaceholderPattern.FindStringSubmatch(match)[1]
+		if imagery.IsImageryPlaceholder(name) {
+			return fmt.Sprintf(`<p><a href="%s">▶ View the 72-hour animated Sun imagery on the website</a></p>`, template.HTMLEscapeString(reportURL))
+		}
+		sn, ok := snippetByID[reports.ChartPlaceholders[name]]
+		if !ok || sn.Static == nil {
+			return ""
+		}
+		height := chartImageHeight
+		if sn.Static.Kind == charts.StaticGaugeKind {
+			height = gaugeImageHeight
+		}
+		png, err := charts.RenderPNG(sn.Static, chartImageWidth, height)
+		if err != nil {
+			logger.Warn("Failed to render chart for email", map[string]interface{}{"chart": sn.ID, "error": err.Error()})
+			return ""
+		}
+		cid := sn.ID + "@radiocast"
+		inline = append(inline, InlineImage{ContentID: cid, Filename: sn.ID + ".png", ContentType: "image/png", Data: png})
+		return fmt.Sprintf(`<p><img src="cid:%s" alt="%s" width="%d" style="max-width:100%%;height:auto;"></p>`,
+			cid, template.HTMLEscapeString(sn.Title), chartImageWidth)
+	})
+
+	var htmlBuf bytes.Buffer
+	err = digestTemplate.Execute(&htmlBuf, map[string]interface{}{
+		"Title":          "Radio Propagation Report – " + date,
+		"Body":           template.HTML(body),
+		"ReportURL":      reportURL,
+		"UnsubscribeURL": template.URL(unsubscribeMarker),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render digest HTML: %w", err)
+	}
+
+	text := fmt.Sprintf("RADIO PROPAGATION REPORT – %s\n\n%s\nFull report with interactive charts:\n%s\n\n--\nYou receive this because you subscribed to the Radiocast digest.\nUnsubscribe: %s\n",
+		date, MarkdownToText(report.Markdown), reportURL, unsubscribeMarker)
+
+	return &Digest{
+		Subject: "Radio Propagation Report – " + date,
+		Text:    text,
+		HTML:    htmlBuf.String(),
+		Inline:  inline,
+	}, nil
+}
+
+// ConfirmationMessage builds the double opt-in email
+func ConfirmationMessage(to, confirmURL string) *Message {
+	text := fmt.Sprintf("Hello,\n\nplease confirm your subscription to the daily Radiocast propagation digest by opening this link:\n\n%s\n\nIf you did not request this, just ignore this email and you will not hear from us again.\n", confirmURL)
+	var htmlBuf bytes.Buffer
+	confirmTemplate.Execute(&htmlBuf, map[string]string{"ConfirmURL": confirmURL})
+	return &Message{
+		To:      []string{to},
+		Subject: "Confirm your Radiocast digest subscription",
+		Text:    text,
+		HTML:    htmlBuf.String(),
+	}
+}
+
+// Email clients ignore <style> blocks inconsistently, so styling is inline
+var digestTemplate = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body style="margin:0;padding:0;background:#f4f6f8;font-family:Arial,Helvetica,sans-serif;color:#343a40;">
+<div style="max-width:680px;margin:0 auto;background:#ffffff;padding:20px;">
+<h1 style="font-size:22px;color:#1a3a5c;margin-top:0;">{{.Title}}</h1>
+<div style="font-size:15px;line-height:1.5;">
+{{.Body}}
+</div>
+<p style="margin-top:24px;"><a href="{{.ReportURL}}" style="color:#1a73e8;">Open the full report with interactive charts</a></p>
+<hr style="border:none;border-top:1px solid #e9ecef;">
+<p style="font-size:12px;color:#6c757d;">You receive this because you subscribed to the Radiocast digest.
+<a href="{{.UnsubscribeURL}}" style="color:#6c757d;">Unsubscribe</a></p>
+</div>
+</body>
+</html>`))
+
+var confirmTemplate = template.Must(template.New("confirm").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family:Arial,Helvetica,sans-serif;color:#343a40;">
+<p>Hello,</p>
+<p>please confirm your subscription to the daily Radiocast propagation digest:</p>
+<p><a href="{{.ConfirmURL}}" style="display:inline-block;padding:10px 18px;background:#1a73e8;color:#ffffff;text-decoration:none;border-radius:4px;">Confirm subscription</a></p>
+<p style="font-size:12px;color:#6c757d;">If you did not request this, just ignore this email and you will not hear from us again.</p>
+</body>
+</html>`))
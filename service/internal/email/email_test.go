@@ -0,0 +1,457 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+	"radiocast/internal/reports"
+	"radiocast/internal/storage"
+)
+
+// fakeSMTPServer is a minimal SMTP stand-in that records delivered messages
+type fakeSMTPServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []receivedMessage
+	authUser string
+}
+
+type receivedMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := &fakeSMTPServer{listener: ln}
+	go srv.serve()
+	t.Cleanup(func() { ln.Close() })
+	return srv
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) received() []receivedMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedMessage(nil), s.messages...)
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 fake.smtp ESMTP ready")
+	var current receivedMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250-fake.smtp")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(cmd, "AUTH PLAIN"):
+			decoded, _ := base64.StdEncoding.DecodeString(strings.TrimSpace(line[len("AUTH PLAIN"):]))
+			parts := strings.Split(string(decoded), "\x00")
+			if len(parts) == 3 {
+				s.mu.Lock()
+				s.authUser = parts[1]
+				s.mu.Unlock()
+			}
+			reply("235 Authentication successful")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			current = receivedMessage{From: strings.Trim(line[len("MAIL FROM:"):], "<> ")}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			current.To = append(current.To, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dl, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dl == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dl, "."))
+			}
+			current.Data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, current)
+			s.mu.Unlock()
+			reply("250 OK queued")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func newTestStorage(t *testing.T) storage.StorageClient {
+	t.Helper()
+	originalDir, _ := os.Getwd()
+	os.Chdir(t.TempDir())
+	t.Cleanup(func() { os.Chdir(originalDir) })
+
+	client, err := storage.NewLocalStorageClient("")
+	if err != nil {
+		t.Fatalf("failed to create local storage: %v", err)
+	}
+	return client
+}
+
+func newTestSender(t *testing.T, srv *fakeSMTPServer) *Sender {
+	t.Helper()
+	sender, err := NewSender(SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     srv.port(),
+		TLSMode:  TLSNone,
+		Username: "radiocast",
+		Password: "secret",
+		From:     "Radiocast <reports@example.org>",
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewSender failed: %v", err)
+	}
+	return sender
+}
+
+func testReport() *reports.PublishedReport {
+	return &reports.PublishedReport{
+		Data: &models.PropagationData{
+			Timestamp:  time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC),
+			SolarData:  models.SolarData{SolarFluxIndex: 172, SunspotNumber: 174, XRayFlux: "C1.2"},
+			GeomagData: models.GeomagData{KIndex: 3},
+		},
+		Markdown: "{{.SunGif}}\n\n## 📋 Propagation Summary\n\nConditions are **very good** with _high_ flux. See [NOAA](https://swpc.noaa.gov).\n\n{{.GaugePanelChart}}\n\n| Band | Day | Night |\n|------|-----|-------|\n| 20m | Good | Fair |\n\n{{.ForecastChart}}\n",
+		FolderPath: "2025/09/17/PropagationReport-2025-09-17-12-00-00",
+	}
+}
+
+// parseParts splits a multipart body into content type -> decoded payload
+func parseParts(t *testing.T, contentType string, body io.Reader, out map[string][]string) {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("bad content type %q: %v", contentType, err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		data, _ := io.ReadAll(body)
+		out[mediaType] = append(out[mediaType], string(data))
+		return
+	}
+	mr := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("failed to read part: %v", err)
+		}
+		var reader io.Reader = part // quoted-printable is decoded by multipart.Reader
+		if part.Header.Get("Content-Transfer-Encoding") == "base64" {
+			reader = base64.NewDecoder(base64.StdEncoding, part)
+		}
+		parseParts(t, part.Header.Get("Content-Type"), reader, out)
+	}
+}
+
+func TestSender_DeliversMultipartMessage(t *testing.T) {
+	srv := newFakeSMTPServer(t)
+	sender := newTestSender(t, srv)
+
+	msg := &Message{
+		To:      []string{"ham@example.net"},
+		Subject: "Test ✓",
+		Text:    "plain body",
+		HTML:    `<p>html body <img src="cid:img1"></p>`,
+		Inline:  []InlineImage{{ContentID: "img1", Filename: "a.png", ContentType: "image/png", Data: []byte("PNGDATA")}},
+	}
+	if err := sender.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	got := srv.received()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(got))
+	}
+	if got[0].From != "reports@example.org" || len(got[0].To) != 1 || got[0].To[0] != "ham@example.net" {
+		t.Errorf("unexpected envelope: %+v", got[0])
+	}
+	if srv.authUser != "radiocast" {
+		t.Errorf("expected AUTH PLAIN as radiocast, got %q", srv.authUser)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(got[0].Data))
+	if err != nil {
+		t.Fatalf("invalid message: %v", err)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject")); subject != "Test ✓" {
+		t.Errorf("subject = %q", subject)
+	}
+	parts := map[string][]string{}
+	parseParts(t, parsed.Header.Get("Content-Type"), parsed.Body, parts)
+	if len(parts["text/plain"]) != 1 || strings.TrimSpace(parts["text/plain"][0]) != "plain body" {
+		t.Errorf("text part = %v", parts["text/plain"])
+	}
+	if len(parts["text/html"]) != 1 || !strings.Contains(parts["text/html"][0], "cid:img1") {
+		t.Errorf("html part = %v", parts["text/html"])
+	}
+	if len(parts["image/png"]) != 1 || parts["image/png"][0] != "PNGDATA" {
+		t.Errorf("image part = %v", parts["image/png"])
+	}
+}
+
+func TestNewSender_ValidatesConfig(t *testing.T) {
+	if _, err := NewSender(SMTPConfig{Port: 25, From: "a@b.c", TLSMode: TLSNone}); err == nil {
+		t.Error("expected error for missing host")
+	}
+	if _, err := NewSender(SMTPConfig{Host: "h", Port: 25, From: "a@b.c", TLSMode: "ssl"}); err == nil {
+		t.Error("expected error for unknown TLS mode")
+	}
+}
+
+func TestMarkdownToText(t *testing.T) {
+	text := MarkdownToText(testReport().Markdown)
+
+	for _, unwanted := range []string{"{{", "**", "_high_", "|---", "##"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("plaintext still contains %q:\n%s", unwanted, text)
+		}
+	}
+	for _, want := range []string{"📋 Propagation Summary\n---", "very good", "NOAA (https://swpc.noaa.gov)", "20m   Good  Fair"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("plaintext missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestSubscriberStore_DoubleOptIn(t *testing.T) {
+	ctx := context.Background()
+	store := NewSubscriberStore(newTestStorage(t))
+
+	if _, err := store.Subscribe(ctx, "not-an-email"); err != ErrInvalidEmail {
+		t.Errorf("expected ErrInvalidEmail, got %v", err)
+	}
+
+	sub, err := store.Subscribe(ctx, "Op <OP@Example.net>")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if sub.Email != "op@example.net" || sub.Status != StatusPending || sub.ConfirmToken == "" {
+		t.Fatalf("unexpected subscriber: %+v", sub)
+	}
+	if active, _ := store.Active(ctx); len(active) != 0 {
+		t.Errorf("pending subscriber must not be active")
+	}
+
+	if _, err := store.Confirm(ctx, "bogus"); err != ErrTokenNotFound {
+		t.Errorf("expected ErrTokenNotFound, got %v", err)
+	}
+	if _, err := store.Confirm(ctx, sub.ConfirmToken); err != nil {
+		t.Fatalf("Confirm failed: %v", err)
+	}
+	if _, err := store.Confirm(ctx, sub.ConfirmToken); err != ErrTokenNotFound {
+		t.Errorf("confirmation token must be single use, got %v", err)
+	}
+	if active, _ := store.Active(ctx); len(active) != 1 {
+		t.Errorf("expected 1 active subscriber, got %d", len(active))
+	}
+
+	if _, err := store.Unsubscribe(ctx, sub.UnsubscribeToken); err != nil {
+		t.Fatalf("Unsubscribe failed: %v", err)
+	}
+	if _, err := store.Unsubscribe(ctx, sub.UnsubscribeToken); err != nil {
+		t.Errorf("repeated unsubscribe should succeed, got %v", err)
+	}
+	if active, _ := store.Active(ctx); len(active) != 0 {
+		t.Errorf("expected no active subscribers after unsubscribe")
+	}
+}
+
+func TestSubscriberStore_ConcurrentInstances(t *testing.T) {
+	ctx := context.Background()
+	shared := storage.NewMemoryStorageClient()
+	shared.InjectFaults(storage.MemoryFaults{Latency: time.Millisecond})
+	// Two stores stand in for two service instances that only share storage
+	stores := []*SubscriberStore{NewSubscriberStore(shared), NewSubscriberStore(shared)}
+
+	const perStore = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, len(stores)*perStore)
+	for i, store := range stores {
+		for j := 0; j < perStore; j++ {
+			wg.Add(1)
+			go func(store *SubscriberStore, addr string) {
+				defer wg.Done()
+				if _, err := store.Subscribe(ctx, addr); err != nil {
+					errs <- err
+				}
+			}(store, fmt.Sprintf("op%d-%d@example.net", i, j))
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Subscribe failed: %v", err)
+	}
+
+	subs, err := stores[0].List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(subs) != len(stores)*perStore {
+		t.Errorf("expected %d subscribers, got %d: an update was lost", len(stores)*perStore, len(subs))
+	}
+}
+
+func TestSubscriberStore_ExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	store := NewSubscriberStore(newTestStorage(t))
+	store.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	sub, err := store.Subscribe(ctx, "late@example.net")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	store.now = func() time.Time { return time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC) }
+	if _, err := store.Confirm(ctx, sub.ConfirmToken); err != ErrTokenExpired {
+		t.Errorf("expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestSubscriberStore_ResendCooldown(t *testing.T) {
+	ctx := context.Background()
+	store := NewSubscriberStore(newTestStorage(t))
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return start }
+
+	first, err := store.Subscribe(ctx, "busy@example.net")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	store.now = func() time.Time { return start.Add(ConfirmResendInterval - time.Second) }
+	if _, err := store.Subscribe(ctx, "busy@example.net"); err != ErrConfirmationSent {
+		t.Fatalf("expected ErrConfirmationSent inside the resend interval, got %v", err)
+	}
+	if _, err := store.Confirm(ctx, first.ConfirmToken); err != nil {
+		t.Errorf("first token must stay valid during the cooldown, got %v", err)
+	}
+
+	store.Unsubscribe(ctx, first.UnsubscribeToken)
+	again, err := store.Subscribe(ctx, "busy@example.net")
+	if err != nil {
+		t.Fatalf("resubscribing after unsubscribe failed: %v", err)
+	}
+
+	store.now = func() time.Time { return start.Add(ConfirmResendInterval + time.Hour) }
+	resent, err := store.Subscribe(ctx, "busy@example.net")
+	if err != nil {
+		t.Fatalf("Subscribe after the resend interval failed: %v", err)
+	}
+	if resent.ConfirmToken == again.ConfirmToken {
+		t.Error("a resend must issue a fresh confirmation token")
+	}
+}
+
+func TestMailer_ReportPublishedSendsDigest(t *testing.T) {
+	ctx := context.Background()
+	srv := newFakeSMTPServer(t)
+	store := NewSubscriberStore(newTestStorage(t))
+	mailer := NewMailer(newTestSender(t, srv), store, "https://radio.example/")
+
+	sub, _ := store.Subscribe(ctx, "active@example.net")
+	if err := mailer.SendConfirmation(ctx, sub); err != nil {
+		t.Fatalf("SendConfirmation failed: %v", err)
+	}
+	store.Confirm(ctx, sub.ConfirmToken)
+	store.Subscribe(ctx, "pending@example.net")
+
+	if err := mailer.ReportPublished(ctx, testReport()); err != nil {
+		t.Fatalf("ReportPublished failed: %v", err)
+	}
+
+	got := srv.received()
+	if len(got) != 2 {
+		t.Fatalf("expected confirmation + 1 digest, got %d messages", len(got))
+	}
+	if !strings.Contains(got[0].Data, "subscribe/confirm?token=") {
+		t.Errorf("confirmation email lacks confirm link")
+	}
+
+	digest := got[1]
+	if digest.To[0] != "active@example.net" {
+		t.Errorf("digest sent to %v, want only the active subscriber", digest.To)
+	}
+	parsed, err := mail.ReadMessage(strings.NewReader(digest.Data))
+	if err != nil {
+		t.Fatalf("invalid digest: %v", err)
+	}
+	unsubscribeURL := "https://radio.example/unsubscribe?token=" + sub.UnsubscribeToken
+	if parsed.Header.Get("List-Unsubscribe") != "<"+unsubscribeURL+">" {
+		t.Errorf("List-Unsubscribe = %q", parsed.Header.Get("List-Unsubscribe"))
+	}
+
+	parts := map[string][]string{}
+	parseParts(t, parsed.Header.Get("Content-Type"), parsed.Body, parts)
+	if len(parts["image/png"]) != 2 {
+		t.Errorf("expected 2 inline chart images, got %d", len(parts["image/png"]))
+	}
+	html := parts["text/html"][0]
+	if strings.Contains(html, "echarts") || strings.Contains(html, "{{") {
+		t.Error("HTML part must not contain ECharts scripts or placeholders")
+	}
+	for _, want := range []string{"cid:chart-gauge-panel@radiocast", "cid:chart-forecast@radiocast", unsubscribeURL,
+		"https://radio.example/reports/2025/09/17/PropagationReport-2025-09-17-12-00-00/index.html"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML part missing %q", want)
+		}
+	}
+	text := parts["text/plain"][0]
+	if !strings.Contains(text, "Unsubscribe: "+unsubscribeURL) || strings.Contains(text, "{{") {
+		t.Errorf("unexpected text part:\n%s", text)
+	}
+}
@@ -0,0 +1,95 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"radiocast/internal/config"
+	"radiocast/internal/logger"
+	"radiocast/internal/reports"
+	"radiocast/internal/storage"
+)
+
+// Mailer sends confirmation emails and the report digest to active subscribers
+type Mailer struct {
+	sender  *Sender
+	store   *SubscriberStore
+	baseURL string
+}
+
+// NewMailer creates a mailer. baseURL is the public service URL used in links.
+func NewMailer(sender *Sender, store *SubscriberStore, baseURL string) *Mailer {
+	return &Mailer{sender: sender, store: store, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// NewMailerFromConfig creates a mailer from service configuration
+func NewMailerFromConfig(cfg *config.Config, storageClient storage.StorageClient) (*Mailer, error) {
+	sender, err := NewSender(SMTPConfigFromConfig(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP configuration: %w", err)
+	}
+	return NewMailer(sender, NewSubscriberStore(storageClient), cfg.PublicBaseURL), nil
+}
+
+// Store returns the subscriber store
+func (m *Mailer) Store() *SubscriberStore {
+	return m.store
+}
+
+// ConfirmURL returns the double opt-in link for a token
+func (m *Mailer) ConfirmURL(token string) string {
+	return m.baseURL + "/subscribe/confirm?token=" + url.QueryEscape(token)
+}
+
+// UnsubscribeURL returns the unsubscribe link for a token
+func (m *Mailer) UnsubscribeURL(token string) string {
+	return m.baseURL + "/unsubscribe?token=" + url.QueryEscape(token)
+}
+
+// SendConfirmation emails the double opt-in link to a pending subscriber
+func (m *Mailer) SendConfirmation(ctx context.Context, sub *Subscriber) error {
+	if sub.Status != StatusPending {
+		return nil
+	}
+	if err := m.sender.Send(ctx, ConfirmationMessage(sub.Email, m.ConfirmURL(sub.ConfirmToken))); err != nil {
+		return fmt.Errorf("failed to send confirmation email: %w", err)
+	}
+	return nil
+}
+
+// ReportPublished implements reports.PublishHook: it emails the digest to every active subscriber
+func (m *Mailer) ReportPublished(ctx context.Context, report *reports.PublishedReport) error {
+	subs, err := m.store.Active(ctx)
+	if err != nil {
+		return err
+	}
+	if len(subs) == 0 {
+		logger.Debug("No active email subscribers, skipping digest")
+		return nil
+	}
+
+	digest, err := BuildDigest(report, m.baseURL)
+	if err != nil {
+		return fmt.Errorf("failed to build digest: %w", err)
+	}
+
+	failed := 0
+	for _, sub := range subs {
+		if err := m.sender.Send(ctx, digest.MessageFor(sub.Email, m.UnsubscribeURL(sub.UnsubscribeToken))); err != nil {
+			failed++
+			logger.Warn("Failed to send email digest", map[string]interface{}{"error": err.Error()})
+		}
+	}
+
+	logger.Info("Email digest sent", map[string]interface{}{
+		"recipients": len(subs),
+		"failed":     failed,
+		"images":     len(digest.Inline),
+	})
+	if failed > 0 {
+		return fmt.Errorf("%d of %d digest emails failed", failed, len(subs))
+	}
+	return nil
+}
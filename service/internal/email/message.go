@@ -0,0 +1,185 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// InlineImage is an image attached to the HTML part and referenced as cid:<ContentID>
+type InlineImage struct {
+	ContentID   string
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Message is a multipart email with a plaintext and an optional HTML alternative
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+	Inline  []InlineImage
+	Headers map[string]string // Extra headers such as List-Unsubscribe
+	Date    time.Time
+}
+
+// Bytes renders the message in RFC 5322 format.
+// Structure: multipart/alternative { text/plain, multipart/related { text/html, images... } }
+func (m *Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+
+	date := m.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	messageID, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{
+		"From":         m.From,
+		"To":           strings.Join(m.To, ", "),
+		"Subject":      mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date":         date.UTC().Format(time.RFC1123Z),
+		"Message-ID":   fmt.Sprintf("<%s@%s>", messageID, domainOf(m.From)),
+		"MIME-Version": "1.0",
+	}
+	for k, v := range m.Headers {
+		headers[k] = v
+	}
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "%s: %s\r\n", k, headers[k])
+	}
+
+	if m.HTML == "" {
+		fmt.Fprintf(&buf, "Content-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, m.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	alternative := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", alternative.Boundary())
+
+	textPart, err := alternative.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeQuotedPrintable(textPart, m.Text); err != nil {
+		return nil, err
+	}
+
+	if err := m.writeRelated(alternative); err != nil {
+		return nil, err
+	}
+	if err := alternative.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeRelated writes the HTML part together with its inline images
+func (m *Message) writeRelated(alternative *multipart.Writer) error {
+	var relatedBuf bytes.Buffer
+	related := multipart.NewWriter(&relatedBuf)
+
+	htmlPart, err := related.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeQuotedPrintable(htmlPart, m.HTML); err != nil {
+		return err
+	}
+
+	for _, img := range m.Inline {
+		part, err := related.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {img.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-ID":                {"<" + img.ContentID + ">"},
+			"Content-Disposition":       {fmt.Sprintf("inline; filename=%q", img.Filename)},
+		})
+		if err != nil {
+			return err
+		}
+		if err := writeBase64(part, img.Data); err != nil {
+			return err
+		}
+	}
+	if err := related.Close(); err != nil {
+		return err
+	}
+
+	part, err := alternative.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {fmt.Sprintf("multipart/related; boundary=%s", related.Boundary())},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(relatedBuf.Bytes())
+	return err
+}
+
+// writeQuotedPrintable encodes s (the text-mode writer emits CRLF line breaks)
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(s)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeBase64 encodes data in 76-character lines
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := fmt.Fprintf(w, "%s\r\n", encoded[:76]); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := fmt.Fprintf(w, "%s\r\n", encoded)
+	return err
+}
+
+// randomID returns a random hex identifier
+func randomID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate message ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// domainOf returns the domain part of an address, used for Message-ID
+func domainOf(addr string) string {
+	addr = addressOnly(addr)
+	if i := strings.LastIndex(addr, "@"); i >= 0 && i < len(addr)-1 {
+		return addr[i+1:]
+	}
+	return "radiocast.local"
+}
@@ -0,0 +1,195 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"radiocast/internal/config"
+)
+
+// TLSMode selects how the SMTP connection is secured
+type TLSMode string
+
+const (
+	TLSNone     TLSMode = "none"     // Plain SMTP (local relays and test servers only)
+	TLSStartTLS TLSMode = "starttls" // Upgrade with STARTTLS, usually on port 587
+	TLSImplicit TLSMode = "tls"      // TLS from the first byte, usually on port 465
+)
+
+// SMTPConfig holds SMTP connection settings
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	TLSMode  TLSMode
+	Username string
+	Password string
+	From     string
+
+	// TLSConfig overrides the default TLS settings (optional)
+	TLSConfig *tls.Config
+	// Timeout bounds dialing and the whole SMTP conversation (default 30s)
+	Timeout time.Duration
+}
+
+// SMTPConfigFromConfig builds SMTP settings from service configuration
+func SMTPConfigFromConfig(cfg *config.Config) SMTPConfig {
+	return SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		TLSMode:  TLSMode(strings.ToLower(cfg.SMTPTLSMode)),
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	}
+}
+
+// Validate checks that the settings are usable
+func (c SMTPConfig) Validate() error {
+	if c.Host == "" {
+		return fmt.Errorf("SMTP host is required")
+	}
+	if c.Port <= 0 {
+		return fmt.Errorf("invalid SMTP port %d", c.Port)
+	}
+	if c.From == "" {
+		return fmt.Errorf("SMTP from address is required")
+	}
+	switch c.TLSMode {
+	case TLSNone, TLSStartTLS, TLSImplicit:
+	default:
+		return fmt.Errorf("unsupported SMTP TLS mode %q (expected none, starttls or tls)", c.TLSMode)
+	}
+	return nil
+}
+
+// Sender delivers messages through an SMTP server
+type Sender struct {
+	cfg SMTPConfig
+}
+
+// NewSender creates an SMTP sender
+func NewSender(cfg SMTPConfig) (*Sender, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &Sender{cfg: cfg}, nil
+}
+
+// From returns the configured sender address
+func (s *Sender) From() string {
+	return s.cfg.From
+}
+
+// Send delivers one message to all of its recipients
+func (s *Sender) Send(ctx context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return fmt.Errorf("message has no recipients")
+	}
+	if msg.From == "" {
+		msg.From = s.cfg.From
+	}
+	raw, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer client.Close()
+
+	if s.cfg.TLSMode == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server %s does not support STARTTLS", s.cfg.Host)
+		}
+		if err := client.StartTLS(s.tlsConfig()); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+
+	if s.cfg.Username != "" {
+		auth := smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(addressOnly(msg.From)); err != nil {
+		return fmt.Errorf("MAIL FROM rejected: %w", err)
+	}
+	for _, rcpt := range msg.To {
+		if err := client.Rcpt(addressOnly(rcpt)); err != nil {
+			return fmt.Errorf("RCPT TO %s rejected: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("DATA rejected: %w", err)
+	}
+	if _, err := w.Write(raw); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("message rejected: %w", err)
+	}
+	return client.Quit()
+}
+
+// dial opens the connection and performs the SMTP greeting
+func (s *Sender) dial(ctx context.Context) (*smtp.Client, net.Conn, error) {
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	dialer := &net.Dialer{Timeout: s.cfg.Timeout}
+
+	var conn net.Conn
+	var err error
+	if s.cfg.TLSMode == TLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: s.tlsConfig()}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+
+	deadline := time.Now().Add(s.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("SMTP handshake failed: %w", err)
+	}
+	return client, conn, nil
+}
+
+// tlsConfig returns the TLS settings for the configured host
+func (s *Sender) tlsConfig() *tls.Config {
+	if s.cfg.TLSConfig != nil {
+		return s.cfg.TLSConfig
+	}
+	return &tls.Config{ServerName: s.cfg.Host, MinVersion: tls.VersionTLS12}
+}
+
+// addressOnly extracts "user@host" from "Name <user@host>"
+func addressOnly(addr string) string {
+	if start := strings.LastIndex(addr, "<"); start >= 0 {
+		if end := strings.LastIndex(addr, ">"); end > start {
+			return addr[start+1 : end]
+		}
+	}
+	return strings.TrimSpace(addr)
+}
@@ -0,0 +1,296 @@
+package email
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"radiocast/internal/storage"
+)
+
+// subscribersPath is where the subscriber list is stored
+const subscribersPath = "subscribers/subscribers.json"
+
+// ConfirmTokenTTL is how long a double opt-in confirmation link stays valid
+const ConfirmTokenTTL = 7 * 24 * time.Hour
+
+// ConfirmResendInterval is the minimum time between two confirmation emails to the same
+// pending address, so the public subscribe endpoint cannot be used to flood a mailbox
+const ConfirmResendInterval = 15 * time.Minute
+
+var (
+	ErrInvalidEmail     = errors.New("invalid email address")
+	ErrTokenNotFound    = errors.New("token not found")
+	ErrTokenExpired     = errors.New("confirmation token expired")
+	ErrConfirmationSent = errors.New("confirmation email recently sent")
+)
+
+// SubscriberStatus is the double opt-in state of a subscriber
+type SubscriberStatus string
+
+const (
+	StatusPending      SubscriberStatus = "pending"
+	StatusActive       SubscriberStatus = "active"
+	StatusUnsubscribed SubscriberStatus = "unsubscribed"
+)
+
+// Subscriber is one digest recipient
+type Subscriber struct {
+	Email            string           `json:"email"`
+	Status           SubscriberStatus `json:"status"`
+	ConfirmToken     string           `json:"confirm_token,omitempty"`
+	UnsubscribeToken string           `json:"unsubscribe_token"`
+	CreatedAt        time.Time        `json:"created_at"`
+	ConfirmSentAt    time.Time        `json:"confirm_sent_at"`
+	ConfirmedAt      *time.Time       `json:"confirmed_at,omitempty"`
+	UnsubscribedAt   *time.Time       `json:"unsubscribed_at,omitempty"`
+}
+
+// SubscriberStore keeps the subscriber list as a JSON document in storage. Several service
+// instances may share the list: every change is a read-modify-write that only stores the list
+// if nobody else wrote it since it was read (StoreFileIfMatch), and starts over otherwise.
+type SubscriberStore struct {
+	storage storage.StorageClient
+	mu      sync.Mutex // keeps this instance's own updates from conflicting with each other
+	now     func() time.Time
+}
+
+// maxUpdateAttempts bounds the retries of an update that lost the race against another instance
+const maxUpdateAttempts = 10
+
+// NewSubscriberStore creates a store backed by the given storage client
+func NewSubscriberStore(storageClient storage.StorageClient) *SubscriberStore {
+	return &SubscriberStore{storage: storageClient, now: time.Now}
+}
+
+// Subscribe registers an address as pending and issues a fresh confirmation token.
+// Already active subscribers are returned unchanged. ErrConfirmationSent is returned while a
+// pending address is within ConfirmResendInterval of its last confirmation email.
+func (s *SubscriberStore) Subscribe(ctx context.Context, address string) (*Subscriber, error) {
+	addr, err := normalizeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Subscriber
+	err = s.update(ctx, func(subs []*Subscriber) ([]*Subscriber, bool, error) {
+		now := s.now().UTC()
+		sub := findSubscriber(subs, func(sub *Subscriber) bool { return sub.Email == addr })
+		if sub == nil {
+			unsubscribeToken, err := newToken()
+			if err != nil {
+				return nil, false, err
+			}
+			subs = append(subs, &Subscriber{Email: addr, CreatedAt: now, UnsubscribeToken: unsubscribeToken})
+			sub = subs[len(subs)-1]
+		}
+		if sub.Status == StatusActive {
+			result = *sub
+			return subs, false, nil
+		}
+		if sub.Status == StatusPending && now.Sub(sub.ConfirmSentAt) < ConfirmResendInterval {
+			return nil, false, ErrConfirmationSent
+		}
+
+		confirmToken, err := newToken()
+		if err != nil {
+			return nil, false, err
+		}
+		sub.Status = StatusPending
+		sub.ConfirmToken = confirmToken
+		sub.ConfirmSentAt = now
+		sub.UnsubscribedAt = nil
+		result = *sub
+		return subs, true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Confirm activates the subscriber holding the confirmation token
+func (s *SubscriberStore) Confirm(ctx context.Context, token string) (*Subscriber, error) {
+	var result Subscriber
+	err := s.update(ctx, func(subs []*Subscriber) ([]*Subscriber, bool, error) {
+		sub := findSubscriber(subs, func(sub *Subscriber) bool { return token != "" && sub.ConfirmToken == token })
+		if sub == nil {
+			return nil, false, ErrTokenNotFound
+		}
+		now := s.now().UTC()
+		if now.Sub(sub.ConfirmSentAt) > ConfirmTokenTTL {
+			return nil, false, ErrTokenExpired
+		}
+
+		sub.Status = StatusActive
+		sub.ConfirmToken = ""
+		sub.ConfirmedAt = &now
+		result = *sub
+		return subs, true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Unsubscribe deactivates the subscriber holding the unsubscribe token. Repeated calls succeed.
+func (s *SubscriberStore) Unsubscribe(ctx context.Context, token string) (*Subscriber, error) {
+	var result Subscriber
+	err := s.update(ctx, func(subs []*Subscriber) ([]*Subscriber, bool, error) {
+		sub := findSubscriber(subs, func(sub *Subscriber) bool { return token != "" && sub.UnsubscribeToken == token })
+		if sub == nil {
+			return nil, false, ErrTokenNotFound
+		}
+		changed := sub.Status != StatusUnsubscribed
+		if changed {
+			now := s.now().UTC()
+			sub.Status = StatusUnsubscribed
+			sub.ConfirmToken = ""
+			sub.UnsubscribedAt = &now
+		}
+		result = *sub
+		return subs, changed, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindByUnsubscribeToken returns the subscriber holding the unsubscribe token without changing it
+func (s *SubscriberStore) FindByUnsubscribeToken(ctx context.Context, token string) (*Subscriber, error) {
+	subs, _, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sub := findSubscriber(subs, func(sub *Subscriber) bool { return token != "" && sub.UnsubscribeToken == token })
+	if sub == nil {
+		return nil, ErrTokenNotFound
+	}
+	result := *sub
+	return &result, nil
+}
+
+// List returns all subscribers sorted by address
+func (s *SubscriberStore) List(ctx context.Context) ([]Subscriber, error) {
+	subs, _, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Subscriber, 0, len(subs))
+	for _, sub := range subs {
+		out = append(out, *sub)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Email < out[j].Email })
+	return out, nil
+}
+
+// Active returns confirmed subscribers
+func (s *SubscriberStore) Active(ctx context.Context) ([]Subscriber, error) {
+	all, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var active []Subscriber
+	for _, sub := range all {
+		if sub.Status == StatusActive {
+			active = append(active, sub)
+		}
+	}
+	return active, nil
+}
+
+// update applies change to the current subscriber list and stores the result if change asks
+// for it. When another instance wrote the list in the meantime, the update starts over from
+// the newer list.
+func (s *SubscriberStore) update(ctx context.Context, change func(subs []*Subscriber) ([]*Subscriber, bool, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		subs, etag, err := s.load(ctx)
+		if err != nil {
+			return err
+		}
+		subs, save, err := change(subs)
+		if err != nil || !save {
+			return err
+		}
+		err = s.save(ctx, subs, etag)
+		if !errors.Is(err, storage.ErrPreconditionFailed) || attempt == maxUpdateAttempts {
+			return err
+		}
+	}
+}
+
+// load reads the subscriber list and the ETag it was read at; a missing file means no
+// subscribers and an empty ETag
+func (s *SubscriberStore) load(ctx context.Context) ([]*Subscriber, string, error) {
+	info, err := s.storage.Stat(ctx, subscribersPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to check subscriber list: %w", err)
+	}
+	// Read after Stat: if the list changes in between, the save with the older ETag fails
+	raw, err := s.storage.GetFile(ctx, subscribersPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read subscriber list: %w", err)
+	}
+	var subs []*Subscriber
+	if err := json.Unmarshal(raw, &subs); err != nil {
+		return nil, "", fmt.Errorf("failed to parse subscriber list: %w", err)
+	}
+	return subs, info.ETag, nil
+}
+
+// save writes the subscriber list back to storage if it is still at etag
+func (s *SubscriberStore) save(ctx context.Context, subs []*Subscriber, etag string) error {
+	raw, err := json.MarshalIndent(subs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscriber list: %w", err)
+	}
+	if err := s.storage.StoreFileIfMatch(ctx, subscribersPath, raw, etag); err != nil {
+		return fmt.Errorf("failed to store subscriber list: %w", err)
+	}
+	return nil
+}
+
+// findSubscriber returns the first subscriber matching the predicate
+func findSubscriber(subs []*Subscriber, match func(*Subscriber) bool) *Subscriber {
+	for _, sub := range subs {
+		if match(sub) {
+			return sub
+		}
+	}
+	return nil
+}
+
+// normalizeAddress validates an address and returns its lowercase bare form
+func normalizeAddress(address string) (string, error) {
+	parsed, err := mail.ParseAddress(strings.TrimSpace(address))
+	if err != nil || !strings.Contains(parsed.Address, "@") {
+		return "", ErrInvalidEmail
+	}
+	return strings.ToLower(parsed.Address), nil
+}
+
+// newToken returns a random URL-safe token
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
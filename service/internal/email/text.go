@@ -0,0 +1,143 @@
+package email
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{\{\s*\.[A-Za-z]+\s*\}\}`)
+	imagePattern       = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	linkPattern        = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	boldPattern        = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	italicPattern      = regexp.MustCompile(`(^|[\s(])[*_]([^*_\n]+)[*_]([\s.,;:!?)]|$)`)
+	htmlTagPattern     = regexp.MustCompile(`<[^>]+>`)
+	tableRulePattern   = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?$`)
+	blankLinesPattern  = regexp.MustCompile(`\n{3,}`)
+)
+
+// MarkdownToText converts report markdown into readable plaintext: headings are
+// underlined, emphasis markers dropped, links expanded and tables aligned
+func MarkdownToText(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	var out []string
+	var table [][]string
+
+	flushTable := func() {
+		if len(table) > 0 {
+			out = append(out, formatTable(table)...)
+			table = nil
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "|") {
+			if !tableRulePattern.MatchString(trimmed) {
+				table = append(table, splitTableRow(trimmed))
+			}
+			continue
+		}
+		flushTable()
+
+		trimmed = placeholderPattern.ReplaceAllString(trimmed, "")
+		if trimmed == "" && strings.TrimSpace(line) != "" {
+			continue // line held only a chart placeholder
+		}
+
+		if level := headingLevel(trimmed); level > 0 {
+			title := inlineText(strings.TrimSpace(trimmed[level:]))
+			underline := "-"
+			if level == 1 {
+				underline = "="
+			}
+			out = append(out, "", title, strings.Repeat(underline, utf8.RuneCountInString(title)))
+			continue
+		}
+
+		if trimmed == "---" || trimmed == "***" {
+			out = append(out, strings.Repeat("-", 40))
+			continue
+		}
+
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") {
+			trimmed = "• " + trimmed[2:]
+		}
+		out = append(out, indent+inlineText(trimmed))
+	}
+	flushTable()
+
+	text := strings.Join(out, "\n")
+	text = blankLinesPattern.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text) + "\n"
+}
+
+// inlineText strips inline markdown and HTML from a single line
+func inlineText(s string) string {
+	s = imagePattern.ReplaceAllString(s, "$1")
+	s = linkPattern.ReplaceAllString(s, "$1 ($2)")
+	s = boldPattern.ReplaceAllString(s, "$2")
+	s = italicPattern.ReplaceAllString(s, "$1$2$3")
+	s = strings.ReplaceAll(s, "`", "")
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	return s
+}
+
+// headingLevel returns the ATX heading level of a line, or 0
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ') {
+		return 0
+	}
+	return level
+}
+
+// splitTableRow splits "| a | b |" into cells
+func splitTableRow(row string) []string {
+	row = strings.TrimPrefix(strings.TrimSuffix(row, "|"), "|")
+	cells := strings.Split(row, "|")
+	for i, c := range cells {
+		cells[i] = inlineText(strings.TrimSpace(c))
+	}
+	return cells
+}
+
+// formatTable pads table cells into aligned columns
+func formatTable(rows [][]string) []string {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	lines := make([]string, 0, len(rows)+1)
+	for r, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2))
+			}
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+		if r == 0 && len(rows) > 1 {
+			total := 0
+			for _, w := range widths {
+				total += w + 2
+			}
+			lines = append(lines, strings.Repeat("-", total-2))
+		}
+	}
+	return lines
+}
@@ -1,10 +1,13 @@
 package fetchers
 
 import (
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"radiocast/internal/geo"
 	"radiocast/internal/logger"
 	"radiocast/internal/models"
 
@@ -195,6 +198,145 @@ func (n *DataNormalizer) NormalizeData(kIndex []models.NOAAKIndexResponse, solar
 
 // Removed GenerateBasicForecast - let LLM handle all forecasting and analysis
 
+// IonosondeMinConfidence is the lowest autoscaling confidence score of a reported ionogram
+const IonosondeMinConfidence = 25
+
+// NormalizeIonosondes keeps the usable readings of the IonosondeWindow before end: foF2 and
+// MUF(3000) scaled with enough confidence. Each station gets its latest reading and the latest of
+// every hour; stations without a usable reading are left out.
+func (n *DataNormalizer) NormalizeIonosondes(stations []models.IonosondeStation, end time.Time) []models.Ionosonde {
+	start := end.Add(-IonosondeWindow)
+	var result []models.Ionosonde
+	for _, station := range stations {
+		var readings []models.IonosondeReading
+		for _, r := range station.Readings {
+			if r.FoF2 <= 0 || r.MUF3000 <= 0 || (r.Confidence >= 0 && r.Confidence < IonosondeMinConfidence) {
+				continue
+			}
+			if r.Time.After(start) && !r.Time.After(end) {
+				readings = append(readings, r)
+			}
+		}
+		if len(readings) == 0 {
+			continue
+		}
+		sort.SliceStable(readings, func(i, j int) bool { return readings[i].Time.Before(readings[j].Time) })
+
+		hourly := []models.IonosondeReading{}
+		for i, r := range readings {
+			if i == len(readings)-1 || !readings[i+1].Time.Truncate(time.Hour).Equal(r.Time.Truncate(time.Hour)) {
+				hourly = append(hourly, r)
+			}
+		}
+		result = append(result, models.Ionosonde{
+			Code:      station.Code,
+			Name:      station.Name,
+			Grid:      geo.GridOf(station.Latitude, station.Longitude),
+			Latitude:  station.Latitude,
+			Longitude: station.Longitude,
+			Latest:    readings[len(readings)-1],
+			Hourly:    hourly,
+			Source:    station.Source,
+		})
+	}
+	return result
+}
+
+// ObservedMinSpots is the fewest spots that make a continent pair an opening; single spots are
+// often false decodes
+const ObservedMinSpots = 3
+
+// NormalizeSpots aggregates the spots between from and to by band and continent pair. Spots
+// outside the amateur bands, with a time outside the window or without 4-character locators are
+// left out. It returns nil without spots.
+func (n *DataNormalizer) NormalizeSpots(spots []models.Spot, sources []string, from, to time.Time) *models.ObservedBands {
+	if len(spots) == 0 {
+		return nil
+	}
+
+	// Locators repeat, the continent of each square is looked up once
+	squares := make(map[string]*geo.Location)
+	continents := make(map[string]string)
+	locate := func(grid string) (*geo.Location, string) {
+		if len(grid) < 4 {
+			return nil, ""
+		}
+		square := strings.ToUpper(grid[:4])
+		if loc, ok := squares[square]; ok {
+			return loc, continents[square]
+		}
+		var result *geo.Location
+		if loc, err := geo.ParseGrid(square); err == nil {
+			result = &loc
+			continents[square] = geo.ZonesOf(loc).Continent
+		}
+		squares[square] = result
+		return result, continents[square]
+	}
+	order := make(map[string]int, len(geo.Continents))
+	for i, c := range geo.Continents {
+		order[c] = i
+	}
+
+	type pairKey struct{ band, from, to string }
+	pairs := make(map[pairKey]*models.ObservedOpening)
+	bandSpots := make(map[string]int)
+	total := 0
+	for _, spot := range spots {
+		if spot.Band == "" || spot.Count <= 0 {
+			continue
+		}
+		if !spot.Time.IsZero() && (!spot.Time.After(from) || spot.Time.After(to)) {
+			continue
+		}
+		tx, txContinent := locate(spot.TxGrid)
+		rx, rxContinent := locate(spot.RxGrid)
+		if tx == nil || rx == nil || txContinent == "" || rxContinent == "" {
+			continue
+		}
+		if order[txContinent] > order[rxContinent] {
+			txContinent, rxContinent = rxContinent, txContinent
+		}
+
+		key := pairKey{spot.Band, txContinent, rxContinent}
+		opening, ok := pairs[key]
+		if !ok {
+			opening = &models.ObservedOpening{From: txContinent, To: rxContinent, BestSNR: spot.SNR}
+			pairs[key] = opening
+		}
+		opening.Spots += spot.Count
+		opening.BestSNR = math.Max(opening.BestSNR, spot.SNR)
+		opening.LongestKm = math.Max(opening.LongestKm, math.Round(geo.Distance(*tx, *rx)))
+		bandSpots[spot.Band] += spot.Count
+		total += spot.Count
+	}
+	if total == 0 {
+		return nil
+	}
+
+	observed := &models.ObservedBands{From: from, To: to, Sources: sources, Spots: total, Bands: []models.ObservedBand{}}
+	for _, b := range models.Bands {
+		if bandSpots[b.Name] == 0 {
+			continue
+		}
+		band := models.ObservedBand{Band: b.Name, Group: models.BandGroup(b.Name), Spots: bandSpots[b.Name], Openings: []models.ObservedOpening{}}
+		for key, opening := range pairs {
+			if key.band == b.Name && opening.Spots >= ObservedMinSpots {
+				band.Openings = append(band.Openings, *opening)
+			}
+		}
+		sort.Slice(band.Openings, func(i, j int) bool {
+			a, c := band.Openings[i], band.Openings[j]
+			if a.Spots != c.Spots {
+				return a.Spots > c.Spots
+			}
+			return a.From+a.To < c.From+c.To
+		})
+		observed.Bands = append(observed.Bands, band)
+	}
+	return observed
+}
+
 // parseTimeMulti attempts to parse time strings with multiple possible layouts
 func parseTimeMulti(s string) (time.Time, error) {
 	layouts := []string{
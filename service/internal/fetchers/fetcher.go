@@ -7,6 +7,7 @@ import (
 
 	"radiocast/internal/logger"
 	"radiocast/internal/models"
+	"radiocast/internal/timeseries"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/mmcdole/gofeed"
@@ -18,7 +19,13 @@ type DataFetcher struct {
 	noaaFetcher *NOAAFetcher
 	n0nbhFetcher *N0NBHFetcher
 	sidcFetcher *SIDCFetcher
+	ionosondeFetcher *IonosondeFetcher
+	spotsFetcher *SpotsFetcher
 	normalizer  *DataNormalizer
+	history     *timeseries.Store // nil unless SetHistoryStore was called
+	historyOpts HistoryOptions
+	ionosondes  IonosondeOptions  // no stations unless SetIonosondes was called
+	spots       SpotOptions       // no sources unless SetSpots was called
 }
 
 // NewDataFetcher creates a new data fetcher instance
@@ -33,6 +40,8 @@ func NewDataFetcher() *DataFetcher {
 		noaaFetcher:  NewNOAAFetcher(client),
 		n0nbhFetcher: NewN0NBHFetcher(client),
 		sidcFetcher:  NewSIDCFetcher(client),
+		ionosondeFetcher: NewIonosondeFetcher(client),
+		spotsFetcher: NewSpotsFetcher(client),
 		normalizer:   NewDataNormalizer(),
 	}
 }
@@ -46,13 +55,15 @@ func (f *DataFetcher) FetchAllDataWithSources(ctx context.Context, noaaKURL, noa
 	solarChan := make(chan []models.NOAASolarResponse, 1)
 	n0nbhChan := make(chan *models.N0NBHResponse, 1)
 	sidcChan := make(chan []*gofeed.Item, 1)
+	ionosondeChan := make(chan []models.IonosondeStation, 1)
+	spotsChan := make(chan *models.ObservedBands, 1)
 	
 	errChan := make(chan error, 4)
 	
 	// NOAA K-index data
 	go func() {
 		logger.Debug("Fetching NOAA K-index data...")
-		data, err := f.noaaFetcher.FetchKIndex(ctx, noaaKURL)
+		data, err := f.noaaFetcher.FetchKIndexHistory(ctx, noaaKURL)
 		if err != nil {
 			logger.Error("NOAA K-index fetch failed", err)
 			errChan <- fmt.Errorf("NOAA K-index fetch failed: %w", err)
@@ -65,7 +76,7 @@ func (f *DataFetcher) FetchAllDataWithSources(ctx context.Context, noaaKURL, noa
 	// NOAA Solar data
 	go func() {
 		logger.Debug("Fetching NOAA Solar data...")
-		data, err := f.noaaFetcher.FetchSolar(ctx, noaaSolarURL)
+		data, err := f.noaaFetcher.FetchSolarHistory(ctx, noaaSolarURL)
 		if err != nil {
 			logger.Error("NOAA Solar fetch failed", err)
 			errChan <- fmt.Errorf("NOAA Solar fetch failed: %w", err)
@@ -105,14 +116,46 @@ func (f *DataFetcher) FetchAllDataWithSources(ctx context.Context, noaaKURL, noa
 		sidcChan <- data
 	}()
 	
+	// Ionosondes are optional: a failure is logged and the report goes ahead without them
+	sources := 4
+	if len(f.ionosondes.Stations) > 0 {
+		sources++
+		go func() {
+			logger.Debug("Fetching ionosonde data...")
+			data, err := f.FetchIonosondes(ctx, time.Now())
+			if err != nil {
+				logger.Warn("Ionosonde fetch failed", map[string]interface{}{"error": err.Error()})
+			}
+			ionosondeChan <- data
+		}()
+	}
+	
+	// Spots are optional too; they are aggregated right away, the report only gets the openings
+	if len(f.spots.Sources) > 0 {
+		sources++
+		go func() {
+			logger.Debug("Fetching spots...")
+			end := time.Now()
+			spots, spotSources, err := f.FetchSpots(ctx, end)
+			if err != nil {
+				logger.Warn("Spot fetch failed", map[string]interface{}{"error": err.Error()})
+				spotsChan <- nil
+				return
+			}
+			spotsChan <- f.normalizer.NormalizeSpots(spots, spotSources, end.Add(-time.Duration(f.spots.Hours)*time.Hour), end)
+		}()
+	}
+	
 	// Collect results
 	var kIndexData []models.NOAAKIndexResponse
 	var solarData []models.NOAASolarResponse
 	var n0nbhData *models.N0NBHResponse
 	var sidcData []*gofeed.Item
+	var ionosondeData []models.IonosondeStation
+	var observedBands *models.ObservedBands
 	
 	completed := 0
-	for completed < 4 {
+	for completed < sources {
 		select {
 		case data := <-kIndexChan:
 			kIndexData = data
@@ -126,6 +169,12 @@ func (f *DataFetcher) FetchAllDataWithSources(ctx context.Context, noaaKURL, noa
 		case data := <-sidcChan:
 			sidcData = data
 			completed++
+		case data := <-ionosondeChan:
+			ionosondeData = data
+			completed++
+		case data := <-spotsChan:
+			observedBands = data
+			completed++
 		case err := <-errChan:
 			logger.Error("Data fetch error", err)
 			completed++
@@ -134,22 +183,37 @@ func (f *DataFetcher) FetchAllDataWithSources(ctx context.Context, noaaKURL, noa
 		}
 	}
 	
+	// Keep the full fetched history when a store is configured, the report gets the recent part
+	if f.history != nil {
+		f.recordHistory(ctx, kIndexData, solarData, n0nbhData, time.Now())
+	}
+	kIndexData = f.noaaFetcher.filterKIndexRecent(kIndexData)
+	solarData = f.noaaFetcher.filterSolarRecent(solarData)
+	
 	// Create source data structure
 	sourceData := &models.SourceData{
 		NOAAKIndex: kIndexData,
 		NOAASolar:  solarData,
 		N0NBH:      n0nbhData,
 		SIDC:       sidcData,
+		Ionosondes: ionosondeData,
 	}
 	
 	// Normalize and combine all data
 	propagationData := f.normalizer.NormalizeData(kIndexData, solarData, n0nbhData, sidcData)
+	propagationData.Ionosondes = f.normalizer.NormalizeIonosondes(ionosondeData, propagationData.Timestamp)
+	propagationData.ObservedBands = observedBands
+	if f.history != nil {
+		f.applyHistory(ctx, propagationData)
+	}
 	
 	logger.Debug("Data fetch and normalization completed successfully", map[string]interface{}{
 		"noaa_k_index_points": len(kIndexData),
 		"noaa_solar_points": len(solarData),
 		"n0nbh_available": n0nbhData != nil,
 		"sidc_points": len(sidcData),
+		"ionosondes": len(ionosondeData),
+		"observed_bands": observedBands != nil,
 	})
 	return propagationData, sourceData, nil
 }
@@ -159,3 +223,8 @@ func (f *DataFetcher) FetchAllData(ctx context.Context, noaaKURL, noaaSolarURL,
 	data, _, err := f.FetchAllDataWithSources(ctx, noaaKURL, noaaSolarURL, n0nbhURL, sidcURL)
 	return data, err
 }
+
+// FetchPredictedCycle fetches NOAA's predicted solar cycle (monthly SSN and F10.7)
+func (f *DataFetcher) FetchPredictedCycle(ctx context.Context, url string) ([]models.NOAAPredictedCycleResponse, error) {
+	return f.noaaFetcher.FetchPredictedCycle(ctx, url)
+}
@@ -2,6 +2,8 @@ package fetchers
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 )
@@ -144,3 +146,18 @@ func TestFetchNOAASolarInvalidURL(t *testing.T) {
 		t.Errorf("Expected specific error message, got: %v", err)
 	}
 }
+
+func TestFetchNOAAPredictedCycle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"time-tag":"2025-09","predicted_ssn":118.2,"high_ssn":133.2,"low_ssn":103.2,"predicted_f10.7":148.1,"high_f10.7":163.1,"low_f10.7":133.1}]`))
+	}))
+	defer server.Close()
+
+	data, err := NewDataFetcher().FetchPredictedCycle(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("FetchPredictedCycle failed: %v", err)
+	}
+	if len(data) != 1 || data[0].TimeTag != "2025-09" || data[0].PredictedSSN != 118.2 || data[0].LowF107 != 133.1 || data[0].Source != "NOAA SWPC" {
+		t.Errorf("Unexpected predicted cycle: %+v", data)
+	}
+}
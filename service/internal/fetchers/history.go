@@ -0,0 +1,212 @@
+package fetchers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"radiocast/internal/logger"
+	"radiocast/internal/models"
+	"radiocast/internal/timeseries"
+	"radiocast/internal/trends"
+)
+
+// f107FirstMonth is the first month with F10.7 measurements; NOAA's record fills earlier months
+// with a placeholder that FetchSolarHistory replaces by a default, so they are not recorded
+var f107FirstMonth = time.Date(1947, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+// BandHistoryDays is the default band calendar window handed to reports
+const BandHistoryDays = 30
+
+// HistoryOptions sets the history windows handed to reports when a history store is configured
+type HistoryOptions struct {
+	KIndexHours int // K-index window ending at the latest point (default KIndexHistoryHours)
+	SolarMonths int // number of monthly solar points (default SolarDataHistoryMonths)
+	BandDays    int // days of band conditions ending with the report day (default BandHistoryDays)
+}
+
+// SetHistoryStore makes every fetch upsert its observations into store, and fills the report's
+// historical series from the store so they span opts instead of what a single fetch returns
+func (f *DataFetcher) SetHistoryStore(store *timeseries.Store, opts HistoryOptions) {
+	if opts.KIndexHours <= 0 {
+		opts.KIndexHours = KIndexHistoryHours
+	}
+	if opts.SolarMonths <= 0 {
+		opts.SolarMonths = SolarDataHistoryMonths
+	}
+	if opts.BandDays <= 0 {
+		opts.BandDays = BandHistoryDays
+	}
+	f.history = store
+	f.historyOpts = opts
+}
+
+// recordHistory upserts everything a fetch returned; failures are logged and never fail the fetch
+func (f *DataFetcher) recordHistory(ctx context.Context, kIndex []models.NOAAKIndexResponse, solar []models.NOAASolarResponse, n0nbh *models.N0NBHResponse, timestamp time.Time) {
+	series := HistoryPoints(kIndex, solar, n0nbh, timestamp)
+
+	total := 0
+	for metric, points := range series {
+		changed, err := f.history.Upsert(ctx, metric, points)
+		if err != nil {
+			logger.Warn("Failed to record history", map[string]interface{}{"metric": string(metric), "error": err.Error()})
+			continue
+		}
+		total += changed
+	}
+	logger.Debug("Recorded observation history", map[string]interface{}{"series": len(series), "points_changed": total})
+}
+
+// HistoryPoints converts raw source data into time-series points. N0NBH publishes a snapshot, which
+// is recorded at the hour of timestamp (the fetch time).
+func HistoryPoints(kIndex []models.NOAAKIndexResponse, solar []models.NOAASolarResponse, n0nbh *models.N0NBHResponse, timestamp time.Time) map[timeseries.Metric][]timeseries.Point {
+	series := make(map[timeseries.Metric][]timeseries.Point)
+
+	for _, k := range kIndex {
+		if t, err := parseTimeMulti(k.TimeTag); err == nil {
+			series[timeseries.MetricKp] = append(series[timeseries.MetricKp], timeseries.Point{Time: t, Value: k.KpIndex, Source: k.Source})
+		}
+	}
+	for _, s := range solar {
+		t, err := parseTimeMulti(s.TimeTag)
+		if err != nil {
+			continue
+		}
+		series[timeseries.MetricSSNMonthly] = append(series[timeseries.MetricSSNMonthly], timeseries.Point{Time: t, Value: s.SunspotNumber, Source: s.Source})
+		if !t.Before(f107FirstMonth) {
+			series[timeseries.MetricSFIMonthly] = append(series[timeseries.MetricSFIMonthly], timeseries.Point{Time: t, Value: s.SolarFlux, Source: s.Source})
+		}
+	}
+
+	if n0nbh != nil {
+		hour := timestamp.UTC().Truncate(time.Hour)
+		snapshot := map[timeseries.Metric]string{
+			timeseries.MetricSFI:       n0nbh.SolarData.SolarFlux,
+			timeseries.MetricSSN:       n0nbh.SolarData.SunSpots,
+			timeseries.MetricAIndex:    n0nbh.SolarData.AIndex,
+			timeseries.MetricSolarWind: n0nbh.SolarData.SolarWind,
+		}
+		for metric, raw := range snapshot {
+			if value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64); err == nil {
+				series[metric] = append(series[metric], timeseries.Point{Time: hour, Value: value, Source: n0nbh.Source})
+			}
+		}
+		for _, band := range n0nbh.Calculatedconditions.Band {
+			addBandPoints(series, strings.ToLower(band.Name), band.Day, band.Night, hour, n0nbh.Source)
+		}
+	}
+	return series
+}
+
+// PropagationHistoryPoints converts normalized report data into time-series points, for reports
+// whose raw source files are gone. Bands map back to the N0NBH band groups they were copied from.
+func PropagationHistoryPoints(data *models.PropagationData) map[timeseries.Metric][]timeseries.Point {
+	series := make(map[timeseries.Metric][]timeseries.Point)
+
+	for _, k := range data.HistoricalKIndex {
+		series[timeseries.MetricKp] = append(series[timeseries.MetricKp], timeseries.Point{Time: k.Timestamp, Value: k.KIndex, Source: k.Source})
+	}
+	for _, s := range data.HistoricalSolar {
+		series[timeseries.MetricSSNMonthly] = append(series[timeseries.MetricSSNMonthly], timeseries.Point{Time: s.Timestamp, Value: s.SunspotNumber, Source: s.Source})
+		if !s.Timestamp.Before(f107FirstMonth) {
+			series[timeseries.MetricSFIMonthly] = append(series[timeseries.MetricSFIMonthly], timeseries.Point{Time: s.Timestamp, Value: s.SolarFlux, Source: s.Source})
+		}
+	}
+
+	hour := data.Timestamp.UTC().Truncate(time.Hour)
+	snapshot := []struct {
+		metric timeseries.Metric
+		value  float64
+		source string
+	}{
+		{timeseries.MetricSFI, data.SolarData.SolarFluxIndex, data.SolarData.SolarFluxDataSource},
+		{timeseries.MetricSSN, float64(data.SolarData.SunspotNumber), data.SolarData.SunspotDataSource},
+		{timeseries.MetricAIndex, data.GeomagData.AIndex, data.GeomagData.AIndexDataSource},
+		{timeseries.MetricSolarWind, data.SolarData.SolarWindSpeed, data.SolarData.SolarWindDataSource},
+	}
+	for _, s := range snapshot {
+		if s.source != "" {
+			series[s.metric] = append(series[s.metric], timeseries.Point{Time: hour, Value: s.value, Source: s.source})
+		}
+	}
+
+	for _, group := range data.BandData.Groups() {
+		addBandPoints(series, group.Group, group.Day, group.Night, hour, data.BandData.BandDataSource)
+	}
+	return series
+}
+
+// addBandPoints adds the day and night condition of a band group, skipping unknown conditions
+func addBandPoints(series map[timeseries.Metric][]timeseries.Point, group, day, night string, t time.Time, source string) {
+	if value, ok := models.ConditionValue(day); ok {
+		metric := timeseries.BandMetric(group, "day")
+		series[metric] = append(series[metric], timeseries.Point{Time: t, Value: float64(value), Source: source})
+	}
+	if value, ok := models.ConditionValue(night); ok {
+		metric := timeseries.BandMetric(group, "night")
+		series[metric] = append(series[metric], timeseries.Point{Time: t, Value: float64(value), Source: source})
+	}
+}
+
+// applyHistory replaces the report's historical series with the configured windows from the store
+func (f *DataFetcher) applyHistory(ctx context.Context, data *models.PropagationData) {
+	// K-index: the window ends at the newest point, like filterKIndexRecent
+	kp, err := f.history.Query(ctx, timeseries.MetricKp, time.Time{}, time.Time{})
+	if err != nil {
+		logger.Warn("Failed to read K-index history", map[string]interface{}{"error": err.Error()})
+	} else if len(kp) > 0 {
+		cutoff := kp[len(kp)-1].Time.Add(-time.Duration(f.historyOpts.KIndexHours) * time.Hour)
+		var points []models.KIndexPoint
+		for _, p := range kp {
+			if !p.Time.Before(cutoff) {
+				points = append(points, models.KIndexPoint{Timestamp: p.Time, KIndex: p.Value, EstimatedKp: p.Value, Source: p.Source})
+			}
+		}
+		data.HistoricalKIndex = points
+	}
+
+	// Band conditions by day, ending with the report day
+	end := data.Timestamp
+	if end.IsZero() {
+		end = time.Now()
+	}
+	calendar, err := trends.BuildBandCalendar(ctx, f.history, end.AddDate(0, 0, 1-f.historyOpts.BandDays), end)
+	if err != nil {
+		logger.Warn("Failed to read band condition history", map[string]interface{}{"error": err.Error()})
+	} else if len(calendar.Rows) > 0 {
+		data.BandHistory = calendar
+	}
+
+	// Monthly solar data: the last SolarMonths months with a sunspot number, joined with their flux
+	ssn, err := f.history.Query(ctx, timeseries.MetricSSNMonthly, time.Time{}, time.Time{})
+	if err != nil || len(ssn) == 0 {
+		if err != nil {
+			logger.Warn("Failed to read solar history", map[string]interface{}{"error": err.Error()})
+		}
+		return
+	}
+	if len(ssn) > f.historyOpts.SolarMonths {
+		ssn = ssn[len(ssn)-f.historyOpts.SolarMonths:]
+	}
+	sfi, err := f.history.Query(ctx, timeseries.MetricSFIMonthly, ssn[0].Time, time.Time{})
+	if err != nil {
+		logger.Warn("Failed to read solar history", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	flux := make(map[int64]float64, len(sfi))
+	for _, p := range sfi {
+		flux[p.Time.Unix()] = p.Value
+	}
+	points := make([]models.SolarPoint, 0, len(ssn))
+	for _, p := range ssn {
+		points = append(points, models.SolarPoint{
+			Timestamp:         p.Time,
+			SolarFlux:         flux[p.Time.Unix()],
+			SolarFluxAdjusted: flux[p.Time.Unix()],
+			SunspotNumber:     p.Value,
+			Source:            p.Source,
+		})
+	}
+	data.HistoricalSolar = points
+}
@@ -0,0 +1,96 @@
+package fetchers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+	"radiocast/internal/storage"
+	"radiocast/internal/timeseries"
+)
+
+// kIndexRun returns the 3-hourly NOAA K-index points of days [from, to) of September 2025
+func kIndexRun(from, to int) []models.NOAAKIndexResponse {
+	var points []models.NOAAKIndexResponse
+	for day := from; day < to; day++ {
+		for hour := 0; hour < 24; hour += 3 {
+			points = append(points, models.NOAAKIndexResponse{
+				TimeTag: fmt.Sprintf("2025-09-%02dT%02d:00:00", day, hour),
+				KpIndex: float64(day),
+				Source:  "NOAA SWPC",
+			})
+		}
+	}
+	return points
+}
+
+func TestHistoryStore_RecordAndApply(t *testing.T) {
+	ctx := context.Background()
+	store := timeseries.NewStore(storage.NewMemoryStorageClient())
+	fetcher := NewDataFetcher()
+	fetcher.SetHistoryStore(store, HistoryOptions{KIndexHours: 10 * 24, SolarMonths: 3})
+
+	n0nbh := &models.N0NBHResponse{Source: "N0NBH"}
+	n0nbh.SolarData.SolarFlux = "155"
+	n0nbh.SolarData.SunSpots = "n/a"
+	n0nbh.Calculatedconditions.Band = append(n0nbh.Calculatedconditions.Band, struct {
+		Name   string `json:"name"`
+		Time   string `json:"time"`
+		Day    string `json:"day"`
+		Night  string `json:"night"`
+		Source string `json:"source"`
+	}{Name: "12m-10m", Day: "Good", Night: "Poor"})
+
+	solar := []models.NOAASolarResponse{
+		{TimeTag: "1946-12", SolarFlux: 100, SunspotNumber: 90, Source: "NOAA SWPC"},
+		{TimeTag: "2025-05", SolarFlux: 140, SunspotNumber: 110, Source: "NOAA SWPC"},
+		{TimeTag: "2025-06", SolarFlux: 130, SunspotNumber: 100, Source: "NOAA SWPC"},
+		{TimeTag: "2025-07", SolarFlux: 135, SunspotNumber: 105, Source: "NOAA SWPC"},
+		{TimeTag: "2025-08", SolarFlux: 150, SunspotNumber: 120, Source: "NOAA SWPC"},
+	}
+
+	// Two fetches a week apart: together they cover more than either feed
+	fetchedAt := time.Date(2025, 9, 14, 12, 30, 0, 0, time.UTC)
+	fetcher.recordHistory(ctx, kIndexRun(1, 8), solar, n0nbh, fetchedAt)
+	fetcher.recordHistory(ctx, kIndexRun(7, 15), solar, nil, fetchedAt)
+
+	kp, _ := store.Query(ctx, timeseries.MetricKp, time.Time{}, time.Time{})
+	if len(kp) != 14*8 {
+		t.Errorf("Recorded %d K-index points, want %d without duplicates", len(kp), 14*8)
+	}
+	if sfi, _ := store.Query(ctx, timeseries.MetricSFIMonthly, time.Time{}, time.Time{}); len(sfi) != 4 {
+		t.Errorf("Recorded %d monthly flux points, want 4 (no placeholder before 1947)", len(sfi))
+	}
+	if ssn, _ := store.Query(ctx, timeseries.MetricSSN, time.Time{}, time.Time{}); len(ssn) != 0 {
+		t.Errorf("Recorded unparsable N0NBH sunspots: %v", ssn)
+	}
+	band, _ := store.Query(ctx, timeseries.BandMetric("12m-10m", "day"), time.Time{}, time.Time{})
+	if len(band) != 1 || band[0].Value != 3 || !band[0].Time.Equal(fetchedAt.Truncate(time.Hour)) {
+		t.Errorf("Recorded band condition = %v, want Good (3) at 12:00", band)
+	}
+
+	// The report windows come from the store, not from the last fetch
+	data := &models.PropagationData{}
+	fetcher.applyHistory(ctx, data)
+	if len(data.HistoricalKIndex) != 10*8+1 {
+		t.Errorf("K-index window has %d points, want %d", len(data.HistoricalKIndex), 10*8+1)
+	}
+	if last := data.HistoricalKIndex[len(data.HistoricalKIndex)-1]; last.KIndex != 14 {
+		t.Errorf("Latest K-index point = %+v, want Kp 14", last)
+	}
+	if len(data.HistoricalSolar) != 3 || data.HistoricalSolar[0].SunspotNumber != 100 || data.HistoricalSolar[2].SolarFlux != 150 {
+		t.Errorf("Solar window = %+v, want June to August 2025", data.HistoricalSolar)
+	}
+
+	// The band calendar ends with the report day
+	data = &models.PropagationData{Timestamp: fetchedAt.AddDate(0, 0, 1)}
+	fetcher.applyHistory(ctx, data)
+	if data.BandHistory == nil || len(data.BandHistory.Days) != BandHistoryDays || len(data.BandHistory.Rows) != 2 {
+		t.Fatalf("Band history = %+v, want %d days of 12m-10m day and night", data.BandHistory, BandHistoryDays)
+	}
+	if values := data.BandHistory.Rows[0].Values; values[len(values)-2] == nil || *values[len(values)-2] != 3 || values[len(values)-1] != nil {
+		t.Errorf("12m-10m day calendar ends with %v, want Good (3) the day before the report", values[len(values)-2:])
+	}
+}
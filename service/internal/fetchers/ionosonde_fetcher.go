@@ -0,0 +1,343 @@
+package fetchers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"radiocast/internal/logger"
+	"radiocast/internal/models"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Ionosonde sources
+const (
+	IonosondeSourceGIRO = "giro" // GIRO DIDBase scaled characteristics, one request per station
+	IonosondeSourceKC2G = "kc2g" // prop.kc2g.com-style stations.json with the latest reading of every station
+
+	GIRODefaultURL = "https://lgdc.uml.edu/common/DIDBGetValues"
+	KC2GDefaultURL = "https://prop.kc2g.com/api/stations.json"
+)
+
+// IonosondeWindow is how far back ionosonde readings are fetched and reported
+const IonosondeWindow = 24 * time.Hour
+
+// giroLocation matches the station line of a DIDBase response, e.g.
+// "# Location: GEO 40.0N 254.7E, URSI-Code BC840 BOULDER"
+var giroLocation = regexp.MustCompile(`GEO\s+([\d.]+)([NS])\s+([\d.]+)([EW]),\s*URSI-Code\s+(\w+)\s*(.*)`)
+
+// IonosondeFetcher fetches autoscaled ionogram parameters
+type IonosondeFetcher struct {
+	client *resty.Client
+}
+
+// NewIonosondeFetcher creates a new ionosonde fetcher instance
+func NewIonosondeFetcher(client *resty.Client) *IonosondeFetcher {
+	return &IonosondeFetcher{
+		client: client,
+	}
+}
+
+// FetchGIRO fetches foF2, hmF2 and MUF(3000)F2 of one station between from and to from GIRO DIDBase
+func (f *IonosondeFetcher) FetchGIRO(ctx context.Context, url, code string, from, to time.Time) (*models.IonosondeStation, error) {
+	if url == "" {
+		url = GIRODefaultURL
+	}
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"ursiCode": code,
+			"charName": "foF2,hmF2,MUFD",
+			"DMUF":     "3000",
+			"fromDate": from.UTC().Format("2006/01/02 15:04:05"),
+			"toDate":   to.UTC().Format("2006/01/02 15:04:05"),
+		}).
+		Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch GIRO data for %s: %w", code, err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("GIRO DIDBase returned status %d for %s", resp.StatusCode(), code)
+	}
+
+	station, err := parseGIRO(code, resp.Body())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GIRO response for %s: %w", code, err)
+	}
+	return station, nil
+}
+
+// FetchKC2G fetches the latest reading of every station from a KC2G-style stations.json
+func (f *IonosondeFetcher) FetchKC2G(ctx context.Context, url string) ([]models.IonosondeStation, error) {
+	if url == "" {
+		url = KC2GDefaultURL
+	}
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch KC2G stations: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("KC2G stations API returned status %d", resp.StatusCode())
+	}
+
+	stations, err := parseKC2G(resp.Body())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse KC2G stations: %w", err)
+	}
+	return stations, nil
+}
+
+// parseGIRO parses a DIDBase text response: '#' comment lines with the station location and a
+// "#Time CS foF2 QD hmF2 QD MUFD QD" header, then one line per ionogram with "---" for unscaled values
+func parseGIRO(code string, body []byte) (*models.IonosondeStation, error) {
+	station := &models.IonosondeStation{Code: strings.ToUpper(code), Name: strings.ToUpper(code), Source: "GIRO DIDBase"}
+	var columns []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if m := giroLocation.FindStringSubmatch(line); m != nil {
+				lat, _ := strconv.ParseFloat(m[1], 64)
+				lon, _ := strconv.ParseFloat(m[3], 64)
+				if m[2] == "S" {
+					lat = -lat
+				}
+				if m[4] == "W" {
+					lon = -lon
+				}
+				station.Latitude, station.Longitude = lat, normalizeLongitude(lon)
+				station.Code = m[5]
+				if name := strings.TrimSpace(m[6]); name != "" {
+					station.Name = name
+				}
+			}
+			if fields := strings.Fields(strings.TrimPrefix(line, "#")); len(fields) > 1 && fields[0] == "Time" {
+				columns = fields
+			}
+			continue
+		}
+
+		if columns == nil {
+			return nil, fmt.Errorf("data before the column header")
+		}
+		fields := strings.Fields(line)
+		if len(fields) != len(columns) {
+			return nil, fmt.Errorf("line %q has %d fields, want %d", line, len(fields), len(columns))
+		}
+		t, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q: %w", fields[0], err)
+		}
+		reading := models.IonosondeReading{Time: t.UTC(), Confidence: -1}
+		for i, column := range columns[1:] {
+			value, err := strconv.ParseFloat(fields[i+1], 64)
+			if err != nil {
+				continue // "---" and the QD quality flags
+			}
+			switch column {
+			case "CS":
+				reading.Confidence = giroConfidence(value)
+			case "foF2":
+				reading.FoF2 = value
+			case "hmF2":
+				reading.HmF2 = value
+			case "MUFD":
+				reading.MUF3000 = value
+			}
+		}
+		station.Readings = append(station.Readings, reading)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if columns == nil {
+		return nil, fmt.Errorf("no column header")
+	}
+	return station, nil
+}
+
+// giroConfidence maps a DIDBase confidence score, 999 for manually scaled ionograms, to 0-100
+func giroConfidence(cs float64) float64 {
+	if cs == 999 {
+		return 100
+	}
+	if cs < 0 || cs > 100 {
+		return -1
+	}
+	return cs
+}
+
+// kc2gReading is one entry of a KC2G stations.json; coordinates may be strings and longitudes 0-360
+type kc2gReading struct {
+	Time    string     `json:"time"`
+	CS      *flexFloat `json:"cs"`
+	FoF2    flexFloat  `json:"fof2"`
+	HmF2    flexFloat  `json:"hmf2"`
+	MUFD    flexFloat  `json:"mufd"`
+	Station struct {
+		Code      string    `json:"code"`
+		Name      string    `json:"name"`
+		Latitude  flexFloat `json:"latitude"`
+		Longitude flexFloat `json:"longitude"`
+	} `json:"station"`
+}
+
+// parseKC2G parses a KC2G stations.json into one station per entry, with its latest reading
+func parseKC2G(body []byte) ([]models.IonosondeStation, error) {
+	var entries []kc2gReading
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, err
+	}
+
+	stations := make([]models.IonosondeStation, 0, len(entries))
+	for _, e := range entries {
+		if e.Station.Code == "" {
+			continue
+		}
+		station := models.IonosondeStation{
+			Code:      strings.ToUpper(e.Station.Code),
+			Name:      e.Station.Name,
+			Latitude:  float64(e.Station.Latitude),
+			Longitude: normalizeLongitude(float64(e.Station.Longitude)),
+			Readings:  []models.IonosondeReading{},
+			Source:    "KC2G",
+		}
+		if t, err := parseTimeMulti(e.Time); err == nil {
+			reading := models.IonosondeReading{
+				Time:       t.UTC(),
+				Confidence: -1,
+				FoF2:       float64(e.FoF2),
+				HmF2:       float64(e.HmF2),
+				MUF3000:    float64(e.MUFD),
+			}
+			if e.CS != nil && *e.CS >= 0 && *e.CS <= 100 {
+				reading.Confidence = float64(*e.CS)
+			}
+			station.Readings = append(station.Readings, reading)
+		}
+		stations = append(stations, station)
+	}
+	return stations, nil
+}
+
+// flexFloat decodes a JSON number, a numeric string or null
+type flexFloat float64
+
+func (v *flexFloat) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*v = 0
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %s", data)
+	}
+	*v = flexFloat(f)
+	return nil
+}
+
+// normalizeLongitude maps a longitude east of Greenwich, 0-360, to -180..180
+func normalizeLongitude(lon float64) float64 {
+	if lon > 180 {
+		lon -= 360
+	}
+	return math.Round(lon*100) / 100
+}
+
+// IonosondeOptions selects the ionosondes fetched with every report
+type IonosondeOptions struct {
+	Source   string   // IonosondeSourceGIRO (default) or IonosondeSourceKC2G
+	URL      string   // defaults to the source's public endpoint
+	Stations []string // URSI codes; no ionosondes are fetched when empty
+}
+
+// SetIonosondes makes every fetch include the measured F2 layer at the given stations
+func (f *DataFetcher) SetIonosondes(opts IonosondeOptions) error {
+	opts.Source = strings.ToLower(strings.TrimSpace(opts.Source))
+	switch opts.Source {
+	case "":
+		opts.Source = IonosondeSourceGIRO
+	case IonosondeSourceGIRO, IonosondeSourceKC2G:
+	default:
+		return fmt.Errorf("unknown ionosonde source %q (want %s or %s)", opts.Source, IonosondeSourceGIRO, IonosondeSourceKC2G)
+	}
+	stations := make([]string, 0, len(opts.Stations))
+	for _, code := range opts.Stations {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			stations = append(stations, code)
+		}
+	}
+	opts.Stations = stations
+	f.ionosondes = opts
+	return nil
+}
+
+// FetchIonosondes fetches the configured stations' readings of the IonosondeWindow before end.
+// Stations that fail are logged and skipped; an error is returned only if none could be fetched.
+func (f *DataFetcher) FetchIonosondes(ctx context.Context, end time.Time) ([]models.IonosondeStation, error) {
+	if len(f.ionosondes.Stations) == 0 {
+		return nil, nil
+	}
+
+	if f.ionosondes.Source == IonosondeSourceKC2G {
+		all, err := f.ionosondeFetcher.FetchKC2G(ctx, f.ionosondes.URL)
+		if err != nil {
+			return nil, err
+		}
+		wanted := make(map[string]bool, len(f.ionosondes.Stations))
+		for _, code := range f.ionosondes.Stations {
+			wanted[code] = true
+		}
+		var stations []models.IonosondeStation
+		for _, station := range all {
+			if wanted[station.Code] {
+				stations = append(stations, station)
+			}
+		}
+		return stations, nil
+	}
+
+	results := make([]*models.IonosondeStation, len(f.ionosondes.Stations))
+	errs := make([]error, len(f.ionosondes.Stations))
+	var wg sync.WaitGroup
+	for i, code := range f.ionosondes.Stations {
+		wg.Add(1)
+		go func(i int, code string) {
+			defer wg.Done()
+			results[i], errs[i] = f.ionosondeFetcher.FetchGIRO(ctx, f.ionosondes.URL, code, end.Add(-IonosondeWindow), end)
+		}(i, code)
+	}
+	wg.Wait()
+
+	var stations []models.IonosondeStation
+	for i, station := range results {
+		if errs[i] != nil {
+			logger.Warn("Ionosonde fetch failed", map[string]interface{}{"station": f.ionosondes.Stations[i], "error": errs[i].Error()})
+			continue
+		}
+		stations = append(stations, *station)
+	}
+	if len(stations) == 0 {
+		return nil, fmt.Errorf("no ionosonde could be fetched: %w", errs[0])
+	}
+	return stations, nil
+}
@@ -0,0 +1,208 @@
+package fetchers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	return data
+}
+
+func TestParseGIRO(t *testing.T) {
+	station, err := parseGIRO("bc840", readFixture(t, "giro_bc840.txt"))
+	if err != nil {
+		t.Fatalf("parseGIRO failed: %v", err)
+	}
+	if station.Code != "BC840" || station.Name != "BOULDER" || station.Latitude != 40 || station.Longitude != -105.3 {
+		t.Errorf("Unexpected station %s %q at %v, %v", station.Code, station.Name, station.Latitude, station.Longitude)
+	}
+	if len(station.Readings) != 96 {
+		t.Fatalf("Expected 96 readings, got %d", len(station.Readings))
+	}
+
+	tests := []struct {
+		index                     int
+		time                      string
+		confidence, fof2, hmf2, m float64
+	}{
+		{0, "2025-10-17T14:00:00Z", 15, 4.869, 287.1, 16.05},
+		{1, "2025-10-17T14:15:00Z", 90, 5.143, 283.9, 16.82},
+		{40, "2025-10-18T00:00:00Z", 90, 0, 0, 0}, // not scaled
+		{55, "2025-10-18T03:45:00Z", 100, 3.2, 300, 10.88},
+	}
+	for _, tt := range tests {
+		r := station.Readings[tt.index]
+		if r.Time.Format(time.RFC3339) != tt.time || r.Confidence != tt.confidence || r.FoF2 != tt.fof2 || r.HmF2 != tt.hmf2 || r.MUF3000 != tt.m {
+			t.Errorf("Reading %d = %+v, want %s cs %v foF2 %v hmF2 %v MUF %v", tt.index, r, tt.time, tt.confidence, tt.fof2, tt.hmf2, tt.m)
+		}
+	}
+	if latest := station.Latest(); latest == nil || latest.Time.Format(time.RFC3339) != "2025-10-18T13:45:00Z" {
+		t.Errorf("Unexpected latest reading %+v", latest)
+	}
+}
+
+func TestParseGIROInvalid(t *testing.T) {
+	header := "#Time CS foF2 QD MUFD QD\n"
+	for name, body := range map[string]string{
+		"empty":            "",
+		"no header":        "2025-10-18T00:00:00.000Z 90 5.1 // 15.2 //\n",
+		"missing a column": header + "2025-10-18T00:00:00.000Z 90 5.1 // 15.2\n",
+		"invalid time":     header + "yesterday 90 5.1 // 15.2 //\n",
+	} {
+		if _, err := parseGIRO("BC840", []byte(body)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	// A station with no ionograms in the window is not an error
+	station, err := parseGIRO("BC840", []byte(header))
+	if err != nil || len(station.Readings) != 0 || station.Latest() != nil {
+		t.Errorf("parseGIRO() = %+v, %v", station, err)
+	}
+}
+
+func TestParseKC2G(t *testing.T) {
+	stations, err := parseKC2G(readFixture(t, "kc2g_stations.json"))
+	if err != nil {
+		t.Fatalf("parseKC2G failed: %v", err)
+	}
+	if len(stations) != 4 {
+		t.Fatalf("Expected 4 stations (the one without a code is skipped), got %d", len(stations))
+	}
+
+	boulder := stations[0]
+	if boulder.Code != "BC840" || boulder.Name != "Boulder" || boulder.Latitude != 40 || boulder.Longitude != -105.3 || boulder.Source != "KC2G" {
+		t.Errorf("Unexpected station %+v", boulder)
+	}
+	if r := boulder.Latest(); r == nil || r.Time != time.Date(2025, 10, 18, 13, 45, 0, 0, time.UTC) || r.Confidence != 90 || r.FoF2 != 7.225 || r.MUF3000 != 22.4 {
+		t.Errorf("Unexpected reading %+v", r)
+	}
+	if juliusruh := stations[1]; juliusruh.Code != "JR055" || juliusruh.Longitude != 13.4 || juliusruh.Readings[0].Confidence != -1 {
+		t.Errorf("Unexpected station %+v", juliusruh)
+	}
+	if canberra := stations[2]; canberra.Readings[0].FoF2 != 0 || canberra.Readings[0].MUF3000 != 0 {
+		t.Errorf("Null values should decode as unscaled, got %+v", canberra.Readings[0])
+	}
+	if arguello := stations[3]; len(arguello.Readings) != 0 || arguello.Longitude != -120.5 {
+		t.Errorf("A reading with an invalid time should be dropped, got %+v", arguello)
+	}
+
+	for _, body := range []string{`{}`, `[{"fof2": "high", "station": {"code": "BC840"}}]`} {
+		if _, err := parseKC2G([]byte(body)); err == nil {
+			t.Errorf("parseKC2G(%s) expected an error", body)
+		}
+	}
+}
+
+func TestNormalizeIonosondes(t *testing.T) {
+	giro, err := parseGIRO("BC840", readFixture(t, "giro_bc840.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kc2g, err := parseKC2G(readFixture(t, "kc2g_stations.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	end := time.Date(2025, 10, 18, 14, 0, 0, 0, time.UTC)
+	ionosondes := NewDataNormalizer().NormalizeIonosondes(append(kc2g, *giro), end)
+	if len(ionosondes) != 3 {
+		t.Fatalf("Expected Boulder and Juliusruh from KC2G and Boulder from GIRO, got %+v", ionosondes)
+	}
+
+	if latest := ionosondes[1]; latest.Code != "JR055" || latest.Grid != "JO64qo" || len(latest.Hourly) != 1 || latest.Latest.MUF3000 != 29.9 {
+		t.Errorf("Unexpected KC2G ionosonde %+v", latest)
+	}
+
+	boulder := ionosondes[2]
+	if boulder.Grid != "DN70ia" || boulder.Source != "GIRO DIDBase" {
+		t.Errorf("Unexpected ionosonde %s in %s", boulder.Code, boulder.Grid)
+	}
+	if len(boulder.Hourly) != 24 {
+		t.Fatalf("Expected 24 hourly readings, got %d", len(boulder.Hourly))
+	}
+	for i, r := range boulder.Hourly {
+		if hour := end.Add(time.Duration(i-24) * time.Hour); !r.Time.Truncate(time.Hour).Equal(hour) || r.Confidence < IonosondeMinConfidence || r.FoF2 == 0 {
+			t.Errorf("Hourly reading %d is not a usable reading of %s: %+v", i, hour.Format("15:04"), r)
+		}
+	}
+
+	// The latest usable reading of each hour: 15:45 had a low confidence, 00:00 was not scaled
+	for i, want := range map[int]string{0: "14:45", 1: "15:30", 10: "00:45", 23: "13:45"} {
+		if got := boulder.Hourly[i].Time.Format("15:04"); got != want {
+			t.Errorf("Hourly reading %d at %s, want %s", i, got, want)
+		}
+	}
+	if boulder.Latest != boulder.Hourly[len(boulder.Hourly)-1] {
+		t.Errorf("Latest %+v is not the last hourly reading", boulder.Latest)
+	}
+
+	// Readings older than the window are not reported
+	if stale := NewDataNormalizer().NormalizeIonosondes(kc2g, end.Add(48*time.Hour)); len(stale) != 0 {
+		t.Errorf("Expected no ionosondes two days later, got %+v", stale)
+	}
+}
+
+func TestFetchIonosondes(t *testing.T) {
+	giro := readFixture(t, "giro_bc840.txt")
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/stations.json":
+			w.Write(readFixture(t, "kc2g_stations.json"))
+		case r.URL.Query().Get("ursiCode") == "BC840":
+			query = r.URL.RawQuery
+			w.Write(giro)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	end := time.Date(2025, 10, 18, 14, 0, 0, 0, time.UTC)
+	fetcher := NewDataFetcher()
+	if stations, err := fetcher.FetchIonosondes(ctx, end); err != nil || stations != nil {
+		t.Errorf("Without stations nothing should be fetched, got %+v, %v", stations, err)
+	}
+
+	if err := fetcher.SetIonosondes(IonosondeOptions{URL: server.URL + "/giro", Stations: []string{" bc840", "XX000"}}); err != nil {
+		t.Fatal(err)
+	}
+	stations, err := fetcher.FetchIonosondes(ctx, end)
+	if err != nil || len(stations) != 1 || stations[0].Code != "BC840" || len(stations[0].Readings) != 96 {
+		t.Fatalf("FetchIonosondes() = %d stations, %v; want Boulder only", len(stations), err)
+	}
+	for _, want := range []string{"charName=foF2%2ChmF2%2CMUFD", "DMUF=3000", "fromDate=2025%2F10%2F17+14%3A00%3A00", "toDate=2025%2F10%2F18+14%3A00%3A00"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("GIRO query %q is missing %s", query, want)
+		}
+	}
+
+	fetcher.SetIonosondes(IonosondeOptions{URL: server.URL + "/giro", Stations: []string{"XX000"}})
+	if _, err := fetcher.FetchIonosondes(ctx, end); err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("Expected an error when no station could be fetched, got %v", err)
+	}
+
+	fetcher.SetIonosondes(IonosondeOptions{Source: "KC2G", URL: server.URL + "/stations.json", Stations: []string{"jr055", "AU930"}})
+	stations, err = fetcher.FetchIonosondes(ctx, end)
+	if err != nil || len(stations) != 2 || stations[0].Code != "JR055" || stations[1].Code != "AU930" {
+		t.Errorf("FetchIonosondes() = %+v, %v; want Juliusruh and Canberra", stations, err)
+	}
+
+	if err := fetcher.SetIonosondes(IonosondeOptions{Source: "digisonde"}); err == nil {
+		t.Error("SetIonosondes() expected an error for an unknown source")
+	}
+}
@@ -36,6 +36,15 @@ func NewNOAAFetcher(client *resty.Client) *NOAAFetcher {
 
 // FetchKIndex fetches K-index data from NOAA for the last 72 hours
 func (f *NOAAFetcher) FetchKIndex(ctx context.Context, url string) ([]models.NOAAKIndexResponse, error) {
+	kIndexData, err := f.FetchKIndexHistory(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return f.filterKIndexRecent(kIndexData), nil
+}
+
+// FetchKIndexHistory fetches every K-index point NOAA publishes (about a week)
+func (f *NOAAFetcher) FetchKIndexHistory(ctx context.Context, url string) ([]models.NOAAKIndexResponse, error) {
 	// Always use the provided URL - this is critical for tests
 	kIndexURL := url
 	// Only use default if URL is empty
@@ -100,12 +109,20 @@ func (f *NOAAFetcher) FetchKIndex(ctx context.Context, url string) ([]models.NOA
 		}
 	}
 	
-	// Filter to recent data only (last 72 hours - up to 24 entries)
-	return f.filterKIndexRecent(kIndexData), nil
+	return kIndexData, nil
 }
 
 // FetchSolar fetches solar data from NOAA for the last 6 months
 func (f *NOAAFetcher) FetchSolar(ctx context.Context, url string) ([]models.NOAASolarResponse, error) {
+	data, err := f.FetchSolarHistory(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return f.filterSolarRecent(data), nil
+}
+
+// FetchSolarHistory fetches the whole monthly solar record NOAA publishes (since 1749)
+func (f *NOAAFetcher) FetchSolarHistory(ctx context.Context, url string) ([]models.NOAASolarResponse, error) {
 	// Use the provided URL or fall back to standard endpoint for solar data
 	solarURL := url
 	if solarURL == "" {
@@ -175,8 +192,57 @@ func (f *NOAAFetcher) FetchSolar(ctx context.Context, url string) ([]models.NOAA
 		})
 	}
 	
-	// Filter to recent data only (last 6 months)
-	return f.filterSolarRecent(data), nil
+	return data, nil
+}
+
+// FetchPredictedCycle fetches NOAA's monthly prediction of the current solar cycle
+func (f *NOAAFetcher) FetchPredictedCycle(ctx context.Context, url string) ([]models.NOAAPredictedCycleResponse, error) {
+	predictedURL := url
+	if predictedURL == "" {
+		predictedURL = "https://services.swpc.noaa.gov/json/solar-cycle/predicted-solar-cycle.json"
+	}
+	
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		Get(predictedURL)
+	
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch NOAA predicted cycle: %w", err)
+	}
+	
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("NOAA predicted cycle API returned status %d", resp.StatusCode())
+	}
+	
+	// NOAA returns [{"time-tag":"2025-01","predicted_ssn":137.5,"high_ssn":152.5,"low_ssn":122.5,"predicted_f10.7":162.3,...}]
+	var rawData []struct {
+		TimeTag       string  `json:"time-tag"`
+		PredictedSSN  float64 `json:"predicted_ssn"`
+		HighSSN       float64 `json:"high_ssn"`
+		LowSSN        float64 `json:"low_ssn"`
+		PredictedF107 float64 `json:"predicted_f10.7"`
+		HighF107      float64 `json:"high_f10.7"`
+		LowF107       float64 `json:"low_f10.7"`
+	}
+	if err := json.Unmarshal(resp.Body(), &rawData); err != nil {
+		return nil, fmt.Errorf("failed to parse NOAA predicted cycle response: %w", err)
+	}
+	
+	data := make([]models.NOAAPredictedCycleResponse, 0, len(rawData))
+	for _, item := range rawData {
+		data = append(data, models.NOAAPredictedCycleResponse{
+			TimeTag:       item.TimeTag,
+			PredictedSSN:  item.PredictedSSN,
+			HighSSN:       item.HighSSN,
+			LowSSN:        item.LowSSN,
+			PredictedF107: item.PredictedF107,
+			HighF107:      item.HighF107,
+			LowF107:       item.LowF107,
+			Source:        "NOAA SWPC",
+		})
+	}
+	return data, nil
 }
 
 // filterKIndexRecent filters K-index data to last 72 hours
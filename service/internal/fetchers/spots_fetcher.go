@@ -0,0 +1,303 @@
+package fetchers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"radiocast/internal/logger"
+	"radiocast/internal/models"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Spot sources
+const (
+	SpotSourceWSPR        = "wspr"        // wspr.live ClickHouse HTTP interface
+	SpotSourcePSKReporter = "pskreporter" // PSKReporter XML query API
+
+	WSPRDefaultURL        = "https://db1.wspr.live/"
+	PSKReporterDefaultURL = "https://retrieve.pskreporter.info/query"
+)
+
+// DefaultSpotHours is how far back spots are aggregated unless configured
+const DefaultSpotHours = 6
+
+// pskReporterLimit caps the reception reports of one PSKReporter query
+const pskReporterLimit = 20000
+
+// wsprBands maps the band column of wspr.live, the frequency in whole MHz, to the band
+var wsprBands = map[int]string{
+	1: "160m", 3: "80m", 5: "60m", 7: "40m", 10: "30m", 14: "20m",
+	18: "17m", 21: "15m", 24: "12m", 28: "10m", 50: "6m",
+}
+
+// bandOf returns the amateur band of a frequency in Hz, empty outside the bands
+func bandOf(hz float64) string {
+	return models.BandOf(hz / 1e6)
+}
+
+// SpotsFetcher fetches reception reports from WSPR and PSKReporter
+type SpotsFetcher struct {
+	client *resty.Client
+}
+
+// NewSpotsFetcher creates a new spots fetcher instance
+func NewSpotsFetcher(client *resty.Client) *SpotsFetcher {
+	return &SpotsFetcher{
+		client: client,
+	}
+}
+
+// FetchWSPR fetches the WSPR spots between from and to, counted by band and 4-character locators
+// on the server
+func (f *SpotsFetcher) FetchWSPR(ctx context.Context, url string, from, to time.Time) ([]models.Spot, error) {
+	if url == "" {
+		url = WSPRDefaultURL
+	}
+	query := fmt.Sprintf("SELECT band, substring(tx_loc, 1, 4) AS tx_grid, substring(rx_loc, 1, 4) AS rx_grid, "+
+		"count() AS spots, max(snr) AS snr FROM wspr.rx "+
+		"WHERE time > '%s' AND time <= '%s' AND tx_grid != '' AND rx_grid != '' "+
+		"GROUP BY band, tx_grid, rx_grid FORMAT CSVWithNames",
+		from.UTC().Format("2006-01-02 15:04:05"), to.UTC().Format("2006-01-02 15:04:05"))
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParam("query", query).
+		Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch WSPR spots: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("wspr.live returned status %d", resp.StatusCode())
+	}
+
+	spots, err := parseWSPR(resp.Body())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse WSPR spots: %w", err)
+	}
+	return spots, nil
+}
+
+// FetchPSKReporter fetches the reception reports of the last window
+func (f *SpotsFetcher) FetchPSKReporter(ctx context.Context, url string, window time.Duration) ([]models.Spot, error) {
+	if url == "" {
+		url = PSKReporterDefaultURL
+	}
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"flowStartSeconds": strconv.FormatInt(-int64(window.Seconds()), 10),
+			"rronly":           "1",
+			"noactive":         "1",
+			"rptlimit":         strconv.Itoa(pskReporterLimit),
+		}).
+		Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch PSKReporter spots: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("PSKReporter returned status %d", resp.StatusCode())
+	}
+
+	spots, err := parsePSKReporter(resp.Body())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PSKReporter spots: %w", err)
+	}
+	return spots, nil
+}
+
+// parseWSPR parses a CSVWithNames response. The columns are looked up by name: band or frequency
+// (Hz), tx_grid or tx_loc, rx_grid or rx_loc, and the optional spots, snr and time.
+func parseWSPR(body []byte) ([]models.Spot, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("empty response")
+	}
+	if err != nil {
+		return nil, err
+	}
+	column := make(map[string]int, len(header))
+	for i, name := range header {
+		column[strings.TrimSpace(name)] = i
+	}
+	find := func(names ...string) int {
+		for _, name := range names {
+			if i, ok := column[name]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+	bandCol, freqCol := find("band"), find("frequency")
+	txCol, rxCol := find("tx_grid", "tx_loc"), find("rx_grid", "rx_loc")
+	countCol, snrCol, timeCol := find("spots"), find("snr"), find("time")
+	if (bandCol < 0 && freqCol < 0) || txCol < 0 || rxCol < 0 {
+		return nil, fmt.Errorf("missing columns in header %v", header)
+	}
+
+	var spots []models.Spot
+	for {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		spot := models.Spot{Source: spotSourceName(SpotSourceWSPR), TxGrid: rec[txCol], RxGrid: rec[rxCol], Count: 1}
+		if freqCol >= 0 {
+			hz, err := strconv.ParseFloat(rec[freqCol], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid frequency %q", rec[freqCol])
+			}
+			spot.Band = bandOf(hz)
+		} else {
+			band, err := strconv.Atoi(rec[bandCol])
+			if err != nil {
+				return nil, fmt.Errorf("invalid band %q", rec[bandCol])
+			}
+			spot.Band = wsprBands[band]
+		}
+		if countCol >= 0 {
+			if spot.Count, err = strconv.Atoi(rec[countCol]); err != nil {
+				return nil, fmt.Errorf("invalid spot count %q", rec[countCol])
+			}
+		}
+		if snrCol >= 0 {
+			spot.SNR, _ = strconv.ParseFloat(rec[snrCol], 64)
+		}
+		if timeCol >= 0 {
+			if t, err := parseTimeMulti(rec[timeCol]); err == nil {
+				spot.Time = t.UTC()
+			}
+		}
+		spots = append(spots, spot)
+	}
+	return spots, nil
+}
+
+// pskReporterReports is the XML document of the PSKReporter query API
+type pskReporterReports struct {
+	Reports []struct {
+		ReceiverLocator  string  `xml:"receiverLocator,attr"`
+		SenderLocator    string  `xml:"senderLocator,attr"`
+		Frequency        float64 `xml:"frequency,attr"`
+		FlowStartSeconds int64   `xml:"flowStartSeconds,attr"`
+		SNR              float64 `xml:"sNR,attr"`
+	} `xml:"receptionReport"`
+}
+
+// parsePSKReporter parses reception reports; reports without a frequency are left out
+func parsePSKReporter(body []byte) ([]models.Spot, error) {
+	var doc pskReporterReports
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, err
+	}
+	spots := make([]models.Spot, 0, len(doc.Reports))
+	for _, r := range doc.Reports {
+		if r.Frequency <= 0 {
+			continue
+		}
+		spots = append(spots, models.Spot{
+			Time:   time.Unix(r.FlowStartSeconds, 0).UTC(),
+			Source: spotSourceName(SpotSourcePSKReporter),
+			Band:   bandOf(r.Frequency),
+			TxGrid: r.SenderLocator,
+			RxGrid: r.ReceiverLocator,
+			SNR:    r.SNR,
+			Count:  1,
+		})
+	}
+	return spots, nil
+}
+
+// spotSourceName returns the name spots of a source are reported under
+func spotSourceName(source string) string {
+	if source == SpotSourceWSPR {
+		return "WSPR"
+	}
+	return "PSKReporter"
+}
+
+// SpotOptions selects the spot sources aggregated into every report
+type SpotOptions struct {
+	Sources        []string // SpotSourceWSPR and/or SpotSourcePSKReporter; no spots are fetched when empty
+	Hours          int      // defaults to DefaultSpotHours
+	WSPRURL        string   // defaults to WSPRDefaultURL
+	PSKReporterURL string   // defaults to PSKReporterDefaultURL
+}
+
+// SetSpots makes every fetch include the observed band activity of the given sources
+func (f *DataFetcher) SetSpots(opts SpotOptions) error {
+	sources := make([]string, 0, len(opts.Sources))
+	for _, source := range opts.Sources {
+		switch source = strings.ToLower(strings.TrimSpace(source)); source {
+		case "":
+		case SpotSourceWSPR, SpotSourcePSKReporter:
+			sources = append(sources, source)
+		default:
+			return fmt.Errorf("unknown spot source %q (want %s or %s)", source, SpotSourceWSPR, SpotSourcePSKReporter)
+		}
+	}
+	if opts.Hours < 0 || opts.Hours > 24 {
+		return fmt.Errorf("spot hours must be between 1 and 24, got %d", opts.Hours)
+	}
+	if opts.Hours == 0 {
+		opts.Hours = DefaultSpotHours
+	}
+	opts.Sources = sources
+	f.spots = opts
+	return nil
+}
+
+// FetchSpots fetches the spots of the configured sources over the hours before end. Sources that
+// fail are logged and skipped; an error is returned only if none could be fetched.
+func (f *DataFetcher) FetchSpots(ctx context.Context, end time.Time) ([]models.Spot, []string, error) {
+	if len(f.spots.Sources) == 0 {
+		return nil, nil, nil
+	}
+	from := end.Add(-time.Duration(f.spots.Hours) * time.Hour)
+
+	results := make([][]models.Spot, len(f.spots.Sources))
+	errs := make([]error, len(f.spots.Sources))
+	var wg sync.WaitGroup
+	for i, source := range f.spots.Sources {
+		wg.Add(1)
+		go func(i int, source string) {
+			defer wg.Done()
+			if source == SpotSourceWSPR {
+				results[i], errs[i] = f.spotsFetcher.FetchWSPR(ctx, f.spots.WSPRURL, from, end)
+			} else {
+				results[i], errs[i] = f.spotsFetcher.FetchPSKReporter(ctx, f.spots.PSKReporterURL, end.Sub(from))
+			}
+		}(i, source)
+	}
+	wg.Wait()
+
+	var spots []models.Spot
+	var sources []string
+	var lastErr error
+	for i, source := range f.spots.Sources {
+		if errs[i] != nil {
+			logger.Warn("Spot fetch failed", map[string]interface{}{"source": source, "error": errs[i].Error()})
+			lastErr = errs[i]
+			continue
+		}
+		spots = append(spots, results[i]...)
+		sources = append(sources, spotSourceName(source))
+	}
+	if len(sources) == 0 {
+		return nil, nil, fmt.Errorf("no spot source could be fetched: %w", lastErr)
+	}
+	return spots, sources, nil
+}
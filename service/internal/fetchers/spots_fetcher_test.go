@@ -0,0 +1,202 @@
+package fetchers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBandOf(t *testing.T) {
+	tests := map[float64]string{
+		1838100:   "160m",
+		3573000:   "80m",
+		7074000:   "40m",
+		10136000:  "30m",
+		14095600:  "20m",
+		18100000:  "17m",
+		21074000:  "15m",
+		24915000:  "12m",
+		28074000:  "10m",
+		50313000:  "6m",
+		7500000:   "",
+		144174000: "",
+	}
+	for hz, want := range tests {
+		if got := bandOf(hz); got != want {
+			t.Errorf("bandOf(%v) = %q, want %q", hz, got, want)
+		}
+	}
+}
+
+func TestParseWSPR(t *testing.T) {
+	spots, err := parseWSPR(readFixture(t, "wspr_spots.csv"))
+	if err != nil {
+		t.Fatalf("parseWSPR failed: %v", err)
+	}
+	if len(spots) != 10 {
+		t.Fatalf("Expected 10 spots, got %d", len(spots))
+	}
+	if s := spots[0]; s.Source != "WSPR" || s.Band != "20m" || s.TxGrid != "JO62" || s.RxGrid != "FN31" || s.Count != 42 || s.SNR != -8 || !s.Time.IsZero() {
+		t.Errorf("Unexpected spot %+v", s)
+	}
+	if spots[7].Band != "160m" || spots[8].Band != "" {
+		t.Errorf("Expected 160m and an unknown band, got %q and %q", spots[7].Band, spots[8].Band)
+	}
+
+	// Single spots, as returned by a query without GROUP BY
+	spots, err = parseWSPR([]byte("time,frequency,tx_loc,rx_loc,snr\n2025-10-18 12:02:00,14097063,JO62qm,FN31pr,-21\n"))
+	if err != nil || len(spots) != 1 || spots[0].Band != "20m" || spots[0].Count != 1 || spots[0].Time != time.Date(2025, 10, 18, 12, 2, 0, 0, time.UTC) {
+		t.Errorf("parseWSPR() = %+v, %v", spots, err)
+	}
+
+	for _, body := range []string{
+		"",
+		"tx_grid,rx_grid\nJO62,FN31\n",
+		"band,tx_grid,rx_grid\ntwenty,JO62,FN31\n",
+		"band,tx_grid,rx_grid,spots\n14,JO62,FN31,many\n",
+		"Code: 62. DB::Exception: Syntax error\n",
+	} {
+		if _, err := parseWSPR([]byte(body)); err == nil {
+			t.Errorf("parseWSPR(%q) expected an error", body)
+		}
+	}
+}
+
+func TestParsePSKReporter(t *testing.T) {
+	spots, err := parsePSKReporter(readFixture(t, "pskreporter.xml"))
+	if err != nil {
+		t.Fatalf("parsePSKReporter failed: %v", err)
+	}
+	if len(spots) != 8 {
+		t.Fatalf("Expected 8 spots (one has no frequency), got %d", len(spots))
+	}
+	want := time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC)
+	if s := spots[0]; s.Source != "PSKReporter" || s.Band != "20m" || s.TxGrid != "JO62qm" || s.RxGrid != "FN31pr" || s.SNR != -12 || s.Count != 1 || !s.Time.Equal(want) {
+		t.Errorf("Unexpected spot %+v", s)
+	}
+	if spots[4].Band != "6m" || spots[6].Band != "" {
+		t.Errorf("Expected 6m and an out-of-band spot, got %q and %q", spots[4].Band, spots[6].Band)
+	}
+
+	if _, err := parsePSKReporter([]byte("<receptionReports><receptionReport")); err == nil {
+		t.Error("Expected an error for truncated XML")
+	}
+}
+
+func TestNormalizeSpots(t *testing.T) {
+	wspr, err := parseWSPR(readFixture(t, "wspr_spots.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	psk, err := parsePSKReporter(readFixture(t, "pskreporter.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	to := time.Date(2025, 10, 18, 14, 0, 0, 0, time.UTC)
+	from := to.Add(-6 * time.Hour)
+	observed := NewDataNormalizer().NormalizeSpots(append(wspr, psk...), []string{"WSPR", "PSKReporter"}, from, to)
+	if observed == nil {
+		t.Fatal("Expected observed bands")
+	}
+	if observed.Spots != 554 || !observed.From.Equal(from) || len(observed.Sources) != 2 {
+		t.Errorf("Unexpected totals: %d spots from %v by %v", observed.Spots, observed.From, observed.Sources)
+	}
+
+	var bands []string
+	for _, b := range observed.Bands {
+		bands = append(bands, b.Band)
+	}
+	if got := strings.Join(bands, ","); got != "160m,40m,20m,15m,10m,6m" {
+		t.Fatalf("Unexpected bands %s", got)
+	}
+
+	twenty := observed.Bands[2]
+	if twenty.Group != "30m-20m" || twenty.Spots != 182 || len(twenty.Openings) != 2 {
+		t.Fatalf("Unexpected 20m %+v", twenty)
+	}
+	if o := twenty.Openings[0]; o.From != "EU" || o.To != "EU" || o.Spots != 120 || o.BestSNR != 5 {
+		t.Errorf("Unexpected 20m opening %+v", o)
+	}
+	// Both directions, WSPR and PSKReporter count towards one pair; AS-NA has too few spots
+	if o := twenty.Openings[1]; o.From != "NA" || o.To != "EU" || o.Spots != 60 || o.BestSNR != -8 || o.LongestKm < 5000 || o.LongestKm > 7000 {
+		t.Errorf("Unexpected 20m opening %+v", o)
+	}
+	if o := observed.Bands[3].Openings; len(o) != 1 || o[0].From != "NA" || o[0].To != "SA" || o[0].Spots != 3 || o[0].BestSNR != -5 {
+		t.Errorf("Unexpected 15m openings %+v", o)
+	}
+	if o := observed.Bands[4].Openings; len(o) != 1 || o[0].From != "AS" || o[0].To != "OC" {
+		t.Errorf("Unexpected 10m openings %+v", o)
+	}
+	if six := observed.Bands[5]; six.Group != "6m" || six.Spots != 1 || len(six.Openings) != 0 {
+		t.Errorf("A single 6m spot is not an opening, got %+v", six)
+	}
+	if pairs := observed.Group("30m-20m"); len(pairs) != 2 || pairs[1] != "NA-EU" {
+		t.Errorf("Unexpected 30m-20m pairs %v", pairs)
+	}
+
+	if got := NewDataNormalizer().NormalizeSpots(psk, nil, to.Add(24*time.Hour), to.Add(30*time.Hour)); got != nil {
+		t.Errorf("Expected no observed bands outside the window, got %+v", got)
+	}
+}
+
+func TestFetchSpots(t *testing.T) {
+	var wsprQuery, pskQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wspr":
+			wsprQuery = r.URL.Query().Get("query")
+			w.Write(readFixture(t, "wspr_spots.csv"))
+		case "/psk":
+			pskQuery = r.URL.RawQuery
+			w.Write(readFixture(t, "pskreporter.xml"))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	end := time.Date(2025, 10, 18, 14, 0, 0, 0, time.UTC)
+	fetcher := NewDataFetcher()
+	if spots, sources, err := fetcher.FetchSpots(ctx, end); err != nil || spots != nil || sources != nil {
+		t.Errorf("Without sources nothing should be fetched, got %d spots, %v", len(spots), err)
+	}
+
+	if err := fetcher.SetSpots(SpotOptions{Sources: []string{"WSPR", " pskreporter"}, WSPRURL: server.URL + "/wspr", PSKReporterURL: server.URL + "/psk"}); err != nil {
+		t.Fatal(err)
+	}
+	spots, sources, err := fetcher.FetchSpots(ctx, end)
+	if err != nil || len(spots) != 18 || strings.Join(sources, ",") != "WSPR,PSKReporter" {
+		t.Fatalf("FetchSpots() = %d spots from %v, %v", len(spots), sources, err)
+	}
+	for _, want := range []string{"FROM wspr.rx", "time > '2025-10-18 08:00:00' AND time <= '2025-10-18 14:00:00'", "GROUP BY band, tx_grid, rx_grid", "FORMAT CSVWithNames"} {
+		if !strings.Contains(wsprQuery, want) {
+			t.Errorf("WSPR query %q is missing %s", wsprQuery, want)
+		}
+	}
+	for _, want := range []string{"flowStartSeconds=-21600", "rronly=1"} {
+		if !strings.Contains(pskQuery, want) {
+			t.Errorf("PSKReporter query %q is missing %s", pskQuery, want)
+		}
+	}
+
+	// One failing source is skipped, all failing is an error
+	fetcher.SetSpots(SpotOptions{Sources: []string{"wspr", "pskreporter"}, Hours: 3, WSPRURL: server.URL + "/down", PSKReporterURL: server.URL + "/psk"})
+	if spots, sources, err = fetcher.FetchSpots(ctx, end); err != nil || len(spots) != 8 || len(sources) != 1 || !strings.Contains(pskQuery, "flowStartSeconds=-10800") {
+		t.Errorf("FetchSpots() = %d spots from %v, %v; want PSKReporter only", len(spots), sources, err)
+	}
+	fetcher.SetSpots(SpotOptions{Sources: []string{"wspr"}, WSPRURL: server.URL + "/down"})
+	if _, _, err := fetcher.FetchSpots(ctx, end); err == nil || !strings.Contains(err.Error(), "status 503") {
+		t.Errorf("Expected an error when no source could be fetched, got %v", err)
+	}
+
+	for _, opts := range []SpotOptions{{Sources: []string{"rbn"}}, {Sources: []string{"wspr"}, Hours: 48}} {
+		if err := fetcher.SetSpots(opts); err == nil {
+			t.Errorf("SetSpots(%+v) expected an error", opts)
+		}
+	}
+}
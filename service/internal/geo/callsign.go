@@ -0,0 +1,91 @@
+package geo
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// prefixesCSV lists callsign prefixes with their continent
+//
+//go:embed prefixes.csv
+var prefixesCSV string
+
+var callPrefixes = mustParsePrefixes(prefixesCSV)
+
+// BaseCall returns the part of a callsign that identifies where it operates from: the prefix of
+// calls like EA8/DL1ABC, without suffixes like /P, /QRP or an area digit. Maritime and aeronautical
+// mobile stations (/MM, /AM) have no base call.
+func BaseCall(call string) string {
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(call)), "/")
+	base := ""
+	for _, part := range parts {
+		switch {
+		case part == "MM" || part == "AM":
+			return ""
+		case part == "" || part == "P" || part == "M" || part == "A" || part == "QRP" || part == "QRPP" || len(part) == 1:
+			continue
+		case base == "" || len(part) < len(base):
+			base = part
+		}
+	}
+	return base
+}
+
+// ContinentOfCall returns the continent of a callsign, one of Continents, by its longest matching
+// prefix. Russian calls with the area digit 8, 9 or 0 are in Asia. It returns "" if unknown.
+func ContinentOfCall(call string) string {
+	base := BaseCall(call)
+	if base == "" {
+		return ""
+	}
+	if russian(base) {
+		if i := strings.IndexAny(base, "0123456789"); i > 0 && i < 3 && strings.ContainsRune("890", rune(base[i])) {
+			return "AS"
+		}
+		return "EU"
+	}
+	for n := len(base); n > 0; n-- {
+		if continent, ok := callPrefixes[base[:n]]; ok {
+			return continent
+		}
+	}
+	return ""
+}
+
+// russian reports whether a call is in the Russian R and UA-UI blocks
+func russian(call string) bool {
+	return call[0] == 'R' || (len(call) > 1 && call[0] == 'U' && call[1] >= 'A' && call[1] <= 'I')
+}
+
+// parsePrefixes parses prefix, continent lines
+func parsePrefixes(data string) (map[string]string, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read callsign prefixes: %w", err)
+	}
+
+	prefixes := make(map[string]string, len(records))
+	for _, rec := range records {
+		if rec[0] == "" || ContinentName(rec[1]) == rec[1] {
+			return nil, fmt.Errorf("invalid callsign prefix %q", strings.Join(rec, ","))
+		}
+		if _, ok := prefixes[rec[0]]; ok {
+			return nil, fmt.Errorf("duplicate callsign prefix %q", rec[0])
+		}
+		prefixes[rec[0]] = rec[1]
+	}
+	return prefixes, nil
+}
+
+func mustParsePrefixes(data string) map[string]string {
+	prefixes, err := parsePrefixes(data)
+	if err != nil {
+		panic(err)
+	}
+	return prefixes
+}
@@ -0,0 +1,82 @@
+package geo
+
+import "testing"
+
+func TestBaseCall(t *testing.T) {
+	tests := map[string]string{
+		"dl1abc":       "DL1ABC",
+		"DL1ABC/P":     "DL1ABC",
+		"EA8/DL1ABC":   "EA8",
+		"DL1ABC/EA8":   "EA8",
+		"W1AW/4":       "W1AW",
+		"G4ABC/QRP":    "G4ABC",
+		"VP2MXY/M":     "VP2MXY",
+		"DL1ABC/MM":    "",
+		"N0CALL/AM":    "",
+		"3D2/DL1ABC/P": "3D2",
+	}
+	for call, want := range tests {
+		if got := BaseCall(call); got != want {
+			t.Errorf("BaseCall(%q) = %q, want %q", call, got, want)
+		}
+	}
+}
+
+func TestContinentOfCall(t *testing.T) {
+	tests := map[string]string{
+		"W1AW":       "NA",
+		"K2ABC":      "NA",
+		"AA7XX":      "NA",
+		"KH6ABC":     "OC", // Hawaii
+		"KL7XX":      "NA",
+		"VE3ABC":     "NA",
+		"XE1ABC":     "NA",
+		"PY2XB":      "SA",
+		"LU1ABC":     "SA",
+		"9Y4X":       "SA",
+		"G4ABC":      "EU",
+		"M0XYZ":      "EU",
+		"DL1ABC":     "EU",
+		"IK2ABC":     "EU",
+		"SV9ABC":     "EU",
+		"TA1ABC":     "EU",
+		"TA2ABC":     "AS",
+		"UA3ABC":     "EU",
+		"RA9ABC":     "AS", // Asiatic Russia
+		"RW0AB":      "AS",
+		"R1ABC":      "EU",
+		"UA2FX":      "EU", // Kaliningrad
+		"EA8/DL1ABC": "AF", // Canary Islands
+		"EA6XX":      "EU",
+		"ZS6ABC":     "AF",
+		"5Z4ABC":     "AF",
+		"3Y0J":       "AF", // Bouvet
+		"JA1ABC":     "AS",
+		"BY1AA":      "AS",
+		"9M2XX":      "AS",
+		"9M6XX":      "OC", // East Malaysia
+		"VU2ABC":     "AS",
+		"VK2ABC":     "OC",
+		"ZL1ABC":     "OC",
+		"DU1ABC":     "OC",
+		"3D2AG":      "OC",
+		"DL1ABC/MM":  "",
+		"Q1ABC":      "",
+	}
+	for call, want := range tests {
+		if got := ContinentOfCall(call); got != want {
+			t.Errorf("ContinentOfCall(%q) = %q, want %q", call, got, want)
+		}
+	}
+}
+
+func TestParsePrefixes(t *testing.T) {
+	if len(callPrefixes) < 700 {
+		t.Errorf("Only %d callsign prefixes embedded", len(callPrefixes))
+	}
+	for _, data := range []string{"DL\n", "DL,Europe\n", ",EU\n", "DL,EU\nDL,EU\n"} {
+		if _, err := parsePrefixes(data); err == nil {
+			t.Errorf("parsePrefixes(%q) expected an error", data)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseLatLon parses a latitude and longitude in decimal degrees or degrees, minutes and seconds,
+// signed or with hemisphere letters, e.g. "41.7292, -72.7083", "41.7292N 72.7083W" or
+// `41°43'45"N 72°42'30"W`
+func ParseLatLon(s string) (Location, error) {
+	latPart, lonPart, ok := splitLatLon(strings.ToUpper(strings.TrimSpace(s)))
+	if !ok {
+		return Location{}, fmt.Errorf("invalid coordinates %q: expected a latitude and a longitude", s)
+	}
+	lat, err := parseCoordinate(latPart, 'N', 'S', 90)
+	if err != nil {
+		return Location{}, fmt.Errorf("invalid latitude in %q: %w", s, err)
+	}
+	lon, err := parseCoordinate(lonPart, 'E', 'W', 180)
+	if err != nil {
+		return Location{}, fmt.Errorf("invalid longitude in %q: %w", s, err)
+	}
+	return NewLocation(lat, lon), nil
+}
+
+// ParseLocation parses a Maidenhead locator or a latitude and longitude
+func ParseLocation(s string) (Location, error) {
+	if loc, err := ParseGrid(s); err == nil {
+		return loc, nil
+	}
+	if loc, err := ParseLatLon(s); err == nil {
+		return loc, nil
+	}
+	return Location{}, fmt.Errorf("invalid location %q: expected a Maidenhead locator or a latitude and longitude", s)
+}
+
+// splitLatLon splits at a comma or semicolon, after the latitude's hemisphere letter, before the
+// longitude's, or at the only run of spaces
+func splitLatLon(s string) (string, string, bool) {
+	if i := strings.IndexAny(s, ",;"); i >= 0 {
+		return s[:i], s[i+1:], true
+	}
+	if i := strings.IndexAny(s, "NS"); i >= 0 {
+		if i > 0 {
+			return s[:i+1], s[i+1:], true
+		}
+		if j := strings.IndexAny(s, "EW"); j > 0 {
+			return s[:j], s[j:], true
+		}
+		return "", "", false
+	}
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return "", "", false
+	}
+	return fields[0], fields[1], true
+}
+
+// parseCoordinate parses one coordinate with an optional hemisphere letter, positive or negative
+func parseCoordinate(s string, positive, negative byte, limit float64) (float64, error) {
+	s = strings.TrimSpace(s)
+	sign, hemisphere := 1.0, false
+	if n := len(s); n > 0 {
+		switch {
+		case s[n-1] == positive || s[n-1] == negative:
+			hemisphere, sign = true, hemisphereSign(s[n-1], negative)
+			s = s[:n-1]
+		case s[0] == positive || s[0] == negative:
+			hemisphere, sign = true, hemisphereSign(s[0], negative)
+			s = s[1:]
+		}
+	}
+
+	fields := strings.Fields(strings.NewReplacer("°", " ", "′", " ", "″", " ", "'", " ", `"`, " ").Replace(s))
+	if len(fields) == 0 || len(fields) > 3 {
+		return 0, fmt.Errorf("%q is not degrees, minutes and seconds", s)
+	}
+	value := 0.0
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return 0, fmt.Errorf("%q is not a number", field)
+		}
+		if i == 0 {
+			if v < 0 || strings.HasPrefix(field, "-") {
+				if hemisphere {
+					return 0, fmt.Errorf("%q is negative with a hemisphere letter", s)
+				}
+				sign, v = -1, -v
+			}
+			value = v
+			continue
+		}
+		if v < 0 || v >= 60 {
+			return 0, fmt.Errorf("%q is not between 0 and 60", field)
+		}
+		value += v / math.Pow(60, float64(i))
+	}
+	value *= sign
+	if value < -limit || value > limit {
+		return 0, fmt.Errorf("%v is not between %v and %v", value, -limit, limit)
+	}
+	return value, nil
+}
+
+func hemisphereSign(letter, negative byte) float64 {
+	if letter == negative {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,62 @@
+package geo
+
+import "testing"
+
+func TestParseLatLon(t *testing.T) {
+	tests := []struct {
+		in       string
+		lat, lon float64
+	}{
+		{"41.7292, -72.7083", 41.7292, -72.7083},
+		{"41.7292;-72.7083", 41.7292, -72.7083},
+		{"41.7292 -72.7083", 41.7292, -72.7083},
+		{"41.7292N 72.7083W", 41.7292, -72.7083},
+		{"41.7292n72.7083w", 41.7292, -72.7083},
+		{"N41.7292 W72.7083", 41.7292, -72.7083},
+		{"33.8688S, 151.2093E", -33.8688, 151.2093},
+		{`41°43'45"N 72°42'30"W`, 41.7292, -72.7083},
+		{"33°52′08″S 151°12′33″E", -33.8689, 151.2092},
+		{"51 30 N, 0 7.5 W", 51.5, -0.125},
+		{"-90, 180", -90, 180},
+	}
+	for _, tt := range tests {
+		loc, err := ParseLatLon(tt.in)
+		if err != nil {
+			t.Errorf("ParseLatLon(%q) error = %v", tt.in, err)
+			continue
+		}
+		if loc.Latitude != tt.lat || loc.Longitude != tt.lon || loc.Grid == "" {
+			t.Errorf("ParseLatLon(%q) = %+v, want %v, %v", tt.in, loc, tt.lat, tt.lon)
+		}
+	}
+
+	for _, in := range []string{
+		"", "41.7292", "41.7292, -72.7083, 0", "91, 0", "0, 181", "abc, def", "41°60'N 72°W",
+		"-41.7292N 72.7083W", "41 43 45 12, 0", "NaN, 0", "N, W",
+	} {
+		if loc, err := ParseLatLon(in); err == nil {
+			t.Errorf("ParseLatLon(%q) = %+v, expected an error", in, loc)
+		}
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		in   string
+		grid string
+	}{
+		{"FN31pr", "FN31pr"},
+		{"io91", "IO91"},
+		{"41.7292, -72.7083", "FN31pr"},
+		{"33.8688S 151.2093E", "QF56od"},
+	}
+	for _, tt := range tests {
+		loc, err := ParseLocation(tt.in)
+		if err != nil || loc.Grid != tt.grid {
+			t.Errorf("ParseLocation(%q) = %+v, %v, want grid %s", tt.in, loc, err, tt.grid)
+		}
+	}
+	if _, err := ParseLocation("somewhere"); err == nil {
+		t.Error("ParseLocation() expected an error for an invalid location")
+	}
+}
@@ -0,0 +1,38 @@
+// Package geo converts between Maidenhead locators and coordinates, computes great-circle paths on
+// a spherical earth and looks up CQ and ITU zones
+package geo
+
+import "math"
+
+const (
+	// EarthRadius is the mean radius of the earth in km
+	EarthRadius = 6371.0
+	// Circumference is the length of a great circle in km
+	Circumference = 2 * math.Pi * EarthRadius
+)
+
+// Location is a point on the map, usually the center of a Maidenhead grid square
+type Location struct {
+	Grid      string  `json:"grid"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// NewLocation returns a point with its 6 character locator, rounded to 4 decimals (about 10 m)
+func NewLocation(lat, lon float64) Location {
+	return Location{Grid: GridOf(lat, lon), Latitude: round(lat, 4), Longitude: round(lon, 4)}
+}
+
+// round rounds v to the given number of decimals
+func round(v float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(v*p) / p
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
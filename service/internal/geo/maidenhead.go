@@ -0,0 +1,110 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// locatorPairs are the character pairs of a locator: field, square, subsquare and extended square.
+// Each divides the previous one into divisions steps of longitude and latitude.
+var locatorPairs = []struct {
+	divisions int
+	first     byte // of the normalized form: fields are upper case, subsquares lower case
+}{
+	{18, 'A'},
+	{10, '0'},
+	{24, 'a'},
+	{10, '0'},
+}
+
+// ParseGrid returns the center of a 2, 4, 6 or 8 character Maidenhead locator such as FN31pr
+func ParseGrid(grid string) (Location, error) {
+	grid = strings.TrimSpace(grid)
+	if n := len(grid); n < 2 || n > 8 || n%2 != 0 {
+		return Location{}, fmt.Errorf("invalid grid %q: expected 2, 4, 6 or 8 characters", grid)
+	}
+
+	lon, lat := -180.0, -90.0
+	lonSize, latSize := 360.0, 180.0
+	normalized := make([]byte, 0, len(grid))
+	for i := 0; i < len(grid); i += 2 {
+		pair := locatorPairs[i/2]
+		lonChar, latChar := normalize(grid[i], pair.first), normalize(grid[i+1], pair.first)
+		last := pair.first + byte(pair.divisions-1)
+		if lonChar < pair.first || lonChar > last || latChar < pair.first || latChar > last {
+			return Location{}, fmt.Errorf("invalid grid %q: unexpected %q", grid, grid[i:i+2])
+		}
+		lonSize /= float64(pair.divisions)
+		latSize /= float64(pair.divisions)
+		lon += float64(lonChar-pair.first) * lonSize
+		lat += float64(latChar-pair.first) * latSize
+		normalized = append(normalized, lonChar, latChar)
+	}
+
+	return Location{
+		Grid:      string(normalized),
+		Latitude:  round(lat+latSize/2, 4),
+		Longitude: round(lon+lonSize/2, 4),
+	}, nil
+}
+
+// ParseGrids parses a list of locators, e.g. the configured club QTHs
+func ParseGrids(grids []string) ([]Location, error) {
+	locations := make([]Location, 0, len(grids))
+	for _, grid := range grids {
+		loc, err := ParseGrid(grid)
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, loc)
+	}
+	return locations, nil
+}
+
+// Locator returns the 2, 4, 6 or 8 character locator of the square containing a point
+func Locator(lat, lon float64, chars int) (string, error) {
+	if chars < 2 || chars > 8 || chars%2 != 0 {
+		return "", fmt.Errorf("invalid locator length %d: expected 2, 4, 6 or 8", chars)
+	}
+	if math.IsNaN(lat) || math.IsNaN(lon) {
+		return "", fmt.Errorf("invalid point %v, %v", lat, lon)
+	}
+
+	// The north pole and the antimeridian belong to the last square
+	lon = math.Min(math.Max(lon+180, 0), 360-1e-9)
+	lat = math.Min(math.Max(lat+90, 0), 180-1e-9)
+	lonSize, latSize := 360.0, 180.0
+	locator := make([]byte, 0, chars)
+	for i := 0; i < chars; i += 2 {
+		pair := locatorPairs[i/2]
+		lonSize /= float64(pair.divisions)
+		latSize /= float64(pair.divisions)
+		x := math.Min(math.Floor(lon/lonSize), float64(pair.divisions-1))
+		y := math.Min(math.Floor(lat/latSize), float64(pair.divisions-1))
+		locator = append(locator, pair.first+byte(x), pair.first+byte(y))
+		lon -= x * lonSize
+		lat -= y * latSize
+	}
+	return string(locator), nil
+}
+
+// GridOf returns the 6 character locator of a point, clamped onto the map
+func GridOf(lat, lon float64) string {
+	grid, err := Locator(lat, lon, 6)
+	if err != nil {
+		return ""
+	}
+	return grid
+}
+
+// normalize converts a letter to the case of first; digits are returned unchanged
+func normalize(c, first byte) byte {
+	switch {
+	case first >= 'a' && c >= 'A' && c <= 'Z':
+		return c - 'A' + 'a'
+	case first >= 'A' && first <= 'Z' && c >= 'a' && c <= 'z':
+		return c - 'a' + 'A'
+	}
+	return c
+}
@@ -0,0 +1,106 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseGrid(t *testing.T) {
+	tests := []struct {
+		grid     string
+		want     string
+		lat, lon float64
+	}{
+		{"FN31", "FN31", 41.5, -73},
+		{"fn31PR", "FN31pr", 41.7292, -72.7083},
+		{"JO", "JO", 55, 10},
+		{"RR99xx", "RR99xx", 89.9792, 179.9583},
+		{"AA00aa", "AA00aa", -89.9792, -179.9583},
+		{"IO91wm48", "IO91wm48", 51.5354, -0.1292},
+	}
+	for _, tt := range tests {
+		loc, err := ParseGrid(tt.grid)
+		if err != nil {
+			t.Errorf("ParseGrid(%q) error = %v", tt.grid, err)
+			continue
+		}
+		if loc.Grid != tt.want || loc.Latitude != tt.lat || loc.Longitude != tt.lon {
+			t.Errorf("ParseGrid(%q) = %+v, want %s at %v, %v", tt.grid, loc, tt.want, tt.lat, tt.lon)
+		}
+	}
+
+	for _, grid := range []string{"", "F", "FN3", "SN31", "FNA1", "FN31py", "FN31prx", "FN31pr4", "FN31pr48aa"} {
+		if _, err := ParseGrid(grid); err == nil {
+			t.Errorf("ParseGrid(%q) expected an error", grid)
+		}
+	}
+}
+
+func TestGridOf(t *testing.T) {
+	for _, grid := range []string{"FN31pr", "IO91wm", "QF56oc", "AA00aa", "RR99xx"} {
+		loc, err := ParseGrid(grid)
+		if err != nil {
+			t.Fatalf("ParseGrid(%q) error = %v", grid, err)
+		}
+		if got := GridOf(loc.Latitude, loc.Longitude); got != grid {
+			t.Errorf("GridOf(%v, %v) = %s, want %s", loc.Latitude, loc.Longitude, got, grid)
+		}
+	}
+	if got := GridOf(90, 180); got != "RR99xx" {
+		t.Errorf("GridOf(90, 180) = %s, want RR99xx", got)
+	}
+}
+
+func TestParseGrids(t *testing.T) {
+	locations, err := ParseGrids([]string{"FN31", "JO60"})
+	if err != nil || len(locations) != 2 || locations[1].Grid != "JO60" {
+		t.Errorf("ParseGrids() = %+v, %v", locations, err)
+	}
+	if _, err := ParseGrids([]string{"FN31", "bogus"}); err == nil {
+		t.Error("ParseGrids() expected an error for an invalid grid")
+	}
+}
+
+func TestLocator(t *testing.T) {
+	tests := []struct {
+		lat, lon float64
+		chars    int
+		want     string
+	}{
+		{41.7292, -72.7083, 2, "FN"},
+		{41.7292, -72.7083, 4, "FN31"},
+		{41.7292, -72.7083, 6, "FN31pr"},
+		{51.5354, -0.1292, 8, "IO91wm48"},
+		{-33.8688, 151.2093, 6, "QF56od"},
+		{0, 0, 4, "JJ00"},
+		{-90, -180, 6, "AA00aa"},
+		{90, 180, 8, "RR99xx99"},
+	}
+	for _, tt := range tests {
+		got, err := Locator(tt.lat, tt.lon, tt.chars)
+		if err != nil || got != tt.want {
+			t.Errorf("Locator(%v, %v, %d) = %q, %v, want %q", tt.lat, tt.lon, tt.chars, got, err, tt.want)
+		}
+	}
+
+	for _, chars := range []int{0, 3, 10} {
+		if _, err := Locator(0, 0, chars); err == nil {
+			t.Errorf("Locator(0, 0, %d) expected an error", chars)
+		}
+	}
+	if _, err := Locator(math.NaN(), 0, 6); err == nil {
+		t.Error("Locator(NaN, 0, 6) expected an error")
+	}
+}
+
+func TestLocatorRoundTrip(t *testing.T) {
+	for _, grid := range []string{"FN31pr48", "IO91wm48", "QF56od27", "AA00aa00", "RR99xx99", "JJ00aa00"} {
+		loc, err := ParseGrid(grid)
+		if err != nil {
+			t.Fatalf("ParseGrid(%q) error = %v", grid, err)
+		}
+		if got, _ := Locator(loc.Latitude, loc.Longitude, 8); got != grid {
+			t.Errorf("Locator(ParseGrid(%q)) = %q", grid, got)
+		}
+	}
+}
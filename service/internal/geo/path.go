@@ -0,0 +1,95 @@
+package geo
+
+import "math"
+
+// Path is a great-circle path between two locations, the short or the long way round
+type Path struct {
+	From       Location `json:"from"`
+	To         Location `json:"to"`
+	Long       bool     `json:"long"`
+	DistanceKm float64  `json:"distance_km"`
+	Azimuth    float64  `json:"azimuth"` // initial bearing at From, degrees clockwise from north
+}
+
+// ShortPath returns the shorter great-circle path from one location to another
+func ShortPath(from, to Location) Path {
+	return Path{From: from, To: to, DistanceKm: Distance(from, to), Azimuth: Azimuth(from, to)}
+}
+
+// LongPath returns the path the long way round: the short path's complement, leaving in the
+// opposite direction
+func LongPath(from, to Location) Path {
+	return Path{
+		From:       from,
+		To:         to,
+		Long:       true,
+		DistanceKm: Circumference - Distance(from, to),
+		Azimuth:    math.Mod(Azimuth(from, to)+180, 360),
+	}
+}
+
+// Distance returns the short-path great-circle distance in km (haversine formula)
+func Distance(from, to Location) float64 {
+	lat1, lat2 := radians(from.Latitude), radians(to.Latitude)
+	dLat, dLon := lat2-lat1, radians(to.Longitude-from.Longitude)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(math.Sqrt(a), 1))
+}
+
+// Azimuth returns the initial short-path bearing in degrees clockwise from north, 0 between
+// coincident points
+func Azimuth(from, to Location) float64 {
+	lat1, lat2 := radians(from.Latitude), radians(to.Latitude)
+	dLon := radians(to.Longitude - from.Longitude)
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	if math.Abs(x) < 1e-12 && math.Abs(y) < 1e-12 {
+		return 0
+	}
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the point reached from a location after distanceKm along a great circle
+// leaving at azimuth
+func Destination(from Location, azimuth, distanceKm float64) Location {
+	lat1, lon1 := radians(from.Latitude), radians(from.Longitude)
+	theta, delta := radians(azimuth), distanceKm/EarthRadius
+
+	sinLat := math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta)
+	lat2 := math.Asin(math.Min(math.Max(sinLat, -1), 1))
+	lon2 := lon1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*sinLat)
+	lon := math.Mod(degrees(lon2)+540, 360) - 180
+	return NewLocation(degrees(lat2), lon)
+}
+
+// PointAt returns the point distanceKm along the path
+func (p Path) PointAt(distanceKm float64) Location {
+	return Destination(p.From, p.Azimuth, distanceKm)
+}
+
+// Midpoint returns the point halfway along the path
+func (p Path) Midpoint() Location {
+	return p.PointAt(p.DistanceKm / 2)
+}
+
+// Hops returns the number of equal hops, none longer than maxHopKm, that cover the path
+func (p Path) Hops(maxHopKm float64) int {
+	if maxHopKm <= 0 || p.DistanceKm <= maxHopKm {
+		return 1
+	}
+	return int(math.Ceil(p.DistanceKm / maxHopKm))
+}
+
+// ControlPoints returns the reflection point of each of hops equal hops along the path: the
+// ionosphere is sampled at the middle of every hop
+func (p Path) ControlPoints(hops int) []Location {
+	if hops < 1 {
+		hops = 1
+	}
+	points := make([]Location, hops)
+	hop := p.DistanceKm / float64(hops)
+	for i := range points {
+		points[i] = p.PointAt(hop * (float64(i) + 0.5))
+	}
+	return points
+}
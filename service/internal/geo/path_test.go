@@ -0,0 +1,143 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+var (
+	london  = Location{Latitude: 51.5074, Longitude: -0.1278}
+	newYork = Location{Latitude: 40.7128, Longitude: -74.006}
+	sydney  = Location{Latitude: -33.8688, Longitude: 151.2093}
+	origin  = Location{}
+)
+
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
+
+func TestShortPath(t *testing.T) {
+	tests := []struct {
+		name          string
+		from, to      Location
+		distance, azi float64
+	}{
+		{"London to New York", london, newYork, 5570, 288.3},
+		{"New York to London", newYork, london, 5570, 51.2},
+		{"London to Sydney", london, sydney, 16994, 60.7},
+		{"east along the equator", origin, Location{Longitude: 90}, Circumference / 4, 90},
+		{"west along the equator", origin, Location{Longitude: -90}, Circumference / 4, 270},
+		{"north to the pole", origin, Location{Latitude: 90}, Circumference / 4, 0},
+		{"south to the pole", origin, Location{Latitude: -90}, Circumference / 4, 180},
+		{"across the antimeridian", Location{Longitude: 179}, Location{Longitude: -179}, Circumference / 180, 90},
+		{"nowhere", london, london, 0, 0},
+	}
+	for _, tt := range tests {
+		p := ShortPath(tt.from, tt.to)
+		if p.Long || !near(p.DistanceKm, tt.distance, 10) || !near(p.Azimuth, tt.azi, 0.1) {
+			t.Errorf("%s: ShortPath() = %.1f km at %.1f°, want %.1f km at %.1f°", tt.name, p.DistanceKm, p.Azimuth, tt.distance, tt.azi)
+		}
+	}
+}
+
+func TestLongPath(t *testing.T) {
+	short, long := ShortPath(london, sydney), LongPath(london, sydney)
+	if !long.Long || !near(short.DistanceKm+long.DistanceKm, Circumference, 1e-6) {
+		t.Errorf("LongPath() = %.1f km, want the complement of %.1f km", long.DistanceKm, short.DistanceKm)
+	}
+	if !near(long.Azimuth, 240.7, 0.1) {
+		t.Errorf("LongPath() azimuth = %.1f°, want 240.7°", long.Azimuth)
+	}
+
+	// The long path ends at the destination too
+	if end := long.PointAt(long.DistanceKm); Distance(end, sydney) > 1 {
+		t.Errorf("LongPath() ends at %+v, want %+v", end, sydney)
+	}
+}
+
+func TestDestination(t *testing.T) {
+	tests := []struct {
+		name          string
+		from          Location
+		azimuth, km   float64
+		wantLatitude  float64
+		wantLongitude float64
+	}{
+		{"east along the equator", origin, 90, Circumference / 4, 0, 90},
+		{"north to the pole", origin, 0, Circumference / 4, 90, 0},
+		{"across the antimeridian", Location{Longitude: 179}, 90, Circumference / 180, 0, -179},
+		{"half way round", origin, 0, Circumference / 2, 0, 180},
+		{"no distance", london, 123, 0, london.Latitude, london.Longitude},
+	}
+	for _, tt := range tests {
+		got := Destination(tt.from, tt.azimuth, tt.km)
+		lonError := math.Abs(math.Mod(got.Longitude-tt.wantLongitude+540, 360) - 180)
+		if !near(got.Latitude, tt.wantLatitude, 1e-3) || lonError > 1e-3 || got.Grid == "" {
+			t.Errorf("%s: Destination() = %+v, want %v, %v", tt.name, got, tt.wantLatitude, tt.wantLongitude)
+		}
+	}
+
+	// Going out along the short path arrives at the destination
+	for _, to := range []Location{newYork, sydney, {Latitude: -89, Longitude: 45}} {
+		p := ShortPath(london, to)
+		if end := Destination(london, p.Azimuth, p.DistanceKm); Distance(end, to) > 0.1 {
+			t.Errorf("Destination() along the path to %+v ends at %+v", to, end)
+		}
+	}
+}
+
+func TestHops(t *testing.T) {
+	tests := []struct {
+		distance, maxHop float64
+		want             int
+	}{
+		{0, 3500, 1},
+		{3500, 3500, 1},
+		{3501, 3500, 2},
+		{16000, 3500, 5},
+		{16000, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := (Path{DistanceKm: tt.distance}).Hops(tt.maxHop); got != tt.want {
+			t.Errorf("Hops(%v) over %v km = %d, want %d", tt.maxHop, tt.distance, got, tt.want)
+		}
+	}
+}
+
+func TestControlPoints(t *testing.T) {
+	p := ShortPath(origin, Location{Longitude: 120})
+	tests := []struct {
+		hops       int
+		longitudes []float64
+	}{
+		{0, []float64{60}},
+		{1, []float64{60}},
+		{2, []float64{30, 90}},
+		{3, []float64{20, 60, 100}},
+	}
+	for _, tt := range tests {
+		points := p.ControlPoints(tt.hops)
+		if len(points) != len(tt.longitudes) {
+			t.Errorf("ControlPoints(%d) returned %d points, want %d", tt.hops, len(points), len(tt.longitudes))
+			continue
+		}
+		for i, cp := range points {
+			if !near(cp.Latitude, 0, 1e-3) || !near(cp.Longitude, tt.longitudes[i], 1e-3) {
+				t.Errorf("ControlPoints(%d)[%d] = %+v, want 0, %v", tt.hops, i, cp, tt.longitudes[i])
+			}
+		}
+	}
+
+	// Every control point lies on the path, at the middle of its hop
+	p = ShortPath(london, sydney)
+	hops := p.Hops(3500)
+	for i, cp := range p.ControlPoints(hops) {
+		want := p.DistanceKm * (float64(i) + 0.5) / float64(hops)
+		if d := Distance(london, cp); !near(d, want, 0.1) || !near(d+Distance(cp, sydney), p.DistanceKm, 0.1) {
+			t.Errorf("Control point %d at %+v is off the path", i, cp)
+		}
+	}
+	if mid := p.Midpoint(); !near(Distance(london, mid), Distance(mid, sydney), 0.1) {
+		t.Errorf("Midpoint() = %+v is not half way", mid)
+	}
+}
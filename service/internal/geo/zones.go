@@ -0,0 +1,110 @@
+package geo
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// zonesCSV lists reference points with their CQ and ITU zones and continent
+//
+//go:embed zones.csv
+var zonesCSV string
+
+// Zones are the CQ (WAZ) and ITU zones and the continent of a location, with the reference point
+// they were taken from
+type Zones struct {
+	CQ        int    `json:"cq"`
+	ITU       int    `json:"itu"`
+	Continent string `json:"continent"` // one of Continents
+	Reference string `json:"reference"`
+	Prefix    string `json:"prefix"`
+}
+
+// Continents are the continent abbreviations used for DXCC and WAC, west to east
+var Continents = []string{"NA", "SA", "EU", "AF", "AS", "OC"}
+
+// ContinentName returns the name of a continent abbreviation, or the abbreviation if unknown
+func ContinentName(code string) string {
+	switch code {
+	case "NA":
+		return "North America"
+	case "SA":
+		return "South America"
+	case "EU":
+		return "Europe"
+	case "AF":
+		return "Africa"
+	case "AS":
+		return "Asia"
+	case "OC":
+		return "Oceania"
+	}
+	return code
+}
+
+type zoneReference struct {
+	Zones
+	Location Location
+}
+
+var zoneReferences = mustParseZones(zonesCSV)
+
+// ZonesOf returns the zones of a location. They are those of the nearest reference point, which
+// is reliable inside an entity but coarse near zone boundaries and over open ocean.
+func ZonesOf(loc Location) Zones {
+	best, bestKm := Zones{}, Circumference
+	for _, ref := range zoneReferences {
+		if km := Distance(loc, ref.Location); km < bestKm {
+			best, bestKm = ref.Zones, km
+		}
+	}
+	return best
+}
+
+// ZonesAt returns the zones of a point
+func ZonesAt(lat, lon float64) Zones {
+	return ZonesOf(Location{Latitude: lat, Longitude: lon})
+}
+
+// parseZones parses reference points: name, prefix, latitude, longitude, CQ zone, ITU zone, continent
+func parseZones(data string) ([]zoneReference, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 7
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zone references: %w", err)
+	}
+
+	refs := make([]zoneReference, 0, len(records))
+	for _, rec := range records {
+		lat, latErr := strconv.ParseFloat(rec[2], 64)
+		lon, lonErr := strconv.ParseFloat(rec[3], 64)
+		cq, cqErr := strconv.Atoi(rec[4])
+		itu, ituErr := strconv.Atoi(rec[5])
+		switch {
+		case latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180:
+			return nil, fmt.Errorf("invalid position for zone reference %q", rec[0])
+		case cqErr != nil || ituErr != nil || cq < 1 || cq > 40 || itu < 1 || itu > 90:
+			return nil, fmt.Errorf("invalid zones for zone reference %q", rec[0])
+		case ContinentName(rec[6]) == rec[6]:
+			return nil, fmt.Errorf("invalid continent for zone reference %q", rec[0])
+		}
+		refs = append(refs, zoneReference{
+			Zones:    Zones{CQ: cq, ITU: itu, Continent: rec[6], Reference: rec[0], Prefix: rec[1]},
+			Location: Location{Latitude: lat, Longitude: lon},
+		})
+	}
+	return refs, nil
+}
+
+func mustParseZones(data string) []zoneReference {
+	refs, err := parseZones(data)
+	if err != nil {
+		panic(err)
+	}
+	return refs
+}
@@ -0,0 +1,121 @@
+package geo
+
+import "testing"
+
+func TestZonesOf(t *testing.T) {
+	tests := []struct {
+		grid    string
+		cq, itu int
+	}{
+		{"FN31pr", 5, 8}, // Connecticut
+		{"FN42", 5, 8},   // Boston
+		{"EN61", 4, 8},   // Chicago
+		{"EN82", 4, 8},   // Detroit
+		{"EM12", 4, 7},   // Dallas
+		{"DM79", 4, 7},   // Denver
+		{"DN17", 4, 6},   // Montana west of 110°W
+		{"CN87", 3, 6},   // Seattle
+		{"CM87", 3, 6},   // San Francisco
+		{"EL96", 5, 8},   // Florida
+		{"FN25", 4, 4},   // Ontario
+		{"BP51", 1, 1},   // Alaska
+		{"BL11", 31, 61}, // Hawaii
+		{"FK68", 8, 11},  // Puerto Rico
+		{"FH17", 10, 12}, // Peru
+		{"GG66", 11, 15}, // Brazil
+		{"GF05", 13, 14}, // Argentina
+		{"FF46", 12, 14}, // Chile
+		{"IO91", 14, 27}, // England
+		{"JN18", 14, 27}, // France
+		{"IN80", 14, 37}, // Spain
+		{"JO62", 14, 28}, // Germany
+		{"JN61", 15, 28}, // Italy
+		{"KM18", 20, 28}, // Greece
+		{"KN41", 20, 39}, // European Turkey
+		{"KO85", 16, 29}, // Moscow
+		{"HP94", 40, 17}, // Iceland
+		{"KI88", 37, 48}, // Kenya
+		{"JJ00", 35, 46}, // Ghana
+		{"KG33", 38, 57}, // South Africa
+		{"NL07", 22, 41}, // India
+		{"OK03", 26, 49}, // Thailand
+		{"OJ11", 28, 54}, // Singapore
+		{"OL72", 24, 44}, // Hong Kong
+		{"PM95", 25, 45}, // Japan
+		{"OF78", 29, 58}, // Western Australia
+		{"QF56", 30, 59}, // New South Wales
+		{"RE78", 32, 60}, // New Zealand
+	}
+	for _, tt := range tests {
+		loc, err := ParseGrid(tt.grid)
+		if err != nil {
+			t.Fatalf("ParseGrid(%q) error = %v", tt.grid, err)
+		}
+		if z := ZonesOf(loc); z.CQ != tt.cq || z.ITU != tt.itu {
+			t.Errorf("ZonesOf(%s) = CQ %d ITU %d (from %s), want CQ %d ITU %d", tt.grid, z.CQ, z.ITU, z.Reference, tt.cq, tt.itu)
+		}
+	}
+
+	if z := ZonesAt(40.7, -74.0); z.Reference != "New York" || z.Prefix != "W2" {
+		t.Errorf("ZonesAt() = %+v, want the New York reference", z)
+	}
+}
+
+func TestContinents(t *testing.T) {
+	tests := map[string]string{
+		"FN31pr": "NA",
+		"FK68":   "NA", // Puerto Rico
+		"GP44":   "NA", // Greenland
+		"FH17":   "SA",
+		"IO91":   "EU",
+		"KM18":   "EU", // Greece, CQ zone 20
+		"KN41":   "EU", // European Turkey
+		"KM69":   "AS", // Ankara
+		"HP94":   "EU", // Iceland, CQ zone 40
+		"KO85":   "EU",
+		"MO06":   "AS", // Yekaterinburg
+		"IM63":   "AF", // Morocco
+		"KG33":   "AF",
+		"PM95":   "AS",
+		"BL11":   "OC", // Hawaii
+		"QF56":   "OC",
+	}
+	for grid, want := range tests {
+		loc, err := ParseGrid(grid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ZonesOf(loc).Continent; got != want {
+			t.Errorf("Continent of %s = %s, want %s", grid, got, want)
+		}
+	}
+	for _, code := range Continents {
+		if ContinentName(code) == code {
+			t.Errorf("Continent %s has no name", code)
+		}
+	}
+}
+
+func TestParseZones(t *testing.T) {
+	if len(zoneReferences) < 300 {
+		t.Errorf("Only %d zone references embedded", len(zoneReferences))
+	}
+
+	refs, err := parseZones("# comment\nLondon,G,51.5,-0.1,14,27,EU\n")
+	if err != nil || len(refs) != 1 || refs[0].CQ != 14 || refs[0].ITU != 27 || refs[0].Continent != "EU" || refs[0].Location.Longitude != -0.1 {
+		t.Errorf("parseZones() = %+v, %v", refs, err)
+	}
+
+	for _, data := range []string{
+		"London,G,51.5,-0.1,14,27\n",
+		"London,G,91,-0.1,14,27,EU\n",
+		"London,G,51.5,east,14,27,EU\n",
+		"London,G,51.5,-0.1,41,27,EU\n",
+		"London,G,51.5,-0.1,14,0,EU\n",
+		"London,G,51.5,-0.1,14,27,Europe\n",
+	} {
+		if _, err := parseZones(data); err == nil {
+			t.Errorf("parseZones(%q) expected an error", data)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package imagery
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	_ "image/jpeg" // register JPEG decoding for Helioviewer frames
+	_ "image/png"  // register PNG decoding for Helioviewer frames
+	"sort"
+	"time"
+
+	"radiocast/internal/raster"
+)
+
+// Frame is one source image of the Sun animation
+type Frame struct {
+	Image image.Image
+	Time  time.Time
+}
+
+// AnimationOptions controls how frames are assembled into the animations
+type AnimationOptions struct {
+	Width  int // output width in pixels; height keeps the aspect ratio of the first frame
+	Delay  int // delay between frames in 1/100 s
+	Loops  int // number of repetitions (0 loops forever)
+	Colors int // size of the palette shared by all frames (max 256)
+}
+
+// DefaultAnimationOptions returns the published animation settings: 512px wide, 4 fps, 10 loops, 64 colors
+func DefaultAnimationOptions() AnimationOptions {
+	return AnimationOptions{Width: 512, Delay: 25, Loops: 10, Colors: 64}
+}
+
+// DecodeFrame decodes a PNG or JPEG image downloaded from Helioviewer
+func DecodeFrame(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// EncodeAnimation scales and timestamps the frames, quantizes them with one shared palette and
+// encodes an animated GIF. The output depends only on the input, so it can be golden-tested.
+func EncodeAnimation(frames []Frame, opts AnimationOptions) ([]byte, error) {
+	out, err := EncodeAnimations(frames, opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return out[FormatGIF], nil
+}
+
+// EncodeAnimations encodes the frames as a GIF and in each of the extra formats. WebP reuses the
+// GIF palette and dithering.
+func EncodeAnimations(frames []Frame, opts AnimationOptions, formats []Format) (map[Format][]byte, error) {
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("no frames to encode")
+	}
+	if opts.Width <= 0 {
+		return nil, fmt.Errorf("invalid animation width %d", opts.Width)
+	}
+	if opts.Colors < 2 || opts.Colors > 256 {
+		return nil, fmt.Errorf("palette size must be between 2 and 256, got %d", opts.Colors)
+	}
+
+	sorted := make([]Frame, len(frames))
+	copy(sorted, frames)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	first := sorted[0].Image.Bounds()
+	height := max(1, first.Dy()*opts.Width/max(1, first.Dx()))
+
+	annotated := make([]*image.RGBA, len(sorted))
+	for i, f := range sorted {
+		canvas := raster.FromImage(scaleBox(f.Image, opts.Width, height))
+		annotateTimestamp(canvas, f.Time)
+		annotated[i] = canvas.Image()
+	}
+
+	q := newQuantizer(annotated, opts.Colors)
+	anim := &gif.GIF{
+		LoopCount: opts.Loops,
+		Config:    image.Config{ColorModel: q.palette, Width: opts.Width, Height: height},
+	}
+	paletted := make([]*image.Paletted, len(annotated))
+	for i, img := range annotated {
+		paletted[i] = q.dither(img)
+		anim.Image = append(anim.Image, paletted[i])
+		anim.Delay = append(anim.Delay, opts.Delay)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, fmt.Errorf("failed to encode GIF: %w", err)
+	}
+	out := map[Format][]byte{FormatGIF: buf.Bytes()}
+	for _, format := range formats {
+		switch format {
+		case FormatWebP:
+			out[format] = encodeWebP(paletted, q.palette, opts)
+		}
+	}
+	return out, nil
+}
+
+// annotateTimestamp draws the frame time centered at the bottom on a translucent band
+func annotateTimestamp(c *raster.Canvas, t time.Time) {
+	label := t.UTC().Format("Jan 02 15:04 UTC")
+	scale := max(1, c.Width()/170)
+	pad := 2 * scale
+	textW, textH := raster.TextWidth(label, scale), raster.TextHeight(scale)
+	y := c.Height() - textH - 4*scale
+	x := c.Width() / 2
+	c.FillRect(x-textW/2-pad, y-pad, x+textW/2+pad, y+textH+pad, color.RGBA{A: 0x99})
+	c.Text(x, y, label, scale, color.White, raster.AlignCenter)
+}
+
+// scaleBox resizes an image by averaging the source pixels covered by each output pixel
+func scaleBox(src image.Image, width, height int) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy0 := b.Min.Y + y*b.Dy()/height
+		sy1 := max(sy0+1, b.Min.Y+(y+1)*b.Dy()/height)
+		for x := 0; x < width; x++ {
+			sx0 := b.Min.X + x*b.Dx()/width
+			sx1 := max(sx0+1, b.Min.X+(x+1)*b.Dx()/width)
+			var r, g, bl, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, _ := src.At(sx, sy).RGBA()
+					r, g, bl, n = r+uint64(cr), g+uint64(cg), bl+uint64(cb), n+1
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n >> 8), uint8(g / n >> 8), uint8(bl / n >> 8), 0xff})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,113 @@
+package imagery
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// syntheticSun draws a limb-darkened disk with a bright region that rotates between frames
+func syntheticSun(size, step int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	c := float64(size) / 2
+	spotX := c + c*0.5*math.Cos(float64(step)*0.6)
+	spotY := c + c*0.3*math.Sin(float64(step)*0.6)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			d := math.Hypot(float64(x)-c, float64(y)-c) / (c * 0.85)
+			v := 0.0
+			if d < 1 {
+				v = 0.55 + 0.45*math.Sqrt(1-d*d)
+			}
+			v += 0.6 * math.Exp(-math.Hypot(float64(x)-spotX, float64(y)-spotY)/(c*0.12))
+			v = math.Min(1, v)
+			img.Set(x, y, color.RGBA{uint8(255 * v), uint8(180 * v * v), uint8(60 * v * v * v), 0xff})
+		}
+	}
+	return img
+}
+
+func testFrames(t *testing.T) []Frame {
+	t.Helper()
+	start := time.Date(2025, 9, 17, 0, 0, 0, 0, time.UTC)
+	var frames []Frame
+	for i := 0; i < 4; i++ {
+		// Round-trip through PNG like real Helioviewer downloads
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, syntheticSun(192, i)); err != nil {
+			t.Fatal(err)
+		}
+		img, err := DecodeFrame(buf.Bytes())
+		if err != nil {
+			t.Fatalf("DecodeFrame failed: %v", err)
+		}
+		frames = append(frames, Frame{Image: img, Time: start.Add(time.Duration(i) * time.Hour)})
+	}
+	// Out of order input must still produce chronological frames
+	frames[0], frames[3] = frames[3], frames[0]
+	return frames
+}
+
+func TestEncodeAnimationGolden(t *testing.T) {
+	opts := AnimationOptions{Width: 96, Delay: 25, Loops: 10, Colors: 32}
+	out, err := EncodeAnimation(testFrames(t), opts)
+	if err != nil {
+		t.Fatalf("EncodeAnimation failed: %v", err)
+	}
+
+	again, _ := EncodeAnimation(testFrames(t), opts)
+	if !bytes.Equal(out, again) {
+		t.Fatal("EncodeAnimation output is not deterministic")
+	}
+
+	anim, err := gif.DecodeAll(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("output is not a valid GIF: %v", err)
+	}
+	if len(anim.Image) != 4 || anim.LoopCount != 10 || anim.Delay[0] != 25 {
+		t.Errorf("got %d frames, loop %d, delay %d", len(anim.Image), anim.LoopCount, anim.Delay[0])
+	}
+	if b := anim.Image[0].Bounds(); b.Dx() != 96 || b.Dy() != 96 {
+		t.Errorf("frame size = %v, want 96x96", b)
+	}
+	if n := len(anim.Image[0].Palette); n > 32 {
+		t.Errorf("palette has %d colors, want at most 32", n)
+	}
+
+	golden := filepath.Join("testdata", "sun_animation.gif")
+	if *updateGolden {
+		if err := os.WriteFile(golden, out, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("missing golden file (run go test -update): %v", err)
+	}
+	if !bytes.Equal(out, want) {
+		t.Errorf("animation differs from %s (run go test ./internal/imagery -update after intended changes)", golden)
+	}
+}
+
+func TestEncodeAnimationValidation(t *testing.T) {
+	if _, err := EncodeAnimation(nil, DefaultAnimationOptions()); err == nil {
+		t.Error("expected error for no frames")
+	}
+	frames := []Frame{{Image: syntheticSun(16, 0), Time: time.Now()}}
+	if _, err := EncodeAnimation(frames, AnimationOptions{Width: 16, Colors: 300}); err == nil {
+		t.Error("expected error for oversized palette")
+	}
+	if _, err := DecodeFrame([]byte("not an image")); err == nil {
+		t.Error("expected error decoding garbage")
+	}
+}
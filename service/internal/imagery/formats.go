@@ -0,0 +1,57 @@
+package imagery
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format is a file format of the Sun animations
+type Format string
+
+const (
+	FormatGIF  Format = "gif"  // always produced; fallback for the other formats, email and PDF
+	FormatWebP Format = "webp" // animated lossless WebP with the GIF palette, smaller than the GIF
+)
+
+// ParseFormats resolves the configured extra formats (case-insensitive, duplicates ignored).
+// GIF is always produced, so it is accepted but not returned.
+func ParseFormats(names []string) ([]Format, error) {
+	var formats []Format
+	seen := make(map[Format]bool)
+	for _, name := range names {
+		f := Format(strings.ToLower(strings.TrimSpace(name)))
+		if f == "" || f == FormatGIF || seen[f] {
+			continue
+		}
+		if f != FormatWebP {
+			return nil, fmt.Errorf("unknown imagery format %q", name)
+		}
+		seen[f] = true
+		formats = append(formats, f)
+	}
+	return formats, nil
+}
+
+// File returns the name of the product's animation in the given format
+func (p Product) File(f Format) string {
+	return strings.TrimSuffix(p.FileName, ".gif") + "." + string(f)
+}
+
+// SunImage is a product whose animation was generated, with the formats available besides GIF
+type SunImage struct {
+	Product
+	Formats []Format
+}
+
+// Has reports whether the animation is available in format f
+func (s SunImage) Has(f Format) bool {
+	if f == FormatGIF {
+		return true
+	}
+	for _, format := range s.Formats {
+		if format == f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package imagery
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"radiocast/internal/storage"
+)
+
+// frameCachePrefix is where downloaded Helioviewer images are kept between report runs
+const frameCachePrefix = "cache/helioviewer/"
+
+// FrameCacheMaxAge is how long a cached frame is kept. A frame is cached when it falls inside
+// the animation window, so once it is older than the window (plus an hour for the closest-image
+// lookup) no animation can use it again.
+const FrameCacheMaxAge = (animationHours + 1) * time.Hour
+
+// FrameCache stores downloaded Helioviewer images by image ID. Images never change once
+// published; entries are only dropped by Prune once they have aged out of every animation.
+type FrameCache interface {
+	// Get returns the cached image, or nil if it is not cached
+	Get(ctx context.Context, id int64) ([]byte, error)
+	Put(ctx context.Context, id int64, data []byte) error
+	// Prune deletes the images cached before cutoff and returns how many were deleted
+	Prune(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+// StorageFrameCache keeps frames in the report storage (local_gcs or the GCS bucket)
+type StorageFrameCache struct {
+	storage storage.StorageClient
+}
+
+// NewStorageFrameCache creates a frame cache on top of a storage client
+func NewStorageFrameCache(storageClient storage.StorageClient) *StorageFrameCache {
+	return &StorageFrameCache{storage: storageClient}
+}
+
+// Get returns the cached image, or nil if it is not cached
+func (c *StorageFrameCache) Get(ctx context.Context, id int64) ([]byte, error) {
+	path := frameCachePath(id)
+	exists, err := c.storage.FileExists(ctx, path)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return c.storage.GetFile(ctx, path)
+}
+
+// Put stores an image in the cache
+func (c *StorageFrameCache) Put(ctx context.Context, id int64, data []byte) error {
+	return c.storage.StoreFile(ctx, frameCachePath(id), data)
+}
+
+// Prune deletes the images stored before cutoff
+func (c *StorageFrameCache) Prune(ctx context.Context, cutoff time.Time) (int, error) {
+	paths, err := c.storage.ListDir(ctx, frameCachePrefix, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list frame cache: %w", err)
+	}
+	deleted := 0
+	for _, path := range paths {
+		info, err := c.storage.Stat(ctx, path)
+		if err != nil || !info.ModTime.Before(cutoff) {
+			continue // listed directories and concurrently removed frames have nothing to prune
+		}
+		if err := c.storage.Delete(ctx, path); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func frameCachePath(id int64) string {
+	return frameCachePrefix + strconv.FormatInt(id, 10)
+}
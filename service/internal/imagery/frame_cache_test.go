@@ -0,0 +1,41 @@
+package imagery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"radiocast/internal/storage"
+)
+
+func TestStorageFrameCachePrune(t *testing.T) {
+	ctx := context.Background()
+	client := storage.NewMemoryStorageClient()
+	cache := NewStorageFrameCache(client)
+	for _, id := range []int64{1001, 1002} {
+		if err := cache.Put(ctx, id, []byte("frame")); err != nil {
+			t.Fatalf("Put(%d): %v", id, err)
+		}
+	}
+	if err := client.StoreFile(ctx, "reports/keep.json", []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	if pruned, err := cache.Prune(ctx, time.Now().Add(-FrameCacheMaxAge)); err != nil || pruned != 0 {
+		t.Fatalf("Prune of fresh frames = %d, %v; want 0, nil", pruned, err)
+	}
+	if data, _ := cache.Get(ctx, 1001); data == nil {
+		t.Fatal("fresh frame was pruned")
+	}
+
+	pruned, err := cache.Prune(ctx, time.Now().Add(time.Second))
+	if err != nil || pruned != 2 {
+		t.Fatalf("Prune of expired frames = %d, %v; want 2, nil", pruned, err)
+	}
+	if data, _ := cache.Get(ctx, 1002); data != nil {
+		t.Error("expired frame is still cached")
+	}
+	if exists, _ := client.FileExists(ctx, "reports/keep.json"); !exists {
+		t.Error("Prune deleted a file outside the frame cache")
+	}
+}
@@ -1,175 +1,275 @@
 package imagery
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"image"
 	"io"
 	"net/http"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"radiocast/internal/logger"
 )
 
-// GenerateSunGIF downloads last 72 hourly solar images from Helioviewer and assembles them into a GIF.
-// The GIF duration is ~24s (4 fps across 72 frames) and loops 10 times. Each frame is annotated with the UTC hour label.
-func GenerateSunGIF(ctx context.Context, reportDir string, ts time.Time, outputGIF string) error {
-	// Create a single temporary directory for all processing
-	tmpRootDir := filepath.Join(os.TempDir(), fmt.Sprintf("helio_gif_%d", time.Now().UnixNano()))
-	framesDir := filepath.Join(tmpRootDir, "frames")
-	annDir := filepath.Join(tmpRootDir, "annotated")
-	finalDir := filepath.Join(tmpRootDir, "final")
-
-	// Create all necessary directories
-	for _, dir := range []string{framesDir, annDir, finalDir} {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", dir, err)
-		}
+// helioviewerAPI is the base URL of the Helioviewer v2 API
+const helioviewerAPI = "https://api.helioviewer.org/v2/"
+
+// DefaultWorkers is the number of concurrent Helioviewer requests when none is configured
+const DefaultWorkers = 6
+
+// animationHours is the number of hourly frames in each animation
+const animationHours = 72
+
+// GenerateSunGIF downloads last 72 hourly SDO/AIA 171 images from Helioviewer and assembles them into a GIF.
+func GenerateSunGIF(ctx context.Context, ts time.Time) ([]byte, error) {
+	return GenerateProductGIF(ctx, Products[0], ts)
+}
+
+// GenerateProductGIF downloads last 72 hourly images of a product from Helioviewer and assembles them into a GIF,
+// without a frame cache.
+func GenerateProductGIF(ctx context.Context, product Product, ts time.Time) ([]byte, error) {
+	gifData, _, err := NewFrameFetcher(nil, DefaultWorkers).GenerateProductGIF(ctx, product, ts)
+	return gifData, err
+}
+
+// FetchStats summarizes the Helioviewer requests of one animation
+type FetchStats struct {
+	Product    string  `json:"product"`
+	Hours      int     `json:"hours"`      // hourly frames requested
+	Frames     int     `json:"frames"`     // frames in the animation
+	Duplicates int     `json:"duplicates"` // hours resolved to an image already used by another hour
+	CacheHits  int     `json:"cache_hits"` // images read from the frame cache
+	Downloads  int     `json:"downloads"`  // images downloaded from Helioviewer
+	Failures   int     `json:"failures"`   // hours without a usable image
+	DurationS  float64 `json:"duration_s"` // wall-clock time of the fetch and encoding
+}
+
+// FrameFetcher downloads Helioviewer frames with a bounded worker pool and an optional cache
+type FrameFetcher struct {
+	client  *hvHTTP
+	cache   FrameCache // nil disables caching
+	workers int
+	options AnimationOptions
+	formats []Format // produced besides the GIF
+
+	sourcesMu sync.Mutex
+	sources   []byte // getDataSources response, shared by all products of a run
+}
+
+// NewFrameFetcher creates a fetcher; cache may be nil
+func NewFrameFetcher(cache FrameCache, workers int) *FrameFetcher {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	return &FrameFetcher{
+		client:  &hvHTTP{baseURL: helioviewerAPI, client: &http.Client{Timeout: 30 * time.Second}},
+		cache:   cache,
+		workers: workers,
+		options: DefaultAnimationOptions(),
 	}
+}
+
+// SetFormats sets the formats produced besides the GIF
+func (f *FrameFetcher) SetFormats(formats []Format) {
+	f.formats = formats
+}
 
-	// Ensure cleanup of all temporary files when done
-	// defer os.RemoveAll(tmpRootDir)
+// PruneCache drops cached frames that are too old for an animation ending at ts
+func (f *FrameFetcher) PruneCache(ctx context.Context, ts time.Time) (int, error) {
+	if f.cache == nil {
+		return 0, nil
+	}
+	return f.cache.Prune(ctx, ts.Add(-FrameCacheMaxAge))
+}
+
+// GenerateProductGIF downloads last 72 hourly images of a product from Helioviewer and assembles them into a GIF.
+func (f *FrameFetcher) GenerateProductGIF(ctx context.Context, product Product, ts time.Time) ([]byte, *FetchStats, error) {
+	animations, stats, err := f.GenerateProductAnimations(ctx, product, ts)
+	return animations[FormatGIF], stats, err
+}
 
-	client := &hvHTTP{timeout: 30 * time.Second}
+// GenerateProductAnimations downloads last 72 hourly images of a product from Helioviewer and assembles them into
+// a GIF and the configured extra formats. The duration is ~18s (4 fps across 72 frames) and the GIF loops 10 times.
+// Each frame is annotated with the UTC hour label.
+// Everything happens in memory; no external tools or temporary files are needed.
+func (f *FrameFetcher) GenerateProductAnimations(ctx context.Context, product Product, ts time.Time) (map[Format][]byte, *FetchStats, error) {
+	started := time.Now()
+	stats := &FetchStats{Product: product.ID, Hours: animationHours}
 
-	// Resolve a Helioviewer sourceId for SDO/AIA with preferred measurements
-	sourceID, err := client.lookupSourceID(ctx, "SDO", "AIA", "AIA", "171")
+	// Resolve the Helioviewer sourceId of the product
+	sourceID, err := f.lookupSourceID(ctx, product)
 	if err != nil {
-		return fmt.Errorf("helio datasource lookup failed; %w", err)
+		return nil, stats, fmt.Errorf("helio datasource lookup failed; %w", err)
 	}
-	logger.Debugf("SunGIF: Using SDO/AIA 171 with sourceID: %d", sourceID)
+	logger.Debugf("SunGIF: Using %s (%s) with sourceID: %d", product.Name, product.ID, sourceID)
 
 	// Generate time points for the last 72 hours
 	base := ts.UTC().Truncate(time.Hour)
-	var hours []time.Time
-	for i := 71; i >= 0; i-- {
-		hours = append(hours, base.Add(-time.Duration(i)*time.Hour))
+	hours := make([]time.Time, animationHours)
+	for i := range hours {
+		hours[i] = base.Add(-time.Duration(animationHours-1-i) * time.Hour)
 	}
 
-	// Download and process images for each hour
-	var frames []string
-	for i, t := range hours {
-		dateStr := t.Format("2006-01-02T15:04:05Z")
-		id, err := client.getClosestImageIDBySourceID(ctx, dateStr, sourceID)
+	// Resolve the closest image of each hour
+	ids := make([]int64, len(hours))
+	f.forEach(ctx, len(hours), func(i int) {
+		dateStr := hours[i].Format("2006-01-02T15:04:05Z")
+		id, err := f.client.getClosestImageIDBySourceID(ctx, dateStr, sourceID)
 		if err != nil {
 			logger.Debugf("SunGIF: getClosestImage failed for %s: %v", dateStr, err)
-			continue
+			return
 		}
-
-		// Download the image
-		data, ext, err := client.downloadThumbnail(ctx, id)
-		if err != nil {
-			logger.Debugf("SunGIF: downloadThumbnail failed for %s: %v", dateStr, err)
-			continue
+		ids[i] = id
+	})
+
+	// Adjacent hours can resolve to the same image (e.g. during data gaps); keep the first hour only
+	var unique []int
+	seen := make(map[int64]bool)
+	for i, id := range ids {
+		switch {
+		case id == 0:
+			stats.Failures++
+		case seen[id]:
+			stats.Duplicates++
+		default:
+			seen[id] = true
+			unique = append(unique, i)
 		}
+	}
 
-		// Save the original image
-		framePath := filepath.Join(framesDir, fmt.Sprintf("frame_%02d.%s", i, ext))
-		if err := os.WriteFile(framePath, data, 0644); err != nil {
-			logger.Debugf("SunGIF: failed to write frame: %v", err)
-			continue
+	// Fetch and decode each distinct image, from the cache when possible
+	decoded := make([]image.Image, len(unique))
+	var mu sync.Mutex
+	count := func(counter *int) {
+		mu.Lock()
+		*counter++
+		mu.Unlock()
+	}
+	f.forEach(ctx, len(unique), func(n int) {
+		i := unique[n]
+		data, cached := f.cachedImage(ctx, ids[i])
+		if cached {
+			count(&stats.CacheHits)
+		} else {
+			var err error
+			data, err = f.client.downloadImage(ctx, ids[i])
+			if err != nil {
+				logger.Debugf("SunGIF: downloadImage failed for %s: %v", hours[i].Format(time.RFC3339), err)
+				count(&stats.Failures)
+				return
+			}
+			count(&stats.Downloads)
 		}
 
-		// Convert PNG to JPG if needed for consistency
-		jpgFramePath := filepath.Join(framesDir, fmt.Sprintf("frame_%02d.jpg", i))
-		if ext == "png" {
-			convCmd := exec.CommandContext(ctx, "ffmpeg", "-y", "-i", framePath, jpgFramePath)
-			convCmd.Stdout = io.Discard
-			convCmd.Stderr = io.Discard
-			if err := convCmd.Run(); err != nil {
-				logger.Debugf("SunGIF: PNG to JPG conversion failed: %v", err)
-				continue
+		img, err := DecodeFrame(data)
+		if err != nil {
+			logger.Debugf("SunGIF: %v", err)
+			count(&stats.Failures)
+			return
+		}
+		if !cached && f.cache != nil {
+			if err := f.cache.Put(ctx, ids[i], data); err != nil {
+				logger.Warn("Failed to cache Helioviewer frame", map[string]interface{}{"id": ids[i], "error": err.Error()})
 			}
-			framePath = jpgFramePath
 		}
+		decoded[n] = img
+	})
 
-		// Add timestamp annotation with the correct time for each frame
-		timestamp := t.Format("Jan 02 15:04 UTC")
-		annPath := filepath.Join(annDir, fmt.Sprintf("frame_%02d.jpg", i))
-
-		// Use ffmpeg to add timestamp
-		timestamp = strings.ReplaceAll(timestamp, ":", "\\:") // Escape colons for ffmpeg
-		drawtext := fmt.Sprintf(
-			"drawtext=text='%s':fontcolor=white:fontsize=32:box=1:boxcolor=white@0.5:boxborderw=5:x=(w-text_w)/2:y=h-th-10",
-			timestamp,
-		)
-		cmd := exec.CommandContext(
-			ctx, 
-			"ffmpeg", 
-			"-y", 
-			"-i", framePath,
-			"-vf", drawtext,
-			annPath)
-		cmd.Stdout = io.Discard
-		cmd.Stderr = io.Discard
-		if err := cmd.Run(); err != nil {
-			logger.Debugf("SunGIF: annotation failed: %v", err)
-			continue
+	var frames []Frame
+	for n, img := range decoded {
+		if img != nil {
+			frames = append(frames, Frame{Image: img, Time: hours[unique[n]]})
 		}
-
-		frames = append(frames, annPath)
 	}
+	stats.Frames = len(frames)
 
 	// Check if we have any frames
 	if len(frames) == 0 {
-		return fmt.Errorf("no frames were successfully processed")
+		stats.DurationS = time.Since(started).Seconds()
+		return nil, stats, fmt.Errorf("no frames were successfully processed")
 	}
 	logger.Debugf("SunGIF: Found %d frames for GIF assembly", len(frames))
 
-	// Sort frames to ensure correct order
-	sort.Strings(frames)
-
-	// Copy frames to final directory with sequential numbering
-	for i, frame := range frames {
-		destPath := filepath.Join(finalDir, fmt.Sprintf("frame%02d.jpg", i))
-		srcData, err := os.ReadFile(frame)
-		if err != nil {
-			return fmt.Errorf("failed to read frame %s: %w", frame, err)
-		}
-		if err := os.WriteFile(destPath, srcData, 0644); err != nil {
-			return fmt.Errorf("failed to write final frame: %w", err)
-		}
+	animations, err := EncodeAnimations(frames, f.options, f.formats)
+	stats.DurationS = time.Since(started).Seconds()
+	if err != nil {
+		return nil, stats, fmt.Errorf("assemble animation: %w", err)
 	}
 
-	// Use the optimized ffmpeg command for GIF generation
-	cmd := exec.CommandContext(ctx, "ffmpeg", "-y",
-		"-framerate", "4",
-		"-i", filepath.Join(finalDir, "frame%02d.jpg"),
-		"-vf", "scale=512:-1:flags=lanczos,split[s0][s1];[s0]palettegen=max_colors=64:stats_mode=diff[p];[s1][p]paletteuse=dither=bayer:bayer_scale=5:diff_mode=rectangle",
-		"-loop", "10",
-		outputGIF,
-	)
-
-	// Capture stderr for logging
-	var stderr bytes.Buffer
-	cmd.Stdout = io.Discard
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		logger.Debugf("SunGIF: ffmpeg stderr: %s", stderr.String())
-		return fmt.Errorf("assemble gif: %w", err)
+	fields := map[string]interface{}{
+		"product":    product.ID,
+		"frames":     stats.Frames,
+		"bytes":      len(animations[FormatGIF]),
+		"cache_hits": stats.CacheHits,
+		"downloads":  stats.Downloads,
+		"duplicates": stats.Duplicates,
+		"failures":   stats.Failures,
+		"duration_s": stats.DurationS,
 	}
+	for _, format := range f.formats {
+		fields[string(format)+"_bytes"] = len(animations[format])
+	}
+	logger.Info("SunGIF: Created animation", fields)
+	return animations, stats, nil
+}
 
-	// GIF generation completed successfully
+// cachedImage returns a frame from the cache; cache errors are treated as misses
+func (f *FrameFetcher) cachedImage(ctx context.Context, id int64) ([]byte, bool) {
+	if f.cache == nil {
+		return nil, false
+	}
+	data, err := f.cache.Get(ctx, id)
+	if err != nil {
+		logger.Debugf("SunGIF: frame cache read failed for %d: %v", id, err)
+		return nil, false
+	}
+	return data, data != nil
+}
 
-	// Verify the GIF was created
-	if _, err := os.Stat(outputGIF); err != nil {
-		return fmt.Errorf("GIF not created: %w", err)
+// forEach calls fn for 0..n-1 on at most f.workers goroutines and waits for all of them.
+// Remaining items are skipped once ctx is cancelled.
+func (f *FrameFetcher) forEach(ctx context.Context, n int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(f.workers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
 	}
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
 
-	logger.Infof("SunGIF: Successfully created %s", outputGIF)
-	return nil
+// lookupSourceID resolves the sourceId of a product, fetching getDataSources once per fetcher
+func (f *FrameFetcher) lookupSourceID(ctx context.Context, product Product) (int64, error) {
+	f.sourcesMu.Lock()
+	defer f.sourcesMu.Unlock()
+	if f.sources == nil {
+		b, err := f.client.get(ctx, f.client.baseURL+"getDataSources/")
+		if err != nil {
+			return 0, err
+		}
+		f.sources = b
+	}
+	return parseSourceID(f.sources, product.Observatory, product.Instrument, product.Detector, product.Measurement)
 }
 
 // hvHTTP is a lightweight HTTP helper for Helioviewer calls
-type hvHTTP struct{ timeout time.Duration }
+type hvHTTP struct {
+	baseURL string
+	client  *http.Client
+}
 
 func (c *hvHTTP) get(ctx context.Context, url string) ([]byte, error) {
 	logger.Debugf("Helioviewer API request: GET %s", url)
@@ -177,8 +277,7 @@ func (c *hvHTTP) get(ctx context.Context, url string) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	hc := &http.Client{Timeout: c.timeout}
-	resp, err := hc.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -198,7 +297,7 @@ func (c *hvHTTP) get(ctx context.Context, url string) ([]byte, error) {
 }
 
 func (c *hvHTTP) getClosestImageIDBySourceID(ctx context.Context, date string, sourceID int64) (int64, error) {
-	url := fmt.Sprintf("https://api.helioviewer.org/v2/getClosestImage/?date=%s&sourceId=%d", date, sourceID)
+	url := fmt.Sprintf("%sgetClosestImage/?date=%s&sourceId=%d", c.baseURL, date, sourceID)
 	b, err := c.get(ctx, url)
 	if err != nil {
 		return 0, err
@@ -239,160 +338,57 @@ func (c *hvHTTP) getClosestImageIDBySourceID(ctx context.Context, date string, s
 	return imageID, nil
 }
 
-func (c *hvHTTP) downloadThumbnail(ctx context.Context, id int64) ([]byte, string, error) {
-	// Use the downloadImage endpoint to get a pre-colorized PNG image
-	url := fmt.Sprintf("https://api.helioviewer.org/v2/downloadImage/?id=%d&width=1024", id)
+// downloadImage fetches a pre-colorized PNG or JPEG rendering of the image
+func (c *hvHTTP) downloadImage(ctx context.Context, id int64) ([]byte, error) {
+	url := fmt.Sprintf("%sdownloadImage/?id=%d&width=1024", c.baseURL, id)
 	logger.Debugf("SunGIF: Downloading colorized image from %s", url)
 	b, err := c.get(ctx, url)
 	if err != nil {
-		logger.Debugf("SunGIF: downloadImage failed: %v, falling back to JP2", err)
-		// Fall back to JP2 if downloadImage endpoint fails
-		url = fmt.Sprintf("https://api.helioviewer.org/v2/getJP2Image/?id=%d", id)
-		b, err = c.get(ctx, url)
-		if err != nil {
-			return nil, "", err
-		}
-
-		// Convert JP2 to JPEG using ffmpeg with color mapping for SDO/AIA 304
-		tmpFile := filepath.Join(os.TempDir(), fmt.Sprintf("helio_%d.jp2", id))
-		tmpJpg := filepath.Join(os.TempDir(), fmt.Sprintf("helio_%d.jpg", id))
-		if err := os.WriteFile(tmpFile, b, 0644); err != nil {
-			return nil, "", err
-		}
-
-		// Apply a color palette to the image - SDO/AIA 304 is typically rendered in reddish-orange
-		// Using a colormap filter to convert grayscale to color
-		cmd := exec.CommandContext(ctx, "ffmpeg", "-y", "-i", tmpFile,
-			"-vf", "eq=gamma=1.5:saturation=2,colorchannelmixer=.5:.8:.1:0:.2:.5:.1:0:.1:.2:.5:0",
-			tmpJpg)
-		cmd.Stdout = io.Discard
-		cmd.Stderr = io.Discard
-		if err := cmd.Run(); err != nil {
-			return nil, "", fmt.Errorf("failed to convert JP2 to JPG: %w", err)
-		}
-
-		// Read the converted JPG
-		jpgData, err := os.ReadFile(tmpJpg)
-		if err != nil {
-			return nil, "", fmt.Errorf("failed to read converted JPG: %w", err)
-		}
-
-		// Clean up temp files
-		os.Remove(tmpFile)
-		os.Remove(tmpJpg)
-
-		return jpgData, "jpg", nil
+		return nil, err
 	}
 
 	// Check content type of downloaded image
 	contentType := http.DetectContentType(b)
 	logger.Debugf("SunGIF: Downloaded image content type: %s", contentType)
 
-	if strings.Contains(contentType, "image/png") {
-		return b, "png", nil
-	} else if strings.Contains(contentType, "image/jpeg") {
-		return b, "jpg", nil
+	if !strings.Contains(contentType, "image/png") && !strings.Contains(contentType, "image/jpeg") {
+		return nil, fmt.Errorf("unknown content type: %s", contentType)
 	}
-
-	// If we got here, we have an unknown content type
-	return nil, "", fmt.Errorf("unknown content type: %s", contentType)
+	return b, nil
 }
 
-// lookupSourceID queries getDataSources and finds a sourceId matching the given parameters.
-func (c *hvHTTP) lookupSourceID(ctx context.Context, observatory, instrument, detector, measurement string) (int64, error) {
-	// Endpoint returns a JSON of available sources
-	url := "https://api.helioviewer.org/v2/getDataSources/"
-	b, err := c.get(ctx, url)
-	if err != nil {
-		return 0, err
-	}
-
-	// The actual structure is a flat map: { "SDO": { "AIA": { "304": { "sourceId": N } } } }
-	// Parse as generic map to handle the variable structure
+// parseSourceID walks the nested getDataSources response down to the sourceId
+func parseSourceID(body []byte, observatory, instrument, detector, measurement string) (int64, error) {
 	var data map[string]interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
+	if err := json.Unmarshal(body, &data); err != nil {
 		return 0, fmt.Errorf("failed to parse data sources: %w", err)
 	}
 
-	// Navigate through the nested maps to find the sourceId
-	obsData, ok := data[observatory]
-	if !ok {
-		return 0, fmt.Errorf("observatory not found: %s", observatory)
+	levels := []struct{ kind, name string }{
+		{"observatory", observatory},
+		{"instrument", instrument},
+		{"detector", detector},
+		{"measurement", measurement},
 	}
-
-	obsMap, ok := obsData.(map[string]interface{})
-	if !ok {
-		return 0, fmt.Errorf("invalid observatory data format")
-	}
-
-	instData, ok := obsMap[instrument]
-	if !ok {
-		return 0, fmt.Errorf("instrument not found: %s", instrument)
-	}
-
-	// For SDO/AIA, the structure is SDO->AIA->304->sourceId (no detector level)
-	var sourceID int64
-	if detector == "AIA" && instrument == "AIA" {
-		// Special case for SDO/AIA where detector is skipped
-		instMap, ok := instData.(map[string]interface{})
-		if !ok {
-			return 0, fmt.Errorf("invalid instrument data format")
-		}
-
-		measData, ok := instMap[measurement]
-		if !ok {
-			return 0, fmt.Errorf("measurement not found: %s", measurement)
-		}
-
-		measMap, ok := measData.(map[string]interface{})
-		if !ok {
-			return 0, fmt.Errorf("invalid measurement data format")
-		}
-
-		sourceIDFloat, ok := measMap["sourceId"].(float64)
-		if !ok {
-			return 0, fmt.Errorf("sourceId not found or invalid")
-		}
-
-		sourceID = int64(sourceIDFloat)
-	} else {
-		// General case with detector level
-		instMap, ok := instData.(map[string]interface{})
-		if !ok {
-			return 0, fmt.Errorf("invalid instrument data format")
-		}
-
-		detData, ok := instMap[detector]
-		if !ok {
-			return 0, fmt.Errorf("detector not found: %s", detector)
-		}
-
-		detMap, ok := detData.(map[string]interface{})
-		if !ok {
-			return 0, fmt.Errorf("invalid detector data format")
-		}
-
-		measData, ok := detMap[measurement]
-		if !ok {
-			return 0, fmt.Errorf("measurement not found: %s", measurement)
+	node := data
+	for _, level := range levels {
+		if level.name == "" {
+			continue
 		}
-
-		measMap, ok := measData.(map[string]interface{})
+		child, ok := node[level.name]
 		if !ok {
-			return 0, fmt.Errorf("invalid measurement data format")
+			return 0, fmt.Errorf("%s not found: %s", level.kind, level.name)
 		}
-
-		sourceIDFloat, ok := measMap["sourceId"].(float64)
+		childMap, ok := child.(map[string]interface{})
 		if !ok {
-			return 0, fmt.Errorf("sourceId not found or invalid")
+			return 0, fmt.Errorf("invalid %s data format", level.kind)
 		}
-
-		sourceID = int64(sourceIDFloat)
+		node = childMap
 	}
 
-	if sourceID == 0 {
+	sourceIDFloat, ok := node["sourceId"].(float64)
+	if !ok || sourceIDFloat == 0 {
 		return 0, fmt.Errorf("no sourceId for %s/%s/%s/%s", observatory, instrument, detector, measurement)
 	}
-
-	return sourceID, nil
+	return int64(sourceIDFloat), nil
 }
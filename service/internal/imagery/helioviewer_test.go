@@ -0,0 +1,127 @@
+package imagery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type memoryFrameCache struct {
+	mu     sync.Mutex
+	frames map[int64][]byte
+}
+
+func (c *memoryFrameCache) Get(ctx context.Context, id int64) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.frames[id], nil
+}
+
+func (c *memoryFrameCache) Put(ctx context.Context, id int64, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frames[id] = data
+	return nil
+}
+
+func (c *memoryFrameCache) Prune(ctx context.Context, cutoff time.Time) (int, error) {
+	return 0, nil
+}
+
+// fakeHelioviewer resolves two adjacent hours to the same image and fails to serve image 1010
+func fakeHelioviewer(t *testing.T, workers int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	for i := range img.Pix {
+		img.Pix[i] = 0xc0
+	}
+	var frame bytes.Buffer
+	if err := png.Encode(&frame, img); err != nil {
+		t.Fatal(err)
+	}
+
+	var downloads, active, peak atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/getDataSources/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"SDO": {"AIA": {"171": {"sourceId": 10}}}}`)
+	})
+	mux.HandleFunc("/getClosestImage/", func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(2 * time.Millisecond)
+		date, err := time.Parse("2006-01-02T15:04:05Z", r.URL.Query().Get("date"))
+		if err != nil || r.URL.Query().Get("sourceId") != "10" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		hour := int(date.Sub(start) / time.Hour)
+		fmt.Fprintf(w, `{"id": "%d"}`, 1000+hour/2)
+	})
+	mux.HandleFunc("/downloadImage/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") == "1010" {
+			http.NotFound(w, r)
+			return
+		}
+		downloads.Add(1)
+		w.Write(frame.Bytes())
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		server.Close()
+		if int(peak.Load()) > workers {
+			t.Errorf("Expected at most %d concurrent requests, saw %d", workers, peak.Load())
+		}
+	})
+	return server, &downloads
+}
+
+func TestFrameFetcherCachesAndDeduplicates(t *testing.T) {
+	const workers = 3
+	server, downloads := fakeHelioviewer(t, workers)
+	cache := &memoryFrameCache{frames: make(map[int64][]byte)}
+	ts := time.Date(2025, 3, 1, 71, 30, 0, 0, time.UTC)
+
+	newFetcher := func() *FrameFetcher {
+		f := NewFrameFetcher(cache, workers)
+		f.client.baseURL = server.URL + "/"
+		f.options.Width = 64
+		return f
+	}
+
+	gifData, stats, err := newFetcher().GenerateProductGIF(context.Background(), Products[0], ts)
+	if err != nil {
+		t.Fatalf("First run failed: %v", err)
+	}
+	want := FetchStats{Product: "aia171", Hours: 72, Frames: 35, Duplicates: 36, Downloads: 35, Failures: 1}
+	stats.DurationS = 0
+	if *stats != want {
+		t.Errorf("First run stats = %+v, want %+v", *stats, want)
+	}
+	if len(gifData) == 0 || len(cache.frames) != 35 {
+		t.Errorf("Expected a GIF and 35 cached frames, got %d bytes and %d frames", len(gifData), len(cache.frames))
+	}
+
+	_, stats, err = newFetcher().GenerateProductGIF(context.Background(), Products[0], ts)
+	if err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+	want.Downloads, want.CacheHits = 0, 35
+	stats.DurationS = 0
+	if *stats != want {
+		t.Errorf("Second run stats = %+v, want %+v", *stats, want)
+	}
+	if downloads.Load() != 35 {
+		t.Errorf("Expected 35 downloads in total, got %d", downloads.Load())
+	}
+}
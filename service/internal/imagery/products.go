@@ -0,0 +1,107 @@
+package imagery
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Product is a Helioviewer image series rendered into its own 72-hour animation
+type Product struct {
+	ID          string // configuration name, e.g. "aia304"
+	Name        string // tab label
+	Description string // what the product shows
+	Observatory string
+	Instrument  string
+	Detector    string // empty when the data source tree has no detector level (SDO)
+	Measurement string
+	FileName    string // animation stored next to index.html
+	Placeholder string // markdown placeholder embedding only this animation
+	Credit      string
+}
+
+// SunViewerPlaceholder embeds the tabbed viewer with every configured product
+const SunViewerPlaceholder = "SunGif"
+
+// Products lists the supported imagery products in display order
+var Products = []Product{
+	{
+		ID: "aia171", Name: "AIA 171 Å", Description: "Quiet corona and coronal loops",
+		Observatory: "SDO", Instrument: "AIA", Measurement: "171",
+		FileName: "sun_72h.gif", Placeholder: "SunAIA171Gif", Credit: "SDO/NASA",
+	},
+	{
+		ID: "aia193", Name: "AIA 193 Å", Description: "Hot corona and coronal holes",
+		Observatory: "SDO", Instrument: "AIA", Measurement: "193",
+		FileName: "sun_aia193_72h.gif", Placeholder: "SunAIA193Gif", Credit: "SDO/NASA",
+	},
+	{
+		ID: "aia304", Name: "AIA 304 Å", Description: "Chromosphere, filaments and prominences",
+		Observatory: "SDO", Instrument: "AIA", Measurement: "304",
+		FileName: "sun_aia304_72h.gif", Placeholder: "SunAIA304Gif", Credit: "SDO/NASA",
+	},
+	{
+		ID: "hmi", Name: "HMI Magnetogram", Description: "Line-of-sight magnetic field of active regions",
+		Observatory: "SDO", Instrument: "HMI", Measurement: "magnetogram",
+		FileName: "sun_hmi_72h.gif", Placeholder: "SunHMIGif", Credit: "SDO/NASA",
+	},
+	{
+		ID: "lasco_c2", Name: "LASCO C2", Description: "Inner corona coronagraph (2-6 solar radii), shows CMEs",
+		Observatory: "SOHO", Instrument: "LASCO", Detector: "C2", Measurement: "white-light",
+		FileName: "lasco_c2_72h.gif", Placeholder: "LascoC2Gif", Credit: "SOHO (ESA & NASA)",
+	},
+	{
+		ID: "lasco_c3", Name: "LASCO C3", Description: "Outer corona coronagraph (3.7-30 solar radii), shows CMEs",
+		Observatory: "SOHO", Instrument: "LASCO", Detector: "C3", Measurement: "white-light",
+		FileName: "lasco_c3_72h.gif", Placeholder: "LascoC3Gif", Credit: "SOHO (ESA & NASA)",
+	},
+}
+
+// DefaultProducts returns the products generated when nothing is configured (SDO/AIA 171)
+func DefaultProducts() []Product {
+	return []Product{Products[0]}
+}
+
+// ParseProducts resolves configured product IDs (case-insensitive, duplicates ignored)
+func ParseProducts(ids []string) ([]Product, error) {
+	var products []Product
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if id == "" || seen[id] {
+			continue
+		}
+		p, ok := ProductByID(id)
+		if !ok {
+			return nil, fmt.Errorf("unknown imagery product %q", id)
+		}
+		seen[id] = true
+		products = append(products, p)
+	}
+	if len(products) == 0 {
+		return DefaultProducts(), nil
+	}
+	return products, nil
+}
+
+// ProductByID looks up a product from the catalog
+func ProductByID(id string) (Product, bool) {
+	for _, p := range Products {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Product{}, false
+}
+
+// IsImageryPlaceholder reports whether a markdown placeholder refers to Sun imagery
+func IsImageryPlaceholder(name string) bool {
+	if name == SunViewerPlaceholder {
+		return true
+	}
+	for _, p := range Products {
+		if p.Placeholder == name {
+			return true
+		}
+	}
+	return false
+}
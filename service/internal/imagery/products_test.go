@@ -0,0 +1,134 @@
+package imagery
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseProducts(t *testing.T) {
+	products, err := ParseProducts([]string{" AIA304", "lasco_c2", "aia304", ""})
+	if err != nil {
+		t.Fatalf("ParseProducts failed: %v", err)
+	}
+	if len(products) != 2 || products[0].ID != "aia304" || products[1].ID != "lasco_c2" {
+		t.Errorf("Expected [aia304 lasco_c2], got %v", products)
+	}
+
+	products, err = ParseProducts(nil)
+	if err != nil || len(products) != 1 || products[0].ID != "aia171" {
+		t.Errorf("Expected default [aia171], got %v (%v)", products, err)
+	}
+
+	if _, err := ParseProducts([]string{"eit195"}); err == nil {
+		t.Error("Expected error for unknown product")
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	formats, err := ParseFormats([]string{"WebP", " gif", "webp", ""})
+	if err != nil || len(formats) != 1 || formats[0] != FormatWebP {
+		t.Errorf("Expected [webp], got %v (%v)", formats, err)
+	}
+	for _, name := range []string{"avif", "mp4"} {
+		if _, err := ParseFormats([]string{name}); err == nil {
+			t.Errorf("Expected error for unknown format %s", name)
+		}
+	}
+	if got := Products[4].File(FormatWebP); got != "lasco_c2_72h.webp" {
+		t.Errorf("Expected lasco_c2_72h.webp, got %s", got)
+	}
+}
+
+func TestProductCatalogIsUnique(t *testing.T) {
+	ids, files, placeholders := map[string]bool{}, map[string]bool{}, map[string]bool{SunViewerPlaceholder: true}
+	for _, p := range Products {
+		if ids[p.ID] || files[p.FileName] || placeholders[p.Placeholder] {
+			t.Errorf("Product %s reuses an ID, file name or placeholder", p.ID)
+		}
+		ids[p.ID], files[p.FileName], placeholders[p.Placeholder] = true, true, true
+		if !IsImageryPlaceholder(p.Placeholder) {
+			t.Errorf("Placeholder %s not recognized", p.Placeholder)
+		}
+	}
+	if IsImageryPlaceholder("KIndexChart") {
+		t.Error("Chart placeholder must not be treated as imagery")
+	}
+}
+
+func TestParseSourceID(t *testing.T) {
+	body := []byte(`{
+		"SDO": {
+			"AIA": {"171": {"sourceId": 10}, "304": {"sourceId": 13}},
+			"HMI": {"magnetogram": {"sourceId": 19}}
+		},
+		"SOHO": {
+			"LASCO": {"C2": {"white-light": {"sourceId": 4}}, "C3": {"white-light": {"sourceId": 5}}}
+		}
+	}`)
+
+	for _, p := range Products {
+		if p.ID == "aia193" {
+			continue
+		}
+		if _, err := parseSourceID(body, p.Observatory, p.Instrument, p.Detector, p.Measurement); err != nil {
+			t.Errorf("%s: %v", p.ID, err)
+		}
+	}
+
+	c2, _ := ProductByID("lasco_c2")
+	if id, _ := parseSourceID(body, c2.Observatory, c2.Instrument, c2.Detector, c2.Measurement); id != 4 {
+		t.Errorf("Expected LASCO C2 sourceId 4, got %d", id)
+	}
+	if _, err := parseSourceID(body, "SDO", "AIA", "", "193"); err == nil || !strings.Contains(err.Error(), "measurement not found") {
+		t.Errorf("Expected missing measurement error, got %v", err)
+	}
+}
+
+func sunImages(products []Product, formats ...Format) []SunImage {
+	images := make([]SunImage, len(products))
+	for i, p := range products {
+		images[i] = SunImage{Product: p, Formats: formats}
+	}
+	return images
+}
+
+func TestGenerateSunImagesHTML(t *testing.T) {
+	sig := NewSunImageGenerator()
+
+	single := string(sig.GenerateSunImagesHTML(sunImages(DefaultProducts()), "2025/01/02/report"))
+	if !strings.Contains(single, `src="/reports/2025/01/02/report/sun_72h.gif"`) || strings.Contains(single, "sun-tabs") {
+		t.Errorf("Single product should render a plain image: %s", single)
+	}
+
+	products, _ := ParseProducts([]string{"aia171", "aia304", "lasco_c2"})
+	tabs := string(sig.GenerateSunImagesHTML(sunImages(products), ""))
+	if strings.Count(tabs, `type="radio"`) != 3 || strings.Count(tabs, " checked") != 1 {
+		t.Errorf("Expected 3 tabs with the first selected: %s", tabs)
+	}
+	for _, want := range []string{`src="sun_aia304_72h.gif"`, `src="lasco_c2_72h.gif"`, "SOHO (ESA &amp; NASA)", `for="sun-tab-lasco_c2"`} {
+		if !strings.Contains(tabs, want) {
+			t.Errorf("Tabbed viewer missing %q", want)
+		}
+	}
+
+	if strings.Contains(tabs, "<video") || strings.Contains(tabs, "<picture") {
+		t.Errorf("GIF-only imagery must not reference other formats: %s", tabs)
+	}
+
+	media := string(sig.GenerateSunImagesHTML(sunImages(DefaultProducts(), FormatWebP), "2025/01/02/report"))
+	for _, want := range []string{
+		`<source srcset="/reports/2025/01/02/report/sun_72h.webp" type="image/webp">`,
+		`<img src="/reports/2025/01/02/report/sun_72h.gif"`,
+	} {
+		if !strings.Contains(media, want) {
+			t.Errorf("Expected %q in %s", want, media)
+		}
+	}
+	if strings.Index(media, ".webp") > strings.Index(media, ".gif") {
+		t.Errorf("Expected WebP, then the GIF fallback: %s", media)
+	}
+
+	if sig.GenerateSunImagesHTML(nil, "") != "" {
+		t.Error("Expected no HTML without products")
+	}
+}
@@ -0,0 +1,180 @@
+package imagery
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// bayer8 is the 8x8 ordered dithering threshold matrix
+var bayer8 = [8][8]int{
+	{0, 32, 8, 40, 2, 34, 10, 42},
+	{48, 16, 56, 24, 50, 18, 58, 26},
+	{12, 44, 4, 36, 14, 46, 6, 38},
+	{60, 28, 52, 20, 62, 30, 54, 22},
+	{3, 35, 11, 43, 1, 33, 9, 41},
+	{51, 19, 59, 27, 49, 17, 57, 25},
+	{15, 47, 7, 39, 13, 45, 5, 37},
+	{63, 31, 55, 23, 61, 29, 53, 21},
+}
+
+// ditherSpread is the amplitude of the ordered dither in 8-bit color steps
+const ditherSpread = 12
+
+// quantizer maps colors onto a palette shared by all frames, built with median cut over a
+// 15-bit color histogram. Ordered (not error-diffusion) dithering keeps static areas identical
+// between frames and the output deterministic.
+type quantizer struct {
+	palette color.Palette
+	lookup  []int16 // 15-bit color -> nearest palette index, -1 until first use
+}
+
+func colorKey(r, g, b uint8) int {
+	return int(r>>3)<<10 | int(g>>3)<<5 | int(b>>3)
+}
+
+func keyChannel(key, ch int) int {
+	return key >> (10 - 5*ch) & 0x1f
+}
+
+func expand5(v int) uint8 {
+	return uint8(v<<3 | v>>2)
+}
+
+func newQuantizer(images []*image.RGBA, colors int) *quantizer {
+	hist := make([]uint64, 1<<15)
+	for _, img := range images {
+		for i := 0; i+3 < len(img.Pix); i += 4 {
+			hist[colorKey(img.Pix[i], img.Pix[i+1], img.Pix[i+2])]++
+		}
+	}
+	var keys []int
+	for key, n := range hist {
+		if n > 0 {
+			keys = append(keys, key)
+		}
+	}
+
+	boxes := [][]int{keys}
+	for len(boxes) < colors {
+		// Split the most populated box that still has more than one color
+		best, bestCount := -1, uint64(0)
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			var count uint64
+			for _, key := range box {
+				count += hist[key]
+			}
+			if count > bestCount {
+				best, bestCount = i, count
+			}
+		}
+		if best < 0 {
+			break
+		}
+		lo, hi := splitBox(boxes[best], hist, bestCount)
+		boxes[best] = lo
+		boxes = append(boxes, hi)
+	}
+
+	q := &quantizer{lookup: make([]int16, 1<<15)}
+	for i := range q.lookup {
+		q.lookup[i] = -1
+	}
+	for _, box := range boxes {
+		var r, g, b, n uint64
+		for _, key := range box {
+			w := hist[key]
+			r += uint64(expand5(keyChannel(key, 0))) * w
+			g += uint64(expand5(keyChannel(key, 1))) * w
+			b += uint64(expand5(keyChannel(key, 2))) * w
+			n += w
+		}
+		if n == 0 {
+			continue
+		}
+		q.palette = append(q.palette, color.RGBA{uint8(r / n), uint8(g / n), uint8(b / n), 0xff})
+	}
+	if len(q.palette) == 0 {
+		q.palette = color.Palette{color.Black}
+	}
+	return q
+}
+
+// splitBox divides a box at the weighted median of its widest channel
+func splitBox(box []int, hist []uint64, count uint64) ([]int, []int) {
+	channel, widest := 0, -1
+	for ch := 0; ch < 3; ch++ {
+		lo, hi := 31, 0
+		for _, key := range box {
+			v := keyChannel(key, ch)
+			lo, hi = min(lo, v), max(hi, v)
+		}
+		if hi-lo > widest {
+			channel, widest = ch, hi-lo
+		}
+	}
+	sort.Slice(box, func(i, j int) bool {
+		vi, vj := keyChannel(box[i], channel), keyChannel(box[j], channel)
+		if vi != vj {
+			return vi < vj
+		}
+		return box[i] < box[j]
+	})
+	var acc uint64
+	split := 1
+	for i, key := range box[:len(box)-1] {
+		acc += hist[key]
+		split = i + 1
+		if acc*2 >= count {
+			break
+		}
+	}
+	return box[:split:split], box[split:]
+}
+
+// index returns the palette entry closest to the color
+func (q *quantizer) index(r, g, b uint8) uint8 {
+	key := colorKey(r, g, b)
+	if idx := q.lookup[key]; idx >= 0 {
+		return uint8(idx)
+	}
+	cr, cg, cb := int(expand5(keyChannel(key, 0))), int(expand5(keyChannel(key, 1))), int(expand5(keyChannel(key, 2)))
+	best, bestDist := 0, 1<<30
+	for i, c := range q.palette {
+		pc := c.(color.RGBA)
+		dr, dg, db := cr-int(pc.R), cg-int(pc.G), cb-int(pc.B)
+		if d := dr*dr + dg*dg + db*db; d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	q.lookup[key] = int16(best)
+	return uint8(best)
+}
+
+// dither maps an image onto the palette with ordered dithering
+func (q *quantizer) dither(img *image.RGBA) *image.Paletted {
+	b := img.Bounds()
+	out := image.NewPaletted(b, q.palette)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i := img.PixOffset(x, y)
+			offset := (bayer8[y&7][x&7]*2 - 63) * ditherSpread / 126
+			out.SetColorIndex(x, y, q.index(
+				clamp8(int(img.Pix[i])+offset), clamp8(int(img.Pix[i+1])+offset), clamp8(int(img.Pix[i+2])+offset)))
+		}
+	}
+	return out
+}
+
+func clamp8(v int) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
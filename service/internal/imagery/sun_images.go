@@ -3,6 +3,10 @@ package imagery
 import (
 	"fmt"
 	"html/template"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
 	"strings"
 )
 
@@ -14,21 +18,92 @@ func NewSunImageGenerator() *SunImageGenerator {
 	return &SunImageGenerator{}
 }
 
-// GenerateSunImagesHTML creates the HTML snippet for the Sun Images section
-func (sig *SunImageGenerator) GenerateSunImagesHTML(gifRelName, folderPath string) template.HTML {
-	var imgSrc string
-	if folderPath != "" {
-		// GCS mode - use the full folder path
-		if !strings.HasSuffix(folderPath, "/") {
-			folderPath += "/"
+// GenerateSunImagesHTML creates the HTML snippet for the Sun Images section. Several products are
+// shown as tabs that switch with CSS only, so the viewer also works in static and offline reports.
+func (sig *SunImageGenerator) GenerateSunImagesHTML(images []SunImage, folderPath string) template.HTML {
+	if len(images) == 0 {
+		return ""
+	}
+	if len(images) == 1 {
+		return sig.GenerateProductHTML(images[0], folderPath)
+	}
+
+	var b strings.Builder
+	b.WriteString(`<div class="chart-section"><div class="chart-container"><h3>Sun Images for Past 72 Hours</h3><div class="sun-tabs">`)
+	for i, p := range images {
+		checked := ""
+		if i == 0 {
+			checked = " checked"
 		}
-		imgSrc = "/reports/" + folderPath + gifRelName
-	} else {
-		// Local mode - use relative path
-		imgSrc = gifRelName
+		id := "sun-tab-" + p.ID
+		fmt.Fprintf(&b, `<input type="radio" name="sun-tabs" id="%s"%s><label for="%s" title="%s">%s</label>`,
+			id, checked, id, template.HTMLEscapeString(p.Description), template.HTMLEscapeString(p.Name))
+		fmt.Fprintf(&b, `<div class="sun-tab-panel">%s<br/><i>%s. Images copyrighted by the %s and Helioviewer project</i></div>`,
+			sunMediaHTML(p, folderPath, i > 0), template.HTMLEscapeString(p.Description), template.HTMLEscapeString(p.Credit))
+	}
+	b.WriteString(`</div></div></div>`)
+	return template.HTML(b.String())
+}
+
+// GenerateProductHTML creates the HTML snippet showing the animation of a single product
+func (sig *SunImageGenerator) GenerateProductHTML(p SunImage, folderPath string) template.HTML {
+	title := "Sun Images for Past 72 Hours"
+	if p.ID != Products[0].ID {
+		title = p.Name + " for Past 72 Hours"
 	}
-	
-	html := fmt.Sprintf(`<div class="chart-section"><div class="chart-container"><h3>Sun Images for Past 72 Hours</h3><img src="%s" alt="Sun last 72h" style="max-width:100%%;height:auto;border-radius:8px;" /><br/><i>Images copyrighted by the SDO/NASA and Helioviewer project</i></div></div>`, imgSrc)
-	
+	html := fmt.Sprintf(`<div class="chart-section"><div class="chart-container"><h3>%s</h3>%s<br/><i>Images copyrighted by the %s and Helioviewer project</i></div></div>`,
+		template.HTMLEscapeString(title), sunMediaHTML(p, folderPath, false), template.HTMLEscapeString(p.Credit))
 	return template.HTML(html)
 }
+
+// sunMediaStyle sizes the animation inside the report card
+const sunMediaStyle = "max-width:100%;height:auto;border-radius:8px;"
+
+// sunMediaHTML embeds an animation as WebP picture when available, with the GIF as fallback for
+// browsers without WebP support
+func sunMediaHTML(p SunImage, folderPath string, lazy bool) string {
+	alt := template.HTMLEscapeString(p.Name) + " last 72h"
+	loading := ""
+	if lazy {
+		loading = ` loading="lazy"`
+	}
+	media := fmt.Sprintf(`<img src="%s" alt="%s"%s style="%s" />`, sunImageSrc(p.FileName, folderPath), alt, loading, sunMediaStyle)
+	if p.Has(FormatWebP) {
+		media = fmt.Sprintf(`<picture><source srcset="%s" type="image/webp">%s</picture>`, sunImageSrc(p.File(FormatWebP), folderPath), media)
+	}
+	return media
+}
+
+// sunImageSrc builds the URL of an animation stored next to the report
+func sunImageSrc(fileName, folderPath string) string {
+	if folderPath == "" {
+		// Local mode - use relative path
+		return fileName
+	}
+	// GCS mode - use the full folder path
+	if !strings.HasSuffix(folderPath, "/") {
+		folderPath += "/"
+	}
+	return "/reports/" + folderPath + fileName
+}
+
+// LatestFrame decodes an animated GIF and returns its final frame (the most recent Sun image)
+func LatestFrame(r io.Reader) (image.Image, error) {
+	anim, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode GIF: %w", err)
+	}
+	if len(anim.Image) == 0 {
+		return nil, fmt.Errorf("GIF has no frames")
+	}
+	bounds := image.Rect(0, 0, anim.Config.Width, anim.Config.Height)
+	if bounds.Empty() {
+		bounds = anim.Image[0].Bounds()
+	}
+	// Frames may only cover the changed region, so composite them in order
+	frame := image.NewRGBA(bounds)
+	for _, img := range anim.Image {
+		draw.Draw(frame, img.Bounds(), img, img.Bounds().Min, draw.Over)
+	}
+	return frame, nil
+}
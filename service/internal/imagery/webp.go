@@ -0,0 +1,550 @@
+package imagery
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"math/bits"
+)
+
+// Animated WebP made of lossless (VP8L) frames. The frames use the GIF's palette through the
+// color indexing transform, so they show exactly what the GIF shows. After the first frame,
+// pixels that did not change are transparent and blended over the previous frame, which
+// leaves long runs for LZ77 to collapse.
+
+const (
+	vp8lMaxLength   = 4096
+	vp8lMinLength   = 3
+	vp8lMaxDistance = 1<<20 - 120
+	vp8lHashBits    = 16
+	vp8lChainDepth  = 16
+)
+
+// vp8lCodeLengthOrder is the order in which code length code lengths are stored
+var vp8lCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// lsbWriter writes a bit stream least significant bit first
+type lsbWriter struct {
+	buf []byte
+	acc uint64
+	n   uint
+}
+
+func (w *lsbWriter) bits(v uint32, n int) {
+	w.acc |= uint64(v) << w.n
+	w.n += uint(n)
+	for w.n >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.n -= 8
+	}
+}
+
+func (w *lsbWriter) bytes() []byte {
+	if w.n > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.n = 0, 0
+	}
+	return w.buf
+}
+
+// webpFrame is one frame of the animation: a rectangle of palette indices and its duration
+type webpFrame struct {
+	rect     image.Rectangle
+	indices  []uint8 // rect.Dx()*rect.Dy(), transparent where blend is set
+	blend    bool
+	duration int // milliseconds
+}
+
+// encodeWebP encodes paletted frames sharing one palette as a looping animated WebP
+func encodeWebP(frames []*image.Paletted, palette color.Palette, opts AnimationOptions) []byte {
+	bounds := frames[0].Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	table := make([]uint32, 0, len(palette)+1)
+	for _, c := range palette {
+		r, g, b, a := c.RGBA()
+		table = append(table, a>>8<<24|r>>8<<16|g>>8<<8|b>>8)
+	}
+	transparent := -1
+	if len(table) < 256 {
+		transparent = len(table)
+		table = append(table, 0)
+	}
+
+	duration := max(1, opts.Delay) * 10
+	var out []webpFrame
+	for i, f := range frames {
+		if i == 0 || transparent < 0 {
+			out = append(out, webpFrame{rect: image.Rect(0, 0, width, height), indices: paletteRows(f, image.Rect(0, 0, width, height)), duration: duration})
+			continue
+		}
+		prev := frames[i-1]
+		changed := image.Rectangle{}
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				if f.Pix[y*f.Stride+x] != prev.Pix[y*prev.Stride+x] {
+					changed = changed.Union(image.Rect(x, y, x+1, y+1))
+				}
+			}
+		}
+		if changed.Empty() {
+			out[len(out)-1].duration += duration
+			continue
+		}
+		// Frame offsets are stored in units of two pixels
+		changed.Min.X &^= 1
+		changed.Min.Y &^= 1
+		indices := paletteRows(f, changed)
+		k := 0
+		for y := changed.Min.Y; y < changed.Max.Y; y++ {
+			for x := changed.Min.X; x < changed.Max.X; x++ {
+				if f.Pix[y*f.Stride+x] == prev.Pix[y*prev.Stride+x] {
+					indices[k] = uint8(transparent)
+				}
+				k++
+			}
+		}
+		out = append(out, webpFrame{rect: changed, indices: indices, blend: true, duration: duration})
+	}
+
+	loops := 0
+	if opts.Loops > 0 {
+		loops = opts.Loops + 1 // GIF counts repetitions, WebP counts plays
+	}
+	flags := byte(0x02) // animation
+	if transparent >= 0 {
+		flags |= 0x10 // alpha
+	}
+	vp8x := append([]byte{flags, 0, 0, 0}, u24(width-1)...)
+	vp8x = append(vp8x, u24(height-1)...)
+	body := []byte("WEBP")
+	body = append(body, riffChunk("VP8X", vp8x)...)
+	body = append(body, riffChunk("ANIM", []byte{0, 0, 0, 0xff, byte(loops), byte(loops >> 8)})...)
+	for _, f := range out {
+		anmf := append(u24(f.rect.Min.X/2), u24(f.rect.Min.Y/2)...)
+		anmf = append(anmf, u24(f.rect.Dx()-1)...)
+		anmf = append(anmf, u24(f.rect.Dy()-1)...)
+		anmf = append(anmf, u24(f.duration)...)
+		if f.blend {
+			anmf = append(anmf, 0) // alpha-blend, do not dispose
+		} else {
+			anmf = append(anmf, 0x02) // do not blend
+		}
+		anmf = append(anmf, riffChunk("VP8L", encodeVP8L(f.indices, f.rect.Dx(), f.rect.Dy(), table, f.blend))...)
+		body = append(body, riffChunk("ANMF", anmf)...)
+	}
+	return riffChunk("RIFF", body)
+}
+
+// paletteRows copies the palette indices inside r
+func paletteRows(img *image.Paletted, r image.Rectangle) []uint8 {
+	out := make([]uint8, 0, r.Dx()*r.Dy())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		out = append(out, img.Pix[y*img.Stride+r.Min.X:y*img.Stride+r.Max.X]...)
+	}
+	return out
+}
+
+func riffChunk(fourCC string, payload []byte) []byte {
+	out := binary.LittleEndian.AppendUint32([]byte(fourCC), uint32(len(payload)))
+	out = append(out, payload...)
+	if len(payload)%2 == 1 {
+		out = append(out, 0)
+	}
+	return out
+}
+
+func u24(v int) []byte {
+	return []byte{byte(v), byte(v >> 8), byte(v >> 16)}
+}
+
+// encodeVP8L writes a lossless bitstream of palette indices using the color indexing transform
+func encodeVP8L(indices []uint8, width, height int, table []uint32, alpha bool) []byte {
+	w := &lsbWriter{}
+	w.bits(0x2f, 8)
+	w.bits(uint32(width-1), 14)
+	w.bits(uint32(height-1), 14)
+	if alpha {
+		w.bits(1, 1)
+	} else {
+		w.bits(0, 1)
+	}
+	w.bits(0, 3) // version
+
+	w.bits(1, 1) // transform present
+	w.bits(3, 2) // color indexing
+	w.bits(uint32(len(table)-1), 8)
+	deltas := make([]uint32, len(table))
+	for i, c := range table {
+		if i == 0 {
+			deltas[i] = c
+			continue
+		}
+		p := table[i-1]
+		for shift := 0; shift < 32; shift += 8 {
+			deltas[i] |= uint32(uint8(c>>shift)-uint8(p>>shift)) << shift
+		}
+	}
+	writeEntropyImage(w, deltas, len(table), false)
+	w.bits(0, 1) // no more transforms
+
+	// Small palettes pack several indices into one pixel
+	widthBits := 0
+	switch {
+	case len(table) <= 2:
+		widthBits = 3
+	case len(table) <= 4:
+		widthBits = 2
+	case len(table) <= 16:
+		widthBits = 1
+	}
+	packedWidth := (width + 1<<widthBits - 1) >> widthBits
+	pixelBits := 8 >> widthBits
+	pixels := make([]uint32, packedWidth*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p := &pixels[y*packedWidth+x>>widthBits]
+			*p |= uint32(indices[y*width+x]) << (8 + pixelBits*(x&(1<<widthBits-1)))
+		}
+	}
+	for i := range pixels {
+		pixels[i] |= 0xff000000
+	}
+	writeEntropyImage(w, pixels, packedWidth, true)
+	return w.bytes()
+}
+
+// vp8lToken is a literal pixel or, when length > 0, a backward reference
+type vp8lToken struct {
+	pixel  uint32
+	length int
+	dist   int // distance code
+}
+
+// writeEntropyImage writes an image coded with one set of prefix codes and no color cache
+func writeEntropyImage(w *lsbWriter, pixels []uint32, width int, main bool) {
+	w.bits(0, 1) // no color cache
+	if main {
+		w.bits(0, 1) // no meta prefix codes
+	}
+	tokens := backwardRefs(pixels, width)
+
+	green := make([]uint32, 256+24)
+	red, blue, alpha := make([]uint32, 256), make([]uint32, 256), make([]uint32, 256)
+	dist := make([]uint32, 40)
+	for _, t := range tokens {
+		if t.length == 0 {
+			green[t.pixel>>8&0xff]++
+			red[t.pixel>>16&0xff]++
+			blue[t.pixel&0xff]++
+			alpha[t.pixel>>24]++
+			continue
+		}
+		code, _, _ := vp8lPrefix(t.length)
+		green[256+code]++
+		code, _, _ = vp8lPrefix(t.dist)
+		dist[code]++
+	}
+	codes := [5]*prefixCode{}
+	for i, freq := range [][]uint32{green, red, blue, alpha, dist} {
+		codes[i] = newPrefixCode(freq, 15)
+		codes[i].write(w)
+	}
+	for _, t := range tokens {
+		if t.length == 0 {
+			codes[0].emit(w, int(t.pixel>>8&0xff))
+			codes[1].emit(w, int(t.pixel>>16&0xff))
+			codes[2].emit(w, int(t.pixel&0xff))
+			codes[3].emit(w, int(t.pixel>>24))
+			continue
+		}
+		code, extraBits, extra := vp8lPrefix(t.length)
+		codes[0].emit(w, 256+code)
+		w.bits(extra, extraBits)
+		code, extraBits, extra = vp8lPrefix(t.dist)
+		codes[4].emit(w, code)
+		w.bits(extra, extraBits)
+	}
+}
+
+// vp8lPrefix splits a length or distance code into its prefix symbol and extra bits
+func vp8lPrefix(v int) (code, extraBits int, extra uint32) {
+	v--
+	if v < 4 {
+		return v, 0, 0
+	}
+	hb := bits.Len(uint(v)) - 1
+	second := v >> (hb - 1) & 1
+	extraBits = hb - 1
+	return 2*hb + second, extraBits, uint32(v & (1<<extraBits - 1))
+}
+
+// backwardRefs runs greedy LZ77 over the pixels with a hash chain, trying the pixel above
+// and the pixel to the left first
+func backwardRefs(pixels []uint32, width int) []vp8lToken {
+	n := len(pixels)
+	head := make([]int32, 1<<vp8lHashBits)
+	for i := range head {
+		head[i] = -1
+	}
+	prev := make([]int32, n)
+	hash := func(i int) uint32 {
+		return (pixels[i]*0x9e3779b1 ^ pixels[i+1]*0x85ebca6b) >> (32 - vp8lHashBits)
+	}
+	insert := func(i int) {
+		if i+1 < n {
+			h := hash(i)
+			prev[i] = head[h]
+			head[h] = int32(i)
+		}
+	}
+	matchLen := func(i, j int) int {
+		limit := min(vp8lMaxLength, n-i)
+		l := 0
+		for l < limit && pixels[i+l] == pixels[j+l] {
+			l++
+		}
+		return l
+	}
+
+	var tokens []vp8lToken
+	for i := 0; i < n; {
+		bestLen, bestDist := 0, 0
+		for _, d := range []int{width, 1} {
+			if d <= i {
+				if l := matchLen(i, i-d); l > bestLen {
+					bestLen, bestDist = l, d
+				}
+			}
+		}
+		if i+1 < n {
+			for j, depth := head[hash(i)], 0; j >= 0 && depth < vp8lChainDepth && i-int(j) <= vp8lMaxDistance; j, depth = prev[j], depth+1 {
+				if l := matchLen(i, int(j)); l > bestLen {
+					bestLen, bestDist = l, i-int(j)
+				}
+			}
+		}
+		if bestLen < vp8lMinLength {
+			tokens = append(tokens, vp8lToken{pixel: pixels[i]})
+			insert(i)
+			i++
+			continue
+		}
+		code := bestDist + 120
+		switch bestDist {
+		case width:
+			code = 1
+		case 1:
+			code = 2
+		}
+		tokens = append(tokens, vp8lToken{length: bestLen, dist: code})
+		for k := 0; k < bestLen; k++ {
+			insert(i + k)
+		}
+		i += bestLen
+	}
+	return tokens
+}
+
+// prefixCode is a canonical prefix code; symbols of a code with one used symbol take no bits
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint16 // bit-reversed for the LSB-first stream
+	used    []int
+}
+
+func newPrefixCode(freq []uint32, limit int) *prefixCode {
+	c := &prefixCode{lengths: huffmanLengths(freq, limit), codes: make([]uint16, len(freq))}
+	for s, f := range freq {
+		if f > 0 {
+			c.used = append(c.used, s)
+		}
+	}
+	var count [16]int
+	for _, l := range c.lengths {
+		count[l]++
+	}
+	count[0] = 0
+	var next [16]int
+	code := 0
+	for l := 1; l < 16; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+	for s, l := range c.lengths {
+		if l > 0 {
+			c.codes[s] = uint16(bits.Reverse16(uint16(next[l])) >> (16 - l))
+			next[l]++
+		}
+	}
+	return c
+}
+
+func (c *prefixCode) emit(w *lsbWriter, symbol int) {
+	if len(c.used) > 1 {
+		w.bits(uint32(c.codes[symbol]), int(c.lengths[symbol]))
+	}
+}
+
+// write stores the code, as a simple code when it has at most two symbols below 256
+func (c *prefixCode) write(w *lsbWriter) {
+	if len(c.used) <= 2 && (len(c.used) == 0 || c.used[len(c.used)-1] < 256) {
+		w.bits(1, 1) // simple code
+		if len(c.used) == 0 {
+			w.bits(0, 1) // one symbol: 0
+			w.bits(0, 1)
+			w.bits(0, 1)
+			return
+		}
+		w.bits(uint32(len(c.used)-1), 1)
+		if c.used[0] < 2 {
+			w.bits(0, 1)
+			w.bits(uint32(c.used[0]), 1)
+		} else {
+			w.bits(1, 1)
+			w.bits(uint32(c.used[0]), 8)
+		}
+		if len(c.used) == 2 {
+			w.bits(uint32(c.used[1]), 8)
+		}
+		// The simple code assigns code 0 to the first symbol and 1 to the second
+		if len(c.used) == 2 {
+			c.lengths[c.used[0]], c.codes[c.used[0]] = 1, 0
+			c.lengths[c.used[1]], c.codes[c.used[1]] = 1, 1
+		}
+		return
+	}
+
+	// Run-length code the code lengths with symbols 16 (repeat previous), 17 and 18 (zeros)
+	type rle struct{ symbol, extraBits, extra int }
+	var seq []rle
+	lengths := c.lengths
+	for i := 0; i < len(lengths); {
+		run := 1
+		for i+run < len(lengths) && lengths[i+run] == lengths[i] {
+			run++
+		}
+		v := int(lengths[i])
+		i += run
+		if v == 0 {
+			for run > 0 {
+				switch {
+				case run >= 11:
+					r := min(run, 138)
+					seq = append(seq, rle{18, 7, r - 11})
+					run -= r
+				case run >= 3:
+					seq = append(seq, rle{17, 3, run - 3})
+					run = 0
+				default:
+					seq = append(seq, rle{0, 0, 0})
+					run--
+				}
+			}
+			continue
+		}
+		seq = append(seq, rle{v, 0, 0})
+		for run--; run > 0; {
+			if run < 3 {
+				seq = append(seq, rle{v, 0, 0})
+				run--
+				continue
+			}
+			r := min(run, 6)
+			seq = append(seq, rle{16, 2, r - 3})
+			run -= r
+		}
+	}
+	freq := make([]uint32, 19)
+	for _, s := range seq {
+		freq[s.symbol]++
+	}
+	lengthCode := newPrefixCode(freq, 7)
+	numCodes := 4
+	for i, s := range vp8lCodeLengthOrder {
+		if lengthCode.lengths[s] > 0 {
+			numCodes = max(numCodes, i+1)
+		}
+	}
+	w.bits(0, 1) // normal code
+	w.bits(uint32(numCodes-4), 4)
+	for _, s := range vp8lCodeLengthOrder[:numCodes] {
+		w.bits(uint32(lengthCode.lengths[s]), 3)
+	}
+	w.bits(0, 1) // code lengths for the whole alphabet
+	for _, s := range seq {
+		lengthCode.emit(w, s.symbol)
+		w.bits(uint32(s.extra), s.extraBits)
+	}
+}
+
+// huffmanLengths computes prefix code lengths no longer than limit, flattening the frequencies
+// until the tree is shallow enough
+func huffmanLengths(freq []uint32, limit int) []uint8 {
+	lengths := make([]uint8, len(freq))
+	weights := make([]uint64, len(freq))
+	var used []int
+	for s, f := range freq {
+		if f > 0 {
+			used = append(used, s)
+			weights[s] = uint64(f)
+		}
+	}
+	if len(used) == 1 {
+		lengths[used[0]] = 1
+	}
+	if len(used) < 2 {
+		return lengths
+	}
+	for {
+		type node struct {
+			weight      uint64
+			left, right int // children, -1 for leaves
+			symbol      int
+		}
+		nodes := make([]node, 0, 2*len(used))
+		active := make([]int, 0, len(used))
+		for _, s := range used {
+			nodes = append(nodes, node{weight: weights[s], left: -1, right: -1, symbol: s})
+			active = append(active, len(nodes)-1)
+		}
+		for len(active) > 1 {
+			// Take the two lightest nodes; ties go to the earliest for deterministic output
+			a, b := 0, 1
+			if nodes[active[b]].weight < nodes[active[a]].weight {
+				a, b = b, a
+			}
+			for i := 2; i < len(active); i++ {
+				switch wi := nodes[active[i]].weight; {
+				case wi < nodes[active[a]].weight:
+					a, b = i, a
+				case wi < nodes[active[b]].weight:
+					b = i
+				}
+			}
+			nodes = append(nodes, node{weight: nodes[active[a]].weight + nodes[active[b]].weight, left: active[a], right: active[b]})
+			hi, lo := max(a, b), min(a, b)
+			active = append(active[:hi], active[hi+1:]...)
+			active[lo] = len(nodes) - 1
+		}
+
+		deepest := 0
+		var walk func(n, depth int)
+		walk = func(n, depth int) {
+			if nodes[n].left < 0 {
+				lengths[nodes[n].symbol] = uint8(depth)
+				deepest = max(deepest, depth)
+				return
+			}
+			walk(nodes[n].left, depth+1)
+			walk(nodes[n].right, depth+1)
+		}
+		walk(active[0], 0)
+		if deepest <= limit {
+			return lengths
+		}
+		for _, s := range used {
+			weights[s] = weights[s]>>1 | 1
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package imagery
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+func TestVP8LPrefix(t *testing.T) {
+	// Decode as in the VP8L specification and compare
+	for v := 1; v < 1<<20; v += 1 + v/7 {
+		code, extraBits, extra := vp8lPrefix(v)
+		got := code + 1
+		if code >= 4 {
+			bits := (code - 2) >> 1
+			if bits != extraBits {
+				t.Fatalf("%d: %d extra bits, spec says %d", v, extraBits, bits)
+			}
+			got = (2+code&1)<<bits + int(extra) + 1
+		}
+		if got != v {
+			t.Fatalf("vp8lPrefix(%d) decodes to %d", v, got)
+		}
+	}
+}
+
+func TestHuffmanLengthsAreComplete(t *testing.T) {
+	freq := make([]uint32, 280)
+	for i := range freq {
+		freq[i] = uint32(1) << (i % 30) // very skewed, forces length limiting
+	}
+	lengths := huffmanLengths(freq, 15)
+	var kraft float64
+	for _, l := range lengths {
+		if l == 0 || l > 15 {
+			t.Fatalf("invalid length %d", l)
+		}
+		kraft += 1 / float64(uint(1)<<l)
+	}
+	if kraft != 1 {
+		t.Errorf("Code is not complete: Kraft sum %v", kraft)
+	}
+}
+
+func TestEncodeWebPStructure(t *testing.T) {
+	var rgba []*image.RGBA
+	for i := 0; i < 4; i++ {
+		rgba = append(rgba, syntheticSun(40, i/2)) // frames 0-1 and 2-3 are identical
+	}
+	q := newQuantizer(rgba, 16)
+	var frames []*image.Paletted
+	for _, img := range rgba {
+		frames = append(frames, q.dither(img))
+	}
+	out := encodeWebP(frames, q.palette, AnimationOptions{Delay: 25, Loops: 10})
+
+	if string(out[:4]) != "RIFF" || string(out[8:12]) != "WEBP" || int(binary.LittleEndian.Uint32(out[4:])) != len(out)-8 {
+		t.Fatalf("Invalid RIFF header % x", out[:12])
+	}
+	var chunks []string
+	var durations []int
+	for pos := 12; pos < len(out); {
+		fourCC, size := string(out[pos:pos+4]), int(binary.LittleEndian.Uint32(out[pos+4:]))
+		payload := out[pos+8 : pos+8+size]
+		chunks = append(chunks, fourCC)
+		switch fourCC {
+		case "VP8X":
+			if payload[0]&0x02 == 0 || int(payload[4])+1 != 40 {
+				t.Errorf("Unexpected VP8X % x", payload)
+			}
+		case "ANIM":
+			if loops := binary.LittleEndian.Uint16(payload[4:]); loops != 11 {
+				t.Errorf("Expected 11 plays, got %d", loops)
+			}
+		case "ANMF":
+			durations = append(durations, int(payload[12])|int(payload[13])<<8)
+			if string(payload[16:20]) != "VP8L" || payload[24] != 0x2f {
+				t.Errorf("Frame is not a VP8L bitstream")
+			}
+		}
+		pos += 8 + size + size%2
+	}
+	if len(chunks) != 4 || chunks[0] != "VP8X" || chunks[1] != "ANIM" {
+		t.Fatalf("Unexpected chunks %v", chunks)
+	}
+	// Unchanged frames extend the previous frame instead of being stored
+	if len(durations) != 2 || durations[0] != 500 || durations[1] != 500 {
+		t.Errorf("Expected two 500 ms frames, got %v", durations)
+	}
+
+	again := encodeWebP(frames, q.palette, AnimationOptions{Delay: 25, Loops: 10})
+	if !bytes.Equal(out, again) {
+		t.Error("encodeWebP output is not deterministic")
+	}
+}
+
+// decodeWebPFrames plays an animated WebP back with golang.org/x/image/webp, which decodes still
+// images only: each ANMF frame is decoded as its own VP8L file and blended onto the canvas
+func decodeWebPFrames(t *testing.T, data []byte, width, height int) (frames []*image.NRGBA, rects []image.Rectangle) {
+	t.Helper()
+	canvas := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for pos := 12; pos < len(data); {
+		fourCC, size := string(data[pos:pos+4]), int(binary.LittleEndian.Uint32(data[pos+4:]))
+		payload := data[pos+8 : pos+8+size]
+		pos += 8 + size + size%2
+		if fourCC != "ANMF" {
+			continue
+		}
+		u24 := func(b []byte) int { return int(b[0]) | int(b[1])<<8 | int(b[2])<<16 }
+		x, y := 2*u24(payload[0:]), 2*u24(payload[3:])
+		rect := image.Rect(x, y, x+u24(payload[6:])+1, y+u24(payload[9:])+1)
+		blend := payload[15]&0x02 == 0
+		if string(payload[16:20]) != "VP8L" {
+			t.Fatalf("Frame %d is not a VP8L bitstream", len(frames))
+		}
+		still := riffChunk("RIFF", append([]byte("WEBP"), payload[16:]...))
+		img, err := webp.Decode(bytes.NewReader(still))
+		if err != nil {
+			t.Fatalf("Frame %d does not decode: %v", len(frames), err)
+		}
+		if img.Bounds().Size() != rect.Size() {
+			t.Fatalf("Frame %d decodes to %v, ANMF says %v", len(frames), img.Bounds(), rect)
+		}
+		for py := 0; py < rect.Dy(); py++ {
+			for px := 0; px < rect.Dx(); px++ {
+				c := color.NRGBAModel.Convert(img.At(img.Bounds().Min.X+px, img.Bounds().Min.Y+py)).(color.NRGBA)
+				if blend && c.A == 0 {
+					continue // unchanged pixel, the previous frame shows through
+				}
+				canvas.SetNRGBA(rect.Min.X+px, rect.Min.Y+py, c)
+			}
+		}
+		frames = append(frames, image.NewNRGBA(canvas.Rect))
+		copy(frames[len(frames)-1].Pix, canvas.Pix)
+		rects = append(rects, rect)
+	}
+	return frames, rects
+}
+
+// assertFrame compares a decoded frame with the paletted frame it was encoded from
+func assertFrame(t *testing.T, i int, got *image.NRGBA, want *image.Paletted) {
+	t.Helper()
+	b := want.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if w := color.NRGBAModel.Convert(want.At(x, y)); got.At(x, y) != w {
+				t.Fatalf("Frame %d pixel (%d,%d) = %v, want %v", i, x, y, got.At(x, y), w)
+			}
+		}
+	}
+}
+
+func TestEncodeWebPDecodes(t *testing.T) {
+	palette := color.Palette{
+		color.RGBA{0x00, 0x00, 0x00, 0xff},
+		color.RGBA{0xff, 0x99, 0x33, 0xff},
+		color.RGBA{0xff, 0xff, 0xcc, 0xff},
+		color.RGBA{0x33, 0x66, 0x99, 0xff},
+	}
+	base := image.NewPaletted(image.Rect(0, 0, 31, 21), palette)
+	for i := range base.Pix {
+		base.Pix[i] = uint8(i * 7 / 5 % len(palette))
+	}
+	// A block changes at odd coordinates, then a single pixel, then nothing
+	block := image.NewPaletted(base.Rect, palette)
+	copy(block.Pix, base.Pix)
+	for y := 7; y < 10; y++ {
+		for x := 13; x < 18; x++ {
+			block.SetColorIndex(x, y, uint8((x+y)%len(palette)))
+		}
+	}
+	pixel := image.NewPaletted(base.Rect, palette)
+	copy(pixel.Pix, block.Pix)
+	pixel.SetColorIndex(30, 20, (pixel.ColorIndexAt(30, 20)+1)%uint8(len(palette)))
+	frames := []*image.Paletted{base, block, pixel, pixel}
+
+	data := encodeWebP(frames, palette, AnimationOptions{Delay: 10})
+	decoded, rects := decodeWebPFrames(t, data, 31, 21)
+	if len(decoded) != 3 {
+		t.Fatalf("Expected 3 stored frames, got %d", len(decoded))
+	}
+	// Later frames are sub-rectangles starting on even coordinates
+	wantRects := []image.Rectangle{base.Rect, image.Rect(12, 6, 18, 10), image.Rect(30, 20, 31, 21)}
+	for i, want := range wantRects {
+		if rects[i] != want {
+			t.Errorf("Frame %d covers %v, want %v", i, rects[i], want)
+		}
+	}
+	for i, want := range frames[:3] {
+		assertFrame(t, i, decoded[i], want)
+	}
+}
+
+func TestEncodeWebPDecodesSyntheticSun(t *testing.T) {
+	var rgba []*image.RGBA
+	for i := 0; i < 5; i++ {
+		rgba = append(rgba, syntheticSun(48, i))
+	}
+	q := newQuantizer(rgba, 64)
+	var frames []*image.Paletted
+	for _, img := range rgba {
+		frames = append(frames, q.dither(img))
+	}
+	decoded, _ := decodeWebPFrames(t, encodeWebP(frames, q.palette, AnimationOptions{Delay: 25}), 48, 48)
+	if len(decoded) != len(frames) {
+		t.Fatalf("Expected %d stored frames, got %d", len(frames), len(decoded))
+	}
+	for i, want := range frames {
+		assertFrame(t, i, decoded[i], want)
+	}
+}
@@ -142,6 +142,10 @@ Please analyze the following comprehensive space weather data and generate a det
 - Historical K-index trends (24+ hours of data points)
 - Historical solar data trends (multiple data points)
 - Current band conditions and solar metrics
+- Gray-line windows of the club QTHs (gray_line, when QTHs are configured)
+- Measured foF2, hmF2 and MUF(3000)F2 from ionosondes (ionosondes, when stations are configured)
+- WSPR and PSKReporter spots by band and continent pair (observed_bands, when spot sources are configured)
+- Live DX cluster spots by band and continent, with notable calls (dx_cluster, when a cluster is configured)
 
 `, data.Timestamp.Format("2006-01-02 15:04 UTC"))
 
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 	"github.com/mmcdole/gofeed"
 )
@@ -17,6 +18,19 @@ type PropagationData struct {
 	// Historical time series data for trend analysis
 	HistoricalKIndex []KIndexPoint `json:"historical_k_index"`
 	HistoricalSolar  []SolarPoint  `json:"historical_solar"`
+	BandHistory      *BandCalendar `json:"band_history,omitempty"` // daily band conditions, when history is recorded
+
+	// Gray-line windows of the configured QTHs over the 24 hours after Timestamp
+	GrayLine []GrayLine `json:"gray_line,omitempty"`
+
+	// Measured F2 layer at the configured ionosondes, hourly over the 24 hours before Timestamp
+	Ionosondes []Ionosonde `json:"ionosondes,omitempty"`
+
+	// WSPR and PSKReporter spots of the configured hours before Timestamp by band and continent pair
+	ObservedBands *ObservedBands `json:"observed_bands,omitempty"`
+
+	// Live DX cluster spots of the configured window by band and continent, with notable calls
+	DXCluster *DXClusterSummary `json:"dx_cluster,omitempty"`
 }
 
 // SourceData contains raw data from all sources before normalization
@@ -25,6 +39,7 @@ type SourceData struct {
 	NOAASolar  []NOAASolarResponse  `json:"noaa_solar"`
 	N0NBH      *N0NBHResponse       `json:"n0nbh"`
 	SIDC       []*gofeed.Item       `json:"sidc"`
+	Ionosondes []IonosondeStation   `json:"ionosondes,omitempty"`
 }
 
 // SolarData contains solar activity information
@@ -89,6 +104,86 @@ type BandCondition struct {
 	Night string `json:"night"` // Poor/Fair/Good/Excellent
 }
 
+// ConditionValue maps a band condition (Closed, Poor, Fair, Good, Excellent) to 0-4; ok is false
+// for anything else
+func ConditionValue(condition string) (value int, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(condition)) {
+	case "closed":
+		return 0, true
+	case "poor":
+		return 1, true
+	case "fair":
+		return 2, true
+	case "good":
+		return 3, true
+	case "excellent":
+		return 4, true
+	}
+	return 0, false
+}
+
+// BandGroupCondition is the condition of an N0NBH band group, e.g. "12m-10m"
+type BandGroupCondition struct {
+	Group string
+	BandCondition
+}
+
+// Groups returns the conditions by N0NBH band group, lowest band first. N0NBH publishes groups;
+// the normalizer copies each into the bands it covers.
+func (b BandData) Groups() []BandGroupCondition {
+	return []BandGroupCondition{
+		{"80m-40m", b.Band80m},
+		{"30m-20m", b.Band20m},
+		{"17m-15m", b.Band15m},
+		{"12m-10m", b.Band10m},
+		{"6m", b.Band6m},
+	}
+}
+
+// BandCalendar holds daily band conditions: one row per band group and period, one value per day
+type BandCalendar struct {
+	Days []string          `json:"days"` // UTC dates, YYYY-MM-DD
+	Rows []BandCalendarRow `json:"rows"`
+}
+
+// BandCalendarRow is the daily condition of one band group by day or night
+type BandCalendarRow struct {
+	Band   string     `json:"band"`   // N0NBH band group
+	Period string     `json:"period"` // day or night
+	Values []*float64 `json:"values"` // daily mean condition, 0 (Closed) to 4 (Excellent); null without data
+	Days   [5]int     `json:"days"`   // days per condition, Closed to Excellent, by the rounded daily mean
+}
+
+// GrayLine holds the sun times and gray-line windows of one QTH
+type GrayLine struct {
+	Grid      string           `json:"grid"`
+	Latitude  float64          `json:"latitude"`
+	Longitude float64          `json:"longitude"`
+	Sunrise   *time.Time       `json:"sunrise,omitempty"` // next sunrise and sunset; nil during polar day or night
+	Sunset    *time.Time       `json:"sunset,omitempty"`
+	Windows   []GrayLineWindow `json:"windows"`
+}
+
+// GrayLineWindow is a period with the sun within 6° of the horizon at a QTH
+type GrayLineWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Event string    `json:"event"` // sunrise, sunset or twilight
+}
+
+// Ionosonde is the measured F2 layer at a station: its latest reading and the latest reading of
+// each hour
+type Ionosonde struct {
+	Code      string             `json:"code"`
+	Name      string             `json:"name"`
+	Grid      string             `json:"grid"`
+	Latitude  float64            `json:"latitude"`
+	Longitude float64            `json:"longitude"`
+	Latest    IonosondeReading   `json:"latest"`
+	Hourly    []IonosondeReading `json:"hourly"`
+	Source    string             `json:"source"`
+}
+
 // ForecastData contains propagation forecasts
 type ForecastData struct {
 	Today     DayForecast `json:"today"`
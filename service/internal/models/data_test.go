@@ -405,3 +405,14 @@ func TestComplexPropagationDataScenario(t *testing.T) {
 		t.Errorf("Expected High solar activity, got %s", unmarshaled.SolarData.SolarActivity)
 	}
 }
+
+func TestConditionValue(t *testing.T) {
+	for condition, want := range map[string]int{"Closed": 0, "poor": 1, " Fair ": 2, "GOOD": 3, "Excellent": 4} {
+		if got, ok := ConditionValue(condition); !ok || got != want {
+			t.Errorf("ConditionValue(%q) = %v, %v, want %v", condition, got, ok, want)
+		}
+	}
+	if _, ok := ConditionValue("n/a"); ok {
+		t.Error("ConditionValue(n/a) ok = true, want false")
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// DXClusterSummary is the DX cluster activity of the window between From and To: what is being
+// worked right now
+type DXClusterSummary struct {
+	From       time.Time        `json:"from"`
+	To         time.Time        `json:"to"`
+	Host       string           `json:"host"`
+	Connected  bool             `json:"connected"`
+	Spots      int              `json:"spots"`
+	Bands      []DXClusterBand  `json:"bands"`      // lowest band first, bands without spots left out
+	Continents []DXClusterCount `json:"continents"` // spots by continent of the DX station, most first
+	Notable    []DXClusterCall  `json:"notable"`    // watched and most spotted calls
+}
+
+// DXClusterBand counts the spots of one band by continent of the DX station
+type DXClusterBand struct {
+	Band       string           `json:"band"`
+	Spots      int              `json:"spots"`
+	Continents []DXClusterCount `json:"continents"` // most first
+}
+
+// DXClusterCount is a number of spots of DX stations on a continent
+type DXClusterCount struct {
+	Continent string `json:"continent"` // one of geo.Continents, empty if unknown
+	Spots     int    `json:"spots"`
+}
+
+// DXClusterCall is a DX station and how often it was spotted
+type DXClusterCall struct {
+	Call         string    `json:"call"`
+	Continent    string    `json:"continent"`
+	Spots        int       `json:"spots"`
+	Spotters     int       `json:"spotters"` // distinct spotters
+	Bands        []string  `json:"bands"`
+	FrequencyKHz float64   `json:"frequency_khz"` // of the latest spot
+	Comment      string    `json:"comment,omitempty"`
+	LastSpot     time.Time `json:"last_spot"`
+	Watched      bool      `json:"watched"` // listed in the configured watch list
+}
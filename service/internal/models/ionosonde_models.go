@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// IonosondeStation is an ionosonde and the scaled F2-layer parameters fetched from it
+type IonosondeStation struct {
+	Code      string             `json:"code"` // URSI code, e.g. BC840
+	Name      string             `json:"name"`
+	Latitude  float64            `json:"latitude"`
+	Longitude float64            `json:"longitude"`
+	Readings  []IonosondeReading `json:"readings"` // oldest first
+	Source    string             `json:"source"`
+}
+
+// IonosondeReading is one autoscaled ionogram; zero values were not scaled
+type IonosondeReading struct {
+	Time       time.Time `json:"time"`
+	Confidence float64   `json:"confidence"`     // autoscaling confidence score 0-100, -1 if unknown
+	FoF2       float64   `json:"fof2"`           // F2 critical frequency, MHz
+	HmF2       float64   `json:"hmf2,omitempty"` // F2 peak height, km
+	MUF3000    float64   `json:"muf_3000"`       // MUF(3000)F2, MHz
+}
+
+// Latest returns the most recent reading, nil if there is none
+func (s *IonosondeStation) Latest() *IonosondeReading {
+	if len(s.Readings) == 0 {
+		return nil
+	}
+	return &s.Readings[len(s.Readings)-1]
+}
@@ -16,3 +16,15 @@ type NOAASolarResponse struct {
 	SolarFluxAdjusted float64 `json:"f10.7_adj"`
 	Source            string  `json:"source"`
 }
+
+// NOAAPredictedCycleResponse is one month of NOAA's predicted solar cycle, with its confidence range
+type NOAAPredictedCycleResponse struct {
+	TimeTag       string  `json:"time_tag"`
+	PredictedSSN  float64 `json:"predicted_ssn"`
+	HighSSN       float64 `json:"high_ssn"`
+	LowSSN        float64 `json:"low_ssn"`
+	PredictedF107 float64 `json:"predicted_f10.7"`
+	HighF107      float64 `json:"high_f10.7"`
+	LowF107       float64 `json:"low_f10.7"`
+	Source        string  `json:"source"`
+}
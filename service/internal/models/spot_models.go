@@ -0,0 +1,100 @@
+package models
+
+import "time"
+
+// Spot is a reception report of a transmitter by a receiver. Sources that aggregate on the server
+// report one Spot per band and locator pair, with Count reception reports.
+type Spot struct {
+	Time   time.Time `json:"time"` // zero when aggregated
+	Source string    `json:"source"`
+	Band   string    `json:"band"` // e.g. "20m"
+	TxGrid string    `json:"tx_grid"`
+	RxGrid string    `json:"rx_grid"`
+	SNR    float64   `json:"snr"` // dB; the best one when aggregated
+	Count  int       `json:"count"`
+}
+
+// ObservedBands aggregates the spots between From and To by band and continent pair
+type ObservedBands struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Sources []string       `json:"sources"`
+	Spots   int            `json:"spots"`
+	Bands   []ObservedBand `json:"bands"` // lowest band first, bands without spots left out
+}
+
+// ObservedBand holds the continent pairs a band was open between
+type ObservedBand struct {
+	Band     string            `json:"band"`
+	Group    string            `json:"group,omitempty"` // N0NBH band group of band_data, e.g. "30m-20m"
+	Spots    int               `json:"spots"`
+	Openings []ObservedOpening `json:"openings"` // most spots first
+}
+
+// ObservedOpening is a continent pair with spots in either direction
+type ObservedOpening struct {
+	From      string  `json:"from"` // continent, e.g. "EU"
+	To        string  `json:"to"`
+	Spots     int     `json:"spots"`
+	BestSNR   float64 `json:"best_snr_db"`
+	LongestKm float64 `json:"longest_km"`
+}
+
+// Band is an amateur band and its edges in MHz
+type Band struct {
+	Name string
+	Low  float64
+	High float64
+}
+
+// Bands are the amateur bands spots are counted on, lowest first
+var Bands = []Band{
+	{"160m", 1.8, 2.0}, {"80m", 3.5, 4.0}, {"60m", 5.25, 5.45}, {"40m", 7.0, 7.3},
+	{"30m", 10.1, 10.15}, {"20m", 14.0, 14.35}, {"17m", 18.068, 18.168},
+	{"15m", 21.0, 21.45}, {"12m", 24.89, 24.99}, {"10m", 28.0, 29.7}, {"6m", 50.0, 54.0},
+}
+
+// BandOf returns the amateur band of a frequency in MHz, empty outside the bands
+func BandOf(mhz float64) string {
+	for _, b := range Bands {
+		if mhz >= b.Low && mhz <= b.High {
+			return b.Name
+		}
+	}
+	return ""
+}
+
+// BandGroup returns the N0NBH band group of a band, empty if N0NBH does not cover it
+func BandGroup(band string) string {
+	switch band {
+	case "80m", "60m", "40m":
+		return "80m-40m"
+	case "30m", "20m":
+		return "30m-20m"
+	case "17m", "15m":
+		return "17m-15m"
+	case "12m", "10m":
+		return "12m-10m"
+	case "6m":
+		return "6m"
+	}
+	return ""
+}
+
+// Group returns the continent pairs open on any band of an N0NBH band group, as "EU-NA"
+func (o *ObservedBands) Group(group string) []string {
+	seen := make(map[string]bool)
+	var pairs []string
+	for _, band := range o.Bands {
+		if band.Group != group {
+			continue
+		}
+		for _, opening := range band.Openings {
+			if pair := opening.From + "-" + opening.To; !seen[pair] {
+				seen[pair] = true
+				pairs = append(pairs, pair)
+			}
+		}
+	}
+	return pairs
+}
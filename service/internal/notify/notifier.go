@@ -0,0 +1,338 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"radiocast/internal/config"
+	"radiocast/internal/logger"
+	"radiocast/internal/reports"
+	"radiocast/internal/storage"
+)
+
+// EventType identifies what triggered a notification
+type EventType string
+
+const (
+	EventReportPublished EventType = "report.published"
+	EventKpThreshold     EventType = "threshold.kp"
+	EventFlareThreshold  EventType = "threshold.flare"
+	EventBandOpening     EventType = "threshold.band"
+)
+
+// Severity of a notification event
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+const (
+	// statePath stores the last observed space weather state for threshold transitions
+	statePath = "notifications/state.json"
+	// deliveryLogPrefix is where per-report delivery logs are stored
+	deliveryLogPrefix = "notifications/deliveries/"
+)
+
+// Event is the payload delivered to webhook targets
+type Event struct {
+	ID        string                 `json:"id"`
+	Type      EventType              `json:"type"`
+	Severity  Severity               `json:"severity"`
+	Title     string                 `json:"title"`
+	Message   string                 `json:"message"`
+	ReportURL string                 `json:"report_url,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// Delivery records the outcome of sending one event to one target
+type Delivery struct {
+	EventID    string     `json:"event_id"`
+	EventType  EventType  `json:"event_type"`
+	Target     TargetKind `json:"target"`
+	Host       string     `json:"host"`
+	Attempts   int        `json:"attempts"`
+	StatusCode int        `json:"status_code,omitempty"`
+	Success    bool       `json:"success"`
+	Error      string     `json:"error,omitempty"`
+	Timestamp  time.Time  `json:"timestamp"`
+}
+
+// Options configures a Notifier
+type Options struct {
+	Secret     string        // HMAC-SHA256 signing secret (empty disables signing)
+	MaxRetries int           // Retries after the first attempt for retryable failures
+	Backoff    time.Duration // Initial retry delay, doubled after every attempt
+	BaseURL    string        // Public service URL used for absolute report links
+	Rules      Rules
+	HTTPClient *http.Client
+}
+
+// Notifier delivers report and space weather events to webhook targets
+type Notifier struct {
+	targets []Target
+	opts    Options
+	storage storage.StorageClient
+}
+
+// NewNotifier creates a notifier. storageClient may be nil, in which case no state or delivery log is kept.
+func NewNotifier(targets []Target, opts Options, storageClient storage.StorageClient) *Notifier {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 15 * time.Second}
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	return &Notifier{
+		targets: targets,
+		opts:    opts,
+		storage: storageClient,
+	}
+}
+
+// NewNotifierFromConfig creates a notifier from service configuration
+func NewNotifierFromConfig(cfg *config.Config, storageClient storage.StorageClient) (*Notifier, error) {
+	targets, err := ParseTargets(cfg.NotifyWebhooks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFY_WEBHOOKS: %w", err)
+	}
+	return NewNotifier(targets, Options{
+		Secret:     cfg.NotifyWebhookSecret,
+		MaxRetries: cfg.NotifyMaxRetries,
+		BaseURL:    cfg.PublicBaseURL,
+		Rules: Rules{
+			KpThreshold: cfg.NotifyKpThreshold,
+			FlareClass:  cfg.NotifyFlareClass,
+			GoodBands:   cfg.NotifyGoodBands,
+		},
+	}, storageClient), nil
+}
+
+// ReportPublished implements reports.PublishHook: it sends a "report published" event plus
+// any threshold events triggered by the new data, then stores the delivery log
+func (n *Notifier) ReportPublished(ctx context.Context, report *reports.PublishedReport) error {
+	if len(n.targets) == 0 {
+		return nil
+	}
+
+	data := report.Data
+	reportURL := strings.TrimSuffix(n.opts.BaseURL, "/") + report.URLPath()
+
+	events := []Event{{
+		Type:     EventReportPublished,
+		Severity: SeverityInfo,
+		Title:    fmt.Sprintf("New propagation report: %s", data.Timestamp.UTC().Format("2006-01-02 15:04 UTC")),
+		Message: fmt.Sprintf("SFI %.0f, SSN %d, Kp %.1f, X-ray %s.",
+			data.SolarData.SolarFluxIndex, data.SolarData.SunspotNumber, data.GeomagData.KIndex, valueOrNA(data.SolarData.XRayFlux)),
+		Data: map[string]interface{}{"folder": report.FolderPath},
+	}}
+
+	prev := n.loadState(ctx)
+	events = append(events, n.opts.Rules.Evaluate(prev, data)...)
+
+	for i := range events {
+		events[i].ID = fmt.Sprintf("%s-%s-%d", data.Timestamp.UTC().Format("20060102T150405Z"), events[i].Type, i)
+		events[i].ReportURL = reportURL
+		events[i].Timestamp = data.Timestamp
+	}
+
+	deliveries := n.Notify(ctx, events)
+
+	n.saveState(ctx, StateFromData(data))
+	n.saveDeliveryLog(ctx, report.FolderPath, deliveries)
+
+	failed := 0
+	for _, d := range deliveries {
+		if !d.Success {
+			failed++
+		}
+	}
+	logger.Info("Webhook notifications sent", map[string]interface{}{
+		"events":     len(events),
+		"deliveries": len(deliveries),
+		"failed":     failed,
+	})
+	if failed > 0 {
+		return fmt.Errorf("%d of %d webhook deliveries failed", failed, len(deliveries))
+	}
+	return nil
+}
+
+// Notify delivers every event to every target and returns one Delivery per pair
+func (n *Notifier) Notify(ctx context.Context, events []Event) []Delivery {
+	var deliveries []Delivery
+	for _, event := range events {
+		for _, target := range n.targets {
+			deliveries = append(deliveries, n.deliver(ctx, target, event))
+		}
+	}
+	return deliveries
+}
+
+// deliver sends one event to one target, retrying with exponential backoff on retryable failures
+func (n *Notifier) deliver(ctx context.Context, target Target, event Event) Delivery {
+	delivery := Delivery{
+		EventID:   event.ID,
+		EventType: event.Type,
+		Target:    target.Kind,
+		Host:      hostOf(target.URL),
+		Timestamp: time.Now().UTC(),
+	}
+
+	backoff := n.opts.Backoff
+	for attempt := 0; attempt <= n.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				delivery.Error = ctx.Err().Error()
+				return delivery
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		delivery.Attempts = attempt + 1
+		status, retryable, err := n.send(ctx, target, event)
+		delivery.StatusCode = status
+		if err == nil {
+			delivery.Success = true
+			delivery.Error = ""
+			return delivery
+		}
+		delivery.Error = err.Error()
+		logger.Debug("Webhook delivery attempt failed", map[string]interface{}{
+			"target":  target.Kind,
+			"host":    delivery.Host,
+			"attempt": delivery.Attempts,
+			"error":   err.Error(),
+		})
+		if !retryable {
+			break
+		}
+	}
+
+	logger.Warn("Webhook delivery failed", map[string]interface{}{
+		"target":   target.Kind,
+		"host":     delivery.Host,
+		"event":    event.Type,
+		"attempts": delivery.Attempts,
+		"error":    delivery.Error,
+	})
+	return delivery
+}
+
+// send performs a single HTTP attempt. It reports whether a failure is worth retrying.
+func (n *Notifier) send(ctx context.Context, target Target, event Event) (int, bool, error) {
+	req, body, err := newRequest(target, event)
+	if err != nil {
+		return 0, false, err
+	}
+	req = req.WithContext(ctx)
+
+	if n.opts.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Radiocast-Timestamp", timestamp)
+		req.Header.Set("X-Radiocast-Signature", "sha256="+Sign(n.opts.Secret, timestamp, body))
+	}
+
+	resp, err := n.opts.HTTPClient.Do(req)
+	if err != nil {
+		return 0, true, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return resp.StatusCode, retryable, fmt.Errorf("receiver returned status %d", resp.StatusCode)
+}
+
+// Sign computes the hex HMAC-SHA256 of "timestamp.body". Receivers verify the
+// X-Radiocast-Signature header ("sha256=<hex>") with the same secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// loadState reads the last observed state; returns nil when unavailable
+func (n *Notifier) loadState(ctx context.Context) *State {
+	if n.storage == nil {
+		return nil
+	}
+	exists, err := n.storage.FileExists(ctx, statePath)
+	if err != nil || !exists {
+		return nil
+	}
+	raw, err := n.storage.GetFile(ctx, statePath)
+	if err != nil {
+		logger.Warn("Failed to read notification state", map[string]interface{}{"error": err.Error()})
+		return nil
+	}
+	var state State
+	if err := json.Unmarshal(raw, &state); err != nil {
+		logger.Warn("Failed to parse notification state", map[string]interface{}{"error": err.Error()})
+		return nil
+	}
+	return &state
+}
+
+// saveState persists the current state for the next run
+func (n *Notifier) saveState(ctx context.Context, state *State) {
+	if n.storage == nil {
+		return
+	}
+	raw, _ := json.MarshalIndent(state, "", "  ")
+	if err := n.storage.StoreFile(ctx, statePath, raw); err != nil {
+		logger.Warn("Failed to store notification state", map[string]interface{}{"error": err.Error()})
+	}
+}
+
+// saveDeliveryLog stores the deliveries for a report next to other notification data
+func (n *Notifier) saveDeliveryLog(ctx context.Context, folderPath string, deliveries []Delivery) {
+	if n.storage == nil || len(deliveries) == 0 {
+		return
+	}
+	raw, _ := json.MarshalIndent(deliveries, "", "  ")
+	logPath := deliveryLogPrefix + folderPath + ".json"
+	if err := n.storage.StoreFile(ctx, logPath, raw); err != nil {
+		logger.Warn("Failed to store delivery log", map[string]interface{}{"path": logPath, "error": err.Error()})
+	}
+}
+
+// hostOf returns the host of a URL so secrets embedded in webhook paths never reach logs
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Host
+}
+
+// valueOrNA returns "n/a" for empty strings
+func valueOrNA(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "n/a"
+	}
+	return s
+}
@@ -0,0 +1,330 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+	"radiocast/internal/reports"
+	"radiocast/internal/storage"
+)
+
+// receiver is a local webhook endpoint that records every request
+type receiver struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+	statuses []int // statuses returned for consecutive requests; 200 once exhausted
+}
+
+type recordedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func (rc *receiver) handler(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	rc.requests = append(rc.requests, recordedRequest{header: r.Header.Clone(), body: body})
+	status := http.StatusOK
+	if len(rc.requests) <= len(rc.statuses) {
+		status = rc.statuses[len(rc.requests)-1]
+	}
+	rc.mu.Unlock()
+	w.WriteHeader(status)
+}
+
+func newReceiver(t *testing.T, statuses ...int) (*receiver, *httptest.Server) {
+	rc := &receiver{statuses: statuses}
+	srv := httptest.NewServer(http.HandlerFunc(rc.handler))
+	t.Cleanup(srv.Close)
+	return rc, srv
+}
+
+func testEvent() Event {
+	return Event{
+		ID:        "evt-1",
+		Type:      EventReportPublished,
+		Severity:  SeverityInfo,
+		Title:     "New propagation report",
+		Message:   "SFI 150, Kp 2.0",
+		ReportURL: "https://example.net/reports/2025/09/17/PropagationReport-2025-09-17-12-00-00/index.html",
+		Timestamp: time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		entry    string
+		wantKind TargetKind
+		wantURL  string
+		wantErr  bool
+	}{
+		{"discord:https://discord.com/api/webhooks/1/abc", KindDiscord, "https://discord.com/api/webhooks/1/abc", false},
+		{"slack:https://hooks.slack.com/services/x", KindSlack, "https://hooks.slack.com/services/x", false},
+		{"matrix:https://hookshot.example/webhook/abc", KindMatrix, "https://hookshot.example/webhook/abc", false},
+		{"NTFY:https://ntfy.sh/radiocast", KindNtfy, "https://ntfy.sh/radiocast", false},
+		{"https://example.com/hook", KindJSON, "https://example.com/hook", false},
+		{"json:http://localhost:9000/hook", KindJSON, "http://localhost:9000/hook", false},
+		{"discord:not-a-url", "", "", true},
+		{"", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			target, err := ParseTarget(tt.entry)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.entry)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if target.Kind != tt.wantKind || target.URL != tt.wantURL {
+				t.Errorf("Got %s %s, want %s %s", target.Kind, target.URL, tt.wantKind, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestNotify_PayloadFormats(t *testing.T) {
+	tests := []struct {
+		kind  TargetKind
+		check func(t *testing.T, req recordedRequest)
+	}{
+		{KindJSON, func(t *testing.T, req recordedRequest) {
+			var event Event
+			if err := json.Unmarshal(req.body, &event); err != nil {
+				t.Fatalf("Invalid JSON payload: %v", err)
+			}
+			if event.ID != "evt-1" || event.Type != EventReportPublished {
+				t.Errorf("Unexpected event payload: %+v", event)
+			}
+		}},
+		{KindDiscord, func(t *testing.T, req recordedRequest) {
+			var payload struct {
+				Content string `json:"content"`
+				Embeds  []struct {
+					Title string `json:"title"`
+					URL   string `json:"url"`
+				} `json:"embeds"`
+			}
+			if err := json.Unmarshal(req.body, &payload); err != nil {
+				t.Fatalf("Invalid Discord payload: %v", err)
+			}
+			if len(payload.Embeds) != 1 || payload.Embeds[0].URL == "" {
+				t.Errorf("Expected one embed with report URL, got %+v", payload.Embeds)
+			}
+		}},
+		{KindSlack, func(t *testing.T, req recordedRequest) {
+			var payload map[string]string
+			if err := json.Unmarshal(req.body, &payload); err != nil {
+				t.Fatalf("Invalid Slack payload: %v", err)
+			}
+			if !strings.Contains(payload["text"], "|Open report>") {
+				t.Errorf("Expected Slack link in text, got %q", payload["text"])
+			}
+		}},
+		{KindMatrix, func(t *testing.T, req recordedRequest) {
+			var payload map[string]string
+			if err := json.Unmarshal(req.body, &payload); err != nil {
+				t.Fatalf("Invalid Matrix payload: %v", err)
+			}
+			if payload["text"] == "" || !strings.Contains(payload["html"], "<strong>") {
+				t.Errorf("Expected text and html fields, got %+v", payload)
+			}
+		}},
+		{KindNtfy, func(t *testing.T, req recordedRequest) {
+			if string(req.body) != "SFI 150, Kp 2.0" {
+				t.Errorf("Expected plain message body, got %q", req.body)
+			}
+			if req.header.Get("Title") != "New propagation report" {
+				t.Errorf("Expected Title header, got %q", req.header.Get("Title"))
+			}
+			if req.header.Get("Click") == "" {
+				t.Error("Expected Click header with report URL")
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			rc, srv := newReceiver(t)
+			n := NewNotifier([]Target{{Kind: tt.kind, URL: srv.URL}}, Options{}, nil)
+
+			deliveries := n.Notify(context.Background(), []Event{testEvent()})
+			if len(deliveries) != 1 || !deliveries[0].Success {
+				t.Fatalf("Expected one successful delivery, got %+v", deliveries)
+			}
+			if len(rc.requests) != 1 {
+				t.Fatalf("Expected 1 request, got %d", len(rc.requests))
+			}
+			tt.check(t, rc.requests[0])
+		})
+	}
+}
+
+func TestNotify_HMACSignature(t *testing.T) {
+	rc, srv := newReceiver(t)
+	n := NewNotifier([]Target{{Kind: KindJSON, URL: srv.URL}}, Options{Secret: "top-secret"}, nil)
+
+	n.Notify(context.Background(), []Event{testEvent()})
+	if len(rc.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(rc.requests))
+	}
+
+	req := rc.requests[0]
+	timestamp := req.header.Get("X-Radiocast-Timestamp")
+	signature := req.header.Get("X-Radiocast-Signature")
+	if timestamp == "" || signature == "" {
+		t.Fatal("Expected signature headers to be set")
+	}
+	if want := "sha256=" + Sign("top-secret", timestamp, req.body); signature != want {
+		t.Errorf("Signature mismatch: got %s, want %s", signature, want)
+	}
+	if signature == "sha256="+Sign("wrong-secret", timestamp, req.body) {
+		t.Error("Signature should depend on the secret")
+	}
+}
+
+func TestNotify_RetriesWithBackoff(t *testing.T) {
+	rc, srv := newReceiver(t, http.StatusInternalServerError, http.StatusTooManyRequests)
+	n := NewNotifier([]Target{{Kind: KindJSON, URL: srv.URL}}, Options{MaxRetries: 3, Backoff: time.Millisecond}, nil)
+
+	deliveries := n.Notify(context.Background(), []Event{testEvent()})
+	if !deliveries[0].Success {
+		t.Fatalf("Expected delivery to succeed after retries, got %+v", deliveries[0])
+	}
+	if deliveries[0].Attempts != 3 || len(rc.requests) != 3 {
+		t.Errorf("Expected 3 attempts, got %d (requests %d)", deliveries[0].Attempts, len(rc.requests))
+	}
+}
+
+func TestNotify_NoRetryOnClientError(t *testing.T) {
+	rc, srv := newReceiver(t, http.StatusBadRequest)
+	n := NewNotifier([]Target{{Kind: KindJSON, URL: srv.URL}}, Options{MaxRetries: 3, Backoff: time.Millisecond}, nil)
+
+	deliveries := n.Notify(context.Background(), []Event{testEvent()})
+	if deliveries[0].Success {
+		t.Fatal("Expected delivery to fail")
+	}
+	if deliveries[0].StatusCode != http.StatusBadRequest || len(rc.requests) != 1 {
+		t.Errorf("Expected a single 400 attempt, got status %d after %d requests", deliveries[0].StatusCode, len(rc.requests))
+	}
+}
+
+func TestNotify_GivesUpAfterMaxRetries(t *testing.T) {
+	rc, srv := newReceiver(t, 503, 503, 503, 503, 503)
+	n := NewNotifier([]Target{{Kind: KindJSON, URL: srv.URL}}, Options{MaxRetries: 2, Backoff: time.Millisecond}, nil)
+
+	deliveries := n.Notify(context.Background(), []Event{testEvent()})
+	if deliveries[0].Success || deliveries[0].Attempts != 3 || len(rc.requests) != 3 {
+		t.Errorf("Expected 3 failed attempts, got %+v (requests %d)", deliveries[0], len(rc.requests))
+	}
+}
+
+func TestRules_Evaluate(t *testing.T) {
+	rules := Rules{KpThreshold: 5, FlareClass: "X", GoodBands: []string{"10m"}}
+
+	quiet := &models.PropagationData{}
+	quiet.GeomagData.KIndex = 2
+	quiet.SolarData.XRayFlux = "C1.0"
+	quiet.BandData.Band10m = models.BandCondition{Day: "Poor", Night: "Poor"}
+
+	stormy := &models.PropagationData{}
+	stormy.GeomagData.KIndex = 6.3
+	stormy.SolarData.XRayFlux = "X2.1"
+	stormy.BandData.Band10m = models.BandCondition{Day: "Good", Night: "Poor"}
+
+	if events := rules.Evaluate(StateFromData(quiet), quiet); len(events) != 0 {
+		t.Errorf("Expected no events for quiet conditions, got %d", len(events))
+	}
+
+	events := rules.Evaluate(StateFromData(quiet), stormy)
+	types := map[EventType]bool{}
+	for _, e := range events {
+		types[e.Type] = true
+	}
+	for _, want := range []EventType{EventKpThreshold, EventFlareThreshold, EventBandOpening} {
+		if !types[want] {
+			t.Errorf("Expected %s event, got %+v", want, events)
+		}
+	}
+
+	// Conditions that persist do not fire again
+	if events := rules.Evaluate(StateFromData(stormy), stormy); len(events) != 0 {
+		t.Errorf("Expected no repeated events, got %d", len(events))
+	}
+}
+
+func TestReportPublished_StoresStateAndDeliveryLog(t *testing.T) {
+	originalDir, _ := os.Getwd()
+	os.Chdir(t.TempDir())
+	defer os.Chdir(originalDir)
+
+	client, err := storage.NewLocalStorageClient("")
+	if err != nil {
+		t.Fatalf("Failed to create storage client: %v", err)
+	}
+
+	rc, srv := newReceiver(t)
+	n := NewNotifier([]Target{{Kind: KindJSON, URL: srv.URL}}, Options{
+		BaseURL: "https://example.net",
+		Rules:   Rules{KpThreshold: 5},
+	}, client)
+
+	data := &models.PropagationData{Timestamp: time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC)}
+	data.GeomagData.KIndex = 5.7
+	report := &reports.PublishedReport{
+		Data:       data,
+		FolderPath: storage.GenerateReportFolderPath(data.Timestamp),
+	}
+
+	ctx := context.Background()
+	if err := n.ReportPublished(ctx, report); err != nil {
+		t.Fatalf("ReportPublished failed: %v", err)
+	}
+
+	// Published + Kp threshold events
+	if len(rc.requests) != 2 {
+		t.Fatalf("Expected 2 webhook requests, got %d", len(rc.requests))
+	}
+	var event Event
+	json.Unmarshal(rc.requests[0].body, &event)
+	if !strings.HasPrefix(event.ReportURL, "https://example.net/reports/2025/09/17/") {
+		t.Errorf("Unexpected report URL: %s", event.ReportURL)
+	}
+
+	logPath := deliveryLogPrefix + report.FolderPath + ".json"
+	raw, err := client.GetFile(ctx, logPath)
+	if err != nil {
+		t.Fatalf("Expected delivery log at %s: %v", logPath, err)
+	}
+	var deliveries []Delivery
+	if err := json.Unmarshal(raw, &deliveries); err != nil || len(deliveries) != 2 {
+		t.Errorf("Expected 2 logged deliveries, got %d (%v)", len(deliveries), err)
+	}
+
+	state := n.loadState(ctx)
+	if state == nil || state.KIndex != 5.7 {
+		t.Errorf("Expected stored state with Kp 5.7, got %+v", state)
+	}
+
+	// Second report with the same Kp only announces the report
+	rc.requests = nil
+	if err := n.ReportPublished(ctx, report); err != nil {
+		t.Fatalf("ReportPublished failed: %v", err)
+	}
+	if len(rc.requests) != 1 {
+		t.Errorf("Expected only the publish event on unchanged conditions, got %d requests", len(rc.requests))
+	}
+}
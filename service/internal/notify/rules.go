@@ -0,0 +1,155 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+
+	"radiocast/internal/models"
+)
+
+// Rules configures the space weather thresholds that trigger notifications
+type Rules struct {
+	KpThreshold float64  // Fire when Kp rises to or above this value (0 disables)
+	FlareClass  string   // Fire when the X-ray class reaches this letter (A, B, C, M, X; empty disables)
+	GoodBands   []string // Fire when any of these bands changes to Good or Excellent (e.g. "10m")
+}
+
+// State is the last observed space weather state, persisted between runs so that
+// threshold rules fire on transitions instead of on every report
+type State struct {
+	KIndex    float64           `json:"k_index"`
+	XRayClass string            `json:"xray_class"`
+	Bands     map[string]string `json:"bands"`
+}
+
+// StateFromData extracts the rule-relevant state from propagation data
+func StateFromData(data *models.PropagationData) *State {
+	state := &State{
+		KIndex:    data.GeomagData.KIndex,
+		XRayClass: flareClass(data.SolarData.XRayFlux),
+		Bands:     make(map[string]string),
+	}
+	for name, cond := range bandConditions(data.BandData) {
+		state.Bands[name] = bestCondition(cond)
+	}
+	return state
+}
+
+// Evaluate compares the previous state (may be nil) with the current data and returns threshold events
+func (r Rules) Evaluate(prev *State, data *models.PropagationData) []Event {
+	cur := StateFromData(data)
+	var events []Event
+
+	if r.KpThreshold > 0 && cur.KIndex >= r.KpThreshold && (prev == nil || prev.KIndex < r.KpThreshold) {
+		events = append(events, Event{
+			Type:     EventKpThreshold,
+			Severity: kpSeverity(cur.KIndex),
+			Title:    fmt.Sprintf("Geomagnetic storm: Kp %.1f", cur.KIndex),
+			Message: fmt.Sprintf("Planetary K-index reached %.1f (threshold %.1f). Expect degraded HF propagation on polar paths and possible aurora.",
+				cur.KIndex, r.KpThreshold),
+			Data: map[string]interface{}{"k_index": cur.KIndex, "threshold": r.KpThreshold},
+		})
+	}
+
+	if threshold := classRank(r.FlareClass); threshold > 0 {
+		curRank := classRank(cur.XRayClass)
+		prevRank := 0
+		if prev != nil {
+			prevRank = classRank(prev.XRayClass)
+		}
+		if curRank >= threshold && prevRank < threshold {
+			events = append(events, Event{
+				Type:     EventFlareThreshold,
+				Severity: SeverityCritical,
+				Title:    fmt.Sprintf("%s-class solar flare: %s", cur.XRayClass, data.SolarData.XRayFlux),
+				Message: fmt.Sprintf("X-ray flux is at %s. Sunlit HF paths may experience radio blackouts.",
+					data.SolarData.XRayFlux),
+				Data: map[string]interface{}{"xray_flux": data.SolarData.XRayFlux, "threshold": strings.ToUpper(r.FlareClass)},
+			})
+		}
+	}
+
+	for _, band := range r.GoodBands {
+		band = strings.ToLower(strings.TrimSpace(band))
+		cond, ok := cur.Bands[band]
+		if !ok || conditionRank(cond) < conditionRank("good") {
+			continue
+		}
+		if prev != nil && conditionRank(prev.Bands[band]) >= conditionRank("good") {
+			continue
+		}
+		events = append(events, Event{
+			Type:     EventBandOpening,
+			Severity: SeverityInfo,
+			Title:    fmt.Sprintf("%s is open: %s", band, cond),
+			Message:  fmt.Sprintf("N0NBH reports %s conditions on %s.", cond, band),
+			Data:     map[string]interface{}{"band": band, "condition": cond},
+		})
+	}
+
+	return events
+}
+
+// bandConditions maps band names to their day/night conditions
+func bandConditions(b models.BandData) map[string]models.BandCondition {
+	return map[string]models.BandCondition{
+		"80m": b.Band80m,
+		"40m": b.Band40m,
+		"20m": b.Band20m,
+		"17m": b.Band17m,
+		"15m": b.Band15m,
+		"12m": b.Band12m,
+		"10m": b.Band10m,
+		"6m":  b.Band6m,
+	}
+}
+
+// bestCondition returns the better of the day and night conditions
+func bestCondition(cond models.BandCondition) string {
+	if conditionRank(cond.Night) > conditionRank(cond.Day) {
+		return cond.Night
+	}
+	return cond.Day
+}
+
+// conditionRank orders band conditions: Closed < Poor < Fair < Good < Excellent
+func conditionRank(cond string) int {
+	switch strings.ToLower(strings.TrimSpace(cond)) {
+	case "poor":
+		return 1
+	case "fair":
+		return 2
+	case "good":
+		return 3
+	case "excellent":
+		return 4
+	default:
+		return 0
+	}
+}
+
+// flareClass extracts the class letter from an X-ray flux string such as "M1.2"
+func flareClass(xray string) string {
+	xray = strings.ToUpper(strings.TrimSpace(xray))
+	if xray == "" {
+		return ""
+	}
+	return xray[:1]
+}
+
+// classRank orders X-ray classes: A < B < C < M < X
+func classRank(class string) int {
+	class = strings.ToUpper(strings.TrimSpace(class))
+	if len(class) != 1 {
+		return 0
+	}
+	return strings.Index("ABCMX", class) + 1
+}
+
+// kpSeverity maps Kp to an event severity (G3 and above are critical)
+func kpSeverity(kp float64) Severity {
+	if kp >= 7 {
+		return SeverityCritical
+	}
+	return SeverityWarning
+}
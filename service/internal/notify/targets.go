@@ -0,0 +1,185 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TargetKind identifies the payload format expected by a webhook receiver
+type TargetKind string
+
+const (
+	KindJSON    TargetKind = "json"    // Generic JSON POST of the Event
+	KindDiscord TargetKind = "discord" // Discord channel webhook
+	KindSlack   TargetKind = "slack"   // Slack incoming webhook
+	KindMatrix  TargetKind = "matrix"  // Matrix hookshot-compatible generic webhook
+	KindNtfy    TargetKind = "ntfy"    // ntfy-compatible topic URL
+)
+
+// Target is a single webhook receiver
+type Target struct {
+	Kind TargetKind
+	URL  string
+}
+
+// ParseTarget parses a "kind:url" entry. Entries without a known kind prefix are treated as generic JSON webhooks.
+func ParseTarget(entry string) (Target, error) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return Target{}, fmt.Errorf("empty webhook entry")
+	}
+
+	kind := KindJSON
+	url := entry
+	if idx := strings.Index(entry, ":"); idx > 0 {
+		switch prefix := TargetKind(strings.ToLower(entry[:idx])); prefix {
+		case KindJSON, KindDiscord, KindSlack, KindMatrix, KindNtfy:
+			kind = prefix
+			url = entry[idx+1:]
+		}
+	}
+
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return Target{}, fmt.Errorf("invalid webhook URL for %s target: %q", kind, url)
+	}
+	return Target{Kind: kind, URL: url}, nil
+}
+
+// ParseTargets parses a list of "kind:url" entries
+func ParseTargets(entries []string) ([]Target, error) {
+	var targets []Target
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		target, err := ParseTarget(entry)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// buildRequestBody renders the event in the format expected by the target.
+// Returns the body, its content type and any extra headers.
+func buildRequestBody(target Target, event Event) ([]byte, string, map[string]string, error) {
+	switch target.Kind {
+	case KindDiscord:
+		embed := map[string]interface{}{
+			"title":       event.Title,
+			"description": event.Message,
+			"color":       severityColor(event.Severity),
+			"timestamp":   event.Timestamp.UTC().Format(time.RFC3339),
+		}
+		if event.ReportURL != "" {
+			embed["url"] = event.ReportURL
+		}
+		body, err := json.Marshal(map[string]interface{}{
+			"username": "Radiocast",
+			"content":  event.Title,
+			"embeds":   []interface{}{embed},
+		})
+		return body, "application/json", nil, err
+
+	case KindSlack:
+		text := fmt.Sprintf("*%s*\n%s", event.Title, event.Message)
+		if event.ReportURL != "" {
+			text += fmt.Sprintf("\n<%s|Open report>", event.ReportURL)
+		}
+		body, err := json.Marshal(map[string]interface{}{"text": text})
+		return body, "application/json", nil, err
+
+	case KindMatrix:
+		plain := event.Title + "\n" + event.Message
+		htmlBody := fmt.Sprintf("<strong>%s</strong><br/>%s", html.EscapeString(event.Title), html.EscapeString(event.Message))
+		if event.ReportURL != "" {
+			plain += "\n" + event.ReportURL
+			htmlBody += fmt.Sprintf(`<br/><a href="%s">Open report</a>`, html.EscapeString(event.ReportURL))
+		}
+		body, err := json.Marshal(map[string]interface{}{
+			"text":     plain,
+			"html":     htmlBody,
+			"username": "Radiocast",
+		})
+		return body, "application/json", nil, err
+
+	case KindNtfy:
+		headers := map[string]string{
+			"Title":    event.Title,
+			"Priority": ntfyPriority(event.Severity),
+			"Tags":     ntfyTags(event),
+		}
+		if event.ReportURL != "" {
+			headers["Click"] = event.ReportURL
+		}
+		return []byte(event.Message), "text/plain; charset=utf-8", headers, nil
+
+	default:
+		body, err := json.Marshal(event)
+		return body, "application/json", nil, err
+	}
+}
+
+// newRequest builds the HTTP request for a target without signing it
+func newRequest(target Target, event Event) (*http.Request, []byte, error) {
+	body, contentType, headers, err := buildRequestBody(target, event)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build %s payload: %w", target.Kind, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "radiocast-notifier")
+	req.Header.Set("X-Radiocast-Event", string(event.Type))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return req, body, nil
+}
+
+// severityColor maps severity to a Discord embed color
+func severityColor(severity Severity) int {
+	switch severity {
+	case SeverityCritical:
+		return 0xdc3545
+	case SeverityWarning:
+		return 0xfd7e14
+	default:
+		return 0x28a745
+	}
+}
+
+// ntfyPriority maps severity to an ntfy priority (1-5)
+func ntfyPriority(severity Severity) string {
+	switch severity {
+	case SeverityCritical:
+		return "5"
+	case SeverityWarning:
+		return "4"
+	default:
+		return "3"
+	}
+}
+
+// ntfyTags returns ntfy emoji tags for an event
+func ntfyTags(event Event) string {
+	switch event.Type {
+	case EventKpThreshold:
+		return "warning,magnet"
+	case EventFlareThreshold:
+		return "warning,sunny"
+	case EventBandOpening:
+		return "radio"
+	default:
+		return "page_facing_up"
+	}
+}
@@ -0,0 +1,374 @@
+// Package pdf writes simple PDF documents (text in the standard fonts, vector shapes and
+// RGB images) without external dependencies. Coordinates are in points with the origin
+// at the top-left corner of the page.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Size is a page size in points (1/72 inch)
+type Size struct {
+	Width, Height float64
+}
+
+// Standard page sizes
+var (
+	A4     = Size{Width: 595.28, Height: 841.89}
+	Letter = Size{Width: 612, Height: 792}
+)
+
+// Point is a position on the page
+type Point struct {
+	X, Y float64
+}
+
+// Document is a PDF under construction
+type Document struct {
+	Title   string
+	Author  string
+	Subject string
+	Creator string
+
+	size    Size
+	pages   []*Page
+	images  []*Image
+	alphas  map[int]bool // fill opacities in percent, written as ExtGState resources
+	created time.Time
+}
+
+// New creates an empty document with the given page size
+func New(size Size) *Document {
+	return &Document{size: size, created: time.Now()}
+}
+
+// SetCreationDate overrides the creation date recorded in the document info
+func (d *Document) SetCreationDate(t time.Time) {
+	d.created = t
+}
+
+// PageSize returns the page size of the document
+func (d *Document) PageSize() Size {
+	return d.size
+}
+
+// AddPage appends a blank page
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Pages returns the pages added so far
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// Image is an RGB raster image that can be placed on any page of its document
+type Image struct {
+	Width, Height int
+
+	name string
+	data []byte // zlib-compressed RGB samples
+}
+
+// AddImage registers an image with the document; transparent pixels are composited onto white
+func (d *Document) AddImage(img image.Image) (*Image, error) {
+	b := img.Bounds()
+	raw := make([]byte, 0, b.Dx()*b.Dy()*3)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, a := img.At(x, y).RGBA()
+			white := 0xffff - a
+			raw = append(raw, byte((r+white)>>8), byte((g+white)>>8), byte((bl+white)>>8))
+		}
+	}
+	data, err := deflate(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress image: %w", err)
+	}
+	im := &Image{Width: b.Dx(), Height: b.Dy(), name: "Im" + strconv.Itoa(len(d.images)+1), data: data}
+	d.images = append(d.images, im)
+	return im, nil
+}
+
+// Page collects the drawing operators of one page
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+// y converts a top-left based coordinate into PDF user space
+func (p *Page) y(y float64) float64 {
+	return p.doc.size.Height - y
+}
+
+// FillRect fills an axis-aligned rectangle whose top-left corner is (x, y)
+func (p *Page) FillRect(x, y, w, h float64, c color.Color) {
+	fmt.Fprintf(&p.content, "%s rg %s %s %s %s re f\n", rgb(c), num(x), num(p.y(y+h)), num(w), num(h))
+}
+
+// StrokeRect outlines a rectangle
+func (p *Page) StrokeRect(x, y, w, h, width float64, c color.Color) {
+	fmt.Fprintf(&p.content, "%s RG %s w %s %s %s %s re S\n", rgb(c), num(width), num(x), num(p.y(y+h)), num(w), num(h))
+}
+
+// Line strokes a straight line with round caps
+func (p *Page) Line(x0, y0, x1, y1, width float64, c color.Color) {
+	p.Polyline([]Point{{x0, y0}, {x1, y1}}, width, c)
+}
+
+// Polyline strokes connected line segments
+func (p *Page) Polyline(points []Point, width float64, c color.Color) {
+	if len(points) < 2 {
+		return
+	}
+	fmt.Fprintf(&p.content, "%s RG %s w 1 J 1 j\n", rgb(c), num(width))
+	p.path(points)
+	p.content.WriteString("S\n")
+}
+
+// FillPolygon fills a closed polygon
+func (p *Page) FillPolygon(points []Point, c color.Color) {
+	if len(points) < 3 {
+		return
+	}
+	fmt.Fprintf(&p.content, "%s rg\n", rgb(c))
+	p.path(points)
+	p.content.WriteString("h f\n")
+}
+
+// FillPolygonAlpha fills a closed polygon with an opacity between 0 and 1
+func (p *Page) FillPolygonAlpha(points []Point, c color.Color, alpha float64) {
+	percent := int(math.Round(100 * math.Max(0, math.Min(1, alpha))))
+	if percent == 100 || len(points) < 3 {
+		p.FillPolygon(points, c)
+		return
+	}
+	if p.doc.alphas == nil {
+		p.doc.alphas = make(map[int]bool)
+	}
+	p.doc.alphas[percent] = true
+	fmt.Fprintf(&p.content, "q /A%d gs %s rg\n", percent, rgb(c))
+	p.path(points)
+	p.content.WriteString("h f Q\n")
+}
+
+// FillCircle fills a circle using four Bézier segments
+func (p *Page) FillCircle(cx, cy, r float64, c color.Color) {
+	const k = 0.5523 // control point distance for a quarter circle
+	cy = p.y(cy)
+	fmt.Fprintf(&p.content, "%s rg %s %s m\n", rgb(c), num(cx+r), num(cy))
+	quarters := [][6]float64{
+		{cx + r, cy + k*r, cx + k*r, cy + r, cx, cy + r},
+		{cx - k*r, cy + r, cx - r, cy + k*r, cx - r, cy},
+		{cx - r, cy - k*r, cx - k*r, cy - r, cx, cy - r},
+		{cx + k*r, cy - r, cx + r, cy - k*r, cx + r, cy},
+	}
+	for _, q := range quarters {
+		fmt.Fprintf(&p.content, "%s %s %s %s %s %s c\n", num(q[0]), num(q[1]), num(q[2]), num(q[3]), num(q[4]), num(q[5]))
+	}
+	p.content.WriteString("f\n")
+}
+
+func (p *Page) path(points []Point) {
+	for i, pt := range points {
+		op := "l"
+		if i == 0 {
+			op = "m"
+		}
+		fmt.Fprintf(&p.content, "%s %s %s\n", num(pt.X), num(p.y(pt.Y)), op)
+	}
+}
+
+// Text draws a single line of text with its baseline at y
+func (p *Page) Text(x, y float64, s string, font Font, size float64, c color.Color) {
+	text := Encode(s)
+	if len(text) == 0 {
+		return
+	}
+	fmt.Fprintf(&p.content, "BT %s rg /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		rgb(c), int(font)+1, num(size), num(x), num(p.y(y)), escape(text))
+}
+
+// DrawImage places an image scaled into the rectangle with top-left corner (x, y)
+func (p *Page) DrawImage(img *Image, x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "q %s 0 0 %s %s %s cm /%s Do Q\n", num(w), num(h), num(x), num(p.y(y+h)), img.name)
+}
+
+// Bytes serialises the document
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo serialises the document to w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	ww := &pdfWriter{}
+	ww.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Object numbers: catalog, page tree, resources, fonts, images, then page/content pairs, info
+	const catalogID, pagesID, resourcesID = 1, 2, 3
+	fontID := func(f int) int { return 4 + f }
+	imageID := func(i int) int { return 4 + len(fontNames) + i }
+	pageID := func(i int) int { return imageID(len(d.images)) + 2*i }
+	infoID := pageID(len(d.pages))
+
+	ww.object(catalogID, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID))
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageID(i))
+	}
+	ww.object(pagesID, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(d.pages), num(d.size.Width), num(d.size.Height)))
+
+	var res strings.Builder
+	res.WriteString("<< /ProcSet [/PDF /Text /ImageC] /Font <<")
+	for f := range fontNames {
+		fmt.Fprintf(&res, " /F%d %d 0 R", f+1, fontID(f))
+	}
+	res.WriteString(" >>")
+	if len(d.images) > 0 {
+		res.WriteString(" /XObject <<")
+		for i, im := range d.images {
+			fmt.Fprintf(&res, " /%s %d 0 R", im.name, imageID(i))
+		}
+		res.WriteString(" >>")
+	}
+	if len(d.alphas) > 0 {
+		percents := make([]int, 0, len(d.alphas))
+		for percent := range d.alphas {
+			percents = append(percents, percent)
+		}
+		sort.Ints(percents)
+		res.WriteString(" /ExtGState <<")
+		for _, percent := range percents {
+			fmt.Fprintf(&res, " /A%d << /ca %s >>", percent, num(float64(percent)/100))
+		}
+		res.WriteString(" >>")
+	}
+	res.WriteString(" >>")
+	ww.object(resourcesID, res.String())
+
+	for f, name := range fontNames {
+		ww.object(fontID(f), fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+	for i, im := range d.images {
+		ww.stream(imageID(i), fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode",
+			im.Width, im.Height), im.data)
+	}
+	for i, p := range d.pages {
+		ww.object(pageID(i), fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Resources %d 0 R /Contents %d 0 R >>",
+			pagesID, resourcesID, pageID(i)+1))
+		content, err := deflate(p.content.Bytes())
+		if err != nil {
+			return 0, fmt.Errorf("failed to compress page %d: %w", i+1, err)
+		}
+		ww.stream(pageID(i)+1, "/Filter /FlateDecode", content)
+	}
+
+	info := []string{"/Producer (radiocast)", "/CreationDate (D:" + d.created.UTC().Format("20060102150405") + "Z)"}
+	for _, field := range []struct{ key, value string }{
+		{"Title", d.Title}, {"Author", d.Author}, {"Subject", d.Subject}, {"Creator", d.Creator},
+	} {
+		if field.value != "" {
+			info = append(info, "/"+field.key+" ("+escape(Encode(field.value))+")")
+		}
+	}
+	ww.object(infoID, "<< "+strings.Join(info, " ")+" >>")
+
+	xref := ww.buf.Len()
+	fmt.Fprintf(&ww.buf, "xref\n0 %d\n0000000000 65535 f \n", infoID+1)
+	for id := 1; id <= infoID; id++ {
+		fmt.Fprintf(&ww.buf, "%010d 00000 n \n", ww.offsets[id])
+	}
+	fmt.Fprintf(&ww.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		infoID+1, catalogID, infoID, xref)
+
+	n, err := w.Write(ww.buf.Bytes())
+	return int64(n), err
+}
+
+// pdfWriter tracks object offsets for the cross-reference table
+type pdfWriter struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+}
+
+func (w *pdfWriter) object(id int, body string) {
+	w.begin(id)
+	w.buf.WriteString(body)
+	w.buf.WriteString("\nendobj\n")
+}
+
+func (w *pdfWriter) stream(id int, dict string, data []byte) {
+	w.begin(id)
+	fmt.Fprintf(&w.buf, "<< %s /Length %d >>\nstream\n", dict, len(data))
+	w.buf.Write(data)
+	w.buf.WriteString("\nendstream\nendobj\n")
+}
+
+func (w *pdfWriter) begin(id int) {
+	if w.offsets == nil {
+		w.offsets = make(map[int]int)
+	}
+	w.offsets[id] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n", id)
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// escape quotes a WinAnsi byte string for a PDF literal string
+func escape(text []byte) string {
+	var b strings.Builder
+	for _, c := range text {
+		switch c {
+		case '\\', '(', ')':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func rgb(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return num(float64(r)/0xffff) + " " + num(float64(g)/0xffff) + " " + num(float64(b)/0xffff)
+}
+
+// num formats a number with at most three decimals
+func num(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		v = 0
+	}
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
@@ -0,0 +1,111 @@
+package pdf
+
+// Font selects one of the PDF standard Type 1 fonts, which every viewer provides,
+// so no font data has to be embedded
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+	HelveticaOblique
+	HelveticaBoldOblique
+	Courier
+)
+
+var fontNames = [...]string{
+	Helvetica:            "Helvetica",
+	HelveticaBold:        "Helvetica-Bold",
+	HelveticaOblique:     "Helvetica-Oblique",
+	HelveticaBoldOblique: "Helvetica-BoldOblique",
+	Courier:              "Courier",
+}
+
+// Glyph widths in 1/1000 em for ASCII 32..126, taken from the Adobe font metrics
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// Widths of the WinAnsi characters above 127 that reports commonly use
+var specialWidths = map[byte]int{
+	0x85: 1000,           // …
+	0x91: 222, 0x92: 222, // ‘ ’
+	0x93: 333, 0x94: 333, // “ ”
+	0x95: 350,            // •
+	0x96: 556,            // –
+	0x97: 1000,           // —
+	0xB0: 400,            // °
+	0xB1: 584,            // ±
+	0xB2: 333, 0xB3: 333, // ² ³
+	0xD7: 584, // ×
+}
+
+// WinAnsi code points for the Unicode characters outside Latin-1
+var winAnsiSpecial = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, '‰': 0x89,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'™': 0x99, '−': '-', '→': '>', '←': '<', '≈': '~', '≤': '<', '≥': '>',
+}
+
+// Encode converts text to WinAnsiEncoding; characters the standard fonts cannot show
+// (emoji, CJK, ...) are dropped
+func Encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r >= 32 && r < 127:
+			out = append(out, byte(r))
+		case r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiSpecial[r]; ok {
+				out = append(out, b)
+			}
+		}
+	}
+	return out
+}
+
+// TextWidth returns the width of s in points when set in the given font and size
+func TextWidth(font Font, size float64, s string) float64 {
+	return encodedWidth(font, size, Encode(s))
+}
+
+func encodedWidth(font Font, size float64, text []byte) float64 {
+	total := 0
+	for _, b := range text {
+		total += glyphWidth(font, b)
+	}
+	return float64(total) * size / 1000
+}
+
+func glyphWidth(font Font, b byte) int {
+	if font == Courier {
+		return 600
+	}
+	if b >= 32 && b < 127 {
+		if font == HelveticaBold || font == HelveticaBoldOblique {
+			return helveticaBoldWidths[b-32]
+		}
+		return helveticaWidths[b-32]
+	}
+	if w, ok := specialWidths[b]; ok {
+		return w
+	}
+	return 556
+}
@@ -0,0 +1,109 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"image"
+	"image/color"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocumentStructure(t *testing.T) {
+	doc := New(A4)
+	doc.Title = "Propagation (test)"
+	doc.SetCreationDate(time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC))
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	im, err := doc.AddImage(img)
+	if err != nil {
+		t.Fatalf("AddImage failed: %v", err)
+	}
+
+	p1 := doc.AddPage()
+	p1.Text(72, 72, "Hello – world (20m)", HelveticaBold, 14, color.Black)
+	p1.DrawImage(im, 72, 100, 40, 20)
+	p1.FillCircle(100, 200, 5, color.RGBA{G: 128, A: 255})
+	p1.FillPolygonAlpha([]Point{{0, 0}, {50, 0}, {50, 50}}, color.Black, 0.35)
+	doc.AddPage().Line(0, 0, 100, 100, 1, color.Black)
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes failed: %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) {
+		t.Error("expected 2 pages in page tree")
+	}
+	if !bytes.Contains(out, []byte(`/Title (Propagation \(test\))`)) {
+		t.Error("expected escaped title in document info")
+	}
+
+	// Every xref entry must point at the start of its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if startxref == nil {
+		t.Fatal("missing startxref")
+	}
+	xrefOffset, _ := strconv.Atoi(string(startxref[1]))
+	lines := strings.Split(string(out[xrefOffset:]), "\n")
+	if lines[0] != "xref" {
+		t.Fatalf("startxref does not point at xref table: %q", lines[0])
+	}
+	count, _ := strconv.Atoi(strings.Fields(lines[1])[1])
+	for id := 1; id < count; id++ {
+		offset, _ := strconv.Atoi(lines[2+id][:10])
+		want := strconv.Itoa(id) + " 0 obj"
+		if !bytes.HasPrefix(out[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q", id, out[offset:offset+10])
+		}
+	}
+
+	// The first page content stream carries the text in WinAnsi encoding
+	content := firstContentStream(t, out)
+	if !strings.Contains(content, "(Hello \x96 world \\(20m\\)) Tj") {
+		t.Errorf("unexpected page content: %s", content)
+	}
+	if !strings.Contains(content, "/Im1 Do") {
+		t.Error("expected image placement in page content")
+	}
+	if !strings.Contains(content, "q /A35 gs") || !bytes.Contains(out, []byte("/ExtGState << /A35 << /ca 0.35 >> >>")) {
+		t.Error("expected a translucent fill with its graphics state resource")
+	}
+}
+
+func firstContentStream(t *testing.T, out []byte) string {
+	t.Helper()
+	idx := bytes.Index(out, []byte("<< /Filter /FlateDecode /Length "))
+	if idx < 0 {
+		t.Fatal("no content stream found")
+	}
+	start := bytes.Index(out[idx:], []byte("stream\n")) + idx + len("stream\n")
+	zr, err := zlib.NewReader(bytes.NewReader(out[start:]))
+	if err != nil {
+		t.Fatalf("content stream is not zlib data: %v", err)
+	}
+	data, _ := io.ReadAll(zr)
+	return string(data)
+}
+
+func TestEncodeAndTextWidth(t *testing.T) {
+	if got := string(Encode("📡 K-index • 3°")); got != " K-index \x95 3\xb0" {
+		t.Errorf("Encode = %q", got)
+	}
+	if w := TextWidth(Helvetica, 10, "AAAA"); w != 26.68 {
+		t.Errorf("TextWidth(Helvetica) = %v, want 26.68", w)
+	}
+	if TextWidth(HelveticaBold, 10, "Band") <= TextWidth(Helvetica, 10, "Band") {
+		t.Error("bold text should be wider than regular")
+	}
+	if w := TextWidth(Courier, 10, "abc"); w != 18 {
+		t.Errorf("TextWidth(Courier) = %v, want 18", w)
+	}
+}
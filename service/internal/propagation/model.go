@@ -0,0 +1,96 @@
+package propagation
+
+import (
+	"math"
+	"time"
+
+	"radiocast/internal/geo"
+	"radiocast/internal/sun"
+)
+
+// A deliberately simple, deterministic ionospheric model. It is meant to show when paths open and
+// close through the day, not to replace VOACAP: foF2 follows the solar zenith angle between a night
+// floor and a noon value that both grow with the sunspot number, and is depressed during storms.
+const (
+	layerHeight = 300.0  // km, height of F2 reflection
+	maxHop      = 3500.0 // km, longest single F2 hop at a practical elevation angle
+
+	mufSpread = 0.15 // relative day-to-day spread of the MUF around its median
+	lufSpread = 0.20 // relative spread of the absorption-limited LUF
+)
+
+// Indices are the space weather values the model is driven by
+type Indices struct {
+	SSN float64 `json:"ssn"` // effective sunspot number
+	SFI float64 `json:"sfi"` // 10.7 cm solar flux
+	Kp  float64 `json:"kp"`  // latest planetary K-index
+}
+
+// SSNFromSFI converts a 10.7 cm solar flux to the equivalent sunspot number by inverting
+// SFI = 63.7 + 0.728 SSN + 0.00089 SSN²
+func SSNFromSFI(sfi float64) float64 {
+	const a, b, c = 0.00089, 0.728, 63.7
+	if sfi <= c {
+		return 0
+	}
+	return (-b + math.Sqrt(b*b+4*a*(sfi-c))) / (2 * a)
+}
+
+// FoF2 estimates the F2 critical frequency in MHz at a location and time
+func FoF2(lat, lon float64, t time.Time, idx Indices) float64 {
+	r := math.Min(math.Max(idx.SSN, 0), 250)
+	noon := 6.0 + 0.04*r
+	night := 2.5 + 0.012*r
+
+	// The F2 layer stays lit until the sun is well below the horizon, and decays after
+	cosZenith := math.Cos(radians(sun.Zenith(lat, lon, t)))
+	light := math.Min(math.Max((cosZenith+0.25)/1.25, 0), 1)
+	fo := night + (noon-night)*math.Sqrt(light)
+
+	// Geomagnetic storms deplete the F2 layer, most at high latitudes
+	storm := math.Max(idx.Kp-4, 0) * 0.05 * math.Min(math.Abs(lat)/60, 1)
+	return fo * (1 - math.Min(storm, 0.5))
+}
+
+// obliquityFactor is the ratio of the MUF to foF2 for a single hop of hopKm, from the angle of
+// incidence on a layer at layerHeight over a spherical earth
+func obliquityFactor(hopKm float64) float64 {
+	theta := hopKm / (2 * geo.EarthRadius) // half the hop as a central angle
+	ratio := geo.EarthRadius / (geo.EarthRadius + layerHeight)
+	elevation := math.Atan2(math.Cos(theta)-ratio, math.Sin(theta))
+	sinIncidence := ratio * math.Cos(elevation)
+	return 1 / math.Sqrt(1-sinIncidence*sinIncidence)
+}
+
+// absorption is the D-layer absorption of one hop relative to a hop under the overhead sun
+func absorption(lat, lon float64, t time.Time) float64 {
+	cosZenith := math.Cos(radians(sun.Zenith(lat, lon, t)))
+	if cosZenith <= 0 {
+		return 0
+	}
+	return math.Pow(cosZenith, 0.75)
+}
+
+// openProbability is the chance a frequency is usable between the LUF and MUF, treating both as
+// normally distributed around the model's median
+func openProbability(freq, muf, luf float64) float64 {
+	p := normalCDF((muf - freq) / (mufSpread * muf))
+	if luf > 0 {
+		p *= normalCDF((freq - luf) / (lufSpread * luf))
+	}
+	return p
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// round rounds v to the given number of decimals
+func round(v float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(v*p) / p
+}
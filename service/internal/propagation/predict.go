@@ -0,0 +1,185 @@
+package propagation
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"radiocast/internal/geo"
+	"radiocast/internal/sun"
+	"radiocast/internal/timeseries"
+)
+
+// Band is an amateur band and the frequency the model evaluates it at
+type Band struct {
+	Name         string  `json:"name"`
+	FrequencyMHz float64 `json:"frequency_mhz"`
+}
+
+// Bands are the HF bands and 6m
+var Bands = []Band{
+	{"80m", 3.6}, {"40m", 7.1}, {"30m", 10.1}, {"20m", 14.1}, {"17m", 18.1},
+	{"15m", 21.1}, {"12m", 24.9}, {"10m", 28.3}, {"6m", 50.1},
+}
+
+// Region is a common DX target, placed at a representative city
+type Region struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+}
+
+// Regions are the DX targets predicted for every QTH
+var Regions = []Region{
+	{"Europe", 50.1, 8.7},
+	{"North America East", 40.7, -74.0},
+	{"North America West", 37.8, -122.4},
+	{"South America", -23.5, -46.6},
+	{"Southern Africa", -26.2, 28.0},
+	{"South Asia", 28.6, 77.2},
+	{"Japan", 35.7, 139.7},
+	{"Oceania", -33.9, 151.2},
+}
+
+// MinDXDistance skips regions closer than this to the QTH; they are not DX from there
+const MinDXDistance = 1000.0 // km
+
+// KpWindow is how far back the latest K-index is looked up; quiet conditions are assumed beyond
+const KpWindow = 24 * time.Hour
+
+// solarWindow averages the solar flux over one solar rotation, which the F2 layer follows more
+// closely than the daily value
+const solarWindow = 27 * 24 * time.Hour
+
+// ErrNoSolarData is returned when there is no recorded solar flux or sunspot number to predict from
+var ErrNoSolarData = errors.New("no solar flux or sunspot number recorded")
+
+// Prediction is the hourly propagation from one QTH to the DX regions over one UTC day
+type Prediction struct {
+	Location geo.Location `json:"location"`
+	Date     string       `json:"date"`
+	Indices  Indices      `json:"indices"`
+	Sun      sun.Times    `json:"sun"`
+	FoF2     []float64    `json:"fof2"` // hourly foF2 over the QTH, MHz
+	Bands    []Band       `json:"bands"`
+	Paths    []Path       `json:"paths"`
+}
+
+// Path is the short-path prediction to one region
+type Path struct {
+	Region     string       `json:"region"`
+	Location   geo.Location `json:"location"`
+	DistanceKm float64      `json:"distance_km"`
+	Bearing    float64      `json:"bearing"`
+	Hops       int          `json:"hops"`
+	Sun        sun.Times    `json:"sun"` // at the far end
+	Hours      []PathHour   `json:"hours"`
+}
+
+// PathHour is the path prediction of one hour; Open holds a probability per band, in Bands order
+type PathHour struct {
+	Time time.Time `json:"time"`
+	FoF2 float64   `json:"fof2"` // lowest foF2 at the hop midpoints, MHz
+	MUF  float64   `json:"muf"`
+	LUF  float64   `json:"luf"`
+	Open []float64 `json:"open"`
+}
+
+// Predict computes the hourly predictions from a QTH over the UTC day of day
+func Predict(qth geo.Location, idx Indices, day time.Time) *Prediction {
+	day = day.UTC().Truncate(24 * time.Hour)
+	p := &Prediction{
+		Location: qth,
+		Date:     day.Format("2006-01-02"),
+		Indices:  idx,
+		Sun:      sun.TimesOn(qth.Latitude, qth.Longitude, day),
+		Bands:    Bands,
+		Paths:    []Path{},
+	}
+	for h := 0; h < 24; h++ {
+		p.FoF2 = append(p.FoF2, round(FoF2(qth.Latitude, qth.Longitude, day.Add(time.Duration(h)*time.Hour), idx), 2))
+	}
+
+	for _, region := range Regions {
+		target := geo.NewLocation(region.Latitude, region.Longitude)
+		gc := geo.ShortPath(qth, target)
+		if gc.DistanceKm < MinDXDistance {
+			continue
+		}
+		hops := gc.Hops(maxHop)
+		path := Path{
+			Region:     region.Name,
+			Location:   target,
+			DistanceKm: math.Round(gc.DistanceKm),
+			Bearing:    math.Round(gc.Azimuth),
+			Hops:       hops,
+			Sun:        sun.TimesOn(target.Latitude, target.Longitude, day),
+		}
+		for h := 0; h < 24; h++ {
+			path.Hours = append(path.Hours, predictHour(gc, hops, idx, day.Add(time.Duration(h)*time.Hour)))
+		}
+		p.Paths = append(p.Paths, path)
+	}
+	return p
+}
+
+// predictHour evaluates a path at the control point of every hop: the weakest reflection sets the
+// MUF and the absorption of all hops sets the LUF
+func predictHour(gc geo.Path, hops int, idx Indices, t time.Time) PathHour {
+	m := obliquityFactor(gc.DistanceKm / float64(hops))
+	fo := math.Inf(1)
+	loss := 0.0
+	for _, cp := range gc.ControlPoints(hops) {
+		fo = math.Min(fo, FoF2(cp.Latitude, cp.Longitude, t, idx))
+		loss += absorption(cp.Latitude, cp.Longitude, t)
+	}
+	muf := fo * m
+	luf := 3.4 * math.Sqrt(loss*(1+0.008*math.Max(idx.SSN, 0)))
+
+	hour := PathHour{Time: t, FoF2: round(fo, 2), MUF: round(muf, 2), LUF: round(luf, 2)}
+	for _, band := range Bands {
+		hour.Open = append(hour.Open, round(openProbability(band.FrequencyMHz, muf, luf), 2))
+	}
+	return hour
+}
+
+// IndicesFromHistory derives the model inputs from the time-series store: the solar flux averaged
+// over the last solar rotation (or the sunspot number if no flux is recorded) and the latest Kp
+func IndicesFromHistory(ctx context.Context, store *timeseries.Store, now time.Time) (Indices, error) {
+	var idx Indices
+	sfi, err := store.Query(ctx, timeseries.MetricSFI, now.Add(-solarWindow), now)
+	if err != nil {
+		return idx, err
+	}
+	if len(sfi) > 0 {
+		idx.SFI = round(mean(sfi), 1)
+		idx.SSN = round(SSNFromSFI(idx.SFI), 1)
+	} else {
+		ssn, err := store.Query(ctx, timeseries.MetricSSN, now.Add(-solarWindow), now)
+		if err != nil {
+			return idx, err
+		}
+		if len(ssn) == 0 {
+			return idx, ErrNoSolarData
+		}
+		idx.SSN = round(mean(ssn), 1)
+	}
+
+	kp, err := store.Query(ctx, timeseries.MetricKp, now.Add(-KpWindow), now)
+	if err != nil {
+		return idx, err
+	}
+	if len(kp) > 0 {
+		idx.Kp = kp[len(kp)-1].Value
+	}
+	return idx, nil
+}
+
+func mean(points []timeseries.Point) float64 {
+	sum := 0.0
+	for _, p := range points {
+		sum += p.Value
+	}
+	return sum / float64(len(points))
+}
@@ -0,0 +1,123 @@
+package propagation
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"radiocast/internal/geo"
+	"radiocast/internal/storage"
+	"radiocast/internal/timeseries"
+)
+
+func TestSSNFromSFI(t *testing.T) {
+	for _, ssn := range []float64{0, 50, 150, 250} {
+		sfi := 63.7 + 0.728*ssn + 0.00089*ssn*ssn
+		if got := SSNFromSFI(sfi); math.Abs(got-ssn) > 1e-6 {
+			t.Errorf("SSNFromSFI(%v) = %v, want %v", sfi, got, ssn)
+		}
+	}
+	if got := SSNFromSFI(60); got != 0 {
+		t.Errorf("SSNFromSFI(60) = %v, want 0", got)
+	}
+}
+
+func TestFoF2(t *testing.T) {
+	noon := time.Date(2025, 3, 20, 12, 0, 0, 0, time.UTC)
+	quiet := Indices{SSN: 100}
+	day, night := FoF2(0, 0, noon, quiet), FoF2(0, 180, noon, quiet)
+	if math.Abs(day-10) > 0.1 || math.Abs(night-3.7) > 0.01 {
+		t.Errorf("FoF2 = %.2f at noon and %.2f at midnight, want 10 and 3.7", day, night)
+	}
+	if high := FoF2(0, 0, noon, Indices{SSN: 200}); high <= day {
+		t.Errorf("FoF2 does not grow with the sunspot number: %.2f <= %.2f", high, day)
+	}
+	if storm := FoF2(60, 0, noon, Indices{SSN: 100, Kp: 8}); storm >= FoF2(60, 0, noon, quiet)*0.85 {
+		t.Errorf("FoF2 not depleted by a Kp 8 storm at 60N: %.2f", storm)
+	}
+}
+
+func TestObliquityFactor(t *testing.T) {
+	if m := obliquityFactor(0); math.Abs(m-1) > 1e-9 {
+		t.Errorf("Vertical incidence factor = %v, want 1", m)
+	}
+	if m := obliquityFactor(3000); m < 2.9 || m > 3.4 {
+		t.Errorf("M(3000)F2 = %v, want about 3", m)
+	}
+}
+
+func TestPredict(t *testing.T) {
+	qth, err := geo.ParseGrid("FN31pr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Predict(qth, Indices{SSN: 137, SFI: 180, Kp: 2}, time.Date(2025, 10, 18, 15, 30, 0, 0, time.UTC))
+
+	if p.Date != "2025-10-18" || len(p.FoF2) != 24 || len(p.Paths) != 7 {
+		t.Fatalf("Prediction for %s has %d hours and %d paths, want 24 and 7", p.Date, len(p.FoF2), len(p.Paths))
+	}
+	if p.Sun.Sunrise.Format("15:04") != "11:04" || p.Sun.Sunset.Format("15:04") != "22:06" {
+		t.Errorf("QTH sun = %v to %v", p.Sun.Sunrise, p.Sun.Sunset)
+	}
+	for _, path := range p.Paths {
+		if path.Region == "North America East" {
+			t.Errorf("%s is within %v km and should be skipped", path.Region, MinDXDistance)
+		}
+		if len(path.Hours) != 24 || len(path.Hours[0].Open) != len(Bands) {
+			t.Errorf("%s has %d hours of %d bands", path.Region, len(path.Hours), len(path.Hours[0].Open))
+		}
+	}
+
+	europe := p.Paths[0]
+	if europe.Region != "Europe" || europe.Hops != 2 || europe.DistanceKm < 5900 || europe.DistanceKm > 6200 || europe.Bearing != 51 {
+		t.Errorf("Europe path = %s %v km at %v° in %d hops", europe.Region, europe.DistanceKm, europe.Bearing, europe.Hops)
+	}
+	// Midday over the Atlantic opens 10m and closes 80m by absorption; at night it is the reverse
+	const b80, b20, b10 = 0, 3, 7
+	noon, night := europe.Hours[15], europe.Hours[3]
+	if noon.Open[b10] < 0.5 || noon.Open[b80] > 0.2 || noon.MUF <= night.MUF || noon.LUF <= night.LUF {
+		t.Errorf("Europe at 15 UTC = %+v", noon)
+	}
+	if night.Open[b10] > 0.01 || night.Open[b80] < 0.99 || night.Open[b20] > 0.5 {
+		t.Errorf("Europe at 03 UTC = %+v", night)
+	}
+	for _, hour := range europe.Hours {
+		for _, open := range hour.Open {
+			if open < 0 || open > 1 {
+				t.Errorf("Probability out of range at %v: %v", hour.Time, hour.Open)
+			}
+		}
+	}
+}
+
+func TestIndicesFromHistory(t *testing.T) {
+	ctx := context.Background()
+	store := timeseries.NewStore(storage.NewMemoryStorageClient())
+	now := time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	if _, err := IndicesFromHistory(ctx, store, now); !errors.Is(err, ErrNoSolarData) {
+		t.Errorf("IndicesFromHistory() error = %v, want ErrNoSolarData", err)
+	}
+
+	store.Upsert(ctx, timeseries.MetricSSN, []timeseries.Point{{Time: now.AddDate(0, 0, -1), Value: 120}})
+	idx, err := IndicesFromHistory(ctx, store, now)
+	if err != nil || idx.SSN != 120 || idx.SFI != 0 || idx.Kp != 0 {
+		t.Errorf("IndicesFromHistory() = %+v, %v, want SSN 120 without flux", idx, err)
+	}
+
+	store.Upsert(ctx, timeseries.MetricSFI, []timeseries.Point{
+		{Time: now.AddDate(0, 0, -40), Value: 300}, // before the solar rotation
+		{Time: now.AddDate(0, 0, -2), Value: 170},
+		{Time: now.AddDate(0, 0, -1), Value: 190},
+	})
+	store.Upsert(ctx, timeseries.MetricKp, []timeseries.Point{
+		{Time: now.Add(-6 * time.Hour), Value: 3.33},
+		{Time: now.Add(-3 * time.Hour), Value: 5.67},
+	})
+	idx, err = IndicesFromHistory(ctx, store, now)
+	if err != nil || idx.SFI != 180 || idx.SSN != 136.9 || idx.Kp != 5.67 {
+		t.Errorf("IndicesFromHistory() = %+v, %v, want SFI 180, SSN 136.9 and Kp 5.67", idx, err)
+	}
+}
@@ -0,0 +1,263 @@
+// Package raster provides a minimal pure-Go drawing surface (lines, arcs,
+// rectangles and bitmap text) for rendering static charts and annotating images.
+package raster
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Align controls horizontal text alignment relative to the anchor x coordinate
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// Point is a position on the canvas in pixels
+type Point struct {
+	X, Y float64
+}
+
+// Canvas is an RGBA drawing surface
+type Canvas struct {
+	img *image.RGBA
+}
+
+// NewCanvas creates a canvas filled with the background color
+func NewCanvas(width, height int, background color.Color) *Canvas {
+	c := &Canvas{img: image.NewRGBA(image.Rect(0, 0, width, height))}
+	c.FillRect(0, 0, width, height, background)
+	return c
+}
+
+// FromImage creates a canvas holding a copy of img
+func FromImage(img image.Image) *Canvas {
+	b := img.Bounds()
+	c := &Canvas{img: image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))}
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			c.img.Set(x, y, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return c
+}
+
+// Image returns the underlying image
+func (c *Canvas) Image() *image.RGBA {
+	return c.img
+}
+
+// Width returns the canvas width in pixels
+func (c *Canvas) Width() int {
+	return c.img.Bounds().Dx()
+}
+
+// Height returns the canvas height in pixels
+func (c *Canvas) Height() int {
+	return c.img.Bounds().Dy()
+}
+
+// blend draws a single pixel using source-over alpha compositing
+func (c *Canvas) blend(x, y int, col color.Color) {
+	if !(image.Point{x, y}.In(c.img.Rect)) {
+		return
+	}
+	sr, sg, sb, sa := col.RGBA()
+	if sa == 0xffff {
+		c.img.Set(x, y, col)
+		return
+	}
+	if sa == 0 {
+		return
+	}
+	i := c.img.PixOffset(x, y)
+	p := c.img.Pix[i : i+4 : i+4]
+	inv := 0xffff - sa
+	p[0] = uint8((sr + uint32(p[0])*0x101*inv/0xffff) >> 8)
+	p[1] = uint8((sg + uint32(p[1])*0x101*inv/0xffff) >> 8)
+	p[2] = uint8((sb + uint32(p[2])*0x101*inv/0xffff) >> 8)
+	p[3] = uint8((sa + uint32(p[3])*0x101*inv/0xffff) >> 8)
+}
+
+// FillRect fills the rectangle [x0,x1) x [y0,y1)
+func (c *Canvas) FillRect(x0, y0, x1, y1 int, col color.Color) {
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			c.blend(x, y, col)
+		}
+	}
+}
+
+// FillCircle fills a disc centered at (cx, cy)
+func (c *Canvas) FillCircle(cx, cy, r float64, col color.Color) {
+	r2 := r * r
+	for y := int(math.Floor(cy - r)); y <= int(math.Ceil(cy+r)); y++ {
+		for x := int(math.Floor(cx - r)); x <= int(math.Ceil(cx+r)); x++ {
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			if dx*dx+dy*dy <= r2 {
+				c.blend(x, y, col)
+			}
+		}
+	}
+}
+
+// Line draws a straight line of the given width
+func (c *Canvas) Line(x0, y0, x1, y1, width float64, col color.Color) {
+	if width < 1 {
+		width = 1
+	}
+	// Paint every pixel whose center lies within width/2 of the segment
+	half := width / 2
+	minX := int(math.Floor(math.Min(x0, x1) - half))
+	maxX := int(math.Ceil(math.Max(x0, x1) + half))
+	minY := int(math.Floor(math.Min(y0, y1) - half))
+	maxY := int(math.Ceil(math.Max(y0, y1) + half))
+	dx, dy := x1-x0, y1-y0
+	length2 := dx*dx + dy*dy
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			t := 0.0
+			if length2 > 0 {
+				t = math.Max(0, math.Min(1, ((px-x0)*dx+(py-y0)*dy)/length2))
+			}
+			ex, ey := px-(x0+t*dx), py-(y0+t*dy)
+			if ex*ex+ey*ey <= half*half+0.25 {
+				c.blend(x, y, col)
+			}
+		}
+	}
+}
+
+// Polyline draws connected line segments
+func (c *Canvas) Polyline(points []Point, width float64, col color.Color) {
+	for i := 1; i < len(points); i++ {
+		c.Line(points[i-1].X, points[i-1].Y, points[i].X, points[i].Y, width, col)
+	}
+}
+
+// FillPolygon fills a simple polygon using the even-odd rule
+func (c *Canvas) FillPolygon(points []Point, col color.Color) {
+	if len(points) < 3 {
+		return
+	}
+	minY, maxY := points[0].Y, points[0].Y
+	for _, p := range points {
+		minY = math.Min(minY, p.Y)
+		maxY = math.Max(maxY, p.Y)
+	}
+	for y := int(math.Floor(minY)); y <= int(math.Ceil(maxY)); y++ {
+		py := float64(y) + 0.5
+		var xs []float64
+		for i := range points {
+			a, b := points[i], points[(i+1)%len(points)]
+			if (a.Y <= py && b.Y > py) || (b.Y <= py && a.Y > py) {
+				xs = append(xs, a.X+(py-a.Y)*(b.X-a.X)/(b.Y-a.Y))
+			}
+		}
+		sortFloats(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			for x := int(math.Ceil(xs[i] - 0.5)); float64(x)+0.5 <= xs[i+1]; x++ {
+				c.blend(x, y, col)
+			}
+		}
+	}
+}
+
+// FillArc fills an annulus sector between radii rInner and rOuter from startDeg to endDeg.
+// Angles are in degrees, counter-clockwise from the positive x axis (screen up is +90°).
+func (c *Canvas) FillArc(cx, cy, rInner, rOuter, startDeg, endDeg float64, col color.Color) {
+	if endDeg < startDeg {
+		startDeg, endDeg = endDeg, startDeg
+	}
+	for y := int(math.Floor(cy - rOuter)); y <= int(math.Ceil(cy+rOuter)); y++ {
+		for x := int(math.Floor(cx - rOuter)); x <= int(math.Ceil(cx+rOuter)); x++ {
+			dx, dy := float64(x)+0.5-cx, cy-(float64(y)+0.5)
+			dist := math.Hypot(dx, dy)
+			if dist < rInner || dist > rOuter {
+				continue
+			}
+			angle := math.Atan2(dy, dx) * 180 / math.Pi
+			// Normalize the angle into the [startDeg, startDeg+360) window
+			for angle < startDeg {
+				angle += 360
+			}
+			for angle >= startDeg+360 {
+				angle -= 360
+			}
+			if angle <= endDeg {
+				c.blend(x, y, col)
+			}
+		}
+	}
+}
+
+// Text draws s with its top edge at y. scale multiplies the 5x7 glyph size.
+func (c *Canvas) Text(x, y int, s string, scale int, col color.Color, align Align) {
+	if scale < 1 {
+		scale = 1
+	}
+	switch align {
+	case AlignCenter:
+		x -= TextWidth(s, scale) / 2
+	case AlignRight:
+		x -= TextWidth(s, scale)
+	}
+	for _, r := range s {
+		g := glyphFor(r)
+		for row := 0; row < glyphHeight; row++ {
+			for column := 0; column < glyphWidth; column++ {
+				if g[row][column] == '#' {
+					c.FillRect(x+column*scale, y+row*scale, x+(column+1)*scale, y+(row+1)*scale, col)
+				}
+			}
+		}
+		x += (glyphWidth + glyphSpacing) * scale
+	}
+}
+
+// EncodePNG encodes the canvas as PNG
+func (c *Canvas) EncodePNG() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseHexColor parses "#rrggbb" or "#rgb" into an opaque color; invalid input yields black
+func ParseHexColor(s string) color.RGBA {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || len(s) != 6 {
+		return color.RGBA{A: 0xff}
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
+
+// sortFloats sorts a small slice in place (insertion sort keeps the package dependency-free)
+func sortFloats(xs []float64) {
+	for i := 1; i < len(xs); i++ {
+		for j := i; j > 0 && xs[j] < xs[j-1]; j-- {
+			xs[j], xs[j-1] = xs[j-1], xs[j]
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package reports
+
+import (
+	"time"
+
+	"radiocast/internal/models"
+)
+
+// DXClusterSource provides live DX cluster spot statistics, see dxcluster.Client
+type DXClusterSource interface {
+	Summary(now time.Time) *models.DXClusterSummary
+}
+
+// SetDXCluster sets the DX cluster whose spot statistics are added to each report
+func (rg *ReportGenerator) SetDXCluster(source DXClusterSource) {
+	rg.dxCluster = source
+}
+
+// addDXCluster adds what the DX cluster is spotting right now. Mock data keeps its own summary
+// when no cluster is configured, and reports leave the section out when no spots came in.
+func (rg *ReportGenerator) addDXCluster(data *models.PropagationData) {
+	if rg.dxCluster == nil {
+		return
+	}
+	data.DXCluster = nil
+	if summary := rg.dxCluster.Summary(time.Now().UTC()); summary.Spots > 0 {
+		data.DXCluster = summary
+	}
+}
@@ -1,14 +1,19 @@
 package reports
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
+	"html/template"
+	"image"
+	"io"
+	"sort"
 	"strings"
 	"time"
 
+	"radiocast/internal/charts"
+	"radiocast/internal/config"
 	"radiocast/internal/imagery"
 	"radiocast/internal/logger"
 	"radiocast/internal/models"
@@ -20,6 +25,7 @@ type FileGenerator struct {
 	reportGenerator   *ReportGenerator
 	mockService       MockService
 	sunImageGenerator *imagery.SunImageGenerator
+	storage           storage.StorageClient // large media is streamed here while generating
 }
 
 // MockService interface for dependency injection
@@ -32,31 +38,45 @@ type GeneratedFiles struct {
 	HTMLContent    string
 	ChartFiles     []string
 	JSONFiles      map[string][]byte
-	AssetFiles     map[string][]byte // CSS, GIFs, images
+	AssetFiles     map[string][]byte // CSS, charts, images
+	StoredFiles    []ManifestFile    // large media (animations, offline report, PDF) already streamed to storage
 	FolderPath     string            // GCS folder path for consistency
+	SunImages      []imagery.SunImage // imagery products whose animations were generated
+	pending        *PendingReport    // folder the media is streamed into
 }
 
-// NewFileGenerator creates a new file generator
-func NewFileGenerator(reportGenerator *ReportGenerator, mockService MockService) *FileGenerator {
+// SunImageryStatsFile records the Helioviewer fetch statistics of each imagery product
+const SunImageryStatsFile = "sun_imagery_stats.json"
+
+// OfflineReportFile is the self-contained copy of the report stored next to index.html
+const OfflineReportFile = "report.html"
+
+// NewFileGenerator creates a new file generator. Large media is streamed to storageClient into the
+// report folder as soon as it is produced; with a nil client it is kept in AssetFiles instead.
+func NewFileGenerator(reportGenerator *ReportGenerator, mockService MockService, storageClient storage.StorageClient) *FileGenerator {
 	return &FileGenerator{
 		reportGenerator:   reportGenerator,
 		mockService:       mockService,
 		sunImageGenerator: imagery.NewSunImageGenerator(),
+		storage:           storageClient,
 	}
 }
 
-// GenerateAllFiles creates all report files (HTML, charts, JSON, assets)
-func (fg *FileGenerator) GenerateAllFiles(ctx context.Context, data *models.PropagationData, sourceData *models.SourceData, markdown string, systemPrompt string, userPrompt string, mockupMode bool) (*GeneratedFiles, error) {
+// GenerateAllFiles creates all report files (HTML, charts, JSON, assets) for the report folder
+// pending was begun for (StorageOrchestrator.BeginReport)
+func (fg *FileGenerator) GenerateAllFiles(ctx context.Context, pending *PendingReport, data *models.PropagationData, sourceData *models.SourceData, markdown string, systemPrompt string, userPrompt string, mockupMode bool) (*GeneratedFiles, error) {
+	if pending == nil {
+		return nil, fmt.Errorf("report folder was not begun")
+	}
 	timestamp := data.Timestamp
 	
 	files := &GeneratedFiles{
 		JSONFiles:  make(map[string][]byte),
 		AssetFiles: make(map[string][]byte),
+		FolderPath: pending.FolderPath(),
+		pending:    pending,
 	}
 	
-	// Generate unified folder path using storage utility
-	files.FolderPath = storage.GenerateReportFolderPath(timestamp)
-	
 	// 1. Generate JSON files for each data source
 	if err := fg.generateSourceJSONFiles(sourceData, files); err != nil {
 		logger.Warn("Failed to generate source JSON files", map[string]interface{}{"error": err.Error()})
@@ -72,17 +92,30 @@ func (fg *FileGenerator) GenerateAllFiles(ctx context.Context, data *models.Prop
 		logger.Warn("Failed to generate LLM files", map[string]interface{}{"error": err.Error()})
 	}
 	
-	// 4. Generate Sun GIF (last 72h)
-	gifRelName := "sun_72h.gif"
-	if err := fg.generateSunGIF(ctx, mockupMode, timestamp, gifRelName, files); err != nil {
-		logger.Warn("Failed to generate Sun GIF", map[string]interface{}{"error": err.Error()})
+	// 4. Generate Sun animations (last 72h) for every configured imagery product
+	fg.generateSunImages(ctx, mockupMode, timestamp, files)
+
+	// 5. Render static chart images (SVG/PNG) for clients without JavaScript
+	if err := fg.generateStaticCharts(data, sourceData, files); err != nil {
+		logger.Warn("Failed to render static charts", map[string]interface{}{"error": err.Error()})
 	}
 
-	// 5. Generate HTML report (CSS generation removed - all pages use /static/styles.css)
-	if err := fg.generateHTML(markdown, data, sourceData, gifRelName, files); err != nil {
+	// 6. Generate HTML report (CSS generation removed - all pages use /static/styles.css)
+	if err := fg.generateHTML(markdown, data, sourceData, files); err != nil {
 		return nil, fmt.Errorf("failed to generate HTML: %w", err)
 	}
-	
+
+	// 7. Generate single-file offline copy of the report
+	if err := fg.generateOfflineHTML(ctx, markdown, data, sourceData, files); err != nil {
+		logger.Warn("Failed to generate offline report", map[string]interface{}{"error": err.Error()})
+	}
+
+	// 8. Generate printable PDF (optional)
+	if fg.reportGenerator.pdfBuilder != nil {
+		if err := fg.generatePDF(ctx, markdown, data, sourceData, files); err != nil {
+			logger.Warn("Failed to generate PDF report", map[string]interface{}{"error": err.Error()})
+		}
+	}
 	
 	return files, nil
 }
@@ -141,68 +174,270 @@ func (fg *FileGenerator) generateLLMFiles(markdown string, systemPrompt string,
 	return nil
 }
 
-// generateSunGIF generates Sun GIF using Helioviewer or mock data
-func (fg *FileGenerator) generateSunGIF(ctx context.Context, mockupMode bool, timestamp time.Time, gifRelName string, files *GeneratedFiles) error {
+// generateSunImages generates the Sun animations of every imagery product using Helioviewer or mock data:
+// a GIF plus the configured extra formats. A failing product is skipped so the others still appear in the report.
+func (fg *FileGenerator) generateSunImages(ctx context.Context, mockupMode bool, timestamp time.Time, files *GeneratedFiles) {
+	var mockGifData []byte
 	if mockupMode && fg.mockService != nil {
-		// Use mock Sun GIF
+		// Use mock Sun GIF for every product
 		logger.Info("Generating mock Sun GIF data...")
-		mockGifData, err := fg.mockService.LoadMockSunGif()
+		data, err := fg.mockService.LoadMockSunGif()
 		if err != nil {
-			return fmt.Errorf("failed to load mock Sun GIF: %w", err)
+			logger.Warn("Failed to load mock Sun GIF", map[string]interface{}{"error": err.Error()})
+			return
 		}
-		files.AssetFiles[gifRelName] = mockGifData
-		logger.Debug("Generated mock Sun GIF", map[string]interface{}{"bytes": len(mockGifData)})
-	} else {
-		// Generate Sun GIF using Helioviewer and ffmpeg
-		// Create temporary file for generation
-		tempDir := os.TempDir()
-		gifPath := filepath.Join(tempDir, fmt.Sprintf("sun_gif_%d.gif", time.Now().Unix()))
-		
-		if err := imagery.GenerateSunGIF(ctx, tempDir, timestamp, gifPath); err != nil {
-			return fmt.Errorf("failed to generate Sun GIF: %w", err)
+		mockGifData = data
+	}
+
+	var stats []*imagery.FetchStats
+	for _, product := range fg.reportGenerator.sunProducts {
+		animations := map[imagery.Format][]byte{imagery.FormatGIF: mockGifData}
+		if mockGifData == nil {
+			// Generate Sun animations from Helioviewer images
+			data, productStats, err := fg.reportGenerator.frameFetcher.GenerateProductAnimations(ctx, product, timestamp)
+			if productStats != nil {
+				stats = append(stats, productStats)
+			}
+			if err != nil {
+				logger.Warn("Failed to generate Sun GIF", map[string]interface{}{"product": product.ID, "error": err.Error()})
+				continue
+			}
+			animations = data
 		}
-		
-		// Read the generated GIF
-		gifData, err := os.ReadFile(gifPath)
-		if err != nil {
-			return fmt.Errorf("failed to read generated GIF: %w", err)
+		image := imagery.SunImage{Product: product}
+		stored := true
+		for format, data := range animations {
+			if err := fg.storeMedia(ctx, files, product.File(format), writeBytes(data)); err != nil {
+				logger.Warn("Failed to store Sun animation", map[string]interface{}{"product": product.ID, "format": string(format), "error": err.Error()})
+				stored = stored && format != imagery.FormatGIF
+				continue
+			}
+			if format != imagery.FormatGIF {
+				image.Formats = append(image.Formats, format)
+			}
+		}
+		if !stored {
+			continue // the GIF is the fallback of every other format
 		}
-		
-		files.AssetFiles[gifRelName] = gifData
-		logger.Debug("Generated Sun GIF", map[string]interface{}{"bytes": len(gifData)})
-		
-		// Clean up temporary file
-		os.Remove(gifPath)
+		sort.Slice(image.Formats, func(i, j int) bool { return image.Formats[i] < image.Formats[j] })
+		files.SunImages = append(files.SunImages, image)
+		logger.Debug("Generated Sun animations", map[string]interface{}{"product": product.ID, "bytes": len(animations[imagery.FormatGIF]), "formats": len(animations)})
+	}
+
+	if mockGifData == nil {
+		if pruned, err := fg.reportGenerator.frameFetcher.PruneCache(ctx, timestamp); err != nil {
+			logger.Warn("Failed to prune Helioviewer frame cache", map[string]interface{}{"error": err.Error()})
+		} else if pruned > 0 {
+			logger.Debug("Pruned Helioviewer frame cache", map[string]interface{}{"frames": pruned})
+		}
+	}
+
+	if len(stats) > 0 {
+		data, _ := json.MarshalIndent(stats, "", "  ")
+		files.JSONFiles[SunImageryStatsFile] = data
 	}
-	
-	return nil
 }
 
+// generateStaticCharts renders every chart as SVG and PNG stored alongside index.html
+func (fg *FileGenerator) generateStaticCharts(data *models.PropagationData, sourceData *models.SourceData, files *GeneratedFiles) error {
+	snippets, err := fg.reportGenerator.chartGen.GenerateEChartsSnippetsWithSources(data, sourceData)
+	if err != nil {
+		return fmt.Errorf("failed to generate chart snippets: %w", err)
+	}
+	images, err := charts.RenderStaticFiles(snippets)
+	if err != nil {
+		return err
+	}
+	for name, image := range images {
+		files.AssetFiles[name] = image
+		files.ChartFiles = append(files.ChartFiles, name)
+	}
+	sort.Strings(files.ChartFiles)
+	logger.Debug("Rendered static charts", map[string]interface{}{"files": len(images)})
+	return nil
+}
 
 // generateHTML generates HTML report
-func (fg *FileGenerator) generateHTML(markdown string, data *models.PropagationData, sourceData *models.SourceData, gifRelName string, files *GeneratedFiles) error {
+func (fg *FileGenerator) generateHTML(markdown string, data *models.PropagationData, sourceData *models.SourceData, files *GeneratedFiles) error {
 	// Generate HTML with folder path for GCS compatibility
 	html, err := fg.reportGenerator.GenerateHTML(markdown, data, sourceData, files.FolderPath)
 	if err != nil {
 		return fmt.Errorf("failed to generate HTML: %w", err)
 	}
 	
-	// Inject Sun GIF section into HTML
-	files.HTMLContent = fg.injectSunGIFIntoHTML(html, gifRelName, files.FolderPath)
+	// Inject Sun imagery sections into HTML
+	files.HTMLContent = fg.injectSunImagesIntoHTML(html, files)
 	logger.Debug("Generated HTML report", map[string]interface{}{"bytes": len(files.HTMLContent)})
 	return nil
 }
 
+// generateOfflineHTML builds report.html with stylesheets, ECharts and all images embedded
+func (fg *FileGenerator) generateOfflineHTML(ctx context.Context, markdown string, data *models.PropagationData, sourceData *models.SourceData, files *GeneratedFiles) error {
+	loader := fg.reportGenerator.htmlBuilder.templateLoader
+	assets := &OfflineAssets{Files: make(map[string][]byte)}
 
-// prepareSunGIFHTML generates the HTML section for the Sun GIF with the correct path
-func (fg *FileGenerator) prepareSunGIFHTML(gifRelName, folderPath string) string {
-	sunGifHTML := fg.sunImageGenerator.GenerateSunImagesHTML(gifRelName, folderPath)
-	return string(sunGifHTML)
+	for _, name := range []string{"common.css", "report.css"} {
+		css, err := loader.LoadStaticAsset(name)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", name, err)
+		}
+		assets.CSS = append(assets.CSS, css...)
+		assets.CSS = append(assets.CSS, '\n')
+	}
+	if background, err := loader.LoadStaticAsset("background.png"); err == nil {
+		assets.Files["/static/background.png"] = background
+	}
+	echarts, err := loader.LoadStaticAsset(charts.EChartsStaticFile)
+	if err != nil {
+		logger.Warn("Vendored ECharts bundle not found, offline report will load it from the CDN", map[string]interface{}{"error": err.Error()})
+	}
+	assets.ECharts = echarts
+	for name, content := range files.AssetFiles {
+		assets.Files[reportAssetURL(files.FolderPath, name)] = content
+	}
+	for _, image := range files.SunImages {
+		content, err := fg.readMedia(ctx, files, image.FileName)
+		if err != nil {
+			logger.Warn("Failed to embed Sun animation in offline report", map[string]interface{}{"product": image.ID, "error": err.Error()})
+			continue
+		}
+		assets.Files[reportAssetURL(files.FolderPath, image.FileName)] = content
+	}
+
+	html, err := fg.reportGenerator.GenerateOfflineHTML(markdown, data, sourceData, files.FolderPath, fg.prepareSunImagesHTML(files, true), assets)
+	if err != nil {
+		return err
+	}
+	return fg.storeMedia(ctx, files, OfflineReportFile, func(w io.Writer) error {
+		_, err := io.WriteString(w, html)
+		return err
+	})
 }
 
-// injectSunGIFIntoHTML replaces the {{.SunGif}} placeholder with the actual Sun GIF HTML
-func (fg *FileGenerator) injectSunGIFIntoHTML(html, gifRelName, folderPath string) string {
-	sunGifHTML := fg.prepareSunGIFHTML(gifRelName, folderPath)
-	const placeholder = "{{.SunGif}}"
-	return strings.Replace(html, placeholder, sunGifHTML, 1)
+// generatePDF renders report.pdf with vector charts and the latest Sun images as still frames
+func (fg *FileGenerator) generatePDF(ctx context.Context, markdown string, data *models.PropagationData, sourceData *models.SourceData, files *GeneratedFiles) error {
+	snippets, err := fg.reportGenerator.chartGen.GenerateEChartsSnippetsWithSources(data, sourceData)
+	if err != nil {
+		return fmt.Errorf("failed to generate chart snippets: %w", err)
+	}
+	report := &PDFReport{
+		Markdown:    markdown,
+		Data:        data,
+		Charts:      snippets,
+		Version:     config.GetVersion(),
+		GeneratedAt: time.Now(),
+	}
+	for _, product := range files.SunImages {
+		frame, err := fg.latestSunFrame(ctx, files, product.FileName)
+		if err != nil {
+			logger.Warn("Failed to extract Sun image for PDF", map[string]interface{}{"product": product.ID, "error": err.Error()})
+			continue
+		}
+		report.SunImages = append(report.SunImages, SunStill{
+			Placeholder: product.Placeholder,
+			Caption:     "Latest " + product.Name + " image • " + product.Credit + " and the Helioviewer project",
+			Image:       frame,
+		})
+	}
+	return fg.storeMedia(ctx, files, PDFReportFile, func(w io.Writer) error {
+		return fg.reportGenerator.pdfBuilder.Write(w, report)
+	})
+}
+
+// latestSunFrame decodes the most recent frame of a stored Sun animation
+func (fg *FileGenerator) latestSunFrame(ctx context.Context, files *GeneratedFiles, name string) (image.Image, error) {
+	reader, err := fg.openMedia(ctx, files, name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return imagery.LatestFrame(reader)
+}
+
+// storeMedia streams a large media file straight into the report folder so it is not held in
+// memory until StoreAllFiles; without a storage client it is buffered in AssetFiles instead.
+// Media only goes into the folder of files.pending, which BeginReport has marked pending, so a
+// generation that fails midway leaves an unpublished folder that CleanupAbandonedReports removes.
+func (fg *FileGenerator) storeMedia(ctx context.Context, files *GeneratedFiles, name string, write func(w io.Writer) error) error {
+	checksum := newChecksumWriter()
+	if fg.storage == nil {
+		var buf bytes.Buffer
+		if err := write(io.MultiWriter(&buf, checksum)); err != nil {
+			return err
+		}
+		files.AssetFiles[name] = buf.Bytes()
+	} else {
+		if files.pending == nil {
+			return fmt.Errorf("report folder %s was not begun, cannot stream %s into it", files.FolderPath, name)
+		}
+		err := storage.StreamFile(ctx, fg.storage, reportFilePath(files.pending.FolderPath(), name), func(w io.Writer) error {
+			return write(io.MultiWriter(w, checksum))
+		})
+		if err != nil {
+			return err
+		}
+		files.StoredFiles = append(files.StoredFiles, checksum.file(name))
+	}
+	logger.Debug("Generated media file", map[string]interface{}{"filename": name, "bytes": checksum.size})
+	return nil
+}
+
+// openMedia opens a media file written by storeMedia
+func (fg *FileGenerator) openMedia(ctx context.Context, files *GeneratedFiles, name string) (io.ReadCloser, error) {
+	if data, ok := files.AssetFiles[name]; ok {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	if fg.storage == nil {
+		return nil, fmt.Errorf("media file %s was not generated", name)
+	}
+	return fg.storage.OpenReader(ctx, reportFilePath(files.FolderPath, name))
+}
+
+// readMedia reads a whole media file written by storeMedia
+func (fg *FileGenerator) readMedia(ctx context.Context, files *GeneratedFiles, name string) ([]byte, error) {
+	reader, err := fg.openMedia(ctx, files, name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// writeBytes returns a storeMedia producer for content that is already in memory
+func writeBytes(data []byte) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}
+}
+
+// prepareSunImagesHTML generates the HTML of every Sun imagery placeholder with the correct paths;
+// placeholders of products without an animation map to empty HTML. The offline report embeds only
+// the GIFs, since data URIs of every format would multiply its size.
+func (fg *FileGenerator) prepareSunImagesHTML(files *GeneratedFiles, gifOnly bool) map[string]template.HTML {
+	images := files.SunImages
+	if gifOnly {
+		images = make([]imagery.SunImage, len(files.SunImages))
+		for i, image := range files.SunImages {
+			images[i] = imagery.SunImage{Product: image.Product}
+		}
+	}
+	snippets := map[string]template.HTML{
+		imagery.SunViewerPlaceholder: fg.sunImageGenerator.GenerateSunImagesHTML(images, files.FolderPath),
+	}
+	for _, product := range imagery.Products {
+		snippets[product.Placeholder] = ""
+	}
+	for _, image := range images {
+		snippets[image.Placeholder] = fg.sunImageGenerator.GenerateProductHTML(image, files.FolderPath)
+	}
+	return snippets
+}
+
+// injectSunImagesIntoHTML replaces the Sun imagery placeholders (e.g. {{.SunGif}}) with the actual HTML
+func (fg *FileGenerator) injectSunImagesIntoHTML(html string, files *GeneratedFiles) string {
+	for name, snippet := range fg.prepareSunImagesHTML(files, false) {
+		html = strings.ReplaceAll(html, "{{."+name+"}}", string(snippet))
+	}
+	return html
 }
@@ -0,0 +1,50 @@
+package reports
+
+import (
+	"time"
+
+	"radiocast/internal/geo"
+	"radiocast/internal/models"
+	"radiocast/internal/sun"
+)
+
+// grayLineHorizon is how far ahead of the report the gray-line windows are listed
+const grayLineHorizon = 24 * time.Hour
+
+// SetQTHs sets the QTHs whose gray-line windows are added to each report
+func (rg *ReportGenerator) SetQTHs(qths []geo.Location) {
+	rg.qths = qths
+}
+
+// addGrayLine fills the gray-line windows of the configured QTHs from the report time
+func (rg *ReportGenerator) addGrayLine(data *models.PropagationData) {
+	from := data.Timestamp
+	if from.IsZero() {
+		from = time.Now()
+	}
+	data.GrayLine = nil
+	for _, qth := range rg.qths {
+		data.GrayLine = append(data.GrayLine, GrayLineAt(qth, from))
+	}
+}
+
+// GrayLineAt returns the next sunrise and sunset of a QTH and its gray-line windows over the
+// following 24 hours
+func GrayLineAt(qth geo.Location, from time.Time) models.GrayLine {
+	g := models.GrayLine{Grid: qth.Grid, Latitude: qth.Latitude, Longitude: qth.Longitude, Windows: []models.GrayLineWindow{}}
+	for _, w := range sun.GrayLineBetween(qth.Latitude, qth.Longitude, from, from.Add(grayLineHorizon)) {
+		g.Windows = append(g.Windows, models.GrayLineWindow{Start: w.Start, End: w.End, Event: w.Event})
+	}
+
+	day := from.UTC().Truncate(24 * time.Hour)
+	for i := 0; i < 3 && (g.Sunrise == nil || g.Sunset == nil); i++ {
+		times := sun.TimesOn(qth.Latitude, qth.Longitude, day.AddDate(0, 0, i))
+		if g.Sunrise == nil && times.Sunrise != nil && times.Sunrise.After(from) {
+			g.Sunrise = times.Sunrise
+		}
+		if g.Sunset == nil && times.Sunset != nil && times.Sunset.After(from) {
+			g.Sunset = times.Sunset
+		}
+	}
+	return g
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
+	"strings"
 	"time"
 
 	"radiocast/internal/charts"
@@ -21,6 +22,8 @@ import (
 type HTMLBuilder struct {
 	templateLoader *TemplateLoader
 	goldmark       goldmark.Markdown
+	staticCharts   bool // Replace interactive charts with pre-rendered images (no JavaScript)
+	pdfLink        bool // Link the report.pdf stored next to the report
 }
 
 // NewHTMLBuilder creates an HTML builder
@@ -59,6 +62,42 @@ type TemplateData struct {
 	PropagationTimelineChart   template.HTML
 	HistoricalSolarTrendChart  template.HTML
 	SpaceWeatherDashboardChart template.HTML
+	BandCalendarChart          template.HTML
+	GrayLineChart              template.HTML
+	IonosondeChart             template.HTML
+	ObservedBandsChart         template.HTML
+	DXClusterChart             template.HTML
+
+	// Page resources
+	EChartsURL    string       // vendored ECharts bundle; empty when charts are static images
+	EChartsCDNURL string       // loaded if the vendored bundle is missing
+	EChartsInline template.JS  // ECharts bundle inlined into offline reports
+	InlineCSS     template.CSS // stylesheets inlined into offline reports
+	OfflineURL    string       // link to the single-file copy of the report
+	PDFURL        string       // link to the printable copy of the report
+}
+
+// OfflineAssets are embedded into a self-contained report that opens without network access
+type OfflineAssets struct {
+	CSS     []byte            // replaces the /static/ stylesheet links
+	ECharts []byte            // vendored ECharts bundle; nil keeps the script reference
+	Files   map[string][]byte // URL -> content, rewritten to data: URIs (Sun GIF, images)
+}
+
+// ChartPlaceholders maps markdown chart placeholders (e.g. {{.KIndexChart}}) to chart snippet IDs
+var ChartPlaceholders = map[string]string{
+	"GaugePanelChart":            "chart-gauge-panel",
+	"KIndexGaugeChart":           "chart-k-index-gauge",
+	"KIndexChart":                "chart-k-index-trend",
+	"ForecastChart":              "chart-forecast",
+	"PropagationTimelineChart":   "chart-propagation-timeline",
+	"HistoricalSolarTrendChart":  "chart-historical-solar-trend",
+	"SpaceWeatherDashboardChart": "chart-space-weather-dashboard",
+	"BandCalendarChart":          "chart-band-calendar",
+	"GrayLineChart":              "chart-gray-line",
+	"IonosondeChart":             "chart-ionosonde-muf",
+	"ObservedBandsChart":         "chart-observed-bands",
+	"DXClusterChart":             "chart-dx-cluster",
 }
 
 // ConvertMarkdownToHTML converts markdown to HTML using goldmark
@@ -93,38 +132,65 @@ func (h *HTMLBuilder) GenerateChartData(data *models.PropagationData, sourceData
 		PropagationTimelineChart:   template.HTML(""),
 		HistoricalSolarTrendChart:  template.HTML(""),
 		SpaceWeatherDashboardChart: template.HTML(""),
+		BandCalendarChart:          template.HTML(""),
+		GrayLineChart:              template.HTML(""),
+		IonosondeChart:             template.HTML(""),
+		ObservedBandsChart:         template.HTML(""),
+		DXClusterChart:             template.HTML(""),
 	}
 
 	// Map snippets by ID to template data
 	for _, snippet := range snippets {
+		chartHTML := template.HTML(h.chartHTML(snippet, folderPath))
 		switch snippet.ID {
 		case "chart-gauge-panel":
-			chartData.GaugePanelChart = template.HTML(snippet.HTML)
+			chartData.GaugePanelChart = chartHTML
 		case "chart-k-index-gauge":
-			chartData.KIndexGaugeChart = template.HTML(snippet.HTML)
-		case "chart-geomagnetic-conditions":
-			chartData.KIndexChart = template.HTML(snippet.HTML)
+			chartData.KIndexGaugeChart = chartHTML
+		case "chart-k-index-trend":
+			chartData.KIndexChart = chartHTML
 		case "chart-historical-solar-trend":
-			chartData.HistoricalSolarTrendChart = template.HTML(snippet.HTML)
+			chartData.HistoricalSolarTrendChart = chartHTML
 		case "chart-space-weather-dashboard":
-			chartData.SpaceWeatherDashboardChart = template.HTML(snippet.HTML)
+			chartData.SpaceWeatherDashboardChart = chartHTML
 		case "chart-forecast":
-			chartData.ForecastChart = template.HTML(snippet.HTML)
+			chartData.ForecastChart = chartHTML
 		case "chart-propagation-timeline":
-			chartData.PropagationTimelineChart = template.HTML(snippet.HTML)
+			chartData.PropagationTimelineChart = chartHTML
+		case "chart-band-calendar":
+			chartData.BandCalendarChart = chartHTML
+		case "chart-gray-line":
+			chartData.GrayLineChart = chartHTML
+		case "chart-ionosonde-muf":
+			chartData.IonosondeChart = chartHTML
+		case "chart-observed-bands":
+			chartData.ObservedBandsChart = chartHTML
+		case "chart-dx-cluster":
+			chartData.DXClusterChart = chartHTML
 		}
 	}
 
 	return chartData, nil
 }
 
+// chartHTML returns the interactive snippet with a <noscript> image fallback,
+// or only the pre-rendered SVG image when static charts are enabled
+func (h *HTMLBuilder) chartHTML(snippet charts.ChartSnippet, folderPath string) string {
+	src := reportAssetURL(folderPath, charts.StaticFileName(snippet.ID, "svg"))
+	if h.staticCharts {
+		return charts.StaticHTML(snippet, src)
+	}
+	return charts.WithNoscriptFallback(snippet, src)
+}
+
 // BuildCompleteHTML creates a complete HTML document with template substitution
 func (h *HTMLBuilder) BuildCompleteHTML(
 	processedHTMLContent string,
 	data *models.PropagationData,
 	chartData *TemplateData,
 	sunGifHTML template.HTML,
-	folderPath string) (string, error) {
+	folderPath string,
+	offline *OfflineAssets) (string, error) {
 
 	logger.Debug("Building complete HTML...")
 
@@ -149,6 +215,27 @@ func (h *HTMLBuilder) BuildCompleteHTML(
 		PropagationTimelineChart:   chartData.PropagationTimelineChart,
 		HistoricalSolarTrendChart:  chartData.HistoricalSolarTrendChart,
 		SpaceWeatherDashboardChart: chartData.SpaceWeatherDashboardChart,
+		BandCalendarChart:          chartData.BandCalendarChart,
+		GrayLineChart:              chartData.GrayLineChart,
+		IonosondeChart:             chartData.IonosondeChart,
+		ObservedBandsChart:         chartData.ObservedBandsChart,
+		DXClusterChart:             chartData.DXClusterChart,
+	}
+	if !h.staticCharts {
+		templateData.EChartsURL = charts.EChartsStaticURL
+		templateData.EChartsCDNURL = charts.EChartsCDNURL
+	}
+	if offline == nil {
+		templateData.OfflineURL = reportAssetURL(folderPath, OfflineReportFile)
+		if h.pdfLink {
+			templateData.PDFURL = reportAssetURL(folderPath, PDFReportFile)
+		}
+	} else {
+		templateData.InlineCSS = template.CSS(offline.CSS)
+		if offline.ECharts != nil && !h.staticCharts {
+			// A literal </script> inside the bundle would terminate the inline script element
+			templateData.EChartsInline = template.JS(strings.ReplaceAll(string(offline.ECharts), "</script", `<\/script`))
+		}
 	}
 
 	// Execute template
@@ -156,6 +243,9 @@ func (h *HTMLBuilder) BuildCompleteHTML(
 	if err != nil {
 		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
+	if offline != nil {
+		finalHTML = inlineDataURIs(finalHTML, offline.Files)
+	}
 
 
 	logger.Debugf("Complete HTML built successfully (%d characters)", len(finalHTML))
@@ -201,7 +291,7 @@ func (h *HTMLBuilder) executeTemplate(data TemplateData) (string, error) {
 func (h *HTMLBuilder) ProcessMarkdownWithPlaceholders(
 	markdownContent string,
 	chartData *TemplateData,
-	sunGifHTML template.HTML) (string, error) {
+	sunImages map[string]template.HTML) (string, error) {
 
 	// First convert markdown to HTML
 	htmlContent, err := h.ConvertMarkdownToHTML(markdownContent)
@@ -209,31 +299,30 @@ func (h *HTMLBuilder) ProcessMarkdownWithPlaceholders(
 		return "", err
 	}
 
-	// Create a template from the HTML content and execute it with data
-	tmpl, err := template.New("content").Parse(htmlContent)
+	// Create a template from the HTML content and execute it with data.
+	// Placeholders of imagery products that are not configured render as nothing.
+	tmpl, err := template.New("content").Option("missingkey=zero").Parse(htmlContent)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse content template: %w", err)
 	}
 
 	// Prepare data for placeholder substitution
-	data := struct {
-		SunGif                     template.HTML
-		GaugePanelChart            template.HTML
-		KIndexGaugeChart           template.HTML
-		KIndexChart                template.HTML
-		ForecastChart              template.HTML
-		PropagationTimelineChart   template.HTML
-		HistoricalSolarTrendChart  template.HTML
-		SpaceWeatherDashboardChart template.HTML
-	}{
-		SunGif:                     sunGifHTML,
-		GaugePanelChart:            chartData.GaugePanelChart,
-		KIndexGaugeChart:           chartData.KIndexGaugeChart,
-		KIndexChart:                chartData.KIndexChart,
-		ForecastChart:              chartData.ForecastChart,
-		PropagationTimelineChart:   chartData.PropagationTimelineChart,
-		HistoricalSolarTrendChart:  chartData.HistoricalSolarTrendChart,
-		SpaceWeatherDashboardChart: chartData.SpaceWeatherDashboardChart,
+	data := map[string]template.HTML{
+		"GaugePanelChart":            chartData.GaugePanelChart,
+		"KIndexGaugeChart":           chartData.KIndexGaugeChart,
+		"KIndexChart":                chartData.KIndexChart,
+		"ForecastChart":              chartData.ForecastChart,
+		"PropagationTimelineChart":   chartData.PropagationTimelineChart,
+		"HistoricalSolarTrendChart":  chartData.HistoricalSolarTrendChart,
+		"SpaceWeatherDashboardChart": chartData.SpaceWeatherDashboardChart,
+		"BandCalendarChart":          chartData.BandCalendarChart,
+		"GrayLineChart":              chartData.GrayLineChart,
+		"IonosondeChart":             chartData.IonosondeChart,
+		"ObservedBandsChart":         chartData.ObservedBandsChart,
+		"DXClusterChart":             chartData.DXClusterChart,
+	}
+	for name, snippet := range sunImages {
+		data[name] = snippet
 	}
 
 	var buf bytes.Buffer
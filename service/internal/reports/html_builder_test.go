@@ -0,0 +1,26 @@
+package reports
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+)
+
+func TestGenerateChartData_KIndexTrendFillsKIndexChart(t *testing.T) {
+	data := &models.PropagationData{Timestamp: time.Now().UTC()}
+	data.GeomagData.KIndex = 3
+
+	chartData, err := NewHTMLBuilder().GenerateChartData(data, nil, "2026-01-01_12-00-00")
+	if err != nil {
+		t.Fatalf("GenerateChartData: %v", err)
+	}
+	kIndexChart := string(chartData.KIndexChart)
+	if kIndexChart == "" {
+		t.Fatal("KIndexChart is empty; the K-index trend snippet was not mapped")
+	}
+	if !strings.Contains(kIndexChart, "chart-k-index-trend") {
+		t.Errorf("KIndexChart does not hold the K-index trend snippet: %q", kIndexChart)
+	}
+}
@@ -0,0 +1,651 @@
+package reports
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"radiocast/internal/charts"
+	"radiocast/internal/imagery"
+	"radiocast/internal/models"
+	"radiocast/internal/pdf"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// PDFReportFile is the printable copy of the report stored next to index.html
+const PDFReportFile = "report.pdf"
+
+// Page layout in points
+const (
+	pdfMargin      = 54.0
+	pdfBodySize    = 10.0
+	pdfTableSize   = 9.0
+	pdfLineSpacing = 1.4
+	pdfCellPadding = 4.0
+)
+
+var (
+	pdfTextColor   = color.RGBA{0x34, 0x3a, 0x40, 0xff}
+	pdfMutedColor  = color.RGBA{0x6c, 0x75, 0x7d, 0xff}
+	pdfAccentColor = color.RGBA{0x1a, 0x3a, 0x5c, 0xff}
+	pdfRuleColor   = color.RGBA{0xde, 0xe2, 0xe6, 0xff}
+	pdfHeaderFill  = color.RGBA{0xe9, 0xf1, 0xf8, 0xff}
+)
+
+// statusDots are the colored circle emoji the LLM uses for band conditions; the standard
+// PDF fonts have no emoji, so they are drawn as filled circles
+var statusDots = map[rune]color.RGBA{
+	'🔴': {0xdc, 0x35, 0x45, 0xff},
+	'🟠': {0xfd, 0x7e, 0x14, 0xff},
+	'🟡': {0xff, 0xc1, 0x07, 0xff},
+	'🟢': {0x28, 0xa7, 0x45, 0xff},
+	'🔵': {0x00, 0x7b, 0xff, 0xff},
+	'🟣': {0x6f, 0x42, 0xc1, 0xff},
+	'⚪': {0xad, 0xb5, 0xbd, 0xff},
+	'⚫': {0x34, 0x3a, 0x40, 0xff},
+}
+
+var pdfPlaceholderPattern = regexp.MustCompile(`^\{\{\s*\.([A-Za-z0-9]+)\s*\}\}$`)
+
+// PDFReport holds the content of a printable report
+type PDFReport struct {
+	Markdown    string
+	Data        *models.PropagationData
+	Charts      []charts.ChartSnippet
+	SunImages   []SunStill // still frames of the Sun animations; the first one is shown for {{.SunGif}}
+	Version     string
+	GeneratedAt time.Time
+}
+
+// SunStill is the latest frame of one Sun animation
+type SunStill struct {
+	Placeholder string // placeholder of the product, e.g. LascoC2Gif
+	Caption     string
+	Image       image.Image
+}
+
+// PDFBuilder renders reports as PDF documents
+type PDFBuilder struct {
+	goldmark goldmark.Markdown
+}
+
+// NewPDFBuilder creates a PDF builder
+func NewPDFBuilder() *PDFBuilder {
+	return &PDFBuilder{goldmark: goldmark.New(goldmark.WithExtensions(extension.GFM))}
+}
+
+// Build lays out the report and returns the PDF
+func (b *PDFBuilder) Build(report *PDFReport) ([]byte, error) {
+	doc, err := b.layout(report)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Bytes()
+}
+
+// Write lays out the report and streams the PDF to w
+func (b *PDFBuilder) Write(w io.Writer, report *PDFReport) error {
+	doc, err := b.layout(report)
+	if err != nil {
+		return err
+	}
+	_, err = doc.WriteTo(w)
+	return err
+}
+
+// layout lays out the title page, the LLM markdown with charts and the data source attribution
+func (b *PDFBuilder) layout(report *PDFReport) (*pdf.Document, error) {
+	if report == nil || report.Data == nil {
+		return nil, fmt.Errorf("report data cannot be nil")
+	}
+	date := report.Data.Timestamp.UTC()
+
+	doc := pdf.New(pdf.Letter)
+	doc.Title = "Amateur Radio Propagation Report " + date.Format("2006-01-02")
+	doc.Author = "Radiocast"
+	doc.Creator = "Radiocast " + report.Version
+	doc.SetCreationDate(report.GeneratedAt)
+
+	l := &pdfLayout{
+		doc:    doc,
+		left:   pdfMargin,
+		width:  doc.PageSize().Width - 2*pdfMargin,
+		bottom: doc.PageSize().Height - pdfMargin,
+		source: []byte(report.Markdown),
+		charts: make(map[string]charts.ChartSnippet, len(report.Charts)),
+		suns:   make(map[string]pdfSun, len(report.SunImages)+1),
+	}
+	for _, sn := range report.Charts {
+		l.charts[sn.ID] = sn
+	}
+	for i, still := range report.SunImages {
+		img, err := doc.AddImage(still.Image)
+		if err != nil {
+			return nil, err
+		}
+		sun := pdfSun{image: img, caption: still.Caption}
+		l.suns[still.Placeholder] = sun
+		if i == 0 {
+			l.suns[imagery.SunViewerPlaceholder] = sun
+		}
+	}
+
+	l.titlePage(report)
+	l.newPage()
+	root := b.goldmark.Parser().Parse(text.NewReader(l.source))
+	l.blocks(root, 0)
+	l.attribution()
+	l.footers(date)
+
+	return doc, nil
+}
+
+// pdfLayout flows content down the pages
+type pdfLayout struct {
+	doc    *pdf.Document
+	page   *pdf.Page
+	y      float64
+	left   float64
+	width  float64
+	bottom float64
+	source []byte
+	charts map[string]charts.ChartSnippet
+	suns   map[string]pdfSun // by placeholder
+}
+
+type pdfSun struct {
+	image   *pdf.Image
+	caption string
+}
+
+func (l *pdfLayout) newPage() {
+	l.page = l.doc.AddPage()
+	l.y = pdfMargin
+}
+
+// ensure starts a new page unless h points fit below the cursor
+func (l *pdfLayout) ensure(h float64) {
+	if l.y+h > l.bottom && l.y > pdfMargin {
+		l.newPage()
+	}
+}
+
+func (l *pdfLayout) centered(y float64, s string, font pdf.Font, size float64, c color.Color) {
+	x := l.left + (l.width-pdf.TextWidth(font, size, s))/2
+	l.page.Text(x, y, s, font, size, c)
+}
+
+func (l *pdfLayout) titlePage(report *PDFReport) {
+	l.newPage()
+	date := report.Data.Timestamp.UTC()
+	l.centered(200, "Amateur Radio Propagation Report", pdf.HelveticaBold, 26, pdfAccentColor)
+	l.centered(235, date.Format("Monday, January 2, 2006"), pdf.Helvetica, 16, pdfTextColor)
+	l.centered(262, "Generated "+report.GeneratedAt.UTC().Format("2006-01-02 15:04 UTC"), pdf.Helvetica, 11, pdfMutedColor)
+	if report.Version != "" {
+		l.centered(280, report.Version, pdf.Helvetica, 11, pdfMutedColor)
+	}
+
+	solar, geomag := report.Data.SolarData, report.Data.GeomagData
+	rows := [][2]string{
+		{"Solar Flux Index", strconv.FormatFloat(solar.SolarFluxIndex, 'f', 0, 64)},
+		{"Sunspot Number", strconv.Itoa(solar.SunspotNumber)},
+		{"Planetary K-index", strconv.FormatFloat(geomag.KIndex, 'f', 1, 64)},
+		{"A-index", strconv.FormatFloat(geomag.AIndex, 'f', 0, 64)},
+		{"X-ray Flux", solar.XRayFlux},
+		{"Solar Wind", strconv.FormatFloat(solar.SolarWindSpeed, 'f', 0, 64) + " km/s"},
+	}
+	boxW, rowH := 260.0, 22.0
+	x, y := l.left+(l.width-boxW)/2, 330.0
+	l.page.FillRect(x, y, boxW, rowH, pdfHeaderFill)
+	l.page.Text(x+pdfCellPadding*2, y+15, "Conditions at a glance", pdf.HelveticaBold, 11, pdfAccentColor)
+	for i, row := range rows {
+		ry := y + rowH*float64(i+1)
+		l.page.Line(x, ry, x+boxW, ry, 0.5, pdfRuleColor)
+		l.page.Text(x+pdfCellPadding*2, ry+15, row[0], pdf.Helvetica, 10, pdfTextColor)
+		value := row[1]
+		l.page.Text(x+boxW-pdfCellPadding*2-pdf.TextWidth(pdf.HelveticaBold, 10, value), ry+15, value, pdf.HelveticaBold, 10, pdfTextColor)
+	}
+	l.page.StrokeRect(x, y, boxW, rowH*float64(len(rows)+1), 0.5, pdfRuleColor)
+
+	l.centered(l.bottom-20, "Data: NOAA SWPC • N0NBH • SIDC • Imagery: SDO/NASA via Helioviewer", pdf.Helvetica, 9, pdfMutedColor)
+}
+
+// blocks lays out block-level markdown nodes
+func (l *pdfLayout) blocks(parent ast.Node, indent float64) {
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		switch node := n.(type) {
+		case *ast.Heading:
+			l.heading(node)
+		case *ast.Paragraph:
+			l.paragraph(node, indent, pdfBodySize*0.7)
+		case *ast.TextBlock:
+			l.paragraph(node, indent, 2)
+		case *ast.List:
+			l.list(node, indent)
+		case *east.Table:
+			l.table(node, indent)
+		case *ast.ThematicBreak:
+			l.ensure(12)
+			l.page.Line(l.left+indent, l.y+5, l.left+l.width, l.y+5, 0.5, pdfRuleColor)
+			l.y += 12
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			l.code(node, indent)
+		case *ast.HTMLBlock:
+			// Raw HTML has no printable equivalent
+		default:
+			l.blocks(node, indent)
+		}
+	}
+}
+
+func (l *pdfLayout) heading(h *ast.Heading) {
+	sizes := map[int]float64{1: 18, 2: 14, 3: 12}
+	size, ok := sizes[h.Level]
+	if !ok {
+		size = 11
+	}
+	words := l.collect(h, pdf.HelveticaBold)
+	lines := wrapWords(words, size, l.width)
+	// Keep the heading together with the start of the following content
+	l.ensure(float64(len(lines))*size*pdfLineSpacing + 60)
+	l.y += size * 0.6
+	for _, line := range lines {
+		l.drawLine(line, l.left, l.y+size, size, pdfAccentColor)
+		l.y += size * pdfLineSpacing
+	}
+	if h.Level <= 2 {
+		l.page.Line(l.left, l.y, l.left+l.width, l.y, 0.75, pdfRuleColor)
+	}
+	l.y += size * 0.5
+}
+
+func (l *pdfLayout) paragraph(p ast.Node, indent, spaceAfter float64) {
+	words := l.collect(p, pdf.Helvetica)
+	if m := pdfPlaceholderPattern.FindStringSubmatch(plainText(words)); m != nil {
+		l.placeholder(m[1])
+		return
+	}
+	l.text(words, indent, pdfBodySize)
+	l.y += spaceAfter
+}
+
+func (l *pdfLayout) text(words []pdfWord, indent, size float64) {
+	for _, line := range wrapWords(words, size, l.width-indent) {
+		l.ensure(size * pdfLineSpacing)
+		l.drawLine(line, l.left+indent, l.y+size, size, pdfTextColor)
+		l.y += size * pdfLineSpacing
+	}
+}
+
+// placeholder draws the chart or Sun image a {{.Name}} placeholder refers to
+func (l *pdfLayout) placeholder(name string) {
+	if imagery.IsImageryPlaceholder(name) {
+		sun, ok := l.suns[name]
+		if !ok {
+			return
+		}
+		w := 280.0
+		h := w * float64(sun.image.Height) / float64(sun.image.Width)
+		l.ensure(h + 30)
+		l.page.DrawImage(sun.image, l.left+(l.width-w)/2, l.y, w, h)
+		l.y += h + 12
+		l.centered(l.y, sun.caption, pdf.HelveticaOblique, 8, pdfMutedColor)
+		l.y += 16
+		return
+	}
+	sn, ok := l.charts[ChartPlaceholders[name]]
+	if !ok || sn.Static == nil {
+		return
+	}
+	l.ensure(charts.PDFHeight(sn.Static, l.width) + 10)
+	h, err := charts.DrawPDF(l.page, sn.Static, l.left, l.y, l.width)
+	if err != nil {
+		return
+	}
+	l.y += h + 10
+}
+
+func (l *pdfLayout) list(list *ast.List, indent float64) {
+	number := list.Start
+	for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+		marker := "•"
+		if list.IsOrdered() {
+			marker = strconv.Itoa(number) + "."
+			number++
+		}
+		l.ensure(pdfBodySize * pdfLineSpacing)
+		l.page.Text(l.left+indent+2, l.y+pdfBodySize, marker, pdf.Helvetica, pdfBodySize, pdfTextColor)
+		l.blocks(item, indent+16)
+	}
+	l.y += pdfBodySize * 0.5
+}
+
+func (l *pdfLayout) code(n ast.Node, indent float64) {
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		line := strings.TrimRight(string(seg.Value(l.source)), "\n")
+		l.ensure(pdfTableSize * pdfLineSpacing)
+		l.page.Text(l.left+indent+8, l.y+pdfTableSize, line, pdf.Courier, pdfTableSize, pdfTextColor)
+		l.y += pdfTableSize * pdfLineSpacing
+	}
+	l.y += pdfBodySize * 0.7
+}
+
+// table draws a GFM table across the text width, repeating the header after page breaks
+func (l *pdfLayout) table(t *east.Table, indent float64) {
+	var rows [][][]pdfWord
+	for row := t.FirstChild(); row != nil; row = row.NextSibling() {
+		font := pdf.Helvetica
+		if _, header := row.(*east.TableHeader); header {
+			font = pdf.HelveticaBold
+		}
+		var cells [][]pdfWord
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, l.collect(cell, font))
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	// Columns get their natural single-line width, scaled to fill the available width
+	cols := len(rows[0])
+	widths := make([]float64, cols)
+	for _, cells := range rows {
+		for c := 0; c < cols && c < len(cells); c++ {
+			w := lineWidth(cells[c], pdfTableSize) + 2*pdfCellPadding
+			if w > widths[c] {
+				widths[c] = w
+			}
+		}
+	}
+	total := 0.0
+	for _, w := range widths {
+		total += w
+	}
+	available := l.width - indent
+	for c := range widths {
+		widths[c] *= available / total
+	}
+
+	lineH := pdfTableSize * pdfLineSpacing
+	drawRow := func(cells [][]pdfWord, header bool) {
+		wrapped := make([][]pdfLine, cols)
+		rowH := lineH
+		for c := 0; c < cols && c < len(cells); c++ {
+			wrapped[c] = wrapWords(cells[c], pdfTableSize, widths[c]-2*pdfCellPadding)
+			if h := float64(len(wrapped[c])) * lineH; h > rowH {
+				rowH = h
+			}
+		}
+		rowH += 2 * pdfCellPadding
+		l.ensure(rowH)
+		x := l.left + indent
+		if header {
+			l.page.FillRect(x, l.y, available, rowH, pdfHeaderFill)
+		}
+		for c, lines := range wrapped {
+			for i, line := range lines {
+				baseline := l.y + pdfCellPadding + float64(i)*lineH + pdfTableSize
+				l.drawLine(line, x+pdfCellPadding, baseline, pdfTableSize, pdfTextColor)
+			}
+			x += widths[c]
+		}
+		l.y += rowH
+		l.page.Line(l.left+indent, l.y, l.left+indent+available, l.y, 0.5, pdfRuleColor)
+	}
+
+	l.ensure(4 * lineH)
+	drawRow(rows[0], true)
+	for _, cells := range rows[1:] {
+		page := l.page
+		l.ensure(lineH + 2*pdfCellPadding)
+		if l.page != page {
+			drawRow(rows[0], true)
+		}
+		drawRow(cells, false)
+	}
+	l.y += pdfBodySize
+}
+
+func (l *pdfLayout) attribution() {
+	l.ensure(140)
+	l.y += 10
+	l.page.Text(l.left, l.y+14, "Data Sources", pdf.HelveticaBold, 14, pdfAccentColor)
+	l.y += 14 * pdfLineSpacing
+	l.page.Line(l.left, l.y, l.left+l.width, l.y, 0.75, pdfRuleColor)
+	l.y += 7
+	for _, line := range []string{
+		"NOAA Space Weather Prediction Center (services.swpc.noaa.gov): K-index, solar flux, forecasts",
+		"N0NBH Solar Data (hamqsl.com): band conditions, solar wind, X-ray and particle flux",
+		"SIDC, Royal Observatory of Belgium (sidc.be): sunspot numbers and solar event bulletins",
+		"Sun images copyrighted by SDO/NASA, SOHO (ESA & NASA) and the Helioviewer project (helioviewer.org)",
+		"Report generated by Radiocast (github.com/vpoluyaktov/radiocast) using AI. For amateur radio use only; conditions may vary by location.",
+	} {
+		words := (&inlineCollector{}).add(line, pdf.Helvetica).words
+		l.text(words, 0, pdfTableSize)
+		l.y += 2
+	}
+}
+
+// footers numbers every page after the title page
+func (l *pdfLayout) footers(date time.Time) {
+	pages := l.doc.Pages()
+	label := "Radiocast Propagation Report – " + date.Format("2006-01-02")
+	y := l.doc.PageSize().Height - pdfMargin/2
+	for i, page := range pages[1:] {
+		page.Text(l.left, y, label, pdf.Helvetica, 8, pdfMutedColor)
+		num := fmt.Sprintf("Page %d of %d", i+1, len(pages)-1)
+		page.Text(l.left+l.width-pdf.TextWidth(pdf.Helvetica, 8, num), y, num, pdf.Helvetica, 8, pdfMutedColor)
+	}
+}
+
+// collect flattens the inline content of a node into styled words
+func (l *pdfLayout) collect(n ast.Node, font pdf.Font) []pdfWord {
+	c := &inlineCollector{}
+	l.inline(n, font, c)
+	return c.words
+}
+
+func (l *pdfLayout) inline(n ast.Node, font pdf.Font, c *inlineCollector) {
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		switch node := child.(type) {
+		case *ast.Text:
+			c.add(string(node.Segment.Value(l.source)), font)
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				c.space = true
+			}
+		case *ast.String:
+			c.add(string(node.Value), font)
+		case *ast.CodeSpan:
+			l.inline(node, pdf.Courier, c)
+		case *ast.Emphasis:
+			if node.Level >= 2 {
+				l.inline(node, boldFont(font), c)
+			} else {
+				l.inline(node, obliqueFont(font), c)
+			}
+		case *ast.AutoLink:
+			c.add(string(node.URL(l.source)), font)
+		case *ast.RawHTML:
+			// Inline HTML tags are not printed
+		default:
+			l.inline(child, font, c)
+		}
+	}
+}
+
+func boldFont(f pdf.Font) pdf.Font {
+	switch f {
+	case pdf.Helvetica:
+		return pdf.HelveticaBold
+	case pdf.HelveticaOblique:
+		return pdf.HelveticaBoldOblique
+	}
+	return f
+}
+
+func obliqueFont(f pdf.Font) pdf.Font {
+	switch f {
+	case pdf.Helvetica:
+		return pdf.HelveticaOblique
+	case pdf.HelveticaBold:
+		return pdf.HelveticaBoldOblique
+	}
+	return f
+}
+
+// pdfWord is a run of text in one font, or a status dot. Words without a leading space
+// stick to the previous word when lines are wrapped.
+type pdfWord struct {
+	text  string
+	font  pdf.Font
+	dot   *color.RGBA
+	space bool
+}
+
+func (w pdfWord) width(size float64) float64 {
+	if w.dot != nil {
+		return size * 0.8
+	}
+	return pdf.TextWidth(w.font, size, w.text)
+}
+
+// inlineCollector splits text into words, tracking whitespace between style changes
+type inlineCollector struct {
+	words []pdfWord
+	space bool
+}
+
+func (c *inlineCollector) add(s string, font pdf.Font) *inlineCollector {
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			c.words = append(c.words, pdfWord{text: cur.String(), font: font, space: c.space && len(c.words) > 0})
+			c.space = false
+			cur.Reset()
+		}
+	}
+	for _, r := range s {
+		if dot, ok := statusDots[r]; ok {
+			flush()
+			dot := dot
+			c.words = append(c.words, pdfWord{dot: &dot, space: c.space && len(c.words) > 0})
+			c.space = false
+			continue
+		}
+		if unicode.IsSpace(r) {
+			flush()
+			c.space = true
+			continue
+		}
+		if len(pdf.Encode(string(r))) == 0 {
+			continue // emoji and other glyphs the standard fonts lack
+		}
+		cur.WriteRune(r)
+	}
+	flush()
+	return c
+}
+
+func plainText(words []pdfWord) string {
+	var b strings.Builder
+	for _, w := range words {
+		if w.space {
+			b.WriteByte(' ')
+		}
+		b.WriteString(w.text)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+type pdfLine []pdfWord
+
+// wrapWords breaks words into lines no wider than maxWidth
+func wrapWords(words []pdfWord, size, maxWidth float64) []pdfLine {
+	var lines []pdfLine
+	var line pdfLine
+	lineW := 0.0
+	for i := 0; i < len(words); {
+		// A group is a word plus the following words attached without a space
+		j := i + 1
+		for j < len(words) && !words[j].space {
+			j++
+		}
+		group := words[i:j]
+		groupW := lineWidth(group, size)
+		spaceW := 0.0
+		if len(line) > 0 {
+			spaceW = pdf.TextWidth(group[0].font, size, " ")
+		}
+		if len(line) > 0 && lineW+spaceW+groupW > maxWidth {
+			lines = append(lines, line)
+			line, lineW, spaceW = nil, 0, 0
+		}
+		for k, w := range group {
+			if k == 0 {
+				w.space = len(line) > 0
+			}
+			line = append(line, w)
+		}
+		lineW += spaceW + groupW
+		i = j
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// lineWidth measures words set on a single line
+func lineWidth(words []pdfWord, size float64) float64 {
+	total := 0.0
+	for i, w := range words {
+		if w.space && i > 0 {
+			total += pdf.TextWidth(w.font, size, " ")
+		}
+		total += w.width(size)
+	}
+	return total
+}
+
+func (l *pdfLayout) drawLine(line pdfLine, x, baseline, size float64, c color.Color) {
+	for i := 0; i < len(line); {
+		w := line[i]
+		if w.space && i > 0 {
+			x += pdf.TextWidth(w.font, size, " ")
+		}
+		if w.dot != nil {
+			r := size * 0.35
+			l.page.FillCircle(x+r, baseline-size*0.35, r, w.dot)
+			x += w.width(size)
+			i++
+			continue
+		}
+		// Set consecutive words in the same font with one text operator
+		run := w.text
+		j := i + 1
+		for ; j < len(line) && line[j].dot == nil && line[j].font == w.font; j++ {
+			if line[j].space {
+				run += " "
+			}
+			run += line[j].text
+		}
+		l.page.Text(x, baseline, run, w.font, size, c)
+		x += pdf.TextWidth(w.font, size, run)
+		i = j
+	}
+}
@@ -0,0 +1,240 @@
+package reports
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/charts"
+	"radiocast/internal/mocks"
+	"radiocast/internal/models"
+	"radiocast/internal/pdf"
+)
+
+// pdfRun is one text operator of a page content stream
+type pdfRun struct {
+	size float64
+	text string
+}
+
+var (
+	pdfContentPattern = regexp.MustCompile(`<< /Filter /FlateDecode /Length (\d+) >>\nstream\n`)
+	pdfTextPattern    = regexp.MustCompile(`/F\d+ ([\d.]+) Tf [-\d.]+ [-\d.]+ Td \(((?:\\.|[^\\)])*)\) Tj`)
+	pdfPageCount      = regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`)
+	pdfEscapePattern  = regexp.MustCompile(`\\(.)`)
+)
+
+// pdfPages returns the text runs of every page, in drawing order
+func pdfPages(t *testing.T, out []byte) [][]pdfRun {
+	t.Helper()
+	var pages [][]pdfRun
+	for _, m := range pdfContentPattern.FindAllSubmatchIndex(out, -1) {
+		length, _ := strconv.Atoi(string(out[m[2]:m[3]]))
+		zr, err := zlib.NewReader(bytes.NewReader(out[m[1] : m[1]+length]))
+		if err != nil {
+			t.Fatalf("page %d content is not zlib data: %v", len(pages)+1, err)
+		}
+		content, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("failed to inflate page %d: %v", len(pages)+1, err)
+		}
+		var runs []pdfRun
+		for _, tm := range pdfTextPattern.FindAllSubmatch(content, -1) {
+			size, _ := strconv.ParseFloat(string(tm[1]), 64)
+			text := pdfEscapePattern.ReplaceAll(tm[2], []byte("$1"))
+			runs = append(runs, pdfRun{size: size, text: string(text)})
+		}
+		pages = append(pages, runs)
+	}
+	return pages
+}
+
+// runsOfSize returns the text of the runs set at size, across pages
+func runsOfSize(pages [][]pdfRun, size float64) []string {
+	var texts []string
+	for _, runs := range pages {
+		for _, run := range runs {
+			if run.size == size {
+				texts = append(texts, run.text)
+			}
+		}
+	}
+	return texts
+}
+
+// containsSequence reports whether want appears as consecutive elements of texts
+func containsSequence(texts, want []string) bool {
+	for i := 0; i+len(want) <= len(texts); i++ {
+		match := true
+		for j := range want {
+			if texts[i+j] != want[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPDFBuilder_MockReport(t *testing.T) {
+	mockService := mocks.NewMockService(filepath.Join("..", "mocks"))
+	data, sourceData, err := mockService.LoadMockData()
+	if err != nil {
+		t.Fatalf("LoadMockData: %v", err)
+	}
+	markdown, err := mockService.LoadMockLLMResponse()
+	if err != nil {
+		t.Fatalf("LoadMockLLMResponse: %v", err)
+	}
+	snippets, err := charts.NewChartGenerator("").GenerateEChartsSnippetsWithSources(data, sourceData)
+	if err != nil {
+		t.Fatalf("GenerateEChartsSnippetsWithSources: %v", err)
+	}
+
+	out, err := NewPDFBuilder().Build(&PDFReport{
+		Markdown:    markdown,
+		Data:        data,
+		Charts:      snippets,
+		Version:     "v1.2.3",
+		GeneratedAt: time.Date(2025, 9, 17, 12, 5, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4")) {
+		t.Fatalf("output is not a PDF: %q", out[:min(len(out), 16)])
+	}
+
+	// Page count: the page tree, the content streams and the footers agree
+	pages := pdfPages(t, out)
+	m := pdfPageCount.FindSubmatch(out)
+	if m == nil {
+		t.Fatal("no page tree found")
+	}
+	if count, _ := strconv.Atoi(string(m[1])); count != len(pages) || count < 4 {
+		t.Fatalf("page tree counts %d pages for %d content streams, want a title page and the report", count, len(pages))
+	}
+	footers := runsOfSize(pages, 8)
+	for i := 1; i < len(pages); i++ {
+		want := fmt.Sprintf("Page %d of %d", i, len(pages)-1)
+		if !containsSequence(footers, []string{"Radiocast Propagation Report \x96 " + data.Timestamp.UTC().Format("2006-01-02"), want}) {
+			t.Errorf("page %d has no footer %q", i+1, want)
+		}
+	}
+	if title := runsOfSize(pages[:1], 26); len(title) != 1 || title[0] != "Amateur Radio Propagation Report" {
+		t.Errorf("title page = %q", title)
+	}
+
+	// Headings in order, without the emoji the standard fonts lack, then the attribution
+	wantHeadings := []string{
+		"Propagation Summary", "Operator Tips", "Best Operating Times", "DX Opportunities",
+		"Band-by-Band Analysis", "What's Being Worked Right Now", "Current Solar Activity",
+		"Geomagnetic Conditions", "Space Weather Details", "Propagation Timeline & Technical Details",
+		"3-Day Forecast", "Data Sources",
+	}
+	var headings []string
+	for _, h := range runsOfSize(pages[1:], 14) {
+		headings = append(headings, strings.TrimSpace(h))
+	}
+	if strings.Join(headings, "|") != strings.Join(wantHeadings, "|") {
+		t.Errorf("headings = %q\nwant %q", headings, wantHeadings)
+	}
+
+	// Table cells: the bold header and the rows, status emoji drawn as dots next to the text
+	cells := runsOfSize(pages, 9)
+	for _, row := range [][]string{
+		{"Band", "Morning", "Day", "Evening", "Night"},
+		{"80m", "Poor", "Poor", "Fair", "Fair"},
+		{"20m", "Good", "Good", "Good", "Good"},
+		{"10m", "Good", "Good", "Good", "Poor"},
+	} {
+		if !containsSequence(cells, row) {
+			t.Errorf("table row %q not found", row)
+		}
+	}
+
+	// Attribution lines wrap at spaces, so the joined runs read as the original text
+	attribution := strings.Join(cells, " ")
+	for _, want := range []string{
+		"NOAA Space Weather Prediction Center (services.swpc.noaa.gov): K-index, solar flux, forecasts",
+		"SIDC, Royal Observatory of Belgium (sidc.be): sunspot numbers and solar event bulletins",
+		"Sun images copyrighted by SDO/NASA, SOHO (ESA & NASA) and the Helioviewer project (helioviewer.org)",
+		"Report generated by Radiocast (github.com/vpoluyaktov/radiocast) using AI.",
+	} {
+		if !strings.Contains(attribution, want) {
+			t.Errorf("attribution %q not found", want)
+		}
+	}
+}
+
+func TestPDFBuilder_PlaceholdersDrawCharts(t *testing.T) {
+	gauge := charts.ChartSnippet{ID: "chart-gauge-panel", Title: "Gauges",
+		Static: &charts.StaticChart{Kind: charts.StaticGaugeKind, Title: "GAUGE PANEL",
+			Gauges: []charts.StaticGauge{{Label: "SFI", Value: 150, Max: 300}}}}
+	report := &PDFReport{
+		Markdown:    "## Gauges\n\n{{.GaugePanelChart}}\n\n{{.ForecastChart}}\n",
+		Data:        mustMockData(t),
+		Charts:      []charts.ChartSnippet{gauge},
+		GeneratedAt: time.Date(2025, 9, 17, 12, 5, 0, 0, time.UTC),
+	}
+	out, err := NewPDFBuilder().Build(report)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	// The known placeholder is drawn as a vector chart; the one without a chart is left out
+	var texts []string
+	for _, runs := range pdfPages(t, out)[1:] {
+		for _, run := range runs {
+			texts = append(texts, run.text)
+		}
+	}
+	joined := strings.Join(texts, "\n")
+	if !strings.Contains(joined, "GAUGE PANEL") || !strings.Contains(joined, "SFI") {
+		t.Errorf("gauge chart not drawn: %q", texts)
+	}
+	if strings.Contains(joined, "{{") || strings.Contains(joined, "ForecastChart") {
+		t.Errorf("placeholder printed as text: %q", texts)
+	}
+}
+
+func TestWrapWords(t *testing.T) {
+	words := (&inlineCollector{}).add("ab cd ef gh", pdf.Helvetica).words
+	width := lineWidth(words[:2], 10) + 1
+	lines := wrapWords(words, 10, width)
+	if len(lines) != 2 || plainText(lines[0]) != "ab cd" || plainText(lines[1]) != "ef gh" {
+		t.Errorf("wrapWords = %d lines", len(lines))
+	}
+	if lines[1][0].space {
+		t.Error("the first word of a wrapped line must not carry a leading space")
+	}
+
+	// Words attached without a space wrap together, and an overlong group gets its own line
+	words = (&inlineCollector{}).add("see ", pdf.Helvetica).add("bold", pdf.HelveticaBold).add(", then", pdf.Helvetica).words
+	lines = wrapWords(words, 10, pdf.TextWidth(pdf.Helvetica, 10, "see"))
+	if len(lines) != 3 || plainText(lines[1]) != "bold," || plainText(lines[2]) != "then" {
+		t.Errorf("wrapWords = %d lines", len(lines))
+		for _, line := range lines {
+			t.Logf("%q", plainText(line))
+		}
+	}
+}
+
+func mustMockData(t *testing.T) *models.PropagationData {
+	t.Helper()
+	data, _, err := mocks.NewMockService(filepath.Join("..", "mocks")).LoadMockData()
+	if err != nil {
+		t.Fatalf("LoadMockData: %v", err)
+	}
+	return data
+}
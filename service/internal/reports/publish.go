@@ -0,0 +1,202 @@
+package reports
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"sort"
+	"strings"
+	"time"
+
+	"radiocast/internal/logger"
+	"radiocast/internal/storage"
+)
+
+// A report folder is published in two phases: PendingMarker is written before any other file,
+// CommitMarker (a manifest with checksums) after all of them. Only committed folders are listed;
+// folders left pending by a failed run are deleted once they are older than AbandonedReportAge.
+const (
+	PendingMarker      = "_PENDING"
+	CommitMarker       = "_COMPLETE"
+	AbandonedReportAge = time.Hour
+)
+
+// ReportManifest is the content of CommitMarker
+type ReportManifest struct {
+	FolderPath  string         `json:"folder_path"`
+	CommittedAt time.Time      `json:"committed_at"`
+	Files       []ManifestFile `json:"files"`
+}
+
+// ManifestFile records the size and SHA-256 checksum of a published file
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// newManifestFile describes a file that is held in memory
+func newManifestFile(name string, data []byte) ManifestFile {
+	sum := sha256.Sum256(data)
+	return ManifestFile{Name: name, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])}
+}
+
+// checksumWriter sizes and hashes the bytes written through it
+type checksumWriter struct {
+	hash hash.Hash
+	size int64
+}
+
+func newChecksumWriter() *checksumWriter {
+	return &checksumWriter{hash: sha256.New()}
+}
+
+func (c *checksumWriter) Write(p []byte) (int, error) {
+	c.size += int64(len(p))
+	return c.hash.Write(p)
+}
+
+// file returns the manifest entry of the bytes written so far
+func (c *checksumWriter) file(name string) ManifestFile {
+	return ManifestFile{Name: name, Size: c.size, SHA256: hex.EncodeToString(c.hash.Sum(nil))}
+}
+
+// markReportPending writes the pending marker of a report folder
+func markReportPending(ctx context.Context, client storage.StorageClient, folderPath string) error {
+	stamp := []byte(time.Now().UTC().Format(time.RFC3339))
+	if err := client.StoreFile(ctx, reportFilePath(folderPath, PendingMarker), stamp); err != nil {
+		return fmt.Errorf("failed to mark report pending: %w", err)
+	}
+	return nil
+}
+
+// commitReport writes the manifest of a fully stored report folder, which makes it visible
+func commitReport(ctx context.Context, client storage.StorageClient, folderPath string, files []ManifestFile) error {
+	sorted := append([]ManifestFile(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	manifest, err := json.MarshalIndent(ReportManifest{
+		FolderPath:  folderPath,
+		CommittedAt: time.Now().UTC(),
+		Files:       sorted,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := client.StoreFile(ctx, reportFilePath(folderPath, CommitMarker), manifest); err != nil {
+		return fmt.Errorf("failed to commit report: %w", err)
+	}
+
+	// The commit marker wins over a leftover pending marker, so a failure here is harmless
+	if err := client.Delete(ctx, reportFilePath(folderPath, PendingMarker)); err != nil {
+		logger.Warn("Failed to remove pending marker", map[string]interface{}{"folder": folderPath, "error": err.Error()})
+	}
+	return nil
+}
+
+// ReportFolder is the publish state of a report folder found in storage
+type ReportFolder struct {
+	Path      string   // relative to reports/, as made by storage.GenerateReportFolderPath
+	Files     []string // storage paths of every file in the folder, sorted
+	Index     bool     // has index.html
+	Pending   bool     // has PendingMarker
+	Committed bool     // has CommitMarker
+}
+
+// Published reports whether the folder holds a report that may be listed
+func (f *ReportFolder) Published() bool {
+	return f.Index && (f.Committed || !f.Pending)
+}
+
+// ListReportFolders groups the files below reports/ by report folder, sorted by path
+func ListReportFolders(ctx context.Context, client storage.StorageClient) ([]*ReportFolder, error) {
+	allFiles, err := client.ListDir(ctx, "reports", true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+	sort.Strings(allFiles)
+	byPath := make(map[string]*ReportFolder)
+	var folders []*ReportFolder
+	for _, file := range allFiles {
+		file = strings.ReplaceAll(file, "\\", "/")
+		i := strings.LastIndex(file, "/")
+		if i < 0 || !strings.HasPrefix(file, "reports/") {
+			continue
+		}
+		folderPath := strings.TrimPrefix(file[:i], "reports/")
+		folder := byPath[folderPath]
+		if folder == nil {
+			folder = &ReportFolder{Path: folderPath}
+			byPath[folderPath] = folder
+			folders = append(folders, folder)
+		}
+		folder.Files = append(folder.Files, file)
+		switch file[i+1:] {
+		case "index.html":
+			folder.Index = true
+		case PendingMarker:
+			folder.Pending = true
+		case CommitMarker:
+			folder.Committed = true
+		}
+	}
+	sort.Slice(folders, func(i, j int) bool { return folders[i].Path < folders[j].Path })
+	return folders, nil
+}
+
+// ListCommittedReports returns the index.html paths of all published reports, newest first.
+// Folders written before two-phase publishing have neither marker and count as published.
+func ListCommittedReports(ctx context.Context, client storage.StorageClient) ([]string, error) {
+	folders, err := ListReportFolders(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var reports []string
+	for _, folder := range folders {
+		if folder.Published() {
+			reports = append(reports, reportFilePath(folder.Path, "index.html"))
+		}
+	}
+	// Newest first - reverse alphabetical
+	sort.Sort(sort.Reverse(sort.StringSlice(reports)))
+	return reports, nil
+}
+
+// CleanupAbandonedReports deletes report folders that were never committed and have been pending
+// for longer than maxAge. It returns the number of folders deleted.
+func CleanupAbandonedReports(ctx context.Context, client storage.StorageClient, maxAge time.Duration) (int, error) {
+	folders, err := ListReportFolders(ctx, client)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, folder := range folders {
+		if !folder.Pending || folder.Committed {
+			continue
+		}
+		folderPath := folder.Path
+		pendingPath := reportFilePath(folderPath, PendingMarker)
+		info, err := client.Stat(ctx, pendingPath)
+		if err != nil || time.Since(info.ModTime) < maxAge {
+			continue // still being written, or already gone
+		}
+
+		// The pending marker goes last so an interrupted cleanup is retried
+		for _, file := range folder.Files {
+			if file == pendingPath {
+				continue
+			}
+			if err := client.Delete(ctx, file); err != nil {
+				return deleted, fmt.Errorf("failed to delete abandoned report %s: %w", folderPath, err)
+			}
+		}
+		if err := client.Delete(ctx, pendingPath); err != nil {
+			return deleted, fmt.Errorf("failed to delete abandoned report %s: %w", folderPath, err)
+		}
+		logger.Info("Deleted abandoned report", map[string]interface{}{"folder": folderPath, "files": len(folder.Files)})
+		deleted++
+	}
+	return deleted, nil
+}
@@ -0,0 +1,189 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+	"radiocast/internal/storage"
+)
+
+// testReport returns the generated files of a small report taken at ts. Storing it takes six
+// writes: _PENDING, index.html, two JSON files, one asset and _COMPLETE.
+func testReport(ts time.Time) (*GeneratedFiles, *models.PropagationData) {
+	files := &GeneratedFiles{
+		HTMLContent: "<html>report</html>",
+		JSONFiles: map[string][]byte{
+			"normalized_data.json": []byte(`{"k_index": 2}`),
+			"noaa_k_index.json":    []byte(`[]`),
+		},
+		AssetFiles: map[string][]byte{"sun_72h.gif": []byte("GIF89a")},
+		FolderPath: storage.GenerateReportFolderPath(ts),
+	}
+	return files, &models.PropagationData{Timestamp: ts}
+}
+
+// storeTestReport stores testReport(ts) through a fresh orchestrator
+func storeTestReport(ctx context.Context, client storage.StorageClient, ts time.Time) error {
+	files, data := testReport(ts)
+	return NewStorageOrchestrator(client, "test").StoreAllFiles(ctx, files, data)
+}
+
+func findFolder(t *testing.T, client storage.StorageClient, folderPath string) *ReportFolder {
+	t.Helper()
+	folders, err := ListReportFolders(context.Background(), client)
+	if err != nil {
+		t.Fatalf("ListReportFolders: %v", err)
+	}
+	for _, folder := range folders {
+		if folder.Path == folderPath {
+			return folder
+		}
+	}
+	return nil
+}
+
+func TestStoreAllFiles_FailureLeavesReportUnpublished(t *testing.T) {
+	ctx := context.Background()
+	client := storage.NewMemoryStorageClient()
+	published := time.Date(2025, 6, 1, 6, 0, 0, 0, time.UTC)
+	failed := published.Add(6 * time.Hour)
+
+	if err := storeTestReport(ctx, client, published); err != nil {
+		t.Fatalf("StoreAllFiles: %v", err)
+	}
+
+	// Fail the fourth write: index.html and one JSON file are already stored by then
+	client.InjectFaults(storage.MemoryFaults{FailWrite: 4})
+	err := storeTestReport(ctx, client, failed)
+	if !errors.Is(err, storage.ErrInjectedFault) {
+		t.Fatalf("StoreAllFiles error = %v, want the injected fault", err)
+	}
+
+	folder := findFolder(t, client, storage.GenerateReportFolderPath(failed))
+	if folder == nil || !folder.Index || !folder.Pending || folder.Committed {
+		t.Fatalf("failed folder = %+v, want index.html and _PENDING without _COMPLETE", folder)
+	}
+
+	committed, err := ListCommittedReports(ctx, client)
+	if err != nil {
+		t.Fatalf("ListCommittedReports: %v", err)
+	}
+	want := reportFilePath(storage.GenerateReportFolderPath(published), "index.html")
+	if len(committed) != 1 || committed[0] != want {
+		t.Errorf("ListCommittedReports = %v, want only %s", committed, want)
+	}
+}
+
+func TestStoreAllFiles_WritesPendingMarkerOnce(t *testing.T) {
+	ctx := context.Background()
+	client := storage.NewMemoryStorageClient()
+	ts := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	files, data := testReport(ts)
+	orchestrator := NewStorageOrchestrator(client, "test")
+
+	if _, err := orchestrator.BeginReport(ctx, data); err != nil {
+		t.Fatalf("BeginReport: %v", err)
+	}
+	markerPath := reportFilePath(files.FolderPath, PendingMarker)
+	before, err := client.Stat(ctx, markerPath)
+	if err != nil {
+		t.Fatalf("pending marker missing after BeginReport: %v", err)
+	}
+
+	// Fail the last write (_COMPLETE), so the marker is still there to inspect
+	client.InjectFaults(storage.MemoryFaults{FailWrite: 5})
+	if err := orchestrator.StoreAllFiles(ctx, files, data); err == nil {
+		t.Fatal("StoreAllFiles succeeded despite the injected fault")
+	}
+	after, err := client.Stat(ctx, markerPath)
+	if err != nil {
+		t.Fatalf("pending marker missing after StoreAllFiles: %v", err)
+	}
+	if after.ETag != before.ETag {
+		t.Error("StoreAllFiles rewrote the pending marker written by BeginReport")
+	}
+}
+
+func TestCleanupAbandonedReports_WaitsForAbandonedReportAge(t *testing.T) {
+	ctx := context.Background()
+	client := storage.NewMemoryStorageClient()
+	now := time.Now()
+	published := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	abandoned := published.Add(6 * time.Hour)
+	recent := published.Add(12 * time.Hour)
+
+	if err := storeTestReport(ctx, client, published); err != nil {
+		t.Fatalf("StoreAllFiles: %v", err)
+	}
+	for _, run := range []struct {
+		ts  time.Time
+		age time.Duration
+	}{
+		{abandoned, AbandonedReportAge + time.Minute},
+		{recent, AbandonedReportAge - time.Minute},
+	} {
+		client.SetClock(func() time.Time { return now.Add(-run.age) })
+		client.InjectFaults(storage.MemoryFaults{FailWrite: 4})
+		if err := storeTestReport(ctx, client, run.ts); err == nil {
+			t.Fatalf("StoreAllFiles of %s succeeded despite the injected fault", run.ts)
+		}
+	}
+	client.SetClock(time.Now)
+	client.InjectFaults(storage.MemoryFaults{})
+
+	deleted, err := CleanupAbandonedReports(ctx, client, AbandonedReportAge)
+	if err != nil {
+		t.Fatalf("CleanupAbandonedReports: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("CleanupAbandonedReports deleted %d folders, want 1", deleted)
+	}
+	if folder := findFolder(t, client, storage.GenerateReportFolderPath(abandoned)); folder != nil {
+		t.Errorf("folder pending for longer than AbandonedReportAge was kept: %v", folder.Files)
+	}
+	if folder := findFolder(t, client, storage.GenerateReportFolderPath(recent)); folder == nil || !folder.Pending {
+		t.Error("folder pending for less than AbandonedReportAge was deleted")
+	}
+	if folder := findFolder(t, client, storage.GenerateReportFolderPath(published)); folder == nil || !folder.Published() {
+		t.Error("committed report was deleted")
+	}
+}
+
+func TestStoreMedia_RequiresPendingFolder(t *testing.T) {
+	ctx := context.Background()
+	client := storage.NewMemoryStorageClient()
+	files, data := testReport(time.Date(2025, 6, 1, 18, 0, 0, 0, time.UTC))
+	generator := NewFileGenerator(nil, nil, client)
+
+	// Without the handle of BeginReport nothing is written
+	if err := generator.storeMedia(ctx, files, "sun_72h.webp", writeBytes([]byte("RIFF"))); err == nil {
+		t.Fatal("storeMedia streamed into a folder that was not begun")
+	}
+	if written := client.Files(); len(written) != 0 {
+		t.Fatalf("media was written into a folder that was not begun: %v", written)
+	}
+	if _, err := generator.GenerateAllFiles(ctx, nil, data, nil, "", "", "", true); err == nil {
+		t.Fatal("GenerateAllFiles accepted a report folder that was not begun")
+	}
+
+	pending, err := NewStorageOrchestrator(client, "test").BeginReport(ctx, data)
+	if err != nil {
+		t.Fatalf("BeginReport: %v", err)
+	}
+	if pending.FolderPath() != files.FolderPath {
+		t.Fatalf("BeginReport began %s, want %s", pending.FolderPath(), files.FolderPath)
+	}
+	files.pending = pending
+	if err := generator.storeMedia(ctx, files, "sun_72h.webp", writeBytes([]byte("RIFF"))); err != nil {
+		t.Fatalf("storeMedia: %v", err)
+	}
+	if len(files.StoredFiles) != 1 {
+		t.Errorf("StoredFiles = %v, want the streamed media file", files.StoredFiles)
+	}
+	if exists, _ := client.FileExists(ctx, reportFilePath(files.FolderPath, PendingMarker)); !exists {
+		t.Error("pending marker missing next to the streamed media")
+	}
+}
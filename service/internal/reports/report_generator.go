@@ -9,6 +9,8 @@ import (
 	"radiocast/internal/charts"
 	"radiocast/internal/config"
 	"radiocast/internal/fetchers"
+	"radiocast/internal/geo"
+	"radiocast/internal/imagery"
 	"radiocast/internal/llm"
 	"radiocast/internal/logger"
 	"radiocast/internal/models"
@@ -18,13 +20,40 @@ import (
 
 // StorageInterface defines the interface for storage operations
 type StorageInterface interface {
+	// BeginReport marks the report folder pending before any file is written into it
+	BeginReport(ctx context.Context, data *models.PropagationData) (*PendingReport, error)
 	StoreAllFiles(ctx context.Context, files *GeneratedFiles, data *models.PropagationData) error
 }
 
+// PublishedReport describes a report that has been stored successfully
+type PublishedReport struct {
+	Data       *models.PropagationData
+	SourceData *models.SourceData
+	Markdown   string
+	Files      *GeneratedFiles
+	FolderPath string // Report folder relative to "reports/" (see storage.GenerateReportFolderPath)
+}
+
+// URLPath returns the site-relative URL of the report index page
+func (p *PublishedReport) URLPath() string {
+	return "/reports/" + p.FolderPath + "/index.html"
+}
+
+// PublishHook is notified after a report has been published
+type PublishHook interface {
+	ReportPublished(ctx context.Context, report *PublishedReport) error
+}
+
 // ReportGenerator handles report generation and HTML conversion
 type ReportGenerator struct {
-	chartGen    *charts.ChartGenerator
-	htmlBuilder *HTMLBuilder
+	chartGen     *charts.ChartGenerator
+	htmlBuilder  *HTMLBuilder
+	pdfBuilder   *PDFBuilder  // nil unless PDF reports are enabled
+	sunProducts  []imagery.Product
+	frameFetcher *imagery.FrameFetcher
+	publishHooks []PublishHook
+	qths         []geo.Location // gray-line windows are computed for these
+	dxCluster    DXClusterSource // nil unless a DX cluster is configured
 }
 
 // NewReportGenerator creates a new report generator
@@ -32,9 +61,44 @@ func NewReportGenerator() *ReportGenerator {
 	return &ReportGenerator{
 		chartGen:    charts.NewChartGenerator(""), // Empty outputDir since charts don't need it
 		htmlBuilder: NewHTMLBuilder(),
+		sunProducts:  imagery.DefaultProducts(),
+		frameFetcher: imagery.NewFrameFetcher(nil, imagery.DefaultWorkers),
+	}
+}
+
+// AddPublishHook registers a hook that runs after each successfully stored report
+func (rg *ReportGenerator) AddPublishHook(hook PublishHook) {
+	rg.publishHooks = append(rg.publishHooks, hook)
+}
+
+// SetStaticCharts makes reports fully static: charts are embedded as pre-rendered
+// images instead of ECharts scripts
+func (rg *ReportGenerator) SetStaticCharts(enabled bool) {
+	rg.htmlBuilder.staticCharts = enabled
+}
+
+// SetPDFReports enables writing report.pdf next to each report
+func (rg *ReportGenerator) SetPDFReports(enabled bool) {
+	rg.pdfBuilder = nil
+	rg.htmlBuilder.pdfLink = enabled
+	if enabled {
+		rg.pdfBuilder = NewPDFBuilder()
 	}
 }
 
+// SetSunImagery selects the Helioviewer products animated for each report
+func (rg *ReportGenerator) SetSunImagery(products []imagery.Product) {
+	rg.sunProducts = imagery.DefaultProducts()
+	if len(products) > 0 {
+		rg.sunProducts = products
+	}
+}
+
+// SetFrameFetcher sets how Helioviewer frames are downloaded (worker pool size and frame cache)
+func (rg *ReportGenerator) SetFrameFetcher(fetcher *imagery.FrameFetcher) {
+	rg.frameFetcher = fetcher
+}
+
 // GenerateReport generates a complete HTML report
 func (rg *ReportGenerator) GenerateReport(ctx context.Context,
 	propagationData *models.PropagationData,
@@ -42,6 +106,27 @@ func (rg *ReportGenerator) GenerateReport(ctx context.Context,
 	markdownContent string,
 	folderPath string) (string, error) {
 
+	// Keep the Sun imagery placeholders (will be replaced by file manager)
+	sunImages := map[string]template.HTML{imagery.SunViewerPlaceholder: "{{." + imagery.SunViewerPlaceholder + "}}"}
+	for _, p := range imagery.Products {
+		sunImages[p.Placeholder] = template.HTML("{{." + p.Placeholder + "}}")
+	}
+	return rg.buildReport(propagationData, sourceData, markdownContent, folderPath, sunImages, nil)
+}
+
+// GenerateOfflineHTML renders the report as a single self-contained HTML file
+func (rg *ReportGenerator) GenerateOfflineHTML(markdownReport string, data *models.PropagationData, sourceData *models.SourceData, folderPath string, sunImages map[string]template.HTML, assets *OfflineAssets) (string, error) {
+	return rg.buildReport(data, sourceData, markdownReport, folderPath, sunImages, assets)
+}
+
+// buildReport renders charts, markdown and the page template into the final HTML
+func (rg *ReportGenerator) buildReport(propagationData *models.PropagationData,
+	sourceData *models.SourceData,
+	markdownContent string,
+	folderPath string,
+	sunImages map[string]template.HTML,
+	offline *OfflineAssets) (string, error) {
+
 	logger.Debug("Starting HTML report generation...")
 
 	// Generate charts
@@ -51,13 +136,10 @@ func (rg *ReportGenerator) GenerateReport(ctx context.Context,
 		return "", fmt.Errorf("failed to generate charts: %w", err)
 	}
 
-	// Use placeholder for Sun GIF (will be replaced by file manager)
-	sunGifHTML := template.HTML("{{.SunGif}}")
-
 	// Process markdown with template placeholders
 	logger.Debug("Processing markdown with placeholders...")
 	processedContent, err := rg.htmlBuilder.ProcessMarkdownWithPlaceholders(
-		markdownContent, chartData, sunGifHTML)
+		markdownContent, chartData, sunImages)
 	if err != nil {
 		return "", fmt.Errorf("failed to process markdown: %w", err)
 	}
@@ -67,7 +149,7 @@ func (rg *ReportGenerator) GenerateReport(ctx context.Context,
 	logger.Debug("Processed content length", map[string]interface{}{"length": len(processedContent)})
 	logger.Debug("Processed content preview", map[string]interface{}{"preview": processedContent[:min(300, len(processedContent))]})
 	finalHTML, err := rg.htmlBuilder.BuildCompleteHTML(
-		processedContent, propagationData, chartData, sunGifHTML, folderPath)
+		processedContent, propagationData, chartData, sunImages[imagery.SunViewerPlaceholder], folderPath, offline)
 	if err != nil {
 		return "", fmt.Errorf("failed to build complete HTML: %w", err)
 	}
@@ -113,11 +195,16 @@ func (rg *ReportGenerator) GenerateCompleteReport(ctx context.Context,
 		return nil, err
 	}
 
-	// Step 2: Generate files using FileGenerator
-	fileGenerator := NewFileGenerator(rg, mockService)
+	// Step 2: Generate files using FileGenerator; media is streamed into the report folder as it
+	// is generated, so the folder is marked pending first
+	pending, err := storageOrchestrator.BeginReport(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	fileGenerator := NewFileGenerator(rg, mockService, storage)
 	systemPrompt := llmClient.GetSystemPrompt() // Get the system prompt used by LLM
 	userPrompt := llmClient.BuildPrompt(sourceData, data) // Get the user prompt with raw JSON data
-	files, err := fileGenerator.GenerateAllFiles(ctx, data, sourceData, markdownReport, systemPrompt, userPrompt, cfg.MockupMode)
+	files, err := fileGenerator.GenerateAllFiles(ctx, pending, data, sourceData, markdownReport, systemPrompt, userPrompt, cfg.MockupMode)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate files: %w", err)
 	}
@@ -127,6 +214,15 @@ func (rg *ReportGenerator) GenerateCompleteReport(ctx context.Context,
 		return nil, fmt.Errorf("failed to store files: %w", err)
 	}
 
+	// Step 4: Run publish hooks (notifications etc.) - failures never fail the report
+	rg.runPublishHooks(ctx, &PublishedReport{
+		Data:       data,
+		SourceData: sourceData,
+		Markdown:   markdownReport,
+		Files:      files,
+		FolderPath: files.FolderPath,
+	})
+
 	return map[string]interface{}{
 		"status":     "success",
 		"message":    "Report generated successfully",
@@ -136,6 +232,18 @@ func (rg *ReportGenerator) GenerateCompleteReport(ctx context.Context,
 	}, nil
 }
 
+// runPublishHooks notifies all registered publish hooks about a stored report
+func (rg *ReportGenerator) runPublishHooks(ctx context.Context, report *PublishedReport) {
+	for _, hook := range rg.publishHooks {
+		if err := hook.ReportPublished(ctx, report); err != nil {
+			logger.Warn("Publish hook failed", map[string]interface{}{
+				"hook":  fmt.Sprintf("%T", hook),
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
 // fetchDataAndGenerateReport handles data fetching and LLM report generation
 func (rg *ReportGenerator) fetchDataAndGenerateReport(ctx context.Context,
 	cfg *config.Config,
@@ -155,6 +263,8 @@ func (rg *ReportGenerator) fetchDataAndGenerateReport(ctx context.Context,
 		if err != nil {
 			return nil, nil, "", fmt.Errorf("mock data loading failed: %w", err)
 		}
+		rg.addGrayLine(data)
+		rg.addDXCluster(data)
 
 		logger.Debug("Loading mock LLM response...")
 		markdownReport, err = mockService.LoadMockLLMResponse()
@@ -173,6 +283,8 @@ func (rg *ReportGenerator) fetchDataAndGenerateReport(ctx context.Context,
 		}
 
 		logger.Debug("Data fetched successfully", map[string]interface{}{"timestamp": data.Timestamp.Format(time.RFC3339)})
+		rg.addGrayLine(data)
+		rg.addDXCluster(data)
 
 		// Generate LLM report with raw source data
 		logger.Info("Generating LLM report with raw source data...")
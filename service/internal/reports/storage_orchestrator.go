@@ -3,6 +3,8 @@ package reports
 import (
 	"context"
 	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	"radiocast/internal/logger"
@@ -10,10 +12,14 @@ import (
 	"radiocast/internal/storage"
 )
 
-// StorageOrchestrator handles the business logic of storing generated files
+// StorageOrchestrator handles the business logic of storing generated files. It owns the
+// publish markers of the report folders it stores.
 type StorageOrchestrator struct {
 	storage        storage.StorageClient
 	deploymentMode string
+
+	mu    sync.Mutex
+	begun map[string]bool // folders marked pending and not stored yet
 }
 
 // NewStorageOrchestrator creates a new storage orchestrator
@@ -21,7 +27,43 @@ func NewStorageOrchestrator(storage storage.StorageClient, deploymentMode string
 	return &StorageOrchestrator{
 		storage:        storage,
 		deploymentMode: deploymentMode,
+		begun:          make(map[string]bool),
+	}
+}
+
+// PendingReport is a report folder that BeginReport marked pending. The FileGenerator only
+// streams media into the folder of a PendingReport, so nothing is written before the marker.
+type PendingReport struct {
+	folderPath string
+}
+
+// FolderPath returns the report folder relative to "reports/"
+func (p *PendingReport) FolderPath() string {
+	return p.folderPath
+}
+
+// BeginReport marks the report folder of data pending and returns it for GenerateAllFiles.
+// StoreAllFiles marks folders that were not begun yet itself.
+func (so *StorageOrchestrator) BeginReport(ctx context.Context, data *models.PropagationData) (*PendingReport, error) {
+	folderPath := storage.GenerateReportFolderPath(data.Timestamp)
+	if err := so.beginFolder(ctx, folderPath); err != nil {
+		return nil, err
+	}
+	return &PendingReport{folderPath: folderPath}, nil
+}
+
+// beginFolder writes the pending marker of a folder once
+func (so *StorageOrchestrator) beginFolder(ctx context.Context, folderPath string) error {
+	so.mu.Lock()
+	defer so.mu.Unlock()
+	if so.begun[folderPath] {
+		return nil
+	}
+	if err := markReportPending(ctx, so.storage, folderPath); err != nil {
+		return err
 	}
+	so.begun[folderPath] = true
+	return nil
 }
 
 // StoreAllFiles handles storing generated files using StorageClient
@@ -37,16 +79,36 @@ func (so *StorageOrchestrator) StoreAllFiles(ctx context.Context, files *Generat
 	return nil
 }
 
-// storeFilesViaStorage stores files using the StorageClient interface
+// storeFilesViaStorage stores files using the StorageClient interface. The report folder is
+// marked pending first and committed with a manifest of every file last, so a failed or
+// concurrent run never exposes a half-written report.
 func (so *StorageOrchestrator) storeFilesViaStorage(ctx context.Context, files *GeneratedFiles, timestamp time.Time) error {
 	// Build report folder path
-	reportFolderPath := "reports/" + storage.GenerateReportFolderPath(timestamp)
+	folderPath := storage.GenerateReportFolderPath(timestamp)
+	reportFolderPath := "reports/" + folderPath
+	if err := so.beginFolder(ctx, folderPath); err != nil {
+		return err
+	}
+	defer func() {
+		so.mu.Lock()
+		delete(so.begun, folderPath)
+		so.mu.Unlock()
+	}()
+
+	// large media in files.StoredFiles was already streamed to the folder by the FileGenerator
+	manifest := append([]ManifestFile(nil), files.StoredFiles...)
 	
-	// Store HTML file
+	// Store HTML file, streamed to avoid copying the page into a byte slice
 	htmlPath := reportFolderPath + "/index.html"
-	if err := so.storage.StoreFile(ctx, htmlPath, []byte(files.HTMLContent)); err != nil {
+	checksum := newChecksumWriter()
+	err := storage.StreamFile(ctx, so.storage, htmlPath, func(w io.Writer) error {
+		_, err := io.WriteString(io.MultiWriter(w, checksum), files.HTMLContent)
+		return err
+	})
+	if err != nil {
 		return fmt.Errorf("failed to store HTML file: %w", err)
 	}
+	manifest = append(manifest, checksum.file("index.html"))
 	
 	// Store JSON files
 	for filename, data := range files.JSONFiles {
@@ -54,6 +116,7 @@ func (so *StorageOrchestrator) storeFilesViaStorage(ctx context.Context, files *
 		if err := so.storage.StoreFile(ctx, jsonPath, data); err != nil {
 			return fmt.Errorf("failed to store JSON file %s: %w", filename, err)
 		}
+		manifest = append(manifest, newManifestFile(filename, data))
 	}
 	
 	// Store asset files (excluding CSS and background image which are served from /static/)
@@ -66,12 +129,27 @@ func (so *StorageOrchestrator) storeFilesViaStorage(ctx context.Context, files *
 		if err := so.storage.StoreFile(ctx, assetPath, data); err != nil {
 			return fmt.Errorf("failed to store asset file %s: %w", filename, err)
 		}
+		manifest = append(manifest, newManifestFile(filename, data))
 		logger.Debug("Stored asset file", map[string]interface{}{
 			"filename": filename,
 			"bytes": len(data),
 		})
 	}
+
+	// Commit the report: from here on it is listed
+	if err := commitReport(ctx, so.storage, folderPath, manifest); err != nil {
+		return err
+	}
+
+	// Remove folders left behind by earlier runs that failed before committing
+	if _, err := CleanupAbandonedReports(ctx, so.storage, AbandonedReportAge); err != nil {
+		logger.Warn("Failed to clean up abandoned reports", map[string]interface{}{"error": err.Error()})
+	}
 	
 	return nil
 }
 
+// reportFilePath returns the storage path of a file in a report folder
+func reportFilePath(folderPath, name string) string {
+	return "reports/" + folderPath + "/" + name
+}
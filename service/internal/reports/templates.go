@@ -23,3 +23,7 @@ func (t *TemplateLoader) LoadHTMLTemplate() (string, error) {
 	return string(content), nil
 }
 
+// LoadStaticAsset loads a file served from /static/ (CSS, images, vendored scripts)
+func (t *TemplateLoader) LoadStaticAsset(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join("internal", "static", name))
+}
@@ -1,8 +1,11 @@
 package reports
 
 import (
+	"encoding/base64"
 	"strings"
 	"unicode"
+
+	"radiocast/internal/storage"
 )
 
 // ToTitleCase converts a string to title case (first letter of each word capitalized)
@@ -24,3 +27,25 @@ func ToTitleCase(s string) string {
 	}
 	return strings.Join(words, " ")
 }
+
+// reportAssetURL returns the URL of a file stored next to the report's index.html
+func reportAssetURL(folderPath, fileName string) string {
+	if folderPath == "" {
+		return fileName
+	}
+	return "/reports/" + strings.TrimSuffix(folderPath, "/") + "/" + fileName
+}
+
+// inlineDataURIs replaces quoted references to the given URLs with base64 data: URIs
+func inlineDataURIs(html string, files map[string][]byte) string {
+	for url, data := range files {
+		if !strings.Contains(html, url) {
+			continue
+		}
+		uri := "data:" + storage.GetContentType(url) + ";base64," + base64.StdEncoding.EncodeToString(data)
+		for _, quote := range []string{`"`, `'`} {
+			html = strings.ReplaceAll(html, quote+url+quote, quote+uri+quote)
+		}
+	}
+	return html
+}
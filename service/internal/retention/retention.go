@@ -0,0 +1,214 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"time"
+
+	"radiocast/internal/config"
+	"radiocast/internal/logger"
+	"radiocast/internal/reports"
+	"radiocast/internal/storage"
+)
+
+// Tier is the lifecycle stage of a report folder
+type Tier string
+
+const (
+	TierFull    Tier = "full"    // every report with all artifacts
+	TierDaily   Tier = "daily"   // one full report per day
+	TierArchive Tier = "archive" // one report per day reduced to ArchiveFiles
+)
+
+// ArchiveFiles are the files kept of a report in the archive tier
+var ArchiveFiles = []string{"normalized_data.json", "llm_response.md"}
+
+// Policy configures how long report artifacts are kept
+type Policy struct {
+	FullDays  int // reports younger than this keep everything
+	DailyDays int // reports younger than this (and older than FullDays) are thinned to one per day
+}
+
+// Validate checks that the tiers are ordered
+func (p Policy) Validate() error {
+	if p.FullDays < 1 {
+		return fmt.Errorf("full retention must be at least 1 day, got %d", p.FullDays)
+	}
+	if p.DailyDays < p.FullDays {
+		return fmt.Errorf("daily retention (%d days) must not be shorter than full retention (%d days)", p.DailyDays, p.FullDays)
+	}
+	return nil
+}
+
+// tier returns the tier of a report taken at timestamp
+func (p Policy) tier(now, timestamp time.Time) Tier {
+	age := now.Sub(timestamp)
+	switch {
+	case age < time.Duration(p.FullDays)*24*time.Hour:
+		return TierFull
+	case age < time.Duration(p.DailyDays)*24*time.Hour:
+		return TierDaily
+	default:
+		return TierArchive
+	}
+}
+
+// FolderAction describes what a retention run does to one report folder
+type FolderAction struct {
+	Folder  string   `json:"folder"`
+	Tier    Tier     `json:"tier"`
+	Removed bool     `json:"removed"` // the whole folder is deleted
+	Deleted []string `json:"deleted"` // storage paths of the deleted files
+}
+
+// Result summarises a retention run
+type Result struct {
+	DryRun       bool           `json:"dry_run"`
+	Scanned      int            `json:"scanned"`
+	FilesDeleted int            `json:"files_deleted"`
+	Actions      []FolderAction `json:"actions"`
+}
+
+// Enforcer applies a retention policy to the reports in storage
+type Enforcer struct {
+	storage storage.StorageClient
+	policy  Policy
+	dryRun  bool
+	now     func() time.Time
+}
+
+// NewEnforcer creates an enforcer. With dryRun set, runs only report what they would delete.
+func NewEnforcer(storageClient storage.StorageClient, policy Policy, dryRun bool) (*Enforcer, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &Enforcer{
+		storage: storageClient,
+		policy:  policy,
+		dryRun:  dryRun,
+		now:     time.Now,
+	}, nil
+}
+
+// NewEnforcerFromConfig creates an enforcer from service configuration
+func NewEnforcerFromConfig(cfg *config.Config, storageClient storage.StorageClient) (*Enforcer, error) {
+	enforcer, err := NewEnforcer(storageClient, Policy{
+		FullDays:  cfg.RetentionFullDays,
+		DailyDays: cfg.RetentionDailyDays,
+	}, cfg.RetentionDryRun)
+	if err != nil {
+		return nil, fmt.Errorf("invalid retention policy: %w", err)
+	}
+	return enforcer, nil
+}
+
+// DryRun reports whether the enforcer is configured to never delete
+func (e *Enforcer) DryRun() bool {
+	return e.dryRun
+}
+
+// ReportPublished implements reports.PublishHook: it applies the policy after every new report
+func (e *Enforcer) ReportPublished(ctx context.Context, report *reports.PublishedReport) error {
+	_, err := e.Run(ctx, false)
+	return err
+}
+
+// Run applies the policy. Nothing is deleted if dryRun is set or the enforcer is in dry-run mode.
+func (e *Enforcer) Run(ctx context.Context, dryRun bool) (*Result, error) {
+	dryRun = dryRun || e.dryRun
+	folders, err := reports.ListReportFolders(ctx, e.storage)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{DryRun: dryRun, Actions: []FolderAction{}}
+	for _, action := range e.plan(folders) {
+		if len(action.Deleted) == 0 {
+			continue
+		}
+		if !dryRun {
+			for _, file := range action.Deleted {
+				if err := e.storage.Delete(ctx, file); err != nil {
+					return result, fmt.Errorf("failed to delete %s: %w", file, err)
+				}
+			}
+		}
+		result.FilesDeleted += len(action.Deleted)
+		result.Actions = append(result.Actions, action)
+	}
+	result.Scanned = len(folders)
+
+	if result.FilesDeleted > 0 {
+		logger.Info("Applied report retention", map[string]interface{}{
+			"dry_run":       dryRun,
+			"folders":       len(result.Actions),
+			"files_deleted": result.FilesDeleted,
+		})
+	}
+	return result, nil
+}
+
+// plan decides what to delete from every folder. Folders still being published are left to
+// reports.CleanupAbandonedReports, and folders whose name holds no timestamp are never touched.
+func (e *Enforcer) plan(folders []*reports.ReportFolder) []FolderAction {
+	now := e.now()
+
+	// Group the folders past the full tier by UTC day; the newest of each day is kept
+	days := make(map[string][]*reports.ReportFolder)
+	tiers := make(map[*reports.ReportFolder]Tier)
+	for _, folder := range folders {
+		if folder.Pending && !folder.Committed {
+			continue
+		}
+		timestamp, ok := storage.ParseReportFolderPath(folder.Path)
+		if !ok {
+			continue
+		}
+		tier := e.policy.tier(now, timestamp)
+		if tier == TierFull {
+			continue
+		}
+		tiers[folder] = tier
+		day := timestamp.UTC().Format("2006-01-02")
+		days[day] = append(days[day], folder)
+	}
+
+	var actions []FolderAction
+	for _, group := range days {
+		sort.Slice(group, func(i, j int) bool { return group[i].Path > group[j].Path })
+		for i, folder := range group {
+			action := FolderAction{Folder: folder.Path, Tier: tiers[folder]}
+			switch {
+			case i > 0:
+				action.Removed = true
+				action.Deleted = deletionOrder(folder.Files, nil)
+			case action.Tier == TierArchive:
+				action.Deleted = deletionOrder(folder.Files, ArchiveFiles)
+			}
+			actions = append(actions, action)
+		}
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Folder < actions[j].Folder })
+	return actions
+}
+
+// deletionOrder returns the files to delete, skipping those named in keep. index.html goes first
+// so a folder drops out of the report listings before the files it links to disappear.
+func deletionOrder(files []string, keep []string) []string {
+	var first, rest []string
+	for _, file := range files {
+		name := path.Base(file)
+		if slices.Contains(keep, name) {
+			continue
+		}
+		if name == "index.html" {
+			first = append(first, file)
+		} else {
+			rest = append(rest, file)
+		}
+	}
+	return append(first, rest...)
+}
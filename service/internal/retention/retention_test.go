@@ -0,0 +1,147 @@
+package retention
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/reports"
+	"radiocast/internal/storage"
+)
+
+var testNow = time.Date(2025, 9, 17, 12, 0, 0, 0, time.UTC)
+
+// reportFiles are the files of a committed test report
+var reportFiles = []string{"index.html", "normalized_data.json", "llm_response.md", "noaa_k_index.json", "sun_72h.gif", reports.CommitMarker}
+
+// storeReport writes a report folder taken at timestamp and returns its path below reports/
+func storeReport(t *testing.T, client storage.StorageClient, timestamp time.Time, names ...string) string {
+	t.Helper()
+	folder := storage.GenerateReportFolderPath(timestamp)
+	for _, name := range names {
+		if err := client.StoreFile(context.Background(), "reports/"+folder+"/"+name, []byte(name)); err != nil {
+			t.Fatalf("StoreFile() error = %v", err)
+		}
+	}
+	return folder
+}
+
+func newTestEnforcer(t *testing.T, client storage.StorageClient, dryRun bool) *Enforcer {
+	t.Helper()
+	enforcer, err := NewEnforcer(client, Policy{FullDays: 7, DailyDays: 365}, dryRun)
+	if err != nil {
+		t.Fatalf("NewEnforcer() error = %v", err)
+	}
+	enforcer.now = func() time.Time { return testNow }
+	return enforcer
+}
+
+// folderFiles returns the file names left in a report folder
+func folderFiles(client *storage.MemoryStorageClient, folder string) string {
+	var names []string
+	for _, file := range client.Files() {
+		if strings.HasPrefix(file, "reports/"+folder+"/") {
+			names = append(names, strings.TrimPrefix(file, "reports/"+folder+"/"))
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func TestEnforcer_Run(t *testing.T) {
+	client := storage.NewMemoryStorageClient()
+	day := 24 * time.Hour
+
+	recent1 := storeReport(t, client, testNow.Add(-2*day), reportFiles...)
+	recent2 := storeReport(t, client, testNow.Add(-2*day+time.Hour), reportFiles...)
+	dailyOld := storeReport(t, client, testNow.Add(-30*day), reportFiles...)
+	dailyNew := storeReport(t, client, testNow.Add(-30*day+time.Hour), reportFiles...)
+	archiveOld := storeReport(t, client, testNow.Add(-400*day), reportFiles...)
+	archiveNew := storeReport(t, client, testNow.Add(-400*day+time.Hour), reportFiles...)
+	pending := storeReport(t, client, testNow.Add(-30*day+2*time.Hour), "index.html", reports.PendingMarker)
+
+	result, err := newTestEnforcer(t, client, false).Run(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.DryRun || result.Scanned != 7 {
+		t.Errorf("Run() dry_run = %v, scanned = %d, want false, 7", result.DryRun, result.Scanned)
+	}
+
+	full := reports.CommitMarker + ",index.html,llm_response.md,noaa_k_index.json,normalized_data.json,sun_72h.gif"
+	for _, folder := range []string{recent1, recent2, dailyNew} {
+		if got := folderFiles(client, folder); got != full {
+			t.Errorf("%s kept %q, want every file", folder, got)
+		}
+	}
+	for _, folder := range []string{dailyOld, archiveOld} {
+		if got := folderFiles(client, folder); got != "" {
+			t.Errorf("%s kept %q, want the folder removed", folder, got)
+		}
+	}
+	if got := folderFiles(client, archiveNew); got != "llm_response.md,normalized_data.json" {
+		t.Errorf("%s kept %q, want only the archive files", archiveNew, got)
+	}
+	if got := folderFiles(client, pending); got != "_PENDING,index.html" {
+		t.Errorf("Pending folder %s kept %q, want it untouched", pending, got)
+	}
+
+	// The removed folder deletes index.html first so it leaves the listings before its assets
+	if len(result.Actions) != 3 || !result.Actions[0].Removed || !strings.HasSuffix(result.Actions[0].Deleted[0], "/index.html") {
+		t.Errorf("Run() actions = %+v", result.Actions)
+	}
+	if result.FilesDeleted != 6+6+4 {
+		t.Errorf("Run() files_deleted = %d, want 16", result.FilesDeleted)
+	}
+
+	// A second run has nothing left to do
+	again, err := newTestEnforcer(t, client, false).Run(context.Background(), false)
+	if err != nil || again.FilesDeleted != 0 {
+		t.Errorf("Second Run() deleted %d files, error = %v", again.FilesDeleted, err)
+	}
+}
+
+func TestEnforcer_DryRun(t *testing.T) {
+	client := storage.NewMemoryStorageClient()
+	storeReport(t, client, testNow.Add(-400*24*time.Hour), reportFiles...)
+	before := client.Files()
+
+	// Both a dry-run enforcer and a dry run of a normal one leave storage untouched
+	for _, enforcer := range []*Enforcer{newTestEnforcer(t, client, true), newTestEnforcer(t, client, false)} {
+		result, err := enforcer.Run(context.Background(), true)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if !result.DryRun || result.FilesDeleted != 4 {
+			t.Errorf("Run() dry_run = %v, files_deleted = %d, want true, 4", result.DryRun, result.FilesDeleted)
+		}
+	}
+	if after := client.Files(); strings.Join(after, ",") != strings.Join(before, ",") {
+		t.Errorf("Dry run changed storage: %v -> %v", before, after)
+	}
+
+	// The enforcer's own dry-run mode also applies to publish hooks
+	if err := newTestEnforcer(t, client, true).ReportPublished(context.Background(), &reports.PublishedReport{}); err != nil {
+		t.Fatalf("ReportPublished() error = %v", err)
+	}
+	if after := client.Files(); len(after) != len(before) {
+		t.Errorf("Dry-run publish hook deleted files: %v", after)
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		policy Policy
+		valid  bool
+	}{
+		{Policy{FullDays: 30, DailyDays: 365}, true},
+		{Policy{FullDays: 30, DailyDays: 30}, true},
+		{Policy{FullDays: 0, DailyDays: 365}, false},
+		{Policy{FullDays: 30, DailyDays: 7}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.policy.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate(%+v) error = %v, want valid = %v", tt.policy, err, tt.valid)
+		}
+	}
+}
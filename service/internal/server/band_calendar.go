@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"radiocast/internal/logger"
+	"radiocast/internal/trends"
+)
+
+// bandCalendarDays is the default window of the band conditions calendar
+const bandCalendarDays = 90
+
+// HandleBandCalendarAPI serves the daily average band conditions of the last ?days= days (default 90)
+func (s *Server) HandleBandCalendarAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	days := bandCalendarDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > trends.MaxCalendarDays {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "Invalid days, expected 1 to " + strconv.Itoa(trends.MaxCalendarDays)})
+			return
+		}
+		days = n
+	}
+
+	now := time.Now()
+	calendar, err := trends.BuildBandCalendar(r.Context(), s.History, now.AddDate(0, 0, 1-days), now)
+	if err != nil {
+		logger.Error("Failed to build band calendar", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": "Failed to build band calendar"})
+		return
+	}
+	writeJSON(w, http.StatusOK, calendar)
+}
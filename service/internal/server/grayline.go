@@ -0,0 +1,61 @@
+package server
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"radiocast/internal/models"
+	"radiocast/internal/reports"
+	"radiocast/internal/sun"
+)
+
+// grayLineStep is the longitude step of the terminator returned by the API, in degrees
+const grayLineStep = 2.0
+
+// grayLineLocation is a QTH in the gray-line API response
+type grayLineLocation struct {
+	models.GrayLine
+	Elevation float64   `json:"elevation"` // sun elevation at the requested time, degrees
+	Day       sun.Times `json:"day"`       // sun times of the requested UTC day
+}
+
+// HandleGrayLineAPI serves the subsolar point and the day/night terminator at ?time= (RFC 3339,
+// default now), with the sun times and gray-line windows of ?grid= or the club QTHs
+func (s *Server) HandleGrayLineAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	at := time.Now().UTC().Truncate(time.Second)
+	if value := r.URL.Query().Get("time"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "time must be RFC 3339, e.g. 2025-10-18T14:00:00Z"})
+			return
+		}
+		at = t.UTC()
+	}
+	qths, err := s.requestedQTHs(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	locations := make([]grayLineLocation, 0, len(qths))
+	for _, qth := range qths {
+		locations = append(locations, grayLineLocation{
+			GrayLine:  reports.GrayLineAt(qth, at),
+			Elevation: math.Round(sun.Elevation(qth.Latitude, qth.Longitude, at)*10) / 10,
+			Day:       sun.TimesOn(qth.Latitude, qth.Longitude, at),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"time":       at,
+		"subsolar":   sun.Subsolar(at),
+		"terminator": sun.Terminator(at, grayLineStep),
+		"night":      sun.NightPolygon(at, grayLineStep),
+		"locations":  locations,
+	})
+}
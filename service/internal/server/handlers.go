@@ -3,18 +3,23 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"html/template"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
+	"radiocast/internal/charts"
 	"radiocast/internal/config"
 	"radiocast/internal/logger"
 	"radiocast/internal/reports"
+	"radiocast/internal/storage"
+	"radiocast/internal/trends"
 )
 
 // HandleRoot serves the main page with redirect to latest report
@@ -133,9 +138,11 @@ func (s *Server) HandleGenerate(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(result)
 }
 
-// HandleFileProxy serves files from local storage or GCS
+// HandleFileProxy serves files from local storage or GCS. Objects are streamed, conditional requests
+// (If-None-Match, If-Modified-Since) are answered with 304 and Range requests with 206, e.g. for
+// PDF viewers that load report.pdf page by page.
 func (s *Server) HandleFileProxy(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
@@ -159,19 +166,40 @@ func (s *Server) HandleFileProxy(w http.ResponseWriter, r *http.Request) {
 	// Both local and GCS store files with "reports/" prefix in the unified structure
 	actualFilePath := "reports/" + filePath
 	
-	fileData, err := s.Storage.GetFile(ctx, actualFilePath)
+	// Report folders still being written, or abandoned by a failed run, are not published yet
+	committed, pending, err := reportFolderState(ctx, s.Storage, filePath)
 	if err != nil {
-		logger.Error("Failed to get file from storage", err, map[string]interface{}{"path": actualFilePath})
+		logger.Error("Failed to check report folder", err, map[string]interface{}{"path": actualFilePath})
+		http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		return
+	}
+	if pending {
 		http.Error(w, "File not found", http.StatusNotFound)
 		return
 	}
 	
-	// Set appropriate content type
-	contentType := GetContentType(filePath)
-	w.Header().Set("Content-Type", contentType)
+	info, err := s.Storage.Stat(ctx, actualFilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			http.Error(w, "File not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to get file from storage", err, map[string]interface{}{"path": actualFilePath})
+		http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		return
+	}
 	
-	// Write file data to response
-	w.Write(fileData)
+	// Set content type and validators; ServeContent handles conditional and Range requests
+	w.Header().Set("Content-Type", storage.GetContentType(filePath))
+	w.Header().Set("ETag", `"`+info.ETag+`"`)
+	w.Header().Set("Cache-Control", cacheControl(filePath, committed))
+	
+	content := newObjectReader(ctx, s.Storage, actualFilePath, info.Size)
+	defer content.Close()
+	http.ServeContent(w, r, path.Base(filePath), info.ModTime, content)
+	if content.err != nil {
+		logger.Error("Failed to stream file from storage", content.err, map[string]interface{}{"path": actualFilePath})
+	}
 }
 
 // HandleListReports lists recent reports
@@ -194,34 +222,22 @@ func (s *Server) HandleListReports(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	
-	// List all files in reports directory recursively
-	allFiles, err := s.Storage.ListDir(ctx, "reports", true)
+	// List committed reports only (newest first); pending or abandoned folders are hidden
+	committed, err := reports.ListCommittedReports(ctx, s.Storage)
 	if err != nil {
 		logger.Error("Failed to list reports", err)
 		http.Error(w, "Failed to list reports: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
-	// Filter for index.html files and extract report paths
-	var reports []string
-	for _, file := range allFiles {
-		if strings.HasSuffix(file, "/index.html") {
-			reports = append(reports, file)
-		}
-	}
-	
-	// Sort and limit results (newest first - reverse alphabetical)
-	sort.Strings(reports)
-	for i, j := 0, len(reports)-1; i < j; i, j = i+1, j-1 {
-		reports[i], reports[j] = reports[j], reports[i]
-	}
-	if limit > 0 && limit < len(reports) {
-		reports = reports[:limit]
+	// Limit results
+	if limit > 0 && limit < len(committed) {
+		committed = committed[:limit]
 	}
 	
 	response := map[string]interface{}{
-		"reports":   reports,
-		"count":     len(reports),
+		"reports":   committed,
+		"count":     len(committed),
 		"timestamp": time.Now().UTC().Format(time.RFC3339),
 	}
 	
@@ -231,31 +247,17 @@ func (s *Server) HandleListReports(w http.ResponseWriter, r *http.Request) {
 
 // findLatestReportURL finds the URL of the latest report
 func (s *Server) findLatestReportURL(ctx context.Context) (string, error) {
-	// List all files in reports directory recursively
-	allFiles, err := s.Storage.ListDir(ctx, "reports", true)
+	// List committed reports only (newest first)
+	committed, err := reports.ListCommittedReports(ctx, s.Storage)
 	if err != nil {
-		return "", fmt.Errorf("failed to list reports: %w", err)
+		return "", err
 	}
 	
-	// Filter for index.html files
-	var reports []string
-	for _, file := range allFiles {
-		if strings.HasSuffix(file, "/index.html") {
-			reports = append(reports, file)
-		}
-	}
-	
-	if len(reports) == 0 {
+	if len(committed) == 0 {
 		return "", fmt.Errorf("no reports available")
 	}
 	
-	// Sort and get the latest (newest first - reverse alphabetical)
-	sort.Strings(reports)
-	for i, j := 0, len(reports)-1; i < j; i, j = i+1, j-1 {
-		reports[i], reports[j] = reports[j], reports[i]
-	}
-	
-	reportPath := reports[0]
+	reportPath := committed[0]
 	// Add leading slash (reportPath already includes "reports/" prefix)
 	return "/" + reportPath, nil
 }
@@ -286,11 +288,29 @@ func (s *Server) HandleHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
+	// Band conditions calendar; the page goes without it when there is no recorded history
+	var bandCalendarChart template.HTML
+	now := time.Now()
+	calendar, err := trends.BuildBandCalendar(r.Context(), s.History, now.AddDate(0, 0, 1-bandCalendarDays), now)
+	if err != nil {
+		logger.Warn("Failed to build band calendar", map[string]interface{}{"error": err.Error()})
+	} else if sn, err := charts.NewChartGenerator("").GenerateBandCalendarSnippet(calendar); err == nil {
+		bandCalendarChart = template.HTML(sn.HTML)
+	}
+
 	// Prepare template data
 	data := struct {
-		Version string
+		Version           string
+		BandCalendarChart template.HTML
+		BandCalendarDays  int
+		EChartsURL        string
+		EChartsCDNURL     string
 	}{
-		Version: config.GetVersion(),
+		Version:           config.GetVersion(),
+		BandCalendarChart: bandCalendarChart,
+		BandCalendarDays:  bandCalendarDays,
+		EChartsURL:        charts.EChartsStaticURL,
+		EChartsCDNURL:     charts.EChartsCDNURL,
 	}
 	
 	// Execute template
@@ -420,14 +440,15 @@ func (s *Server) HandleStaticFiles(w http.ResponseWriter, r *http.Request) {
 	
 	// Try to get file from static assets storage path
 	filePath := "static/" + filename
-	fileContent, err := s.Storage.GetFile(ctx, filePath)
+	reader, err := s.Storage.OpenReader(ctx, filePath)
 	if err != nil {
 		logger.Error("Failed to load static file from storage", err, map[string]interface{}{"path": filePath})
 		http.Error(w, "File not found", http.StatusNotFound)
 		return
 	}
+	defer reader.Close()
 	
-	w.Write(fileContent)
+	io.Copy(w, reader)
 }
 
 // requireAPIKey is a middleware that validates API key for protected endpoints
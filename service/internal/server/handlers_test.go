@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"radiocast/internal/reports"
+	"radiocast/internal/storage"
+)
+
+const testReportFolder = "2025/09/17/PropagationReport-2025-09-17-12-00-00"
+
+func TestHandleFileProxy_ServesReportPDF(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorageClient()
+	pdf := []byte("%PDF-1.4\n%%EOF\n")
+	for name, data := range map[string][]byte{
+		reports.PDFReportFile: pdf,
+		reports.CommitMarker:  []byte("{}"),
+	} {
+		if err := store.StoreFile(ctx, "reports/"+testReportFolder+"/"+name, data); err != nil {
+			t.Fatalf("StoreFile(%s): %v", name, err)
+		}
+	}
+	s := &Server{Storage: store}
+
+	rec := httptest.NewRecorder()
+	s.HandleFileProxy(rec, httptest.NewRequest(http.MethodGet, "/reports/"+testReportFolder+"/"+reports.PDFReportFile, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET report.pdf returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/pdf" {
+		t.Errorf("Content-Type = %q, want application/pdf", got)
+	}
+	if rec.Body.String() != string(pdf) {
+		t.Errorf("body = %q, want the stored PDF", rec.Body.String())
+	}
+}
+
+func TestHandleFileProxy_PublishState(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorageClient()
+	committed := "2025/09/17/PropagationReport-2025-09-17-12-00-00"
+	pending := "2025/09/17/PropagationReport-2025-09-17-18-00-00"
+	legacy := "2025/09/16/PropagationReport-2025-09-16-12-00-00"
+	for _, path := range []string{
+		committed + "/index.html", committed + "/" + reports.PendingMarker, committed + "/" + reports.CommitMarker,
+		pending + "/index.html", pending + "/" + reports.PendingMarker,
+		legacy + "/index.html",
+	} {
+		if err := store.StoreFile(ctx, "reports/"+path, []byte("<html></html>")); err != nil {
+			t.Fatalf("StoreFile(%s): %v", path, err)
+		}
+	}
+	s := &Server{Storage: store}
+
+	tests := []struct {
+		folder       string
+		status       int
+		cacheControl string
+	}{
+		{committed, http.StatusOK, "public, max-age=31536000, immutable"},
+		{pending, http.StatusNotFound, ""},
+		{legacy, http.StatusOK, "public, no-cache"}, // from before the publish markers
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.HandleFileProxy(rec, httptest.NewRequest(http.MethodGet, "/reports/"+tt.folder+"/index.html", nil))
+		if rec.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.folder, rec.Code, tt.status)
+		}
+		if got := rec.Header().Get("Cache-Control"); tt.cacheControl != "" && got != tt.cacheControl {
+			t.Errorf("%s: Cache-Control %q, want %q", tt.folder, got, tt.cacheControl)
+		}
+		if rec.Code == http.StatusNotFound && rec.Header().Get("Cache-Control") != "" {
+			t.Errorf("%s: a pending file must not be cached, got %q", tt.folder, rec.Header().Get("Cache-Control"))
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"radiocast/internal/charts"
+	"radiocast/internal/config"
+	"radiocast/internal/geo"
+	"radiocast/internal/logger"
+	"radiocast/internal/propagation"
+)
+
+// maxQTHs bounds the grids of one propagation request
+const maxQTHs = 10
+
+// HandlePropagation serves the /propagation page: band-opening charts for ?grid= or the club QTHs
+func (s *Server) HandlePropagation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	templatePath := filepath.Join("internal", "templates", "propagation_template.html")
+	templateContent, err := os.ReadFile(templatePath)
+	if err != nil {
+		logger.Error("Failed to load propagation template", err)
+		http.Error(w, "Propagation page template not found", http.StatusInternalServerError)
+		return
+	}
+	tmpl, err := template.New("propagation").Parse(string(templateContent))
+	if err != nil {
+		logger.Error("Failed to parse propagation template", err)
+		http.Error(w, "Propagation page template error", http.StatusInternalServerError)
+		return
+	}
+
+	data := struct {
+		Version       string
+		Grid          string
+		Error         string
+		Indices       *propagation.Indices
+		Charts        []template.HTML
+		EChartsURL    string
+		EChartsCDNURL string
+	}{
+		Version:       config.GetVersion(),
+		Grid:          r.URL.Query().Get("grid"),
+		EChartsURL:    charts.EChartsStaticURL,
+		EChartsCDNURL: charts.EChartsCDNURL,
+	}
+
+	status := http.StatusOK
+	qths, err := s.requestedQTHs(r)
+	if err != nil {
+		status, data.Error = http.StatusBadRequest, err.Error()
+	} else if len(qths) > 0 {
+		predictions, err := s.predict(r.Context(), qths)
+		switch {
+		case errors.Is(err, propagation.ErrNoSolarData):
+			status, data.Error = http.StatusServiceUnavailable, "No solar data has been recorded yet. Predictions appear once a report has been generated."
+		case err != nil:
+			logger.Error("Failed to predict propagation", err)
+			status, data.Error = http.StatusInternalServerError, "Failed to predict propagation"
+		default:
+			data.Indices = &predictions[0].Indices
+			// Snippets are generated from numbers and parsed grids only, so they are safe to embed
+			cg := charts.NewChartGenerator("")
+			for _, p := range predictions {
+				if sn, err := cg.GeneratePropagationSnippet(p); err == nil {
+					data.Charts = append(data.Charts, template.HTML(sn.HTML))
+				}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(status)
+	if err := tmpl.Execute(w, data); err != nil {
+		logger.Error("Failed to execute propagation template", err)
+	}
+}
+
+// HandlePropagationAPI serves the predictions for ?grid= or the club QTHs as JSON
+func (s *Server) HandlePropagationAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	qths, err := s.requestedQTHs(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": err.Error()})
+		return
+	}
+	if len(qths) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "grid is required, no QTH_GRIDS are configured"})
+		return
+	}
+
+	predictions, err := s.predict(r.Context(), qths)
+	if errors.Is(err, propagation.ErrNoSolarData) {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to predict propagation", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": "Failed to predict propagation"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"predictions": predictions})
+}
+
+// requestedQTHs returns the ?grid= locators of a request (repeated or comma separated), or the
+// configured club QTHs without any
+func (s *Server) requestedQTHs(r *http.Request) ([]geo.Location, error) {
+	var grids []string
+	for _, value := range r.URL.Query()["grid"] {
+		for _, grid := range strings.Split(value, ",") {
+			if grid = strings.TrimSpace(grid); grid != "" {
+				grids = append(grids, grid)
+			}
+		}
+	}
+	if len(grids) == 0 {
+		return s.QTHs, nil
+	}
+	if len(grids) > maxQTHs {
+		return nil, fmt.Errorf("at most %d grids per request", maxQTHs)
+	}
+	return geo.ParseGrids(grids)
+}
+
+// predict computes today's predictions from the recorded space weather
+func (s *Server) predict(ctx context.Context, qths []geo.Location) ([]*propagation.Prediction, error) {
+	now := time.Now()
+	idx, err := propagation.IndicesFromHistory(ctx, s.History, now)
+	if err != nil {
+		return nil, err
+	}
+	predictions := make([]*propagation.Prediction, 0, len(qths))
+	for _, qth := range qths {
+		predictions = append(predictions, propagation.Predict(qth, idx, now))
+	}
+	return predictions, nil
+}
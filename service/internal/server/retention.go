@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"radiocast/internal/logger"
+)
+
+// HandleRetention applies the report retention policy on demand. With ?dry_run=true it only
+// returns what would be deleted.
+func (s *Server) HandleRetention(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "Invalid dry_run value"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	// Retention never runs while a report is being published
+	if !s.generateMutex.TryLock() {
+		writeJSON(w, http.StatusConflict, map[string]interface{}{"error": "Report generation in progress"})
+		return
+	}
+	defer s.generateMutex.Unlock()
+
+	result, err := s.Retention.Run(r.Context(), dryRun)
+	if err != nil {
+		logger.Error("Failed to apply report retention", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": "Failed to apply report retention"})
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -7,14 +7,24 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
+	"radiocast/internal/charts"
 	"radiocast/internal/config"
+	"radiocast/internal/dxcluster"
+	"radiocast/internal/email"
 	"radiocast/internal/fetchers"
+	"radiocast/internal/geo"
+	"radiocast/internal/imagery"
 	"radiocast/internal/llm"
 	"radiocast/internal/logger"
 	"radiocast/internal/mocks"
+	"radiocast/internal/models"
+	"radiocast/internal/notify"
 	"radiocast/internal/reports"
+	"radiocast/internal/retention"
 	"radiocast/internal/storage"
+	"radiocast/internal/timeseries"
 )
 
 
@@ -27,9 +37,20 @@ type Server struct {
 	ReportGenerator *reports.ReportGenerator
 	Storage         storage.StorageClient
 	DeploymentMode  storage.DeploymentMode
+	Mailer          *email.Mailer // nil unless the email digest is enabled
+	Retention       *retention.Enforcer
+	History         *timeseries.Store      // space weather observations recorded by every fetch
+	QTHs            []geo.Location         // club QTHs from QTH_GRIDS
+	DXCluster       *dxcluster.Client      // nil unless DXCLUSTER_ADDR is set
+	stopDXCluster   context.CancelFunc
 	
 	// Mutex to prevent concurrent report generation
 	generateMutex   sync.Mutex
+	
+	// NOAA's predicted solar cycle for /trends, cached by predictedCycle
+	predictedMutex  sync.Mutex
+	predicted       []models.NOAAPredictedCycleResponse
+	predictedAt     time.Time
 }
 
 // NewServer creates a new server instance
@@ -57,8 +78,93 @@ func NewServer(cfg *config.Config, deploymentMode storage.DeploymentMode) (*Serv
 	}
 	server.Storage = storageClient
 	
+	// Record every fetch in the time-series store
+	server.History = timeseries.NewStore(storageClient)
+	server.Fetcher.SetHistoryStore(server.History, fetchers.HistoryOptions{
+		KIndexHours: cfg.HistoryKIndexHours,
+		SolarMonths: cfg.HistorySolarMonths,
+		BandDays:    cfg.HistoryBandDays,
+	})
+	
+	// Measured F2 layer of the configured ionosondes in every report
+	if err := server.Fetcher.SetIonosondes(fetchers.IonosondeOptions{
+		Source:   cfg.IonosondeSource,
+		URL:      cfg.IonosondeURL,
+		Stations: cfg.IonosondeStations,
+	}); err != nil {
+		return nil, fmt.Errorf("invalid IONOSONDE_SOURCE: %w", err)
+	}
+	
+	// Observed band activity from WSPR and PSKReporter spots in every report
+	if err := server.Fetcher.SetSpots(fetchers.SpotOptions{
+		Sources:        cfg.SpotsSources,
+		Hours:          cfg.SpotsHours,
+		WSPRURL:        cfg.WSPRURL,
+		PSKReporterURL: cfg.PSKReporterURL,
+	}); err != nil {
+		return nil, fmt.Errorf("invalid SPOTS_SOURCES or SPOTS_HOURS: %w", err)
+	}
+	
+	// Club QTHs predicted on /propagation, with their gray-line windows in every report
+	server.QTHs, err = geo.ParseGrids(cfg.QTHGrids)
+	if err != nil {
+		return nil, fmt.Errorf("invalid QTH_GRIDS: %w", err)
+	}
+	
 	// Initialize report generator
 	server.ReportGenerator = reports.NewReportGenerator()
+	server.ReportGenerator.SetStaticCharts(cfg.StaticReports)
+	server.ReportGenerator.SetPDFReports(cfg.PDFReports)
+	server.ReportGenerator.SetQTHs(server.QTHs)
+	sunProducts, err := imagery.ParseProducts(cfg.SunImagery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SUN_IMAGERY: %w", err)
+	}
+	server.ReportGenerator.SetSunImagery(sunProducts)
+	sunFormats, err := imagery.ParseFormats(cfg.SunImageryFormats)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SUN_IMAGERY_FORMATS: %w", err)
+	}
+	frameFetcher := imagery.NewFrameFetcher(imagery.NewStorageFrameCache(storageClient), cfg.HelioviewerWorkers)
+	frameFetcher.SetFormats(sunFormats)
+	server.ReportGenerator.SetFrameFetcher(frameFetcher)
+	
+	// Register webhook notifications if any targets are configured
+	if len(cfg.NotifyWebhooks) > 0 {
+		notifier, err := notify.NewNotifierFromConfig(cfg, storageClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize notifier: %w", err)
+		}
+		server.ReportGenerator.AddPublishHook(notifier)
+		logger.Infof("Webhook notifications enabled for %d target(s)", len(cfg.NotifyWebhooks))
+	}
+	
+	// Register the email digest if enabled
+	if cfg.EmailDigestEnabled {
+		mailer, err := email.NewMailerFromConfig(cfg, storageClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize email digest: %w", err)
+		}
+		server.Mailer = mailer
+		server.ReportGenerator.AddPublishHook(mailer)
+		logger.Infof("Email digest enabled via %s:%d", cfg.SMTPHost, cfg.SMTPPort)
+	}
+	
+	// Report retention runs on demand via /admin/retention, and after every report if enabled
+	server.Retention, err = retention.NewEnforcerFromConfig(cfg, storageClient)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RetentionEnabled {
+		server.ReportGenerator.AddPublishHook(server.Retention)
+		logger.Infof("Report retention enabled: full %d days, daily %d days (dry run: %v)",
+			cfg.RetentionFullDays, cfg.RetentionDailyDays, cfg.RetentionDryRun)
+	}
+	
+	// Pages load ECharts from /static/; without the bundle every interactive chart is blank
+	if err := checkEChartsBundle(filepath.Join("internal", "static")); err != nil {
+		return nil, err
+	}
 	
 	// Initialize static assets
 	if err := server.initializeStaticAssets(ctx); err != nil {
@@ -68,10 +174,32 @@ func NewServer(cfg *config.Config, deploymentMode storage.DeploymentMode) (*Serv
 		logger.Debugf("Static assets initialized successfully")
 	}
 	
+	// Live spot statistics from the DX cluster, for reports and /api/v1/spots/summary
+	if cfg.DXClusterAddr != "" {
+		if cfg.DXClusterCallsign == "" {
+			return nil, fmt.Errorf("DXCLUSTER_CALLSIGN is required when DXCLUSTER_ADDR is set")
+		}
+		if cfg.DXClusterWindowMinutes <= 0 {
+			return nil, fmt.Errorf("invalid DXCLUSTER_WINDOW_MINUTES: %d", cfg.DXClusterWindowMinutes)
+		}
+		stats := dxcluster.NewStats(time.Duration(cfg.DXClusterWindowMinutes)*time.Minute, cfg.DXClusterWatch)
+		server.DXCluster = dxcluster.NewClient(cfg.DXClusterAddr, cfg.DXClusterCallsign, stats)
+		server.ReportGenerator.SetDXCluster(server.DXCluster)
+		clusterCtx, cancel := context.WithCancel(context.Background())
+		server.stopDXCluster = cancel
+		go server.DXCluster.Run(clusterCtx)
+		logger.Infof("DX cluster spots from %s as %s", cfg.DXClusterAddr, cfg.DXClusterCallsign)
+	}
+	
 	// Log deployment mode
-	if deploymentMode == storage.DeploymentLocal {
+	switch deploymentMode {
+	case storage.DeploymentLocal:
 		logger.Debugf("Local deployment mode - reports directory determined by storage client")
-	} else {
+	case storage.DeploymentMemory:
+		logger.Debugf("Memory deployment mode - reports are kept in memory and lost on exit")
+	case storage.DeploymentS3:
+		logger.Debugf("S3 deployment mode - reports will be saved to S3 bucket: %s", cfg.S3Bucket)
+	default:
 		logger.Debugf("GCS deployment mode - reports will be saved to GCS bucket: %s", cfg.GCSBucket)
 	}
 	
@@ -89,19 +217,49 @@ func (s *Server) SetupRoutes() *http.ServeMux {
 	mux.HandleFunc("/generate", s.requireAPIKey(s.HandleGenerate))
 	mux.HandleFunc("/reports", s.HandleListReports)
 	mux.HandleFunc("/reports/", s.HandleFileProxy)
+	mux.HandleFunc("/admin/retention", s.requireAPIKey(s.HandleRetention))
 	
 	// Handle static pages
 	mux.HandleFunc("/history", s.HandleHistory)
 	mux.HandleFunc("/theory", s.HandleTheory)
 	mux.HandleFunc("/about", s.HandleAbout)
+	mux.HandleFunc("/trends", s.HandleTrends)
+	mux.HandleFunc("/propagation", s.HandlePropagation)
 	mux.HandleFunc("/static/", s.HandleStaticFiles)
 	
+	// Recorded time series
+	mux.HandleFunc("/api/v1/timeseries", s.HandleTimeSeries)
+	mux.HandleFunc("/api/v1/timeseries/", s.HandleTimeSeries)
+	mux.HandleFunc("/api/v1/trends", s.HandleTrendsAPI)
+	mux.HandleFunc("/api/v1/bands/calendar", s.HandleBandCalendarAPI)
+	mux.HandleFunc("/api/v1/propagation", s.HandlePropagationAPI)
+	mux.HandleFunc("/api/v1/grayline", s.HandleGrayLineAPI)
+	mux.HandleFunc("/api/v1/spots/summary", s.HandleSpotsSummaryAPI)
+	
+	// Email digest subscriptions
+	mux.HandleFunc("/api/v1/subscribers", s.HandleSubscribers)
+	mux.HandleFunc("/subscribe/confirm", s.HandleConfirmSubscription)
+	mux.HandleFunc("/unsubscribe", s.HandleUnsubscribe)
+	
 	// Handle root path last (catch-all)
 	mux.HandleFunc("/", s.HandleRoot)
 	
 	return mux
 }
 
+// checkEChartsBundle fails unless the ECharts bundle is in the static directory
+func checkEChartsBundle(staticDir string) error {
+	info, err := os.Stat(filepath.Join(staticDir, charts.EChartsStaticFile))
+	if err == nil && info.Size() == 0 {
+		err = fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return fmt.Errorf("ECharts %s bundle %s is missing (download it with run_local.sh or the Docker build): %w",
+			charts.EChartsVersion, charts.EChartsStaticFile, err)
+	}
+	return nil
+}
+
 // initializeStaticAssets uploads static assets and HTML pages to storage
 func (s *Server) initializeStaticAssets(ctx context.Context) error {
 	staticDir := filepath.Join("internal", "static")
@@ -181,6 +339,9 @@ func (s *Server) storeHTMLPage(ctx context.Context, htmlData []byte, filePath st
 
 // Close cleans up server resources
 func (s *Server) Close() error {
+	if s.stopDXCluster != nil {
+		s.stopDXCluster()
+	}
 	if s.Storage != nil {
 		return s.Storage.Close()
 	}
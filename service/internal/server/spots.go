@@ -0,0 +1,20 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// HandleSpotsSummaryAPI serves the live DX cluster spot statistics: spots of the rolling window
+// by band and continent of the DX station, and the notable calls
+func (s *Server) HandleSpotsSummaryAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.DXCluster == nil {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": "no DX cluster configured (DXCLUSTER_ADDR)"})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.DXCluster.Summary(time.Now().UTC().Truncate(time.Second)))
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"radiocast/internal/email"
+	"radiocast/internal/logger"
+)
+
+// HandleSubscribers handles POST (subscribe) and GET (admin listing) on /api/v1/subscribers
+func (s *Server) HandleSubscribers(w http.ResponseWriter, r *http.Request) {
+	if s.Mailer == nil {
+		http.Error(w, "Email digest is not enabled", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		s.handleSubscribe(w, r)
+	case http.MethodGet:
+		s.requireAPIKey(s.handleListSubscribers)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleSubscribe registers a pending subscriber and sends the confirmation email.
+// Accepts JSON {"email": "..."} or a form field named email.
+func (s *Server) handleSubscribe(w http.ResponseWriter, r *http.Request) {
+	var address string
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var req struct {
+			Email string `json:"email"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "Invalid JSON body"})
+			return
+		}
+		address = req.Email
+	} else {
+		address = r.FormValue("email")
+	}
+
+	ctx := r.Context()
+	sub, err := s.Mailer.Store().Subscribe(ctx, address)
+	if errors.Is(err, email.ErrInvalidEmail) {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "Invalid email address"})
+		return
+	}
+	if errors.Is(err, email.ErrConfirmationSent) {
+		// Answer like a fresh subscription so the endpoint does not reveal or resend anything
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{
+			"status":  email.StatusPending,
+			"message": "Check your inbox and confirm the subscription.",
+		})
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to register subscriber", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": "Failed to register subscription"})
+		return
+	}
+
+	if sub.Status == email.StatusActive {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"status":  sub.Status,
+			"message": "This address is already subscribed.",
+		})
+		return
+	}
+
+	if err := s.Mailer.SendConfirmation(ctx, sub); err != nil {
+		logger.Error("Failed to send confirmation email", err)
+		writeJSON(w, http.StatusBadGateway, map[string]interface{}{"error": "Failed to send confirmation email"})
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"status":  sub.Status,
+		"message": "Check your inbox and confirm the subscription.",
+	})
+}
+
+// handleListSubscribers returns all subscribers without their tokens
+func (s *Server) handleListSubscribers(w http.ResponseWriter, r *http.Request) {
+	subs, err := s.Mailer.Store().List(r.Context())
+	if err != nil {
+		logger.Error("Failed to list subscribers", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": "Failed to list subscribers"})
+		return
+	}
+
+	type subscriberInfo struct {
+		Email          string                 `json:"email"`
+		Status         email.SubscriberStatus `json:"status"`
+		CreatedAt      time.Time              `json:"created_at"`
+		ConfirmedAt    *time.Time             `json:"confirmed_at,omitempty"`
+		UnsubscribedAt *time.Time             `json:"unsubscribed_at,omitempty"`
+	}
+	list := make([]subscriberInfo, 0, len(subs))
+	active := 0
+	for _, sub := range subs {
+		if sub.Status == email.StatusActive {
+			active++
+		}
+		list = append(list, subscriberInfo{
+			Email:          sub.Email,
+			Status:         sub.Status,
+			CreatedAt:      sub.CreatedAt,
+			ConfirmedAt:    sub.ConfirmedAt,
+			UnsubscribedAt: sub.UnsubscribedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"subscribers": list,
+		"total":       len(list),
+		"active":      active,
+	})
+}
+
+// HandleConfirmSubscription activates a subscriber from the double opt-in link
+func (s *Server) HandleConfirmSubscription(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.Mailer == nil {
+		http.Error(w, "Email digest is not enabled", http.StatusNotFound)
+		return
+	}
+
+	sub, err := s.Mailer.Store().Confirm(r.Context(), r.URL.Query().Get("token"))
+	switch {
+	case errors.Is(err, email.ErrTokenNotFound):
+		writeMessagePage(w, http.StatusNotFound, "Link not valid", "This confirmation link is invalid or has already been used.")
+	case errors.Is(err, email.ErrTokenExpired):
+		writeMessagePage(w, http.StatusGone, "Link expired", "This confirmation link has expired. Please subscribe again.")
+	case err != nil:
+		logger.Error("Failed to confirm subscription", err)
+		writeMessagePage(w, http.StatusInternalServerError, "Something went wrong", "Please try again later.")
+	default:
+		writeMessagePage(w, http.StatusOK, "Subscription confirmed",
+			fmt.Sprintf("%s will now receive the daily propagation digest.", sub.Email))
+	}
+}
+
+// HandleUnsubscribe removes a subscriber. GET only asks for confirmation, so that link scanners
+// and prefetchers cannot unsubscribe anyone; the change happens on POST, which also serves
+// RFC 8058 one-click unsubscribe from the List-Unsubscribe-Post header.
+func (s *Server) HandleUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.Mailer == nil {
+		http.Error(w, "Email digest is not enabled", http.StatusNotFound)
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	var sub *email.Subscriber
+	var err error
+	if r.Method == http.MethodGet {
+		sub, err = s.Mailer.Store().FindByUnsubscribeToken(r.Context(), token)
+	} else {
+		sub, err = s.Mailer.Store().Unsubscribe(r.Context(), token)
+	}
+	switch {
+	case errors.Is(err, email.ErrTokenNotFound):
+		writeMessagePage(w, http.StatusNotFound, "Link not valid", "This unsubscribe link is invalid.")
+	case err != nil:
+		logger.Error("Failed to unsubscribe", err)
+		writeMessagePage(w, http.StatusInternalServerError, "Something went wrong", "Please try again later.")
+	case r.Method == http.MethodGet && sub.Status != email.StatusUnsubscribed:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		unsubscribePageTemplate.Execute(w, map[string]string{"Email": sub.Email, "Token": token})
+	default:
+		writeMessagePage(w, http.StatusOK, "Unsubscribed",
+			fmt.Sprintf("%s will no longer receive the propagation digest.", sub.Email))
+	}
+}
+
+// writeJSON writes a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+var messagePageTemplate = template.Must(template.New("message").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}} - Radiocast</title><link rel="stylesheet" href="/static/common.css"></head>
+<body><div class="container" style="max-width:640px;margin:40px auto;"><h1>{{.Title}}</h1><p>{{.Message}}</p><p><a href="/">Back to the latest report</a></p></div></body>
+</html>`))
+
+var unsubscribePageTemplate = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Unsubscribe - Radiocast</title><link rel="stylesheet" href="/static/common.css"></head>
+<body><div class="container" style="max-width:640px;margin:40px auto;"><h1>Unsubscribe</h1><p>Stop sending the propagation digest to {{.Email}}?</p>
+<form method="post" action="/unsubscribe?token={{.Token}}"><button type="submit">Unsubscribe</button></form>
+<p><a href="/">Back to the latest report</a></p></div></body>
+</html>`))
+
+// writeMessagePage renders a minimal HTML status page
+func writeMessagePage(w http.ResponseWriter, status int, title, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	messagePageTemplate.Execute(w, map[string]string{"Title": title, "Message": message})
+}
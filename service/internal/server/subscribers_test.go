@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"radiocast/internal/email"
+	"radiocast/internal/storage"
+)
+
+func TestHandleUnsubscribe_GetOnlyConfirms(t *testing.T) {
+	ctx := context.Background()
+	store := email.NewSubscriberStore(storage.NewMemoryStorageClient())
+	s := &Server{Mailer: email.NewMailer(nil, store, "https://radiocast.example")}
+
+	sub, err := store.Subscribe(ctx, "op@example.net")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if _, err := store.Confirm(ctx, sub.ConfirmToken); err != nil {
+		t.Fatalf("Confirm failed: %v", err)
+	}
+	target := "/unsubscribe?token=" + sub.UnsubscribeToken
+
+	rec := httptest.NewRecorder()
+	s.HandleUnsubscribe(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET returned %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `<form method="post" action="`+target+`">`) {
+		t.Errorf("GET must render a confirmation form posting back to the link:\n%s", rec.Body.String())
+	}
+	if active, _ := store.Active(ctx); len(active) != 1 {
+		t.Fatalf("GET must not unsubscribe, %d active subscribers left", len(active))
+	}
+
+	rec = httptest.NewRecorder()
+	s.HandleUnsubscribe(rec, httptest.NewRequest(http.MethodPost, target, nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "no longer receive") {
+		t.Fatalf("POST returned %d:\n%s", rec.Code, rec.Body.String())
+	}
+	if active, _ := store.Active(ctx); len(active) != 0 {
+		t.Errorf("POST must unsubscribe, %d active subscribers left", len(active))
+	}
+
+	rec = httptest.NewRecorder()
+	s.HandleUnsubscribe(rec, httptest.NewRequest(http.MethodGet, "/unsubscribe?token=bogus", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET with an unknown token returned %d, want 404", rec.Code)
+	}
+}
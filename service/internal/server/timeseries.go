@@ -0,0 +1,62 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"radiocast/internal/logger"
+	"radiocast/internal/timeseries"
+)
+
+// HandleTimeSeries serves the recorded time series: /api/v1/timeseries lists the metrics and
+// /api/v1/timeseries/<metric>?from=<RFC3339>&to=<RFC3339> returns the points of one
+func (s *Server) HandleTimeSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+
+	metric := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/timeseries"), "/")
+	if metric == "" {
+		metrics, err := s.History.Metrics(ctx)
+		if err != nil {
+			logger.Error("Failed to list time series", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": "Failed to list time series"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"metrics": metrics})
+		return
+	}
+
+	var window [2]time.Time
+	for i, name := range []string{"from", "to"} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "Invalid " + name + " time, expected RFC 3339"})
+			return
+		}
+		window[i] = t
+	}
+
+	points, err := s.History.Query(ctx, timeseries.Metric(metric), window[0], window[1])
+	if errors.Is(err, timeseries.ErrInvalidMetric) {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "Invalid metric name"})
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to read time series", err, map[string]interface{}{"metric": metric})
+		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": "Failed to read time series"})
+		return
+	}
+	if points == nil {
+		points = []timeseries.Point{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"metric": metric, "points": points})
+}
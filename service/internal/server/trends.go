@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"radiocast/internal/charts"
+	"radiocast/internal/config"
+	"radiocast/internal/logger"
+	"radiocast/internal/models"
+	"radiocast/internal/trends"
+)
+
+// NOAA revises its predicted cycle monthly, so it is fetched once a day (retried sooner after a failure)
+const (
+	predictedCycleTTL   = 24 * time.Hour
+	predictedCycleRetry = 15 * time.Minute
+)
+
+// HandleTrends serves the /trends page with the solar-cycle-scale charts
+func (s *Server) HandleTrends(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	t, err := s.buildTrends(r.Context())
+	if err != nil {
+		logger.Error("Failed to build trends", err)
+		http.Error(w, "Failed to build trends", http.StatusInternalServerError)
+		return
+	}
+
+	templatePath := filepath.Join("internal", "templates", "trends_template.html")
+	templateContent, err := os.ReadFile(templatePath)
+	if err != nil {
+		logger.Error("Failed to load trends template", err)
+		http.Error(w, "Trends page template not found", http.StatusInternalServerError)
+		return
+	}
+	tmpl, err := template.New("trends").Parse(string(templateContent))
+	if err != nil {
+		logger.Error("Failed to parse trends template", err)
+		http.Error(w, "Trends page template error", http.StatusInternalServerError)
+		return
+	}
+
+	// Snippets are generated from numbers only, so they are safe to embed
+	var chartHTML []template.HTML
+	for _, sn := range charts.NewChartGenerator("").GenerateTrendSnippets(t) {
+		chartHTML = append(chartHTML, template.HTML(sn.HTML))
+	}
+	data := struct {
+		Version       string
+		Charts        []template.HTML
+		GeneratedAt   string
+		EChartsURL    string
+		EChartsCDNURL string
+	}{
+		Version:       config.GetVersion(),
+		Charts:        chartHTML,
+		GeneratedAt:   t.GeneratedAt.Format("2006-01-02 15:04 UTC"),
+		EChartsURL:    charts.EChartsStaticURL,
+		EChartsCDNURL: charts.EChartsCDNURL,
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	if err := tmpl.Execute(w, data); err != nil {
+		logger.Error("Failed to execute trends template", err)
+		http.Error(w, "Trends page render error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// HandleTrendsAPI serves the data behind the /trends charts as JSON
+func (s *Server) HandleTrendsAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	t, err := s.buildTrends(r.Context())
+	if err != nil {
+		logger.Error("Failed to build trends", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": "Failed to build trends"})
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+// buildTrends computes the trends from the recorded history and NOAA's predicted cycle
+func (s *Server) buildTrends(ctx context.Context) (*trends.Trends, error) {
+	return trends.Build(ctx, s.History, s.predictedCycle(ctx), time.Now())
+}
+
+// predictedCycle returns NOAA's predicted cycle, fetched at most once per predictedCycleTTL. On
+// failure the last prediction is reused, or the charts go without the overlay until the retry.
+func (s *Server) predictedCycle(ctx context.Context) []models.NOAAPredictedCycleResponse {
+	s.predictedMutex.Lock()
+	defer s.predictedMutex.Unlock()
+
+	if s.MockService != nil || time.Since(s.predictedAt) < predictedCycleTTL {
+		return s.predicted
+	}
+	predicted, err := s.Fetcher.FetchPredictedCycle(ctx, s.Config.NOAAPredictedCycleURL)
+	if err != nil {
+		logger.Warn("Failed to fetch NOAA predicted cycle", map[string]interface{}{"error": err.Error()})
+		s.predictedAt = time.Now().Add(predictedCycleRetry - predictedCycleTTL)
+		return s.predicted
+	}
+	s.predicted = predicted
+	s.predictedAt = time.Now()
+	return predicted
+}
@@ -1,25 +1,107 @@
 package server
 
-import "strings"
-
-// GetContentType returns the appropriate content type for a file based on its extension
-func GetContentType(filePath string) string {
-	if strings.HasSuffix(filePath, ".html") {
-		return "text/html"
-	} else if strings.HasSuffix(filePath, ".png") {
-		return "image/png"
-	} else if strings.HasSuffix(filePath, ".gif") {
-		return "image/gif"
-	} else if strings.HasSuffix(filePath, ".json") {
-		return "application/json"
-	} else if strings.HasSuffix(filePath, ".txt") {
-		return "text/plain"
-	} else if strings.HasSuffix(filePath, ".md") {
-		return "text/markdown"
-	} else if strings.HasSuffix(filePath, ".css") {
-		return "text/css"
-	} else if strings.HasSuffix(filePath, ".js") {
-		return "application/javascript"
-	}
-	return "application/octet-stream"
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+
+	"radiocast/internal/reports"
+	"radiocast/internal/storage"
+)
+
+// reportFolderPattern matches files inside a timestamped report folder, which are never rewritten
+// once the report is committed
+var reportFolderPattern = regexp.MustCompile(`^\d{4}/\d{2}/\d{2}/PropagationReport-\d{4}-\d{2}-\d{2}-\d{2}-\d{2}-\d{2}/`)
+
+// cacheControl returns the Cache-Control header of a proxied report file. Files of committed
+// report folders are immutable; anything else must be revalidated with its ETag.
+func cacheControl(filePath string, committed bool) string {
+	if committed && reportFolderPattern.MatchString(filePath) {
+		return "public, max-age=31536000, immutable"
+	}
+	return "public, no-cache"
+}
+
+// reportFolderState looks up the publish markers of the report folder a proxied file lies in.
+// Files outside report folders are neither committed nor pending.
+func reportFolderState(ctx context.Context, storageClient storage.StorageClient, filePath string) (committed, pending bool, err error) {
+	folder := reportFolderPattern.FindString(filePath)
+	if folder == "" {
+		return false, false, nil
+	}
+	committed, err = storageClient.FileExists(ctx, "reports/"+folder+reports.CommitMarker)
+	if err != nil || committed {
+		return committed, false, err
+	}
+	pending, err = storageClient.FileExists(ctx, "reports/"+folder+reports.PendingMarker)
+	return false, pending, err
+}
+
+// objectReader is an io.ReadSeeker over a stored file that opens a streaming reader (ranged after
+// a seek) on the first read, so http.ServeContent can serve files and ranges without buffering them
+type objectReader struct {
+	ctx     context.Context
+	storage storage.StorageClient
+	path    string
+	size    int64
+	offset  int64
+	body    io.ReadCloser
+	err     error // first storage error, for logging after the response
+}
+
+func newObjectReader(ctx context.Context, storageClient storage.StorageClient, filePath string, size int64) *objectReader {
+	return &objectReader{ctx: ctx, storage: storageClient, path: filePath, size: size}
+}
+
+func (o *objectReader) Read(p []byte) (int, error) {
+	if o.offset >= o.size {
+		return 0, io.EOF
+	}
+	if o.body == nil {
+		var body io.ReadCloser
+		var err error
+		if o.offset == 0 {
+			body, err = o.storage.OpenReader(o.ctx, o.path)
+		} else {
+			body, err = o.storage.OpenRange(o.ctx, o.path, o.offset, -1)
+		}
+		if err != nil {
+			o.err = err
+			return 0, err
+		}
+		o.body = body
+	}
+	n, err := o.body.Read(p)
+	o.offset += int64(n)
+	if err != nil && err != io.EOF && o.err == nil {
+		o.err = err
+	}
+	return n, err
+}
+
+func (o *objectReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.size
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("invalid seek to %d", offset)
+	}
+	if offset != o.offset {
+		o.Close()
+		o.offset = offset
+	}
+	return offset, nil
+}
+
+func (o *objectReader) Close() error {
+	if o.body == nil {
+		return nil
+	}
+	err := o.body.Close()
+	o.body = nil
+	return err
 }
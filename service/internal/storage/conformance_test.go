@@ -0,0 +1,391 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The conformance suite checks that every StorageClient behaves the same way, so code written
+// against one backend works on the others. Each backend supplies a constructor returning a
+// client with an empty (or unused) namespace.
+
+func TestConformance_Local(t *testing.T) {
+	runConformanceSuite(t, func(t *testing.T) StorageClient {
+		originalDir, _ := os.Getwd()
+		os.Chdir(t.TempDir())
+		t.Cleanup(func() { os.Chdir(originalDir) })
+
+		client, err := NewLocalStorageClient("")
+		if err != nil {
+			t.Fatalf("Failed to create LocalStorageClient: %v", err)
+		}
+		return client
+	})
+}
+
+func TestConformance_Memory(t *testing.T) {
+	runConformanceSuite(t, func(t *testing.T) StorageClient {
+		return NewMemoryStorageClient()
+	})
+}
+
+func TestConformance_S3(t *testing.T) {
+	runConformanceSuite(t, func(t *testing.T) StorageClient {
+		client, _ := newTestS3Client(t)
+		return client
+	})
+}
+
+// TestConformance_GCS runs against the GCS emulator (e.g. fake-gcs-server) when
+// STORAGE_EMULATOR_HOST and GCS_TEST_BUCKET are set
+func TestConformance_GCS(t *testing.T) {
+	bucket := os.Getenv("GCS_TEST_BUCKET")
+	if os.Getenv("STORAGE_EMULATOR_HOST") == "" || bucket == "" {
+		t.Skip("STORAGE_EMULATOR_HOST and GCS_TEST_BUCKET not set")
+	}
+	runConformanceSuite(t, func(t *testing.T) StorageClient {
+		client, err := NewGCSClient(context.Background(), bucket)
+		if err != nil {
+			t.Fatalf("Failed to create GCSClient: %v", err)
+		}
+		return client
+	})
+}
+
+func runConformanceSuite(t *testing.T, newClient func(t *testing.T) StorageClient) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, client StorageClient, base string)
+	}{
+		{"StoreAndGet", conformStoreAndGet},
+		{"Missing", conformMissing},
+		{"Stat", conformStat},
+		{"OpenRange", conformOpenRange},
+		{"OpenWriter", conformOpenWriter},
+		{"OpenWriterCancelled", conformOpenWriterCancelled},
+		{"StreamFileFailure", conformStreamFileFailure},
+		{"ListDir", conformListDir},
+		{"StoreFileIfMatch", conformStoreFileIfMatch},
+		{"Delete", conformDelete},
+		{"Concurrent", conformConcurrent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newClient(t)
+			defer client.Close()
+			// A unique base keeps runs against shared buckets apart
+			base := "conformance/" + strconv.FormatInt(time.Now().UnixNano(), 36)
+			tt.run(t, client, base)
+		})
+	}
+}
+
+func conformStoreAndGet(t *testing.T, client StorageClient, base string) {
+	ctx := context.Background()
+	filePath := base + "/reports/2025/01/01/index.html"
+
+	if err := client.CreateDir(ctx, base+"/reports/2025/01/01"); err != nil {
+		t.Fatalf("CreateDir() error = %v", err)
+	}
+	if err := client.StoreFile(ctx, filePath, []byte("<html>v1</html>")); err != nil {
+		t.Fatalf("StoreFile() error = %v", err)
+	}
+	if data, err := client.GetFile(ctx, filePath); err != nil || string(data) != "<html>v1</html>" {
+		t.Errorf("GetFile() = %q, %v; want %q", data, err, "<html>v1</html>")
+	}
+	if exists, err := client.FileExists(ctx, filePath); !exists || err != nil {
+		t.Errorf("FileExists() = %v, %v; want true", exists, err)
+	}
+
+	// Overwriting replaces the content
+	if err := client.StoreFile(ctx, filePath, []byte("<html>v2</html>")); err != nil {
+		t.Fatalf("StoreFile() overwrite error = %v", err)
+	}
+	if data, err := client.GetFile(ctx, filePath); err != nil || string(data) != "<html>v2</html>" {
+		t.Errorf("GetFile() after overwrite = %q, %v; want %q", data, err, "<html>v2</html>")
+	}
+
+	// Empty files are files too
+	if err := client.StoreFile(ctx, base+"/empty.txt", nil); err != nil {
+		t.Fatalf("StoreFile(empty) error = %v", err)
+	}
+	if data, err := client.GetFile(ctx, base+"/empty.txt"); err != nil || len(data) != 0 {
+		t.Errorf("GetFile(empty) = %q, %v; want empty", data, err)
+	}
+}
+
+func conformMissing(t *testing.T, client StorageClient, base string) {
+	ctx := context.Background()
+	missing := base + "/missing.gif"
+
+	if exists, err := client.FileExists(ctx, missing); exists || err != nil {
+		t.Errorf("FileExists() = %v, %v; want false, nil", exists, err)
+	}
+	if _, err := client.GetFile(ctx, missing); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("GetFile() error = %v, want os.ErrNotExist", err)
+	}
+	if _, err := client.Stat(ctx, missing); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Stat() error = %v, want os.ErrNotExist", err)
+	}
+	if _, err := client.OpenReader(ctx, missing); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenReader() error = %v, want os.ErrNotExist", err)
+	}
+	if _, err := client.OpenRange(ctx, missing, 0, -1); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenRange() error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func conformStat(t *testing.T, client StorageClient, base string) {
+	ctx := context.Background()
+	filePath := base + "/sun.gif"
+
+	if err := client.StoreFile(ctx, filePath, []byte("GIF89a")); err != nil {
+		t.Fatalf("StoreFile() error = %v", err)
+	}
+	info, err := client.Stat(ctx, filePath)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Size != 6 || info.ETag == "" || info.ModTime.IsZero() {
+		t.Errorf("Stat() = %+v, want size 6 with ETag and ModTime", info)
+	}
+
+	if err := client.StoreFile(ctx, filePath, []byte("GIF89a!")); err != nil {
+		t.Fatalf("StoreFile() rewrite error = %v", err)
+	}
+	if updated, err := client.Stat(ctx, filePath); err != nil || updated.ETag == info.ETag || updated.Size != 7 {
+		t.Errorf("Stat() after rewrite = %+v, %v; want size 7 and a new ETag", updated, err)
+	}
+}
+
+func conformStoreFileIfMatch(t *testing.T, client StorageClient, base string) {
+	ctx := context.Background()
+	filePath := base + "/subscribers.json"
+
+	if err := client.StoreFileIfMatch(ctx, filePath, []byte("[1]"), ""); err != nil {
+		t.Fatalf("StoreFileIfMatch() create error = %v", err)
+	}
+	if err := client.StoreFileIfMatch(ctx, filePath, []byte("[2]"), ""); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("StoreFileIfMatch() create of an existing file error = %v, want ErrPreconditionFailed", err)
+	}
+	info, err := client.Stat(ctx, filePath)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if err := client.StoreFileIfMatch(ctx, filePath, []byte("[1,3]"), info.ETag); err != nil {
+		t.Fatalf("StoreFileIfMatch() with the current ETag error = %v", err)
+	}
+	// The file changed since info was read, so the stale ETag no longer matches
+	if err := client.StoreFileIfMatch(ctx, filePath, []byte("[1,4]"), info.ETag); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("StoreFileIfMatch() with a stale ETag error = %v, want ErrPreconditionFailed", err)
+	}
+	if data, err := client.GetFile(ctx, filePath); err != nil || string(data) != "[1,3]" {
+		t.Errorf("GetFile() = %q, %v; want the write with the current ETag", data, err)
+	}
+}
+
+func conformOpenRange(t *testing.T, client StorageClient, base string) {
+	ctx := context.Background()
+	filePath := base + "/video.mp4"
+	if err := client.StoreFile(ctx, filePath, []byte("0123456789")); err != nil {
+		t.Fatalf("StoreFile() error = %v", err)
+	}
+
+	tests := []struct {
+		offset, length int64
+		want           string
+	}{
+		{0, -1, "0123456789"},
+		{3, 4, "3456"},
+		{7, -1, "789"},
+		{8, 10, "89"},
+	}
+	for _, tt := range tests {
+		reader, err := client.OpenRange(ctx, filePath, tt.offset, tt.length)
+		if err != nil {
+			t.Fatalf("OpenRange(%d, %d) error = %v", tt.offset, tt.length, err)
+		}
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil || string(data) != tt.want {
+			t.Errorf("OpenRange(%d, %d) = %q, %v; want %q", tt.offset, tt.length, data, err, tt.want)
+		}
+	}
+}
+
+func conformOpenWriter(t *testing.T, client StorageClient, base string) {
+	ctx := context.Background()
+	filePath := base + "/report.pdf"
+
+	writer, err := client.OpenWriter(ctx, filePath)
+	if err != nil {
+		t.Fatalf("OpenWriter() error = %v", err)
+	}
+	content := strings.Repeat("%PDF-1.4 radiocast ", 100)
+	for i := 0; i < len(content); i += 64 {
+		if _, err := io.WriteString(writer, content[i:min(i+64, len(content))]); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	// Nothing is visible until Close
+	if exists, _ := client.FileExists(ctx, filePath); exists {
+		t.Error("File visible before Close")
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reader, err := client.OpenReader(ctx, filePath)
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil || string(data) != content {
+		t.Errorf("OpenReader() returned %d bytes, %v; want %d bytes", len(data), err, len(content))
+	}
+
+	// No temporary files are left behind
+	if files, err := client.ListDir(ctx, base, true); err != nil || len(files) != 1 {
+		t.Errorf("ListDir() = %v, %v; want only %s", files, err, filePath)
+	}
+}
+
+func conformOpenWriterCancelled(t *testing.T, client StorageClient, base string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	filePath := base + "/sun.mp4"
+
+	writer, err := client.OpenWriter(ctx, filePath)
+	if err != nil {
+		t.Fatalf("OpenWriter() error = %v", err)
+	}
+	io.WriteString(writer, "partial")
+	cancel()
+	if err := writer.Close(); err == nil {
+		t.Error("Close() after cancel: expected error")
+	}
+
+	if exists, _ := client.FileExists(context.Background(), filePath); exists {
+		t.Error("Cancelled write created the file")
+	}
+}
+
+func conformStreamFileFailure(t *testing.T, client StorageClient, base string) {
+	ctx := context.Background()
+	filePath := base + "/report.html"
+	if err := client.StoreFile(ctx, filePath, []byte("<html></html>")); err != nil {
+		t.Fatalf("StoreFile() error = %v", err)
+	}
+
+	// A failing producer leaves the previous content in place
+	failure := errors.New("encoder failed")
+	err := StreamFile(ctx, client, filePath, func(w io.Writer) error {
+		io.WriteString(w, "<ht")
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("StreamFile() error = %v, want %v", err, failure)
+	}
+	if data, _ := client.GetFile(ctx, filePath); string(data) != "<html></html>" {
+		t.Errorf("GetFile() after failed write = %q, want %q", data, "<html></html>")
+	}
+}
+
+func conformListDir(t *testing.T, client StorageClient, base string) {
+	ctx := context.Background()
+	for _, name := range []string{"reports/a/index.html", "reports/a/sun.gif", "reports/b/c/index.html", "reports/list.json", "reports-old/index.html"} {
+		if err := client.StoreFile(ctx, base+"/"+name, []byte("x")); err != nil {
+			t.Fatalf("StoreFile(%s) error = %v", name, err)
+		}
+	}
+
+	check := func(dirPath string, recursive bool, want ...string) {
+		t.Helper()
+		files, err := client.ListDir(ctx, dirPath, recursive)
+		if err != nil {
+			t.Fatalf("ListDir(%s, %v) error = %v", dirPath, recursive, err)
+		}
+		for i := range want {
+			want[i] = base + "/" + want[i]
+		}
+		sort.Strings(files)
+		if strings.Join(files, ",") != strings.Join(want, ",") {
+			t.Errorf("ListDir(%s, %v) = %v, want %v", dirPath, recursive, files, want)
+		}
+	}
+
+	// Recursive listings return every file below the directory, never a sibling with the same prefix
+	check(base+"/reports", true, "reports/a/index.html", "reports/a/sun.gif", "reports/b/c/index.html", "reports/list.json")
+	check(base+"/reports/", true, "reports/a/index.html", "reports/a/sun.gif", "reports/b/c/index.html", "reports/list.json")
+	check(base+"/reports/b", true, "reports/b/c/index.html")
+	check(base+"/missing", true)
+
+	// Flat listings return files and subdirectories (without a trailing slash)
+	check(base+"/reports", false, "reports/a", "reports/b", "reports/list.json")
+}
+
+func conformDelete(t *testing.T, client StorageClient, base string) {
+	ctx := context.Background()
+	for _, name := range []string{"reports/a/index.html", "reports/a/sun.gif", "reports/b/index.html"} {
+		if err := client.StoreFile(ctx, base+"/"+name, []byte("x")); err != nil {
+			t.Fatalf("StoreFile(%s) error = %v", name, err)
+		}
+	}
+
+	if err := client.Delete(ctx, base+"/reports/a/index.html"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if exists, _ := client.FileExists(ctx, base+"/reports/a/index.html"); exists {
+		t.Error("File exists after Delete")
+	}
+	if err := client.Delete(ctx, base+"/reports/a/index.html"); err != nil {
+		t.Errorf("Delete() of a missing file error = %v, want nil", err)
+	}
+
+	// Deleting the last file of a directory removes the directory from listings
+	if err := client.Delete(ctx, base+"/reports/a/sun.gif"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	files, err := client.ListDir(ctx, base+"/reports", false)
+	if err != nil || strings.Join(files, ",") != base+"/reports/b" {
+		t.Errorf("ListDir() after Delete = %v, %v; want [%s/reports/b]", files, err, base)
+	}
+}
+
+func conformConcurrent(t *testing.T, client StorageClient, base string) {
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := base + "/frames/" + strconv.Itoa(i) + ".png"
+			if err := client.StoreFile(ctx, name, []byte(strconv.Itoa(i))); err != nil {
+				errs <- err
+				return
+			}
+			if data, err := client.GetFile(ctx, name); err != nil || string(data) != strconv.Itoa(i) {
+				errs <- errors.New("read back " + string(data) + " for " + name)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if files, err := client.ListDir(ctx, base+"/frames", true); err != nil || len(files) != 16 {
+		t.Errorf("ListDir() = %d files, %v; want 16", len(files), err)
+	}
+}
@@ -11,8 +11,10 @@ import (
 type DeploymentMode string
 
 const (
-	DeploymentLocal DeploymentMode = "local"
-	DeploymentGCS   DeploymentMode = "gcs"
+	DeploymentLocal  DeploymentMode = "local"
+	DeploymentGCS    DeploymentMode = "gcs"
+	DeploymentS3     DeploymentMode = "s3"     // Amazon S3 or an S3-compatible service
+	DeploymentMemory DeploymentMode = "memory" // in-memory, lost on exit (tests and ephemeral runs)
 )
 
 // NewStorageClient creates a storage client based on deployment mode and configuration
@@ -42,6 +44,24 @@ func NewStorageClient(ctx context.Context, deploymentMode DeploymentMode, cfg *c
 		}
 		return gcsClient, nil
 		
+	case DeploymentS3:
+		s3Client, err := NewS3Client(S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			PathStyle:       cfg.S3PathStyle,
+			AccessKeyID:     cfg.AWSAccessKeyID,
+			SecretAccessKey: cfg.AWSSecretAccessKey,
+			SessionToken:    cfg.AWSSessionToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize S3 client: %w", err)
+		}
+		return s3Client, nil
+		
+	case DeploymentMemory:
+		return NewMemoryStorageClient(), nil
+		
 	default:
 		return nil, fmt.Errorf("unsupported deployment mode: %s", deploymentMode)
 	}
@@ -53,6 +53,37 @@ func TestNewStorageClient_GCS(t *testing.T) {
 	}
 }
 
+func TestNewStorageClient_S3(t *testing.T) {
+	cfg := &config.Config{
+		S3Endpoint:         "http://localhost:9000",
+		S3Region:           "us-east-1",
+		S3Bucket:           "radiocast",
+		S3PathStyle:        true,
+		AWSAccessKeyID:     "minioadmin",
+		AWSSecretAccessKey: "minioadmin",
+	}
+
+	client, err := NewStorageClient(context.Background(), DeploymentS3, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create S3 storage client: %v", err)
+	}
+	defer client.Close()
+
+	s3Client, ok := client.(*S3Client)
+	if !ok {
+		t.Fatalf("Expected S3Client, got %T", client)
+	}
+	if got := s3Client.objectURL("index.html", nil).String(); got != "http://localhost:9000/radiocast/index.html" {
+		t.Errorf("Unexpected object URL %s", got)
+	}
+
+	// A bucket is required
+	cfg.S3Bucket = ""
+	if _, err := NewStorageClient(context.Background(), DeploymentS3, cfg); err == nil {
+		t.Error("Expected error with missing S3 bucket")
+	}
+}
+
 func TestNewStorageClient_LocalFallback(t *testing.T) {
 	// Test local storage with default reports directory
 	cfg := &config.Config{
@@ -2,13 +2,18 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"os"
+	"strconv"
 	"strings"
 
 	"radiocast/internal/logger"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 )
 
@@ -47,19 +52,10 @@ func (g *GCSClient) CreateDir(ctx context.Context, dirPath string) error {
 func (g *GCSClient) StoreFile(ctx context.Context, filePath string, fileData []byte) error {
 	logger.Debugf("Storing file to GCS: gs://%s/%s", g.bucket, filePath)
 	
-	// Get bucket handle
-	bucket := g.client.Bucket(g.bucket)
-	
-	// Create object handle
-	obj := bucket.Object(filePath)
-	
-	// Create writer
-	writer := obj.NewWriter(ctx)
-	
-	// Set content type based on file extension
-	writer.ContentType = GetContentType(filePath)
-	
-	writer.CacheControl = "public, max-age=3600" // Cache for 1 hour
+	writer, err := g.OpenWriter(ctx, filePath)
+	if err != nil {
+		return err
+	}
 	
 	// Write file data
 	if _, err := writer.Write(fileData); err != nil {
@@ -76,14 +72,38 @@ func (g *GCSClient) StoreFile(ctx context.Context, filePath string, fileData []b
 	return nil
 }
 
+// StoreFileIfMatch stores a file if the object's generation (its ETag) is still etag
+func (g *GCSClient) StoreFileIfMatch(ctx context.Context, filePath string, fileData []byte, etag string) error {
+	conditions := storage.Conditions{DoesNotExist: true}
+	if etag != "" {
+		generation, err := strconv.ParseInt(etag, 10, 64)
+		if err != nil {
+			return fmt.Errorf("file %s: invalid generation %q: %w", filePath, etag, ErrPreconditionFailed)
+		}
+		conditions = storage.Conditions{GenerationMatch: generation}
+	}
+	writer := g.client.Bucket(g.bucket).Object(filePath).If(conditions).NewWriter(ctx)
+	writer.ContentType = GetContentType(filePath)
+	writer.CacheControl = "public, max-age=3600"
+	if _, err := writer.Write(fileData); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write file to GCS: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+			return fmt.Errorf("file %s: %w", filePath, ErrPreconditionFailed)
+		}
+		return fmt.Errorf("failed to finalize GCS file upload: %w", err)
+	}
+	return nil
+}
+
 // GetFile retrieves a file from the specified path
 func (g *GCSClient) GetFile(ctx context.Context, filePath string) ([]byte, error) {
-	bucket := g.client.Bucket(g.bucket)
-	obj := bucket.Object(filePath)
-	
-	reader, err := obj.NewReader(ctx)
+	reader, err := g.OpenReader(ctx, filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create reader for file %s: %w", filePath, err)
+		return nil, err
 	}
 	defer reader.Close()
 	
@@ -127,6 +147,12 @@ func (g *GCSClient) ListDir(ctx context.Context, dirPath string, recursive bool)
 			return nil, fmt.Errorf("failed to list objects: %w", err)
 		}
 		
+		// Subdirectories of a non-recursive listing only have a prefix; list them like local storage does
+		if attrs.Name == "" && attrs.Prefix != "" {
+			files = append(files, strings.TrimSuffix(attrs.Prefix, "/"))
+			continue
+		}
+		
 		// Keep the full path to maintain consistency with local storage
 		if attrs.Name != prefix { // Skip the directory itself
 			files = append(files, attrs.Name)
@@ -150,3 +176,58 @@ func (g *GCSClient) FileExists(ctx context.Context, filePath string) (bool, erro
 	}
 	return true, nil
 }
+
+// Stat returns the size, update time and generation (as ETag) of an object
+func (g *GCSClient) Stat(ctx context.Context, filePath string) (*FileInfo, error) {
+	attrs, err := g.client.Bucket(g.bucket).Object(filePath).Attrs(ctx)
+	if err != nil {
+		if err == storage.ErrObjectNotExist {
+			return nil, fmt.Errorf("file %s: %w", filePath, os.ErrNotExist)
+		}
+		return nil, fmt.Errorf("failed to get attributes of %s: %w", filePath, err)
+	}
+	return &FileInfo{
+		Size:    attrs.Size,
+		ModTime: attrs.Updated,
+		ETag:    strconv.FormatInt(attrs.Generation, 10),
+	}, nil
+}
+
+// OpenRange opens an object for reading length bytes starting at offset
+func (g *GCSClient) OpenRange(ctx context.Context, filePath string, offset, length int64) (io.ReadCloser, error) {
+	reader, err := g.client.Bucket(g.bucket).Object(filePath).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		if err == storage.ErrObjectNotExist {
+			return nil, fmt.Errorf("file %s: %w", filePath, os.ErrNotExist)
+		}
+		return nil, fmt.Errorf("failed to create reader for file %s: %w", filePath, err)
+	}
+	return reader, nil
+}
+
+// OpenReader opens an object for streaming reads
+func (g *GCSClient) OpenReader(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	return g.OpenRange(ctx, filePath, 0, -1)
+}
+
+// OpenWriter opens a resumable upload of an object; the object is created when Close returns
+// nil and the upload is abandoned if ctx is cancelled first
+func (g *GCSClient) OpenWriter(ctx context.Context, filePath string) (io.WriteCloser, error) {
+	writer := g.client.Bucket(g.bucket).Object(filePath).NewWriter(ctx)
+	
+	// Set content type based on file extension
+	writer.ContentType = GetContentType(filePath)
+	
+	writer.CacheControl = "public, max-age=3600" // Cache for 1 hour
+	
+	return writer, nil
+}
+
+// Delete removes an object
+func (g *GCSClient) Delete(ctx context.Context, filePath string) error {
+	err := g.client.Bucket(g.bucket).Object(filePath).Delete(ctx)
+	if err != nil && err != storage.ErrObjectNotExist {
+		return fmt.Errorf("failed to delete file %s: %w", filePath, err)
+	}
+	return nil
+}
@@ -2,8 +2,21 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"io"
+	"time"
 )
 
+// ErrPreconditionFailed is returned by StoreFileIfMatch when the file changed since it was read
+var ErrPreconditionFailed = errors.New("storage precondition failed")
+
+// FileInfo describes a stored file
+type FileInfo struct {
+	Size    int64
+	ModTime time.Time
+	ETag    string // opaque version that changes whenever the content changes
+}
+
 // StorageClient defines the interface for basic storage operations
 type StorageClient interface {
 	// Close closes the storage client
@@ -15,6 +28,11 @@ type StorageClient interface {
 	// StoreFile stores a file at the specified path
 	StoreFile(ctx context.Context, filePath string, fileData []byte) error
 	
+	// StoreFileIfMatch stores a file only if its current ETag (see Stat) is etag or, with an
+	// empty etag, only if it does not exist yet. Otherwise nothing is written and the error
+	// matches ErrPreconditionFailed, so concurrent read-modify-write cycles can retry.
+	StoreFileIfMatch(ctx context.Context, filePath string, fileData []byte, etag string) error
+	
 	// GetFile retrieves a file from the specified path
 	GetFile(ctx context.Context, filePath string) ([]byte, error)
 	
@@ -23,4 +41,23 @@ type StorageClient interface {
 	
 	// FileExists checks if a file exists at the specified path
 	FileExists(ctx context.Context, filePath string) (bool, error)
+	
+	// Stat returns the size, modification time and ETag of a file; the error matches
+	// os.ErrNotExist (errors.Is) when the file does not exist
+	Stat(ctx context.Context, filePath string) (*FileInfo, error)
+	
+	// OpenRange opens a file for reading length bytes starting at offset; a negative
+	// length reads to the end of the file
+	OpenRange(ctx context.Context, filePath string, offset, length int64) (io.ReadCloser, error)
+	
+	// OpenReader opens a file for streaming reads; the error matches os.ErrNotExist when the
+	// file does not exist
+	OpenReader(ctx context.Context, filePath string) (io.ReadCloser, error)
+	
+	// OpenWriter opens a file for streaming writes. The file only appears once Close returns
+	// nil; cancelling ctx before Close discards everything written.
+	OpenWriter(ctx context.Context, filePath string) (io.WriteCloser, error)
+	
+	// Delete removes a file; deleting a file that does not exist is not an error
+	Delete(ctx context.Context, filePath string) error
 }
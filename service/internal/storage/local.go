@@ -2,9 +2,14 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 // LocalStorageClient handles local file system storage operations
@@ -108,6 +113,30 @@ func (l *LocalStorageClient) ListDir(ctx context.Context, dirPath string, recurs
 	return files, nil
 }
 
+// localConditionalWrites serializes StoreFileIfMatch; local storage is used by a single process
+var localConditionalWrites sync.Mutex
+
+// StoreFileIfMatch stores a file if its ETag is still etag, replacing it atomically
+func (l *LocalStorageClient) StoreFileIfMatch(ctx context.Context, filePath string, fileData []byte, etag string) error {
+	localConditionalWrites.Lock()
+	defer localConditionalWrites.Unlock()
+
+	current := ""
+	info, err := l.Stat(ctx, filePath)
+	if err == nil {
+		current = info.ETag
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if current != etag {
+		return fmt.Errorf("file %s: %w", filePath, ErrPreconditionFailed)
+	}
+	return StreamFile(ctx, l, filePath, func(w io.Writer) error {
+		_, err := w.Write(fileData)
+		return err
+	})
+}
+
 // FileExists checks if a file exists at the specified path
 func (l *LocalStorageClient) FileExists(ctx context.Context, filePath string) (bool, error) {
 	fullPath := filepath.Join(l.rootDir, filePath)
@@ -121,4 +150,114 @@ func (l *LocalStorageClient) FileExists(ctx context.Context, filePath string) (b
 	return true, nil
 }
 
+// Stat returns the size, modification time and ETag of a file
+func (l *LocalStorageClient) Stat(ctx context.Context, filePath string) (*FileInfo, error) {
+	fullPath := filepath.Join(l.rootDir, filePath)
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file %s: %w", fullPath, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory: %w", fullPath, os.ErrNotExist)
+	}
+	return &FileInfo{
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		ETag:    strconv.FormatInt(info.ModTime().UnixNano(), 36) + "-" + strconv.FormatInt(info.Size(), 36),
+	}, nil
+}
+
+// OpenRange opens a file for reading length bytes starting at offset
+func (l *LocalStorageClient) OpenRange(ctx context.Context, filePath string, offset, length int64) (io.ReadCloser, error) {
+	fullPath := filepath.Join(l.rootDir, filePath)
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", fullPath, err)
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to seek file %s: %w", fullPath, err)
+	}
+	if length < 0 {
+		return file, nil
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(file, length), file}, nil
+}
+
+// OpenReader opens a file for streaming reads
+func (l *LocalStorageClient) OpenReader(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	fullPath := filepath.Join(l.rootDir, filePath)
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", fullPath, err)
+	}
+	return file, nil
+}
+
+// OpenWriter opens a file for streaming writes. Data goes to a temporary file in the same
+// directory that is renamed into place on Close, so readers never see a partial file.
+func (l *LocalStorageClient) OpenWriter(ctx context.Context, filePath string) (io.WriteCloser, error) {
+	fullPath := filepath.Join(l.rootDir, filePath)
+	dir := filepath.Dir(fullPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	file, err := os.CreateTemp(dir, "."+filepath.Base(fullPath)+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file %s: %w", fullPath, err)
+	}
+	return &localWriter{ctx: ctx, file: file, path: fullPath}, nil
+}
+
+// localWriter commits its temporary file on Close unless the context was cancelled
+type localWriter struct {
+	ctx    context.Context
+	file   *os.File
+	path   string
+	closed bool
+}
+
+func (w *localWriter) Write(p []byte) (int, error) {
+	return w.file.Write(p)
+}
+
+func (w *localWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	err := w.file.Close()
+	if err == nil {
+		err = w.ctx.Err()
+	}
+	if err == nil {
+		err = os.Chmod(w.file.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(w.file.Name(), w.path)
+	}
+	if err != nil {
+		os.Remove(w.file.Name())
+		return fmt.Errorf("failed to write file %s: %w", w.path, err)
+	}
+	return nil
+}
 
+// Delete removes a file along with the directories it leaves empty
+func (l *LocalStorageClient) Delete(ctx context.Context, filePath string) error {
+	fullPath := filepath.Join(l.rootDir, filePath)
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file %s: %w", fullPath, err)
+	}
+	// Object stores have no empty directories, so don't leave any behind either
+	for dir := filepath.Dir(fullPath); dir != l.rootDir && strings.HasPrefix(dir, l.rootDir+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
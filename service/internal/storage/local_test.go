@@ -2,6 +2,8 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -328,6 +330,203 @@ func TestLocalStorageClient_FileExists(t *testing.T) {
 	}
 }
 
+func TestLocalStorageClient_Stat(t *testing.T) {
+	originalDir, _ := os.Getwd()
+	tempDir := t.TempDir()
+	os.Chdir(tempDir)
+	defer os.Chdir(originalDir)
+
+	client, err := NewLocalStorageClient("")
+	if err != nil {
+		t.Fatalf("Failed to create LocalStorageClient: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.StoreFile(ctx, "reports/a/sun.gif", []byte("GIF89a")); err != nil {
+		t.Fatalf("Failed to store test file: %v", err)
+	}
+
+	info, err := client.Stat(ctx, "reports/a/sun.gif")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Size != 6 || info.ETag == "" || info.ModTime.IsZero() {
+		t.Errorf("Stat() = %+v, want size 6 with ETag and ModTime", info)
+	}
+
+	// Rewriting the file changes the ETag
+	if err := client.StoreFile(ctx, "reports/a/sun.gif", []byte("GIF89a!")); err != nil {
+		t.Fatalf("Failed to rewrite test file: %v", err)
+	}
+	if updated, _ := client.Stat(ctx, "reports/a/sun.gif"); updated == nil || updated.ETag == info.ETag {
+		t.Errorf("Expected a new ETag after rewrite, got %+v", updated)
+	}
+
+	for _, missing := range []string{"reports/a/missing.gif", "reports/a"} {
+		if _, err := client.Stat(ctx, missing); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Stat(%s) error = %v, want os.ErrNotExist", missing, err)
+		}
+	}
+}
+
+func TestLocalStorageClient_OpenRange(t *testing.T) {
+	originalDir, _ := os.Getwd()
+	tempDir := t.TempDir()
+	os.Chdir(tempDir)
+	defer os.Chdir(originalDir)
+
+	client, err := NewLocalStorageClient("")
+	if err != nil {
+		t.Fatalf("Failed to create LocalStorageClient: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.StoreFile(ctx, "video.mp4", []byte("0123456789")); err != nil {
+		t.Fatalf("Failed to store test file: %v", err)
+	}
+
+	tests := []struct {
+		offset, length int64
+		want           string
+	}{
+		{0, -1, "0123456789"},
+		{3, 4, "3456"},
+		{7, -1, "789"},
+		{8, 10, "89"},
+	}
+	for _, tt := range tests {
+		reader, err := client.OpenRange(ctx, "video.mp4", tt.offset, tt.length)
+		if err != nil {
+			t.Fatalf("OpenRange(%d, %d) error = %v", tt.offset, tt.length, err)
+		}
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil || string(data) != tt.want {
+			t.Errorf("OpenRange(%d, %d) = %q, %v; want %q", tt.offset, tt.length, data, err, tt.want)
+		}
+	}
+
+	if _, err := client.OpenRange(ctx, "missing.mp4", 0, -1); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenRange on a missing file: error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestLocalStorageClient_OpenWriter(t *testing.T) {
+	originalDir, _ := os.Getwd()
+	tempDir := t.TempDir()
+	os.Chdir(tempDir)
+	defer os.Chdir(originalDir)
+
+	client, err := NewLocalStorageClient("")
+	if err != nil {
+		t.Fatalf("Failed to create LocalStorageClient: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	writer, err := client.OpenWriter(ctx, "reports/a/report.pdf")
+	if err != nil {
+		t.Fatalf("OpenWriter() error = %v", err)
+	}
+	io.WriteString(writer, "%PDF-1.4 ")
+	io.WriteString(writer, "%%EOF")
+
+	// Nothing is visible until Close
+	if exists, _ := client.FileExists(ctx, "reports/a/report.pdf"); exists {
+		t.Error("File visible before Close")
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reader, err := client.OpenReader(ctx, "reports/a/report.pdf")
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil || string(data) != "%PDF-1.4 %%EOF" {
+		t.Errorf("OpenReader() = %q, %v; want %q", data, err, "%PDF-1.4 %%EOF")
+	}
+
+	// No temporary files are left behind
+	if files, _ := client.ListDir(ctx, "reports/a", false); len(files) != 1 {
+		t.Errorf("ListDir() = %v, want only report.pdf", files)
+	}
+
+	if _, err := client.OpenReader(ctx, "reports/a/missing.pdf"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenReader on a missing file: error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestLocalStorageClient_OpenWriterCancelled(t *testing.T) {
+	originalDir, _ := os.Getwd()
+	tempDir := t.TempDir()
+	os.Chdir(tempDir)
+	defer os.Chdir(originalDir)
+
+	client, err := NewLocalStorageClient("")
+	if err != nil {
+		t.Fatalf("Failed to create LocalStorageClient: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	writer, err := client.OpenWriter(ctx, "reports/a/sun.gif")
+	if err != nil {
+		t.Fatalf("OpenWriter() error = %v", err)
+	}
+	io.WriteString(writer, "GIF8")
+	cancel()
+	if err := writer.Close(); err == nil {
+		t.Error("Close() after cancel: expected error")
+	}
+
+	if files, _ := client.ListDir(context.Background(), "reports/a", false); len(files) != 0 {
+		t.Errorf("ListDir() = %v, want no files after a cancelled write", files)
+	}
+}
+
+func TestStreamFile(t *testing.T) {
+	originalDir, _ := os.Getwd()
+	tempDir := t.TempDir()
+	os.Chdir(tempDir)
+	defer os.Chdir(originalDir)
+
+	client, err := NewLocalStorageClient("")
+	if err != nil {
+		t.Fatalf("Failed to create LocalStorageClient: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	err = StreamFile(ctx, client, "reports/a/report.html", func(w io.Writer) error {
+		_, err := io.WriteString(w, "<html></html>")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("StreamFile() error = %v", err)
+	}
+	if data, _ := client.GetFile(ctx, "reports/a/report.html"); string(data) != "<html></html>" {
+		t.Errorf("GetFile() = %q, want %q", data, "<html></html>")
+	}
+
+	// A failing producer leaves the previous content in place
+	failure := errors.New("encoder failed")
+	err = StreamFile(ctx, client, "reports/a/report.html", func(w io.Writer) error {
+		io.WriteString(w, "<ht")
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("StreamFile() error = %v, want %v", err, failure)
+	}
+	if data, _ := client.GetFile(ctx, "reports/a/report.html"); string(data) != "<html></html>" {
+		t.Errorf("GetFile() after failed write = %q, want %q", data, "<html></html>")
+	}
+}
+
 func TestLocalStorageClient_ListDir(t *testing.T) {
 	originalDir, _ := os.Getwd()
 	tempDir := t.TempDir()
@@ -0,0 +1,291 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInjectedFault is returned by a MemoryStorageClient write failed by fault injection
+var ErrInjectedFault = errors.New("injected storage fault")
+
+// MemoryFaults configures the faults injected by a MemoryStorageClient
+type MemoryFaults struct {
+	FailWrite int           // fail the Nth write from now (1 = the next one); 0 disables
+	Latency   time.Duration // delay added to every operation
+}
+
+// MemoryStorageClient keeps files in memory, for tests and ephemeral runs.
+// It is safe for concurrent use and mirrors the path semantics of LocalStorageClient.
+type MemoryStorageClient struct {
+	mu         sync.RWMutex
+	files      map[string]memoryFile
+	generation int64
+	faults     MemoryFaults
+	writes     int              // writes since the faults were injected
+	now        func() time.Time // stamps modification times
+}
+
+type memoryFile struct {
+	data       []byte
+	modTime    time.Time
+	generation int64
+}
+
+// NewMemoryStorageClient creates an empty in-memory storage client
+func NewMemoryStorageClient() *MemoryStorageClient {
+	return &MemoryStorageClient{files: make(map[string]memoryFile), now: time.Now}
+}
+
+// SetClock replaces the clock that stamps modification times, e.g. to age files in tests
+func (m *MemoryStorageClient) SetClock(now func() time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+}
+
+// InjectFaults replaces the injected faults and restarts the write count
+func (m *MemoryStorageClient) InjectFaults(faults MemoryFaults) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.faults = faults
+	m.writes = 0
+}
+
+// Close is a no-op for memory storage
+func (m *MemoryStorageClient) Close() error {
+	return nil
+}
+
+// CreateDir is a no-op as directories are implicit
+func (m *MemoryStorageClient) CreateDir(ctx context.Context, dirPath string) error {
+	return m.delay(ctx)
+}
+
+// StoreFile stores a copy of fileData at the specified path
+func (m *MemoryStorageClient) StoreFile(ctx context.Context, filePath string, fileData []byte) error {
+	if err := m.delay(ctx); err != nil {
+		return err
+	}
+	return m.commit(filePath, bytes.Clone(fileData))
+}
+
+// StoreFileIfMatch stores a copy of fileData if the file's generation is still etag
+func (m *MemoryStorageClient) StoreFileIfMatch(ctx context.Context, filePath string, fileData []byte, etag string) error {
+	if err := m.delay(ctx); err != nil {
+		return err
+	}
+	return m.commitIf(filePath, bytes.Clone(fileData), func(file memoryFile, exists bool) bool {
+		if !exists {
+			return etag == ""
+		}
+		return etag == strconv.FormatInt(file.generation, 10)
+	})
+}
+
+// GetFile retrieves a copy of a file
+func (m *MemoryStorageClient) GetFile(ctx context.Context, filePath string) ([]byte, error) {
+	file, err := m.get(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(file.data), nil
+}
+
+// ListDir lists contents of a directory like LocalStorageClient: recursively every file below
+// dirPath, otherwise its files and subdirectories
+func (m *MemoryStorageClient) ListDir(ctx context.Context, dirPath string, recursive bool) ([]string, error) {
+	if err := m.delay(ctx); err != nil {
+		return nil, err
+	}
+	prefix := memoryKey(dirPath)
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var files []string
+	seen := make(map[string]bool)
+	for key := range m.files {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if recursive {
+			files = append(files, key)
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(key, prefix), "/")
+		if !seen[name] {
+			seen[name] = true
+			files = append(files, path.Join(dirPath, name))
+		}
+	}
+	if !recursive && len(files) == 0 && prefix != "" {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, os.ErrNotExist)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// FileExists checks if a file exists at the specified path
+func (m *MemoryStorageClient) FileExists(ctx context.Context, filePath string) (bool, error) {
+	_, err := m.get(ctx, filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Stat returns the size, modification time and ETag of a file
+func (m *MemoryStorageClient) Stat(ctx context.Context, filePath string) (*FileInfo, error) {
+	file, err := m.get(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return &FileInfo{
+		Size:    int64(len(file.data)),
+		ModTime: file.modTime,
+		ETag:    strconv.FormatInt(file.generation, 10),
+	}, nil
+}
+
+// OpenRange opens a file for reading length bytes starting at offset
+func (m *MemoryStorageClient) OpenRange(ctx context.Context, filePath string, offset, length int64) (io.ReadCloser, error) {
+	file, err := m.get(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	data := file.data[min(offset, int64(len(file.data))):]
+	if length >= 0 && length < int64(len(data)) {
+		data = data[:length]
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// OpenReader opens a file for streaming reads
+func (m *MemoryStorageClient) OpenReader(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	return m.OpenRange(ctx, filePath, 0, -1)
+}
+
+// OpenWriter opens a file for streaming writes; the file is stored on Close
+func (m *MemoryStorageClient) OpenWriter(ctx context.Context, filePath string) (io.WriteCloser, error) {
+	if err := m.delay(ctx); err != nil {
+		return nil, err
+	}
+	return &memoryWriter{ctx: ctx, client: m, path: filePath}, nil
+}
+
+// Delete removes a file
+func (m *MemoryStorageClient) Delete(ctx context.Context, filePath string) error {
+	if err := m.delay(ctx); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.files, memoryKey(filePath))
+	return nil
+}
+
+// Files returns the paths of all stored files, sorted
+func (m *MemoryStorageClient) Files() []string {
+	files, _ := m.ListDir(context.Background(), "", true)
+	return files
+}
+
+// get returns a file; stored data is never modified in place, so it can be shared
+func (m *MemoryStorageClient) get(ctx context.Context, filePath string) (memoryFile, error) {
+	if err := m.delay(ctx); err != nil {
+		return memoryFile{}, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	file, ok := m.files[memoryKey(filePath)]
+	if !ok {
+		return memoryFile{}, fmt.Errorf("file %s: %w", filePath, os.ErrNotExist)
+	}
+	return file, nil
+}
+
+// commit stores data unless fault injection fails the write
+func (m *MemoryStorageClient) commit(filePath string, data []byte) error {
+	return m.commitIf(filePath, data, nil)
+}
+
+// commitIf is commit that only writes if match (when set) accepts the current file
+func (m *MemoryStorageClient) commitIf(filePath string, data []byte, match func(file memoryFile, exists bool) bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writes++
+	if m.faults.FailWrite > 0 && m.writes == m.faults.FailWrite {
+		return fmt.Errorf("failed to write file %s: %w", filePath, ErrInjectedFault)
+	}
+	if match != nil {
+		if file, exists := m.files[memoryKey(filePath)]; !match(file, exists) {
+			return fmt.Errorf("file %s: %w", filePath, ErrPreconditionFailed)
+		}
+	}
+	m.generation++
+	m.files[memoryKey(filePath)] = memoryFile{data: data, modTime: m.now(), generation: m.generation}
+	return nil
+}
+
+// delay waits for the injected latency
+func (m *MemoryStorageClient) delay(ctx context.Context) error {
+	m.mu.RLock()
+	latency := m.faults.Latency
+	m.mu.RUnlock()
+	if latency <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// memoryKey cleans a path the way filepath.Join does for the local client
+func memoryKey(filePath string) string {
+	return strings.TrimPrefix(path.Clean("/"+filePath), "/")
+}
+
+// memoryWriter buffers a file until Close
+type memoryWriter struct {
+	ctx    context.Context
+	client *MemoryStorageClient
+	path   string
+	buf    bytes.Buffer
+	closed bool
+}
+
+func (w *memoryWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fmt.Errorf("write to closed file %s", w.path)
+	}
+	return w.buf.Write(p)
+}
+
+func (w *memoryWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.client.delay(w.ctx); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", w.path, err)
+	}
+	return w.client.commit(w.path, w.buf.Bytes())
+}
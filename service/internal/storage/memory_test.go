@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoryStorageClient_Isolation(t *testing.T) {
+	client := NewMemoryStorageClient()
+	ctx := context.Background()
+
+	// Neither the stored slice nor a returned one aliases the stored file
+	data := []byte("GIF89a")
+	if err := client.StoreFile(ctx, "sun.gif", data); err != nil {
+		t.Fatalf("StoreFile() error = %v", err)
+	}
+	data[0] = 'X'
+	got, _ := client.GetFile(ctx, "sun.gif")
+	got[1] = 'X'
+	if again, _ := client.GetFile(ctx, "sun.gif"); string(again) != "GIF89a" {
+		t.Errorf("GetFile() = %q, want %q", again, "GIF89a")
+	}
+
+	// Paths are cleaned like the local client cleans them
+	if exists, _ := client.FileExists(ctx, "/./sun.gif"); !exists {
+		t.Error("FileExists(/./sun.gif) = false, want true")
+	}
+	if files := client.Files(); strings.Join(files, ",") != "sun.gif" {
+		t.Errorf("Files() = %v, want [sun.gif]", files)
+	}
+
+	if _, err := client.ListDir(ctx, "missing", false); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ListDir(missing) error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestMemoryStorageClient_FailWrite(t *testing.T) {
+	client := NewMemoryStorageClient()
+	ctx := context.Background()
+	client.InjectFaults(MemoryFaults{FailWrite: 2})
+
+	if err := client.StoreFile(ctx, "a.json", []byte("{}")); err != nil {
+		t.Fatalf("First write failed: %v", err)
+	}
+
+	// The second write fails, whether whole or streamed
+	err := StreamFile(ctx, client, "b.pdf", func(w io.Writer) error {
+		_, err := io.WriteString(w, "%PDF")
+		return err
+	})
+	if !errors.Is(err, ErrInjectedFault) {
+		t.Errorf("Second write error = %v, want ErrInjectedFault", err)
+	}
+	if exists, _ := client.FileExists(ctx, "b.pdf"); exists {
+		t.Error("Failed write created the file")
+	}
+
+	if err := client.StoreFile(ctx, "c.json", []byte("{}")); err != nil {
+		t.Errorf("Third write failed: %v", err)
+	}
+	if files := client.Files(); strings.Join(files, ",") != "a.json,c.json" {
+		t.Errorf("Files() = %v, want [a.json c.json]", files)
+	}
+}
+
+func TestMemoryStorageClient_Latency(t *testing.T) {
+	client := NewMemoryStorageClient()
+	client.InjectFaults(MemoryFaults{Latency: 20 * time.Millisecond})
+
+	start := time.Now()
+	if err := client.StoreFile(context.Background(), "a.json", []byte("{}")); err != nil {
+		t.Fatalf("StoreFile() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("StoreFile() took %v, want at least 20ms", elapsed)
+	}
+
+	// Latency honours the context deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetFile(ctx, "a.json"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetFile() error = %v, want context.DeadlineExceeded", err)
+	}
+}
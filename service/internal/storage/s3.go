@@ -0,0 +1,607 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"radiocast/internal/logger"
+)
+
+// S3Config configures an S3-compatible storage client
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.us-west-004.backblazeb2.com; empty for AWS
+	Region          string
+	Bucket          string
+	PathStyle       bool // address the bucket in the URL path (MinIO) instead of the host name
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3Client handles Amazon S3 and S3-compatible storage (MinIO, Backblaze B2, Wasabi...)
+// Uses bucket as the root for all operations; requests are signed with AWS Signature Version 4
+type S3Client struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+	partSize int // multipart upload part size of OpenWriter
+	now      func() time.Time
+}
+
+// s3PartSize is the part size of streamed uploads; S3 requires at least 5 MiB for all but the last part
+const s3PartSize = 8 << 20
+
+// s3EmptyHash is the SHA-256 of an empty payload
+const s3EmptyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// NewS3Client creates a new S3 client. Without an access key, requests are sent unsigned.
+func NewS3Client(cfg S3Config) (*S3Client, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket is required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+
+	return &S3Client{
+		cfg:      cfg,
+		endpoint: u,
+		client:   &http.Client{},
+		partSize: s3PartSize,
+		now:      time.Now,
+	}, nil
+}
+
+// Close releases idle connections
+func (s *S3Client) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// CreateDir is a no-op for S3 as directories are implicit
+func (s *S3Client) CreateDir(ctx context.Context, dirPath string) error {
+	return nil
+}
+
+// StoreFile stores a file at the specified path
+func (s *S3Client) StoreFile(ctx context.Context, filePath string, fileData []byte) error {
+	logger.Debugf("Storing file to S3: s3://%s/%s", s.cfg.Bucket, filePath)
+	if err := s.putObject(ctx, filePath, fileData); err != nil {
+		return err
+	}
+	logger.Debugf("File successfully stored: %s", filePath)
+	return nil
+}
+
+// StoreFileIfMatch stores a file with a conditional PUT (If-Match, or If-None-Match: * to create)
+func (s *S3Client) StoreFileIfMatch(ctx context.Context, filePath string, fileData []byte, etag string) error {
+	header := s.objectHeader(filePath)
+	if etag == "" {
+		header.Set("If-None-Match", "*")
+	} else {
+		header.Set("If-Match", `"`+etag+`"`)
+	}
+	resp, err := s.do(ctx, http.MethodPut, filePath, nil, header, fileData)
+	if err != nil {
+		return fmt.Errorf("failed to write file to S3: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// GetFile retrieves a file from the specified path
+func (s *S3Client) GetFile(ctx context.Context, filePath string) ([]byte, error) {
+	reader, err := s.OpenReader(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	fileData, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+	return fileData, nil
+}
+
+// s3ListResult is the response of ListObjectsV2
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// ListDir lists contents of a directory; without recursion, subdirectories are listed
+// like the local client does (without a trailing slash)
+func (s *S3Client) ListDir(ctx context.Context, dirPath string, recursive bool) ([]string, error) {
+	// Ensure dirPath ends with / for prefix matching
+	prefix := dirPath
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	if !recursive {
+		query.Set("delimiter", "/")
+	}
+
+	var files []string
+	for {
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode object listing: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			if object.Key != prefix { // Skip the directory placeholder itself
+				files = append(files, object.Key)
+			}
+		}
+		for _, p := range result.CommonPrefixes {
+			files = append(files, strings.TrimSuffix(p.Prefix, "/"))
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+
+	return files, nil
+}
+
+// FileExists checks if a file exists at the specified path
+func (s *S3Client) FileExists(ctx context.Context, filePath string) (bool, error) {
+	_, err := s.Stat(ctx, filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check file existence %s: %w", filePath, err)
+	}
+	return true, nil
+}
+
+// Stat returns the size, modification time and ETag of an object
+func (s *S3Client) Stat(ctx context.Context, filePath string) (*FileInfo, error) {
+	resp, err := s.do(ctx, http.MethodHead, filePath, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return &FileInfo{
+		Size:    resp.ContentLength,
+		ModTime: modTime,
+		ETag:    strings.Trim(resp.Header.Get("ETag"), `"`),
+	}, nil
+}
+
+// OpenRange opens an object for reading length bytes starting at offset
+func (s *S3Client) OpenRange(ctx context.Context, filePath string, offset, length int64) (io.ReadCloser, error) {
+	if length == 0 {
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+	header := http.Header{}
+	if length < 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	} else {
+		header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, filePath, nil, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusPartialContent {
+		return resp.Body, nil
+	}
+
+	// The service ignored the range and sent the whole object
+	if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to seek file %s: %w", filePath, err)
+	}
+	if length < 0 {
+		return resp.Body, nil
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(resp.Body, length), resp.Body}, nil
+}
+
+// OpenReader opens an object for streaming reads
+func (s *S3Client) OpenReader(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, filePath, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// OpenWriter opens a streaming upload of an object. Small objects are sent with a single PUT on
+// Close; larger ones become a multipart upload holding at most one part in memory.
+func (s *S3Client) OpenWriter(ctx context.Context, filePath string) (io.WriteCloser, error) {
+	return &s3Writer{ctx: ctx, s3: s, key: filePath}, nil
+}
+
+// Delete removes an object
+func (s *S3Client) Delete(ctx context.Context, filePath string) error {
+	resp, err := s.do(ctx, http.MethodDelete, filePath, nil, nil, nil)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete file %s: %w", filePath, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// putObject uploads an object with a single request
+func (s *S3Client) putObject(ctx context.Context, key string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, nil, s.objectHeader(key), data)
+	if err != nil {
+		return fmt.Errorf("failed to write file to S3: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// objectHeader returns the metadata headers of a new object
+func (s *S3Client) objectHeader(key string) http.Header {
+	header := http.Header{}
+	header.Set("Content-Type", GetContentType(key))
+	header.Set("Cache-Control", "public, max-age=3600") // Cache for 1 hour
+	return header
+}
+
+// s3Writer buffers one part at a time and switches to a multipart upload when it fills up
+type s3Writer struct {
+	ctx      context.Context
+	s3       *S3Client
+	key      string
+	buf      []byte
+	uploadID string
+	parts    []s3CompletedPart
+	err      error
+	closed   bool
+}
+
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), w.s3.partSize-len(w.buf))
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+		if len(w.buf) == w.s3.partSize {
+			if err := w.uploadPart(); err != nil {
+				w.err = err
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (w *s3Writer) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+
+	if w.err == nil {
+		w.err = w.ctx.Err()
+	}
+	if w.err == nil {
+		if w.uploadID == "" {
+			w.err = w.s3.putObject(w.ctx, w.key, w.buf)
+		} else if len(w.buf) > 0 {
+			w.err = w.uploadPart()
+		}
+	}
+	if w.err == nil && w.uploadID != "" {
+		w.err = w.complete()
+	}
+	if w.err != nil {
+		w.abort()
+		return fmt.Errorf("failed to upload file %s: %w", w.key, w.err)
+	}
+	return nil
+}
+
+// uploadPart sends the buffered data as the next part, starting the multipart upload if needed
+func (w *s3Writer) uploadPart() error {
+	if w.uploadID == "" {
+		resp, err := w.s3.do(w.ctx, http.MethodPost, w.key, url.Values{"uploads": {""}}, w.s3.objectHeader(w.key), nil)
+		if err != nil {
+			return fmt.Errorf("failed to start multipart upload: %w", err)
+		}
+		var result struct {
+			UploadID string `xml:"UploadId"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil || result.UploadID == "" {
+			return fmt.Errorf("invalid multipart upload response: %v", err)
+		}
+		w.uploadID = result.UploadID
+	}
+
+	number := len(w.parts) + 1
+	query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {w.uploadID}}
+	resp, err := w.s3.do(w.ctx, http.MethodPut, w.key, query, nil, w.buf)
+	if err != nil {
+		return fmt.Errorf("failed to upload part %d: %w", number, err)
+	}
+	resp.Body.Close()
+	w.parts = append(w.parts, s3CompletedPart{PartNumber: number, ETag: resp.Header.Get("ETag")})
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// complete assembles the uploaded parts into the object
+func (w *s3Writer) complete() error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletedPart `xml:"Part"`
+	}{Parts: w.parts})
+	if err != nil {
+		return err
+	}
+	resp, err := w.s3.do(w.ctx, http.MethodPost, w.key, url.Values{"uploadId": {w.uploadID}}, nil, body)
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// S3 may report a failure in the body of a 200 response
+	data, _ := io.ReadAll(resp.Body)
+	if apiErr := parseS3Error(data); apiErr != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", apiErr)
+	}
+	return nil
+}
+
+// abort discards the parts of a failed multipart upload
+func (w *s3Writer) abort() {
+	if w.uploadID == "" {
+		return
+	}
+	resp, err := w.s3.do(context.WithoutCancel(w.ctx), http.MethodDelete, w.key, url.Values{"uploadId": {w.uploadID}}, nil, nil)
+	if err != nil {
+		logger.Warn("Failed to abort S3 multipart upload", map[string]interface{}{"key": w.key, "error": err.Error()})
+		return
+	}
+	resp.Body.Close()
+}
+
+// s3Error is the error document returned by S3
+type s3Error struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+func (e *s3Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// parseS3Error returns the error described by an S3 error document, or nil
+func parseS3Error(data []byte) *s3Error {
+	var e s3Error
+	if xml.Unmarshal(data, &e) != nil || e.Code == "" {
+		return nil
+	}
+	return &e
+}
+
+// do sends a signed request and returns the response if it succeeded. A missing object yields
+// an error matching os.ErrNotExist.
+func (s *S3Client) do(ctx context.Context, method, key string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	u := s.objectURL(key, query)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	payloadHash := s3EmptyHash
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+	s.sign(req, payloadHash, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	apiErr := parseS3Error(data)
+	if resp.StatusCode == http.StatusNotFound && (apiErr == nil || apiErr.Code == "NoSuchKey") {
+		return nil, fmt.Errorf("file %s: %w", key, os.ErrNotExist)
+	}
+	// 412 for a failed If-Match/If-None-Match, 409 when a concurrent conditional write won
+	if resp.StatusCode == http.StatusPreconditionFailed || (resp.StatusCode == http.StatusConflict && apiErr != nil && apiErr.Code == "ConditionalRequestConflict") {
+		return nil, fmt.Errorf("file %s: %w", key, ErrPreconditionFailed)
+	}
+	if apiErr != nil {
+		return nil, fmt.Errorf("%s %s: %w (HTTP %d)", method, key, apiErr, resp.StatusCode)
+	}
+	return nil, fmt.Errorf("%s %s: HTTP %d", method, key, resp.StatusCode)
+}
+
+// objectURL returns the URL of an object in path style (endpoint/bucket/key) or virtual-hosted
+// style (bucket.endpoint/key)
+func (s *S3Client) objectURL(key string, query url.Values) *url.URL {
+	u := *s.endpoint
+	p := strings.TrimSuffix(u.Path, "/")
+	if s.cfg.PathStyle {
+		p += "/" + s.cfg.Bucket
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+	p += "/" + key
+
+	u.Path = p
+	u.RawPath = s3Escape(p, false)
+	u.RawQuery = s3CanonicalQuery(query)
+	return &u
+}
+
+// sign adds the AWS Signature Version 4 headers to req
+func (s *S3Client) sign(req *http.Request, payloadHash string, now time.Time) {
+	if s.cfg.AccessKeyID == "" {
+		return // anonymous access
+	}
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+
+	canonical, signedHeaders := s3CanonicalRequest(req.Method, req.URL.EscapedPath(), req.URL.RawQuery, req.URL.Host, req.Header, payloadHash)
+	scope := amzDate[:8] + "/" + s.cfg.Region + "/s3/aws4_request"
+	signature := s3Signature(s.cfg.SecretAccessKey, s.cfg.Region, amzDate, canonical)
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.cfg.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// s3CanonicalRequest builds the canonical request of Signature Version 4. The host, range,
+// content and x-amz-* headers are signed.
+func s3CanonicalRequest(method, escapedPath, rawQuery, host string, header http.Header, payloadHash string) (canonical, signedHeaders string) {
+	values := map[string]string{"host": host}
+	for name, v := range header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "range" || name == "content-type" || name == "content-md5" {
+			trimmed := make([]string, len(v))
+			for i := range v {
+				trimmed[i] = strings.Join(strings.Fields(v[i]), " ")
+			}
+			values[name] = strings.Join(trimmed, ",")
+		}
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(method + "\n" + escapedPath + "\n" + rawQuery + "\n")
+	for _, name := range names {
+		b.WriteString(name + ":" + values[name] + "\n")
+	}
+	signedHeaders = strings.Join(names, ";")
+	b.WriteString("\n" + signedHeaders + "\n" + payloadHash)
+	return b.String(), signedHeaders
+}
+
+// s3Signature signs a canonical request for S3 in region at amzDate (YYYYMMDDTHHMMSSZ)
+func s3Signature(secret, region, amzDate, canonical string) string {
+	hash := sha256.Sum256([]byte(canonical))
+	scope := amzDate[:8] + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secret), amzDate[:8])
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3CanonicalQuery encodes query parameters sorted by name, as Signature Version 4 requires
+func s3CanonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, s3Escape(name, true)+"="+s3Escape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape percent-encodes everything except unreserved characters (and slashes unless
+// escapeSlash is set)
+func s3Escape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !escapeSlash) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
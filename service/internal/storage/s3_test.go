@@ -0,0 +1,504 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is an in-memory stand-in for an S3 bucket addressed in path style. It verifies the
+// Signature Version 4 of every request against the request it actually received.
+type fakeS3 struct {
+	t       *testing.T
+	bucket  string
+	secret  string
+	maxKeys int // page size of object listings
+
+	mu      sync.Mutex
+	objects map[string]fakeS3Object
+	uploads map[string]map[int][]byte
+	nextID  int
+}
+
+type fakeS3Object struct {
+	data        []byte
+	contentType string
+	modTime     time.Time
+}
+
+// etag is the quoted MD5 of the object, like S3 returns for single-part uploads
+func (o fakeS3Object) etag() string {
+	return fmt.Sprintf(`"%x"`, md5.Sum(o.data))
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{
+		t:       t,
+		bucket:  "radiocast",
+		secret:  "fake-secret",
+		maxKeys: 1000,
+		objects: make(map[string]fakeS3Object),
+		uploads: make(map[string]map[int][]byte),
+	}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+// newTestS3Client returns a client for the fake, or for a real S3-compatible service (e.g. MinIO)
+// when S3_TEST_ENDPOINT and S3_TEST_BUCKET are set along with the AWS_* credentials
+func newTestS3Client(t *testing.T) (*S3Client, *fakeS3) {
+	if endpoint := os.Getenv("S3_TEST_ENDPOINT"); endpoint != "" {
+		client, err := NewS3Client(S3Config{
+			Endpoint:        endpoint,
+			Region:          os.Getenv("S3_TEST_REGION"),
+			Bucket:          os.Getenv("S3_TEST_BUCKET"),
+			PathStyle:       true,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		})
+		if err != nil {
+			t.Fatalf("NewS3Client() error = %v", err)
+		}
+		client.partSize = 5 << 20
+		return client, nil
+	}
+
+	fake, server := newFakeS3(t)
+	client, err := NewS3Client(S3Config{
+		Endpoint:        server.URL,
+		Region:          "eu-central-1",
+		Bucket:          fake.bucket,
+		PathStyle:       true,
+		AccessKeyID:     "fake-key",
+		SecretAccessKey: fake.secret,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Client() error = %v", err)
+	}
+	client.partSize = 16
+	return client, fake
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f.verifySignature(r); err != nil {
+		f.t.Errorf("%s %s: %v", r.Method, r.URL, err)
+		f.fail(w, http.StatusForbidden, "SignatureDoesNotMatch")
+		return
+	}
+	prefix := "/" + f.bucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		f.fail(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+	query := r.URL.Query()
+	body, _ := io.ReadAll(r.Body)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && key == "" && query.Get("list-type") == "2":
+		f.list(w, query)
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		f.nextID++
+		id := "upload-" + strconv.Itoa(f.nextID)
+		f.uploads[id] = make(map[int][]byte)
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", id)
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		parts, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			f.fail(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		parts[number] = body
+		w.Header().Set("ETag", `"part-`+strconv.Itoa(number)+`"`)
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		f.complete(w, key, query.Get("uploadId"), body)
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(f.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		object, exists := f.objects[key]
+		if match := r.Header.Get("If-Match"); (match != "" && (!exists || match != object.etag())) ||
+			(r.Header.Get("If-None-Match") == "*" && exists) {
+			f.fail(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		f.objects[key] = fakeS3Object{data: body, contentType: r.Header.Get("Content-Type"), modTime: time.Now()}
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		object, ok := f.objects[key]
+		if !ok {
+			f.fail(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Header().Set("ETag", object.etag())
+		http.ServeContent(w, r, key, object.modTime, bytes.NewReader(object.data))
+	default:
+		f.fail(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, query url.Values) {
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+	var keys []string
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	type entry struct {
+		Key    string `xml:"Key,omitempty"`
+		Prefix string `xml:"Prefix,omitempty"`
+	}
+	var result struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Contents              []entry  `xml:"Contents"`
+		CommonPrefixes        []entry  `xml:"CommonPrefixes"`
+		IsTruncated           bool     `xml:"IsTruncated"`
+		NextContinuationToken string   `xml:"NextContinuationToken,omitempty"`
+	}
+	seen := make(map[string]bool)
+	after := query.Get("continuation-token")
+	count := 0
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) || key <= after {
+			continue
+		}
+		if count == f.maxKeys {
+			result.IsTruncated = true
+			break
+		}
+		rest := strings.TrimPrefix(key, prefix)
+		if i := strings.Index(rest, delimiter); delimiter != "" && i >= 0 {
+			common := prefix + rest[:i+1]
+			if !seen[common] {
+				seen[common] = true
+				result.CommonPrefixes = append(result.CommonPrefixes, entry{Prefix: common})
+				count++
+			}
+		} else {
+			result.Contents = append(result.Contents, entry{Key: key})
+			count++
+		}
+		result.NextContinuationToken = key
+	}
+	if !result.IsTruncated {
+		result.NextContinuationToken = ""
+	}
+	xml.NewEncoder(w).Encode(result)
+}
+
+func (f *fakeS3) complete(w http.ResponseWriter, key, uploadID string, body []byte) {
+	parts, ok := f.uploads[uploadID]
+	if !ok {
+		f.fail(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+	var request struct {
+		Parts []s3CompletedPart `xml:"Part"`
+	}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		f.fail(w, http.StatusBadRequest, "MalformedXML")
+		return
+	}
+	var data []byte
+	for i, part := range request.Parts {
+		if part.PartNumber != i+1 || part.ETag != `"part-`+strconv.Itoa(i+1)+`"` {
+			f.fail(w, http.StatusBadRequest, "InvalidPart")
+			return
+		}
+		data = append(data, parts[part.PartNumber]...)
+	}
+	delete(f.uploads, uploadID)
+	f.objects[key] = fakeS3Object{data: data, contentType: GetContentType(key), modTime: time.Now()}
+	fmt.Fprint(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+}
+
+func (f *fakeS3) fail(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>fake S3 error</Message></Error>", code)
+}
+
+// verifySignature recomputes the signature from the request as received
+func (f *fakeS3) verifySignature(r *http.Request) error {
+	auth := r.Header.Get("Authorization")
+	var credential, signedHeaders, signature string
+	for _, field := range strings.Split(strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 "), ", ") {
+		name, value, _ := strings.Cut(field, "=")
+		switch name {
+		case "Credential":
+			credential = value
+		case "SignedHeaders":
+			signedHeaders = value
+		case "Signature":
+			signature = value
+		}
+	}
+	scope := strings.SplitN(credential, "/", 3)
+	if len(scope) != 3 {
+		return fmt.Errorf("malformed Authorization header %q", auth)
+	}
+	header := http.Header{}
+	for _, name := range strings.Split(signedHeaders, ";") {
+		if name != "host" {
+			header[name] = r.Header.Values(name)
+		}
+	}
+	canonical, _ := s3CanonicalRequest(r.Method, r.URL.EscapedPath(), r.URL.RawQuery, r.Host, header, r.Header.Get("X-Amz-Content-Sha256"))
+	region := strings.Split(scope[2], "/")[0]
+	if want := s3Signature(f.secret, region, r.Header.Get("X-Amz-Date"), canonical); signature != want {
+		return fmt.Errorf("signature %s, want %s for\n%s", signature, want, canonical)
+	}
+	return nil
+}
+
+func TestS3Signature(t *testing.T) {
+	// Examples from the AWS Signature Version 4 documentation for S3
+	secret := "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+	tests := []struct {
+		name   string
+		path   string
+		query  url.Values
+		header http.Header
+		want   string
+	}{
+		{
+			name:   "GET object with range",
+			path:   "/test.txt",
+			header: http.Header{"Range": {"bytes=0-9"}},
+			want:   "f0e8bdb87c964420e857bd35b5d6ed310bd44f0170aba48dd91039c6036bdb41",
+		},
+		{
+			name:  "GET bucket listing",
+			path:  "/",
+			query: url.Values{"max-keys": {"2"}, "prefix": {"J"}},
+			want:  "34b48302e7b5fa45bde8084f4b7868a86f0a534bc59db6670ed5711ef69dc6f7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{"X-Amz-Date": {"20130524T000000Z"}, "X-Amz-Content-Sha256": {s3EmptyHash}}
+			for name, values := range tt.header {
+				header[name] = values
+			}
+			canonical, _ := s3CanonicalRequest("GET", tt.path, s3CanonicalQuery(tt.query), "examplebucket.s3.amazonaws.com", header, s3EmptyHash)
+			if got := s3Signature(secret, "us-east-1", "20130524T000000Z", canonical); got != tt.want {
+				t.Errorf("s3Signature() = %s, want %s\ncanonical request:\n%s", got, tt.want, canonical)
+			}
+		})
+	}
+}
+
+func TestS3Client_ObjectURL(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  S3Config
+		key  string
+		want string
+	}{
+		{"AWS default endpoint", S3Config{Bucket: "radiocast", Region: "eu-west-1"}, "reports/a.gif", "https://radiocast.s3.eu-west-1.amazonaws.com/reports/a.gif"},
+		{"path style", S3Config{Endpoint: "http://localhost:9000", Bucket: "radiocast", PathStyle: true}, "reports/a.gif", "http://localhost:9000/radiocast/reports/a.gif"},
+		{"virtual hosted", S3Config{Endpoint: "https://s3.us-west-004.backblazeb2.com", Bucket: "radiocast"}, "index.html", "https://radiocast.s3.us-west-004.backblazeb2.com/index.html"},
+		{"escaped key", S3Config{Endpoint: "http://localhost:9000/", Bucket: "radiocast", PathStyle: true}, "a b/c+d.txt", "http://localhost:9000/radiocast/a%20b/c%2Bd.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewS3Client(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewS3Client() error = %v", err)
+			}
+			if got := client.objectURL(tt.key, nil).String(); got != tt.want {
+				t.Errorf("objectURL(%q) = %s, want %s", tt.key, got, tt.want)
+			}
+		})
+	}
+
+	for _, cfg := range []S3Config{{}, {Bucket: "radiocast", Endpoint: "localhost:9000"}} {
+		if _, err := NewS3Client(cfg); err == nil {
+			t.Errorf("NewS3Client(%+v) expected error", cfg)
+		}
+	}
+}
+
+func TestS3Client_StoreAndGet(t *testing.T) {
+	client, fake := newTestS3Client(t)
+	ctx := context.Background()
+
+	files := map[string]string{
+		"reports/2025/01/01/index.html":    "<html></html>",
+		"reports/2025/01/01/sun 72h+1.gif": "GIF89a",
+		"static/styles.css":                "body {}",
+	}
+	for path, content := range files {
+		if err := client.StoreFile(ctx, path, []byte(content)); err != nil {
+			t.Fatalf("StoreFile(%s) error = %v", path, err)
+		}
+	}
+	for path, content := range files {
+		data, err := client.GetFile(ctx, path)
+		if err != nil || string(data) != content {
+			t.Errorf("GetFile(%s) = %q, %v; want %q", path, data, err, content)
+		}
+		if exists, err := client.FileExists(ctx, path); !exists || err != nil {
+			t.Errorf("FileExists(%s) = %v, %v; want true", path, exists, err)
+		}
+	}
+	if fake != nil && fake.objects["static/styles.css"].contentType != "text/css" {
+		t.Errorf("Content-Type = %q, want text/css", fake.objects["static/styles.css"].contentType)
+	}
+
+	info, err := client.Stat(ctx, "reports/2025/01/01/index.html")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Size != 13 || info.ETag == "" || info.ModTime.IsZero() {
+		t.Errorf("Stat() = %+v, want size 13 with ETag and ModTime", info)
+	}
+
+	if exists, err := client.FileExists(ctx, "missing.html"); exists || err != nil {
+		t.Errorf("FileExists(missing) = %v, %v; want false", exists, err)
+	}
+	if _, err := client.GetFile(ctx, "missing.html"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("GetFile(missing) error = %v, want os.ErrNotExist", err)
+	}
+	if _, err := client.Stat(ctx, "missing.html"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Stat(missing) error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestS3Client_OpenRange(t *testing.T) {
+	client, _ := newTestS3Client(t)
+	ctx := context.Background()
+	if err := client.StoreFile(ctx, "video.mp4", []byte("0123456789")); err != nil {
+		t.Fatalf("Failed to store test file: %v", err)
+	}
+
+	tests := []struct {
+		offset, length int64
+		want           string
+	}{
+		{0, -1, "0123456789"},
+		{3, 4, "3456"},
+		{7, -1, "789"},
+		{8, 10, "89"},
+		{5, 0, ""},
+	}
+	for _, tt := range tests {
+		reader, err := client.OpenRange(ctx, "video.mp4", tt.offset, tt.length)
+		if err != nil {
+			t.Fatalf("OpenRange(%d, %d) error = %v", tt.offset, tt.length, err)
+		}
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil || string(data) != tt.want {
+			t.Errorf("OpenRange(%d, %d) = %q, %v; want %q", tt.offset, tt.length, data, err, tt.want)
+		}
+	}
+
+	if _, err := client.OpenRange(ctx, "missing.mp4", 0, -1); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenRange on a missing file: error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestS3Client_ListDir(t *testing.T) {
+	client, fake := newTestS3Client(t)
+	if fake != nil {
+		fake.maxKeys = 2 // exercise continuation tokens
+	}
+	ctx := context.Background()
+
+	for _, path := range []string{"reports/a/index.html", "reports/a/sun.gif", "reports/b/index.html", "reports/list.json", "static/styles.css"} {
+		if err := client.StoreFile(ctx, path, []byte("x")); err != nil {
+			t.Fatalf("StoreFile(%s) error = %v", path, err)
+		}
+	}
+
+	recursive, err := client.ListDir(ctx, "reports", true)
+	if err != nil {
+		t.Fatalf("ListDir(recursive) error = %v", err)
+	}
+	want := "reports/a/index.html,reports/a/sun.gif,reports/b/index.html,reports/list.json"
+	if got := strings.Join(recursive, ","); got != want {
+		t.Errorf("ListDir(recursive) = %s, want %s", got, want)
+	}
+
+	flat, err := client.ListDir(ctx, "reports/", false)
+	if err != nil {
+		t.Fatalf("ListDir() error = %v", err)
+	}
+	sort.Strings(flat)
+	if got := strings.Join(flat, ","); got != "reports/a,reports/b,reports/list.json" {
+		t.Errorf("ListDir() = %s, want reports/a,reports/b,reports/list.json", got)
+	}
+}
+
+func TestS3Client_OpenWriter(t *testing.T) {
+	client, fake := newTestS3Client(t)
+	ctx := context.Background()
+
+	// Small files are uploaded with one PUT, larger ones in parts
+	for _, size := range []int{0, 10, client.partSize, 3*client.partSize + 5} {
+		content := strings.Repeat("radiocast", size/9+1)[:size]
+		path := "reports/a/report-" + strconv.Itoa(size) + ".pdf"
+		err := StreamFile(ctx, client, path, func(w io.Writer) error {
+			for i := 0; i < len(content); i += 7 {
+				if _, err := io.WriteString(w, content[i:min(i+7, len(content))]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamFile(%d bytes) error = %v", size, err)
+		}
+		reader, err := client.OpenReader(ctx, path)
+		if err != nil {
+			t.Fatalf("OpenReader() error = %v", err)
+		}
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+		if string(data) != content {
+			t.Errorf("Round trip of %d bytes returned %d bytes", size, len(data))
+		}
+	}
+	if fake != nil && fake.objects["reports/a/report-53.pdf"].contentType != "application/pdf" {
+		t.Errorf("Multipart Content-Type = %q, want application/pdf", fake.objects["reports/a/report-53.pdf"].contentType)
+	}
+
+	// A failed upload is aborted and leaves nothing behind
+	failure := errors.New("encoder failed")
+	err := StreamFile(ctx, client, "reports/a/broken.mp4", func(w io.Writer) error {
+		io.WriteString(w, strings.Repeat("x", 2*client.partSize))
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("StreamFile() error = %v, want %v", err, failure)
+	}
+	if exists, _ := client.FileExists(ctx, "reports/a/broken.mp4"); exists {
+		t.Error("Aborted upload created the file")
+	}
+	if fake != nil && len(fake.uploads) != 0 {
+		t.Errorf("%d multipart uploads left pending", len(fake.uploads))
+	}
+}
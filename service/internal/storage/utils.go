@@ -1,7 +1,9 @@
 package storage
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 )
@@ -15,6 +17,16 @@ func GenerateReportFolderPath(timestamp time.Time) string {
 		timestamp.Hour(), timestamp.Minute(), timestamp.Second())
 }
 
+// ParseReportFolderPath returns the timestamp of a folder path made by GenerateReportFolderPath
+func ParseReportFolderPath(folderPath string) (time.Time, bool) {
+	name := folderPath[strings.LastIndex(folderPath, "/")+1:]
+	timestamp, err := time.Parse("PropagationReport-2006-01-02-15-04-05", name)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return timestamp, true
+}
+
 
 // GetContentType determines the MIME content type based on file extension
 func GetContentType(filename string) string {
@@ -34,7 +46,39 @@ func GetContentType(filename string) string {
 		return "image/jpeg"
 	} else if strings.HasSuffix(filename, ".gif") {
 		return "image/gif"
+	} else if strings.HasSuffix(filename, ".webp") {
+		return "image/webp"
+	} else if strings.HasSuffix(filename, ".mp4") {
+		return "video/mp4"
+	} else if strings.HasSuffix(filename, ".svg") {
+		return "image/svg+xml"
+	} else if strings.HasSuffix(filename, ".pdf") {
+		return "application/pdf"
+	} else if strings.HasSuffix(filename, ".xml") {
+		return "application/xml"
+	} else if strings.HasSuffix(filename, ".atom") {
+		return "application/atom+xml"
+	} else if strings.HasSuffix(filename, ".js") {
+		return "application/javascript"
 	} else {
 		return "application/octet-stream"
 	}
 }
+
+// StreamFile writes a file through client.OpenWriter with the content produced by write. When write
+// fails the upload is discarded, so a partial file never appears at filePath.
+func StreamFile(ctx context.Context, client StorageClient, filePath string, write func(w io.Writer) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writer, err := client.OpenWriter(ctx, filePath)
+	if err != nil {
+		return err
+	}
+	if err := write(writer); err != nil {
+		cancel()
+		writer.Close()
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+	return writer.Close()
+}
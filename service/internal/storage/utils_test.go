@@ -129,6 +129,41 @@ func TestGetContentType(t *testing.T) {
 			filename: "animation.gif",
 			expected: "image/gif",
 		},
+		{
+			name:     "SVG image",
+			filename: "chart-forecast.svg",
+			expected: "image/svg+xml",
+		},
+		{
+			name:     "PDF report",
+			filename: "reports/2025/09/17/report.pdf",
+			expected: "application/pdf",
+		},
+		{
+			name:     "Animated WebP",
+			filename: "sun_72h.webp",
+			expected: "image/webp",
+		},
+		{
+			name:     "MP4 video",
+			filename: "reports/2025/09/17/sun_72h.mp4",
+			expected: "video/mp4",
+		},
+		{
+			name:     "XML file",
+			filename: "sitemap.xml",
+			expected: "application/xml",
+		},
+		{
+			name:     "Atom feed",
+			filename: "feed.atom",
+			expected: "application/atom+xml",
+		},
+		{
+			name:     "JavaScript file",
+			filename: "static/echarts.min.js",
+			expected: "application/javascript",
+		},
 		{
 			name:     "Unknown file type",
 			filename: "data.xyz",
@@ -303,3 +338,18 @@ func BenchmarkGetContentType(b *testing.B) {
 		}
 	}
 }
+
+func TestParseReportFolderPath(t *testing.T) {
+	timestamp := time.Date(2025, 9, 17, 14, 30, 45, 0, time.UTC)
+	for _, folder := range []string{GenerateReportFolderPath(timestamp), "reports/" + GenerateReportFolderPath(timestamp)} {
+		got, ok := ParseReportFolderPath(folder)
+		if !ok || !got.Equal(timestamp) {
+			t.Errorf("ParseReportFolderPath(%q) = %v, %v, want %v", folder, got, ok, timestamp)
+		}
+	}
+	for _, folder := range []string{"", "2025/09/17", "2025/09/17/report-2025-09-17"} {
+		if _, ok := ParseReportFolderPath(folder); ok {
+			t.Errorf("ParseReportFolderPath(%q) ok = true, want false", folder)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package sun computes the position of the sun, sunrise, sunset and twilight, and the day/night
+// terminator, following the NOAA solar calculator (accurate to about a minute away from the poles)
+package sun
+
+import (
+	"math"
+	"time"
+)
+
+// Point is a location on the earth in degrees
+type Point struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+}
+
+// Zenith returns the solar zenith angle in degrees at a location and time
+func Zenith(lat, lon float64, t time.Time) float64 {
+	t = t.UTC()
+	decl, eqTime := position(t)
+	trueSolarMinutes := float64(t.Hour()*60+t.Minute()) + float64(t.Second())/60 + eqTime + 4*lon
+	ha := radians(trueSolarMinutes/4 - 180)
+	phi := radians(lat)
+	cosZenith := math.Sin(phi)*math.Sin(decl) + math.Cos(phi)*math.Cos(decl)*math.Cos(ha)
+	return degrees(math.Acos(math.Min(math.Max(cosZenith, -1), 1)))
+}
+
+// Elevation returns the elevation of the sun above the horizon in degrees
+func Elevation(lat, lon float64, t time.Time) float64 {
+	return 90 - Zenith(lat, lon, t)
+}
+
+// Subsolar returns the point where the sun is overhead
+func Subsolar(t time.Time) Point {
+	t = t.UTC()
+	decl, eqTime := position(t)
+	minutes := float64(t.Hour()*60+t.Minute()) + float64(t.Second())/60
+	lon := math.Mod((720-minutes-eqTime)/4+540, 360) - 180
+	return Point{Latitude: degrees(decl), Longitude: lon}
+}
+
+// Terminator returns the day/night line (sun on the horizon) every step degrees of longitude from
+// -180 to 180
+func Terminator(t time.Time, step float64) []Point {
+	sub := Subsolar(t)
+	tanDecl := math.Tan(radians(sub.Latitude))
+	if math.Abs(tanDecl) < 1e-6 {
+		// At the equinoxes the terminator runs along two meridians
+		tanDecl = math.Copysign(1e-6, tanDecl)
+	}
+	var points []Point
+	for lon := -180.0; lon <= 180+1e-9; lon += step {
+		lat := degrees(math.Atan(-math.Cos(radians(lon-sub.Longitude)) / tanDecl))
+		points = append(points, Point{Latitude: lat, Longitude: lon})
+	}
+	return points
+}
+
+// NightPolygon closes the terminator over the pole in darkness, outlining the night side on an
+// equirectangular map
+func NightPolygon(t time.Time, step float64) []Point {
+	pole := -90.0
+	if Subsolar(t).Latitude < 0 {
+		pole = 90
+	}
+	points := Terminator(t, step)
+	return append(points, Point{Latitude: pole, Longitude: 180}, Point{Latitude: pole, Longitude: -180})
+}
+
+// position returns the solar declination (radians) and the equation of time (minutes)
+func position(t time.Time) (decl, eqTime float64) {
+	t = t.UTC()
+	gamma := 2 * math.Pi / 365 * (float64(t.YearDay()-1) + (float64(t.Hour())-12)/24)
+	eqTime = 229.18 * (0.000075 + 0.001868*math.Cos(gamma) - 0.032077*math.Sin(gamma) -
+		0.014615*math.Cos(2*gamma) - 0.040849*math.Sin(2*gamma))
+	decl = 0.006918 - 0.399912*math.Cos(gamma) + 0.070257*math.Sin(gamma) -
+		0.006758*math.Cos(2*gamma) + 0.000907*math.Sin(2*gamma) -
+		0.002697*math.Cos(3*gamma) + 0.00148*math.Sin(3*gamma)
+	return decl, eqTime
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
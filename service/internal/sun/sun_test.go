@@ -0,0 +1,128 @@
+package sun
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimesOn(t *testing.T) {
+	london := TimesOn(51.5074, -0.1278, time.Date(2025, 6, 21, 8, 0, 0, 0, time.UTC))
+	if london.Sunrise == nil || london.Sunrise.Format("15:04") != "03:42" || london.Sunset.Format("15:04") != "20:21" {
+		t.Errorf("London midsummer = %v to %v, want 03:42 to 20:21 UTC", london.Sunrise, london.Sunset)
+	}
+	if london.CivilDawn == nil || !london.CivilDawn.Before(*london.Sunrise) || !london.CivilDusk.After(*london.Sunset) {
+		t.Errorf("London civil twilight = %v to %v, want around the sunrise and sunset", london.CivilDawn, london.CivilDusk)
+	}
+	// Sydney's sunrise is on the previous UTC date
+	sydney := TimesOn(-33.87, 151.21, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if sydney.Sunrise.Format("2006-01-02 15:04") != "2024-12-31 18:47" || sydney.Sunset.Format("15:04") != "09:09" {
+		t.Errorf("Sydney = %v to %v", sydney.Sunrise, sydney.Sunset)
+	}
+	if s := TimesOn(78.2, 15.6, time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC)); !s.PolarDay || s.Sunrise != nil {
+		t.Errorf("Svalbard midsummer = %+v, want polar day", s)
+	}
+	if s := TimesOn(78.2, 15.6, time.Date(2025, 12, 21, 0, 0, 0, 0, time.UTC)); !s.PolarNight {
+		t.Errorf("Svalbard midwinter = %+v, want polar night", s)
+	}
+	// White nights: the sun sets but civil twilight lasts all night
+	if s := TimesOn(62, 10, time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC)); s.Sunrise == nil || s.CivilDawn != nil {
+		t.Errorf("Trondheim midsummer = %+v, want a sunrise and no civil dawn", s)
+	}
+}
+
+func TestSubsolar(t *testing.T) {
+	tests := []struct {
+		t        time.Time
+		lat, lon float64
+	}{
+		{time.Date(2025, 3, 20, 12, 7, 0, 0, time.UTC), 0, 0},
+		{time.Date(2025, 6, 21, 12, 0, 0, 0, time.UTC), 23.44, 0.4},
+		{time.Date(2025, 12, 21, 0, 0, 0, 0, time.UTC), -23.41, 179.4},
+		{time.Date(2025, 6, 21, 18, 0, 0, 0, time.UTC), 23.44, -89.6},
+	}
+	for _, tt := range tests {
+		p := Subsolar(tt.t)
+		if math.Abs(p.Latitude-tt.lat) > 0.5 || math.Abs(p.Longitude-tt.lon) > 0.5 {
+			t.Errorf("Subsolar(%v) = %+v, want about %v, %v", tt.t, p, tt.lat, tt.lon)
+		}
+		if z := Zenith(p.Latitude, p.Longitude, tt.t); z > 0.1 {
+			t.Errorf("Zenith at the subsolar point of %v = %v, want 0", tt.t, z)
+		}
+	}
+}
+
+func TestTerminator(t *testing.T) {
+	for _, at := range []time.Time{
+		time.Date(2025, 6, 21, 12, 0, 0, 0, time.UTC),
+		time.Date(2025, 10, 18, 7, 30, 0, 0, time.UTC),
+		time.Date(2025, 12, 21, 23, 0, 0, 0, time.UTC),
+	} {
+		points := Terminator(at, 2)
+		if len(points) != 181 || points[0].Longitude != -180 || points[180].Longitude != 180 {
+			t.Fatalf("Terminator(%v) has %d points from %v to %v", at, len(points), points[0], points[len(points)-1])
+		}
+		for _, p := range points {
+			if z := Zenith(p.Latitude, p.Longitude, at); math.Abs(z-90) > 0.1 {
+				t.Errorf("Zenith on the terminator at %+v on %v = %v, want 90", p, at, z)
+				break
+			}
+		}
+
+		night := NightPolygon(at, 2)
+		pole := night[len(night)-1]
+		if len(night) != len(points)+2 || pole.Longitude != -180 || Elevation(pole.Latitude, 0, at) >= 0 {
+			t.Errorf("NightPolygon(%v) closes over %+v, want the pole in darkness", at, pole)
+		}
+	}
+}
+
+func TestGrayLine(t *testing.T) {
+	day := time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC)
+	london := GrayLine(51.5074, -0.1278, day)
+	times := TimesOn(51.5074, -0.1278, day)
+	if len(london) != 2 || london[0].Event != "sunrise" || london[1].Event != "sunset" {
+		t.Fatalf("London gray line = %+v, want sunrise and sunset windows", london)
+	}
+	for i, sunTime := range []time.Time{*times.Sunrise, *times.Sunset} {
+		w := london[i]
+		if !w.Start.Before(sunTime) || !w.End.After(sunTime) {
+			t.Errorf("London %s window %v to %v does not include %v", w.Event, w.Start, w.End, sunTime)
+		}
+		if d := w.End.Sub(w.Start); d < 45*time.Minute || d > 2*time.Hour {
+			t.Errorf("London %s window lasts %v", w.Event, d)
+		}
+	}
+
+	// White nights: one window from the evening until the next morning
+	trondheim := GrayLine(62, 10, day)
+	if len(trondheim) != 1 || trondheim[0].Event != "twilight" || trondheim[0].End.Sub(trondheim[0].Start) < 4*time.Hour {
+		t.Errorf("Trondheim midsummer gray line = %+v, want one night-long twilight", trondheim)
+	}
+	// The polar noon sun stays low: twilight all day
+	tromso := GrayLine(70, 19, time.Date(2025, 12, 21, 0, 0, 0, 0, time.UTC))
+	if len(tromso) != 1 || tromso[0].Event != "twilight" || !tromso[0].Start.Before(tromso[0].End) {
+		t.Errorf("Tromsø midwinter gray line = %+v, want one daytime twilight", tromso)
+	}
+	if w := GrayLine(78.2, 15.6, day); w != nil {
+		t.Errorf("Svalbard midsummer gray line = %+v, want none", w)
+	}
+}
+
+func TestGrayLineBetween(t *testing.T) {
+	from := time.Date(2025, 6, 21, 12, 0, 0, 0, time.UTC)
+	windows := GrayLineBetween(51.5074, -0.1278, from, from.Add(24*time.Hour))
+	if len(windows) != 2 || windows[0].Event != "sunset" || windows[1].Event != "sunrise" {
+		t.Fatalf("London windows = %+v, want the evening then the next morning", windows)
+	}
+	if !windows[0].Start.After(from) || !windows[1].Start.After(windows[0].End) {
+		t.Errorf("London windows out of order: %+v", windows)
+	}
+
+	// Sydney's morning window falls on the previous UTC date
+	from = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	windows = GrayLineBetween(-33.87, 151.21, from, from.Add(24*time.Hour))
+	if len(windows) != 2 || windows[0].Event != "sunset" || windows[1].Event != "sunrise" || windows[1].Start.Day() != 1 {
+		t.Errorf("Sydney windows = %+v, want the sunset and the next sunrise", windows)
+	}
+}
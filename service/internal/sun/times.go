@@ -0,0 +1,110 @@
+package sun
+
+import (
+	"math"
+	"time"
+)
+
+// Zenith angles of the events of a day
+const (
+	sunriseZenith = 90.833 // upper limb on the horizon, including refraction
+	civilZenith   = 96     // civil twilight: 6° below the horizon
+
+	// The gray line is counted while the sun is within 6° of the horizon
+	grayLineLow  = 96
+	grayLineHigh = 84
+)
+
+// Times are the sunrise, sunset and civil twilight of one UTC day at one location. Far east or
+// west of Greenwich an event can fall on the previous or next UTC date.
+type Times struct {
+	CivilDawn  *time.Time `json:"civil_dawn,omitempty"`
+	Sunrise    *time.Time `json:"sunrise"` // nil during polar day or night
+	Sunset     *time.Time `json:"sunset"`
+	CivilDusk  *time.Time `json:"civil_dusk,omitempty"` // nil when the sun stays above -6° (white nights)
+	PolarDay   bool       `json:"polar_day,omitempty"`
+	PolarNight bool       `json:"polar_night,omitempty"`
+}
+
+// Window is a gray-line window: the sun within 6° of the horizon around sunrise or sunset, or
+// "twilight" when it stays that low all day or all night
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Event string    `json:"event"`
+}
+
+// TimesOn returns the sun times of the UTC day of day
+func TimesOn(lat, lon float64, day time.Time) Times {
+	var times Times
+	var above bool
+	times.Sunrise, times.Sunset, above = crossing(lat, lon, day, sunriseZenith)
+	if times.Sunrise == nil {
+		times.PolarDay, times.PolarNight = above, !above
+	}
+	times.CivilDawn, times.CivilDusk, _ = crossing(lat, lon, day, civilZenith)
+	return times
+}
+
+// GrayLine returns the gray-line windows of the UTC day of day
+func GrayLine(lat, lon float64, day time.Time) []Window {
+	lowStart, lowEnd, _ := crossing(lat, lon, day, grayLineLow)
+	highStart, highEnd, _ := crossing(lat, lon, day, grayLineHigh)
+
+	switch {
+	case lowStart != nil && highStart != nil:
+		return []Window{
+			{Start: *lowStart, End: *highStart, Event: "sunrise"},
+			{Start: *highEnd, End: *lowEnd, Event: "sunset"},
+		}
+	case lowStart != nil:
+		// The sun does not rise above 6°
+		return []Window{{Start: *lowStart, End: *lowEnd, Event: "twilight"}}
+	case highStart != nil:
+		// The sun does not set below -6°: twilight until it climbs past 6° the next morning
+		if next, _, _ := crossing(lat, lon, day.AddDate(0, 0, 1), grayLineHigh); next != nil {
+			return []Window{{Start: *highEnd, End: *next, Event: "twilight"}}
+		}
+	}
+	return nil
+}
+
+// GrayLineBetween returns the gray-line windows that overlap from..to, in time order
+func GrayLineBetween(lat, lon float64, from, to time.Time) []Window {
+	var windows []Window
+	// Far from Greenwich, or in white nights, a window can fall on the UTC day before or after its own
+	last := to.AddDate(0, 0, 1)
+	for day := from.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1); day.Before(last); day = day.AddDate(0, 0, 1) {
+		for _, w := range GrayLine(lat, lon, day) {
+			if w.End.After(from) && w.Start.Before(to) {
+				windows = append(windows, w)
+			}
+		}
+	}
+	return windows
+}
+
+// crossing returns when the sun passes the zenith angle on the way up and down. Both are nil when
+// it never does that day; above then tells whether the sun stays above that angle all day.
+func crossing(lat, lon float64, day time.Time, zenith float64) (up, down *time.Time, above bool) {
+	day = day.UTC()
+	noon := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, time.UTC)
+	decl, eqTime := position(noon)
+
+	phi := radians(lat)
+	cosHA := math.Cos(radians(zenith))/(math.Cos(phi)*math.Cos(decl)) - math.Tan(phi)*math.Tan(decl)
+	switch {
+	case cosHA > 1:
+		return nil, nil, false
+	case cosHA < -1:
+		return nil, nil, true
+	}
+	ha := degrees(math.Acos(cosHA))
+
+	midnight := noon.Add(-12 * time.Hour)
+	at := func(minutes float64) *time.Time {
+		t := midnight.Add(time.Duration(minutes * float64(time.Minute))).Truncate(time.Minute)
+		return &t
+	}
+	return at(720 - 4*(lon+ha) - eqTime), at(720 - 4*(lon-ha) - eqTime), false
+}
@@ -0,0 +1,352 @@
+package timeseries
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"radiocast/internal/logger"
+	"radiocast/internal/storage"
+)
+
+// storePrefix is the storage folder holding the files of every metric
+const storePrefix = "timeseries"
+
+// compactAfter is the number of segments a series keeps; the next upsert merges them into one file
+const compactAfter = 32
+
+// Metric names a stored time series
+type Metric string
+
+const (
+	MetricKp         Metric = "kp"          // planetary Kp, 3-hourly (NOAA SWPC)
+	MetricSFIMonthly Metric = "sfi_monthly" // monthly mean 10.7 cm solar flux (NOAA SWPC)
+	MetricSSNMonthly Metric = "ssn_monthly" // monthly mean sunspot number (NOAA SWPC)
+	MetricSFI        Metric = "sfi"         // daily 10.7 cm solar flux (N0NBH)
+	MetricSSN        Metric = "ssn"         // daily sunspot number (N0NBH)
+	MetricAIndex     Metric = "a_index"     // daily planetary A-index (N0NBH)
+	MetricSolarWind  Metric = "solar_wind"  // solar wind speed in km/s (N0NBH)
+)
+
+// ErrInvalidMetric is returned for metric names other than lowercase letters, digits, '_' and '-'
+var ErrInvalidMetric = errors.New("invalid metric name")
+
+// valid reports whether a metric name is safe to use as a file name
+func (m Metric) valid() bool {
+	if m == "" {
+		return false
+	}
+	for _, c := range m {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// BandMetric names the series of a band condition, e.g. BandMetric("10m", "day"). Conditions are
+// stored as 0 (closed) to 4 (excellent).
+func BandMetric(band, period string) Metric {
+	return Metric("band_" + band + "_" + period)
+}
+
+// ParseBandMetric is the inverse of BandMetric; ok is false for other metrics
+func ParseBandMetric(metric Metric) (band, period string, ok bool) {
+	name, found := strings.CutPrefix(string(metric), "band_")
+	if !found {
+		return "", "", false
+	}
+	i := strings.LastIndex(name, "_")
+	if i <= 0 || i == len(name)-1 {
+		return "", "", false
+	}
+	return name[:i], name[i+1:], true
+}
+
+// Point is one observation. A series holds at most one point per timestamp and source.
+type Point struct {
+	Time   time.Time `json:"t"`
+	Value  float64   `json:"v"`
+	Source string    `json:"s,omitempty"`
+}
+
+// Store keeps time series as compact JSON-lines files in a StorageClient. Each metric has a base
+// file with the whole series, and a segment file per upsert since the last compaction holding
+// only the points that upsert added or changed; object stores cannot append, so this keeps an
+// upsert from rewriting the whole series. Once compactAfter segments have piled up, the next
+// upsert merges them into the base file. Points are kept sorted by time; upserting a point again replaces
+// its value.
+//
+// A Store assumes it is the only writer of its series. Upserts from several processes only
+// add segments and are safe, but two compactions of the same metric at once can drop the
+// points of the segments one of them did not see.
+type Store struct {
+	storage     storage.StorageClient
+	mu          sync.Mutex // serialises read-modify-write cycles within this process
+	lastSegment int64      // keeps segment names increasing within this process
+}
+
+// NewStore creates a store backed by storageClient
+func NewStore(storageClient storage.StorageClient) *Store {
+	return &Store{storage: storageClient}
+}
+
+// Upsert merges points into a series and returns how many were new or changed
+func (s *Store) Upsert(ctx context.Context, metric Metric, points []Point) (int, error) {
+	if len(points) == 0 {
+		return 0, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, segments, err := s.load(ctx, metric)
+	if err != nil {
+		return 0, err
+	}
+	existed := len(series) > 0 || len(segments) > 0
+	index := make(map[pointKey]int, len(series))
+	for i, p := range series {
+		index[keyOf(p)] = i
+	}
+
+	var changed []Point
+	for _, p := range points {
+		p.Time = p.Time.UTC().Truncate(time.Second)
+		if i, ok := index[keyOf(p)]; ok {
+			if series[i].Value != p.Value {
+				series[i].Value = p.Value
+				changed = append(changed, p)
+			}
+			continue
+		}
+		index[keyOf(p)] = len(series)
+		series = append(series, p)
+		changed = append(changed, p)
+	}
+	if len(changed) == 0 {
+		return 0, nil
+	}
+
+	if existed && len(segments) < compactAfter {
+		if err := s.write(ctx, metric, s.nextSegmentPath(metric), changed); err != nil {
+			return 0, err
+		}
+		return len(changed), nil
+	}
+	if err := s.compact(ctx, metric, series, segments); err != nil {
+		return 0, err
+	}
+	return len(changed), nil
+}
+
+// Query returns the points of a series with from <= time <= to, oldest first.
+// A zero from or to leaves that end of the window open.
+func (s *Store) Query(ctx context.Context, metric Metric, from, to time.Time) ([]Point, error) {
+	s.mu.Lock()
+	series, _, err := s.load(ctx, metric)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	var points []Point
+	for _, p := range series {
+		if (!from.IsZero() && p.Time.Before(from)) || (!to.IsZero() && p.Time.After(to)) {
+			continue
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// Metrics lists the stored series
+func (s *Store) Metrics(ctx context.Context) ([]Metric, error) {
+	files, err := s.storage.ListDir(ctx, storePrefix, false)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time series: %w", err)
+	}
+	var metrics []Metric
+	for _, file := range files {
+		if name := path.Base(file); strings.HasSuffix(name, ".jsonl") {
+			metrics = append(metrics, Metric(strings.TrimSuffix(name, ".jsonl")))
+		}
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i] < metrics[j] })
+	return metrics, nil
+}
+
+// load reads a series, the base file merged with its segments in order, and returns the paths
+// of the segments; a missing series is empty
+func (s *Store) load(ctx context.Context, metric Metric) ([]Point, []string, error) {
+	if !metric.valid() {
+		return nil, nil, fmt.Errorf("%w: %q", ErrInvalidMetric, metric)
+	}
+	series, err := s.read(ctx, metric, seriesPath(metric))
+	if err != nil {
+		return nil, nil, err
+	}
+	segments, err := s.storage.ListDir(ctx, segmentsDir(metric), true)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("failed to list time series %s: %w", metric, err)
+	}
+	sort.Strings(segments)
+	if len(segments) == 0 {
+		return series, nil, nil
+	}
+
+	index := make(map[pointKey]int, len(series))
+	for i, p := range series {
+		index[keyOf(p)] = i
+	}
+	for _, segment := range segments {
+		points, err := s.read(ctx, metric, segment)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, p := range points {
+			if i, ok := index[keyOf(p)]; ok {
+				series[i].Value = p.Value
+				continue
+			}
+			index[keyOf(p)] = len(series)
+			series = append(series, p)
+		}
+	}
+	sortPoints(series)
+	return series, segments, nil
+}
+
+// read parses one file of a series; a missing file has no points
+func (s *Store) read(ctx context.Context, metric Metric, filePath string) ([]Point, error) {
+	data, err := s.storage.GetFile(ctx, filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read time series %s: %w", metric, err)
+	}
+
+	var points []Point
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var p Point
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			logger.Warn("Skipping malformed time series point", map[string]interface{}{
+				"metric": string(metric),
+				"file":   filePath,
+				"line":   line,
+				"error":  err.Error(),
+			})
+			continue
+		}
+		points = append(points, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read time series %s: %w", metric, err)
+	}
+	return points, nil
+}
+
+// write stores points in a file of a series, one point per line
+func (s *Store) write(ctx context.Context, metric Metric, filePath string, points []Point) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, p := range points {
+		if err := encoder.Encode(p); err != nil {
+			return err
+		}
+	}
+	if err := s.storage.StoreFile(ctx, filePath, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write time series %s: %w", metric, err)
+	}
+	return nil
+}
+
+// compact writes the whole series to the base file, then deletes the merged segments. A segment
+// left behind by a failed delete is merged again on load, which changes nothing.
+func (s *Store) compact(ctx context.Context, metric Metric, series []Point, segments []string) error {
+	sortPoints(series)
+	if err := s.write(ctx, metric, seriesPath(metric), series); err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if err := s.storage.Delete(ctx, segment); err != nil {
+			logger.Warn("Failed to delete compacted time series segment", map[string]interface{}{
+				"metric":  string(metric),
+				"segment": segment,
+				"error":   err.Error(),
+			})
+		}
+	}
+	return nil
+}
+
+// nextSegmentPath returns the path of a new segment; names sort in the order they were written
+func (s *Store) nextSegmentPath(metric Metric) string {
+	seq := time.Now().UnixNano()
+	if seq <= s.lastSegment {
+		seq = s.lastSegment + 1
+	}
+	s.lastSegment = seq
+	return fmt.Sprintf("%s/%019d.jsonl", segmentsDir(metric), seq)
+}
+
+func seriesPath(metric Metric) string {
+	return storePrefix + "/" + string(metric) + ".jsonl"
+}
+
+// segmentsDir is the folder of a series' segments; it does not end in .jsonl, so Metrics skips it
+func segmentsDir(metric Metric) string {
+	return storePrefix + "/" + string(metric) + ".segments"
+}
+
+type pointKey struct {
+	unix   int64
+	source string
+}
+
+func keyOf(p Point) pointKey {
+	return pointKey{unix: p.Time.Unix(), source: p.Source}
+}
+
+// sortPoints orders points by time, then source
+func sortPoints(points []Point) {
+	sort.Slice(points, func(i, j int) bool {
+		if !points[i].Time.Equal(points[j].Time) {
+			return points[i].Time.Before(points[j].Time)
+		}
+		return points[i].Source < points[j].Source
+	})
+}
+
+// Gap is a stretch without points between two consecutive points of a series
+type Gap struct {
+	Metric Metric    `json:"metric"`
+	From   time.Time `json:"from"` // last point before the gap
+	To     time.Time `json:"to"`   // first point after the gap
+}
+
+// FindGaps returns the gaps of a sorted series where consecutive points are more than maxStep apart
+func FindGaps(metric Metric, points []Point, maxStep time.Duration) []Gap {
+	var gaps []Gap
+	for i := 1; i < len(points); i++ {
+		if points[i].Time.Sub(points[i-1].Time) > maxStep {
+			gaps = append(gaps, Gap{Metric: metric, From: points[i-1].Time, To: points[i].Time})
+		}
+	}
+	return gaps
+}
@@ -0,0 +1,190 @@
+package timeseries
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"radiocast/internal/storage"
+)
+
+func at(hour int) time.Time {
+	return time.Date(2025, 9, 17, hour, 0, 0, 0, time.UTC)
+}
+
+func TestStore_Upsert(t *testing.T) {
+	client := storage.NewMemoryStorageClient()
+	store := NewStore(client)
+	ctx := context.Background()
+
+	changed, err := store.Upsert(ctx, MetricKp, []Point{
+		{Time: at(6), Value: 2.33, Source: "NOAA SWPC"},
+		{Time: at(0), Value: 1.67, Source: "NOAA SWPC"},
+		{Time: at(3), Value: 2.00, Source: "NOAA SWPC"},
+	})
+	if err != nil || changed != 3 {
+		t.Fatalf("Upsert() = %d, %v, want 3, nil", changed, err)
+	}
+
+	// The next fetch overlaps: a revised value and a new point change, the rest is de-duplicated
+	changed, err = store.Upsert(ctx, MetricKp, []Point{
+		{Time: at(3), Value: 2.00, Source: "NOAA SWPC"},
+		{Time: at(6).In(time.FixedZone("CEST", 2*3600)), Value: 2.67, Source: "NOAA SWPC"},
+		{Time: at(9), Value: 3.00, Source: "NOAA SWPC"},
+	})
+	if err != nil || changed != 2 {
+		t.Fatalf("Second Upsert() = %d, %v, want 2, nil", changed, err)
+	}
+
+	// The same timestamp from another source is a separate point
+	if changed, _ := store.Upsert(ctx, MetricKp, []Point{{Time: at(9), Value: 3.33, Source: "N0NBH"}}); changed != 1 {
+		t.Errorf("Upsert() from another source = %d, want 1", changed)
+	}
+
+	points, err := store.Query(ctx, MetricKp, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	want := []Point{
+		{Time: at(0), Value: 1.67, Source: "NOAA SWPC"},
+		{Time: at(3), Value: 2.00, Source: "NOAA SWPC"},
+		{Time: at(6), Value: 2.67, Source: "NOAA SWPC"},
+		{Time: at(9), Value: 3.33, Source: "N0NBH"},
+		{Time: at(9), Value: 3.00, Source: "NOAA SWPC"},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("Query() = %v, want %v", points, want)
+	}
+	for i := range want {
+		if !points[i].Time.Equal(want[i].Time) || points[i].Value != want[i].Value || points[i].Source != want[i].Source {
+			t.Errorf("Query()[%d] = %+v, want %+v", i, points[i], want[i])
+		}
+	}
+
+	// An upsert without changes does not rewrite the file
+	before, _ := client.Stat(ctx, "timeseries/kp.jsonl")
+	if changed, _ := store.Upsert(ctx, MetricKp, want); changed != 0 {
+		t.Errorf("Upsert() of stored points = %d, want 0", changed)
+	}
+	if after, _ := client.Stat(ctx, "timeseries/kp.jsonl"); after.ETag != before.ETag {
+		t.Error("Upsert() without changes rewrote the series")
+	}
+}
+
+func TestStore_AppendsSegmentsAndCompacts(t *testing.T) {
+	client := storage.NewMemoryStorageClient()
+	store := NewStore(client)
+	ctx := context.Background()
+
+	store.Upsert(ctx, MetricSFI, []Point{{Time: at(0), Value: 150}, {Time: at(1), Value: 151}})
+	base, _ := client.Stat(ctx, "timeseries/sfi.jsonl")
+
+	// Upserts into an existing series only write the new and changed points
+	for i := 1; i <= compactAfter; i++ {
+		if changed, err := store.Upsert(ctx, MetricSFI, []Point{{Time: at(1), Value: 151}, {Time: at(1).Add(time.Duration(i) * time.Minute), Value: float64(i)}}); err != nil || changed != 1 {
+			t.Fatalf("Upsert() %d = %d, %v, want 1, nil", i, changed, err)
+		}
+	}
+	if after, _ := client.Stat(ctx, "timeseries/sfi.jsonl"); after.ETag != base.ETag {
+		t.Error("Upsert() rewrote the base file before compacting")
+	}
+	segments, _ := client.ListDir(ctx, "timeseries/sfi.segments", true)
+	if len(segments) != compactAfter {
+		t.Fatalf("expected %d segments, got %d", compactAfter, len(segments))
+	}
+	if data, _ := client.GetFile(ctx, segments[0]); strings.Count(string(data), "\n") != 1 {
+		t.Errorf("segment holds more than the new point: %q", data)
+	}
+	if metrics, _ := store.Metrics(ctx); len(metrics) != 1 || metrics[0] != MetricSFI {
+		t.Errorf("Metrics() = %v, want [sfi]", metrics)
+	}
+
+	// The next upsert compacts; its revised value overrides the base file
+	if changed, _ := store.Upsert(ctx, MetricSFI, []Point{{Time: at(0), Value: 149}}); changed != 1 {
+		t.Errorf("Upsert() of a revised value = %d, want 1", changed)
+	}
+	if segments, _ := client.ListDir(ctx, "timeseries/sfi.segments", true); len(segments) != 0 {
+		t.Errorf("expected the segments to be compacted, %d left", len(segments))
+	}
+	points, err := store.Query(ctx, MetricSFI, time.Time{}, time.Time{})
+	if err != nil || len(points) != compactAfter+2 {
+		t.Fatalf("Query() = %d points, %v, want %d", len(points), err, compactAfter+2)
+	}
+	if points[0].Value != 149 || points[len(points)-1].Value != compactAfter {
+		t.Errorf("Query() = %v, want the revised first point and the last appended one", points)
+	}
+}
+
+func TestStore_Query(t *testing.T) {
+	store := NewStore(storage.NewMemoryStorageClient())
+	ctx := context.Background()
+
+	// A series that was never written is empty
+	if points, err := store.Query(ctx, MetricSFI, time.Time{}, time.Time{}); err != nil || len(points) != 0 {
+		t.Errorf("Query() of missing series = %v, %v, want empty", points, err)
+	}
+
+	var points []Point
+	for hour := 0; hour < 24; hour += 3 {
+		points = append(points, Point{Time: at(hour), Value: float64(hour)})
+	}
+	store.Upsert(ctx, MetricSFI, points)
+
+	got, _ := store.Query(ctx, MetricSFI, at(6), at(12))
+	if len(got) != 3 || got[0].Value != 6 || got[2].Value != 12 {
+		t.Errorf("Query(06:00, 12:00) = %v, want the 06, 09 and 12 points", got)
+	}
+	if got, _ := store.Query(ctx, MetricSFI, at(20), time.Time{}); len(got) != 1 || got[0].Value != 21 {
+		t.Errorf("Query(20:00, open) = %v, want the 21 point", got)
+	}
+
+	if _, err := store.Query(ctx, Metric("../reports/x"), time.Time{}, time.Time{}); !errors.Is(err, ErrInvalidMetric) {
+		t.Errorf("Query() of unsafe metric error = %v, want ErrInvalidMetric", err)
+	}
+}
+
+func TestStore_MalformedLinesAndMetrics(t *testing.T) {
+	client := storage.NewMemoryStorageClient()
+	store := NewStore(client)
+	ctx := context.Background()
+
+	client.StoreFile(ctx, "timeseries/ssn.jsonl", []byte(`{"t":"2025-09-17T00:00:00Z","v":120}
+not json
+
+{"t":"2025-09-18T00:00:00Z","v":131}
+`))
+	points, err := store.Query(ctx, MetricSSN, time.Time{}, time.Time{})
+	if err != nil || len(points) != 2 || points[1].Value != 131 {
+		t.Errorf("Query() = %v, %v, want the two valid points", points, err)
+	}
+
+	store.Upsert(ctx, BandMetric("12m-10m", "day"), []Point{{Time: at(0), Value: 3}})
+	metrics, err := store.Metrics(ctx)
+	if err != nil || len(metrics) != 2 || metrics[0] != "band_12m-10m_day" || metrics[1] != MetricSSN {
+		t.Errorf("Metrics() = %v, %v, want [band_12m-10m_day ssn]", metrics, err)
+	}
+}
+
+func TestParseBandMetric(t *testing.T) {
+	if band, period, ok := ParseBandMetric(BandMetric("12m-10m", "night")); !ok || band != "12m-10m" || period != "night" {
+		t.Errorf("ParseBandMetric() = %q, %q, %v, want 12m-10m, night", band, period, ok)
+	}
+	for _, metric := range []Metric{MetricKp, "band_", "band_10m", "band_10m_"} {
+		if _, _, ok := ParseBandMetric(metric); ok {
+			t.Errorf("ParseBandMetric(%q) ok = true, want false", metric)
+		}
+	}
+}
+
+func TestFindGaps(t *testing.T) {
+	points := []Point{{Time: at(0)}, {Time: at(3)}, {Time: at(3), Source: "N0NBH"}, {Time: at(12)}, {Time: at(15)}}
+	gaps := FindGaps(MetricKp, points, 3*time.Hour)
+	if len(gaps) != 1 || !gaps[0].From.Equal(at(3)) || !gaps[0].To.Equal(at(12)) || gaps[0].Metric != MetricKp {
+		t.Errorf("FindGaps() = %+v, want one gap from 03:00 to 12:00", gaps)
+	}
+	if gaps := FindGaps(MetricKp, points[:1], time.Hour); len(gaps) != 0 {
+		t.Errorf("FindGaps() of a single point = %+v, want none", gaps)
+	}
+}
@@ -0,0 +1,77 @@
+package trends
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"radiocast/internal/models"
+	"radiocast/internal/timeseries"
+)
+
+// MaxCalendarDays bounds the band calendar window
+const MaxCalendarDays = 366
+
+// BuildBandCalendar averages the recorded band conditions by UTC day over the days from..to
+// (inclusive). Rows are ordered lowest band first, day before night.
+func BuildBandCalendar(ctx context.Context, store *timeseries.Store, from, to time.Time) (*models.BandCalendar, error) {
+	from, to = dayOf(from), dayOf(to)
+	var days []string
+	index := make(map[time.Time]int)
+	for day := from; !day.After(to) && len(days) < MaxCalendarDays; day = day.AddDate(0, 0, 1) {
+		index[day] = len(days)
+		days = append(days, day.Format("2006-01-02"))
+	}
+	calendar := &models.BandCalendar{Days: days, Rows: []models.BandCalendarRow{}}
+
+	metrics, err := store.Metrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, metric := range metrics {
+		band, period, ok := timeseries.ParseBandMetric(metric)
+		if !ok {
+			continue
+		}
+		points, err := store.Query(ctx, metric, from, to.Add(24*time.Hour-time.Second))
+		if err != nil {
+			return nil, err
+		}
+		if len(points) == 0 {
+			continue
+		}
+
+		sums := make([]float64, len(days))
+		counts := make([]int, len(days))
+		for _, p := range points {
+			if i, ok := index[dayOf(p.Time)]; ok {
+				sums[i] += p.Value
+				counts[i]++
+			}
+		}
+		row := models.BandCalendarRow{Band: band, Period: period, Values: make([]*float64, len(days))}
+		for i := range days {
+			if counts[i] == 0 {
+				continue
+			}
+			mean := math.Round(sums[i]/float64(counts[i])*100) / 100
+			row.Values[i] = &mean
+			row.Days[min(max(int(math.Round(mean)), 0), 4)]++
+		}
+		calendar.Rows = append(calendar.Rows, row)
+	}
+
+	sort.SliceStable(calendar.Rows, func(i, j int) bool {
+		a, b := calendar.Rows[i], calendar.Rows[j]
+		if a.Band != b.Band {
+			return wavelength(a.Band) > wavelength(b.Band)
+		}
+		return a.Period < b.Period
+	})
+	return calendar, nil
+}
+
+func dayOf(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
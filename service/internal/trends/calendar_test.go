@@ -0,0 +1,46 @@
+package trends
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"radiocast/internal/storage"
+	"radiocast/internal/timeseries"
+)
+
+func TestBuildBandCalendar(t *testing.T) {
+	ctx := context.Background()
+	store := timeseries.NewStore(storage.NewMemoryStorageClient())
+
+	at := func(day, hour int) time.Time { return time.Date(2025, time.September, day, hour, 0, 0, 0, time.UTC) }
+	store.Upsert(ctx, timeseries.BandMetric("12m-10m", "day"), []timeseries.Point{
+		{Time: at(1, 23), Value: 0}, // before the window
+		{Time: at(2, 6), Value: 3},
+		{Time: at(2, 18), Value: 2},
+		{Time: at(4, 23), Value: 3},
+	})
+	store.Upsert(ctx, timeseries.BandMetric("80m-40m", "night"), []timeseries.Point{{Time: at(3, 0), Value: 4}})
+	store.Upsert(ctx, timeseries.BandMetric("6m", "day"), []timeseries.Point{{Time: at(9, 0), Value: 1}}) // after the window
+	store.Upsert(ctx, timeseries.MetricKp, []timeseries.Point{{Time: at(2, 0), Value: 3}})
+
+	calendar, err := BuildBandCalendar(ctx, store, at(2, 12), at(4, 0))
+	if err != nil {
+		t.Fatalf("BuildBandCalendar() error = %v", err)
+	}
+	if len(calendar.Days) != 3 || calendar.Days[0] != "2025-09-02" || calendar.Days[2] != "2025-09-04" {
+		t.Errorf("Days = %v, want 2025-09-02 to 2025-09-04", calendar.Days)
+	}
+	if len(calendar.Rows) != 2 || calendar.Rows[0].Band != "80m-40m" || calendar.Rows[1].Band != "12m-10m" {
+		t.Fatalf("Rows = %+v, want 80m-40m night and 12m-10m day", calendar.Rows)
+	}
+
+	row := calendar.Rows[1]
+	if row.Values[0] == nil || *row.Values[0] != 2.5 || row.Values[1] != nil || row.Values[2] == nil || *row.Values[2] != 3 {
+		t.Errorf("12m-10m day values = %v, want 2.5, none, 3", row.Values)
+	}
+	// 2.5 rounds to Good, like the 3 on the last day
+	if row.Days != [5]int{0, 0, 0, 2, 0} {
+		t.Errorf("12m-10m day counts = %v, want two Good days", row.Days)
+	}
+}
@@ -0,0 +1,333 @@
+package trends
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"radiocast/internal/models"
+	"radiocast/internal/timeseries"
+)
+
+// CycleStart is the first month of Solar Cycle 25 (the December 2019 minimum)
+var CycleStart = time.Date(2019, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+// smoothingWindow is the width of the standard 13-month running mean
+const smoothingWindow = 13
+
+// CycleMonth is one month of the solar cycle. Values are nil where there is no data; smoothed
+// values need the six months on both sides.
+type CycleMonth struct {
+	Month            time.Time `json:"month"`
+	SFI              *float64  `json:"sfi"`
+	SSN              *float64  `json:"ssn"`
+	SFISmoothed      *float64  `json:"sfi_smoothed"`
+	SSNSmoothed      *float64  `json:"ssn_smoothed"`
+	PredictedSFI     *float64  `json:"predicted_sfi"`
+	PredictedSSN     *float64  `json:"predicted_ssn"`
+	PredictedSSNLow  *float64  `json:"predicted_ssn_low"`
+	PredictedSSNHigh *float64  `json:"predicted_ssn_high"`
+}
+
+// KpDistribution is the share of 3-hour Kp values of one year; Percent[k] covers Kp rounded to k
+type KpDistribution struct {
+	Year    int         `json:"year"`
+	Samples int         `json:"samples"`
+	Percent [10]float64 `json:"percent"`
+}
+
+// StormMonth counts the days of one month by the G-level their highest Kp reached
+type StormMonth struct {
+	Month   time.Time `json:"month"`
+	Days    int       `json:"days"`     // days with K-index data
+	GLevels [6]int    `json:"g_levels"` // GLevels[0] are quiet days, GLevels[1..5] reached G1 to G5
+}
+
+// MonthValue is a monthly average
+type MonthValue struct {
+	Month   time.Time `json:"month"`
+	Value   float64   `json:"value"`
+	Samples int       `json:"samples"`
+}
+
+// BandTrend is the monthly average condition of one band, 0 (closed) to 4 (excellent)
+type BandTrend struct {
+	Band   string       `json:"band"`
+	Period string       `json:"period"` // day or night
+	Months []MonthValue `json:"months"`
+}
+
+// Trends holds the long-range statistics shown on the /trends page
+type Trends struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	Cycle       []CycleMonth     `json:"cycle"`
+	KpByYear    []KpDistribution `json:"kp_by_year"`
+	StormDays   []StormMonth     `json:"storm_days"`
+	Bands       []BandTrend      `json:"bands"`
+}
+
+// GLevel returns the NOAA geomagnetic storm level of a Kp value: 0 below Kp 5-, G1 (Kp 5) to G5 (Kp 9)
+func GLevel(kp float64) int {
+	return min(max(int(math.Round(kp))-4, 0), 5)
+}
+
+// Build computes the trends from the recorded history; predicted is NOAA's predicted cycle and may be nil
+func Build(ctx context.Context, store *timeseries.Store, predicted []models.NOAAPredictedCycleResponse, now time.Time) (*Trends, error) {
+	t := &Trends{GeneratedAt: now.UTC()}
+
+	var err error
+	if t.Cycle, err = buildCycle(ctx, store, predicted); err != nil {
+		return nil, err
+	}
+	kp, err := store.Query(ctx, timeseries.MetricKp, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	kp = uniqueTimes(kp)
+	t.KpByYear = kpByYear(kp)
+	t.StormDays = stormDays(kp)
+	if t.Bands, err = bandTrends(ctx, store); err != nil {
+		return nil, err
+	}
+
+	// Empty lists rather than null in the API
+	if t.Cycle == nil {
+		t.Cycle = []CycleMonth{}
+	}
+	if t.Bands == nil {
+		t.Bands = []BandTrend{}
+	}
+	return t, nil
+}
+
+// buildCycle joins the observed monthly values since CycleStart with the prediction
+func buildCycle(ctx context.Context, store *timeseries.Store, predicted []models.NOAAPredictedCycleResponse) ([]CycleMonth, error) {
+	// The smoothed value of the first month needs the six months before it
+	from := CycleStart.AddDate(0, -smoothingWindow/2, 0)
+	sfi, err := monthlyValues(ctx, store, timeseries.MetricSFIMonthly, from)
+	if err != nil {
+		return nil, err
+	}
+	ssn, err := monthlyValues(ctx, store, timeseries.MetricSSNMonthly, from)
+	if err != nil {
+		return nil, err
+	}
+
+	last := time.Time{}
+	for month := range sfi {
+		last = laterMonth(last, month)
+	}
+	for month := range ssn {
+		last = laterMonth(last, month)
+	}
+	forecast := make(map[time.Time]models.NOAAPredictedCycleResponse)
+	for _, p := range predicted {
+		month, err := time.Parse("2006-01", p.TimeTag)
+		if err != nil || month.Before(CycleStart) {
+			continue
+		}
+		forecast[month] = p
+		last = laterMonth(last, month)
+	}
+	if last.IsZero() {
+		return nil, nil
+	}
+
+	var cycle []CycleMonth
+	for month := CycleStart; !month.After(last); month = month.AddDate(0, 1, 0) {
+		m := CycleMonth{
+			Month:       month,
+			SFI:         value(sfi, month),
+			SSN:         value(ssn, month),
+			SFISmoothed: smoothed(sfi, month),
+			SSNSmoothed: smoothed(ssn, month),
+		}
+		if p, ok := forecast[month]; ok {
+			m.PredictedSFI = &p.PredictedF107
+			m.PredictedSSN = &p.PredictedSSN
+			m.PredictedSSNLow = &p.LowSSN
+			m.PredictedSSNHigh = &p.HighSSN
+		}
+		cycle = append(cycle, m)
+	}
+	return cycle, nil
+}
+
+// monthlyValues returns a monthly series keyed by the first of the month
+func monthlyValues(ctx context.Context, store *timeseries.Store, metric timeseries.Metric, from time.Time) (map[time.Time]float64, error) {
+	points, err := store.Query(ctx, metric, from, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[time.Time]float64, len(points))
+	for _, p := range points {
+		values[monthOf(p.Time)] = p.Value
+	}
+	return values, nil
+}
+
+// smoothed is the 13-month running mean centred on month, with the outer months weighted by half
+func smoothed(values map[time.Time]float64, month time.Time) *float64 {
+	half := smoothingWindow / 2
+	sum := 0.0
+	for i := -half; i <= half; i++ {
+		v, ok := values[month.AddDate(0, i, 0)]
+		if !ok {
+			return nil
+		}
+		if i == -half || i == half {
+			v /= 2
+		}
+		sum += v
+	}
+	mean := sum / float64(smoothingWindow-1)
+	return &mean
+}
+
+// kpByYear returns the Kp distribution of every year with data
+func kpByYear(kp []timeseries.Point) []KpDistribution {
+	byYear := make(map[int]*KpDistribution)
+	var years []int
+	for _, p := range kp {
+		year := p.Time.UTC().Year()
+		d := byYear[year]
+		if d == nil {
+			d = &KpDistribution{Year: year}
+			byYear[year] = d
+			years = append(years, year)
+		}
+		d.Percent[min(max(int(math.Round(p.Value)), 0), 9)]++
+		d.Samples++
+	}
+	sort.Ints(years)
+
+	distributions := make([]KpDistribution, 0, len(years))
+	for _, year := range years {
+		d := byYear[year]
+		for k := range d.Percent {
+			d.Percent[k] = math.Round(d.Percent[k]/float64(d.Samples)*1000) / 10
+		}
+		distributions = append(distributions, *d)
+	}
+	return distributions
+}
+
+// stormDays classifies every UTC day by its highest Kp and counts the days per month
+func stormDays(kp []timeseries.Point) []StormMonth {
+	dayMax := make(map[time.Time]float64)
+	for _, p := range kp {
+		day := p.Time.UTC().Truncate(24 * time.Hour)
+		if v, ok := dayMax[day]; !ok || p.Value > v {
+			dayMax[day] = p.Value
+		}
+	}
+
+	byMonth := make(map[time.Time]*StormMonth)
+	for day, kpMax := range dayMax {
+		month := monthOf(day)
+		m := byMonth[month]
+		if m == nil {
+			m = &StormMonth{Month: month}
+			byMonth[month] = m
+		}
+		m.Days++
+		m.GLevels[GLevel(kpMax)]++
+	}
+
+	months := make([]StormMonth, 0, len(byMonth))
+	for _, m := range byMonth {
+		months = append(months, *m)
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i].Month.Before(months[j].Month) })
+	return months
+}
+
+// bandTrends averages every recorded band condition series by month
+func bandTrends(ctx context.Context, store *timeseries.Store) ([]BandTrend, error) {
+	metrics, err := store.Metrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var bands []BandTrend
+	for _, metric := range metrics {
+		band, period, ok := timeseries.ParseBandMetric(metric)
+		if !ok {
+			continue
+		}
+		points, err := store.Query(ctx, metric, time.Time{}, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		bands = append(bands, BandTrend{Band: band, Period: period, Months: monthlyAverages(points)})
+	}
+
+	// Lowest band first, day before night
+	sort.SliceStable(bands, func(i, j int) bool {
+		if bands[i].Band != bands[j].Band {
+			return wavelength(bands[i].Band) > wavelength(bands[j].Band)
+		}
+		return bands[i].Period < bands[j].Period
+	})
+	return bands, nil
+}
+
+// monthlyAverages averages time-ordered points by month
+func monthlyAverages(points []timeseries.Point) []MonthValue {
+	var months []MonthValue
+	for _, p := range points {
+		month := monthOf(p.Time)
+		if n := len(months); n == 0 || !months[n-1].Month.Equal(month) {
+			months = append(months, MonthValue{Month: month})
+		}
+		m := &months[len(months)-1]
+		m.Value += p.Value
+		m.Samples++
+	}
+	for i := range months {
+		months[i].Value = math.Round(months[i].Value/float64(months[i].Samples)*100) / 100
+	}
+	return months
+}
+
+// uniqueTimes keeps one point per timestamp; the store keeps a point per source
+func uniqueTimes(points []timeseries.Point) []timeseries.Point {
+	var unique []timeseries.Point
+	for _, p := range points {
+		if n := len(unique); n > 0 && unique[n-1].Time.Equal(p.Time) {
+			continue
+		}
+		unique = append(unique, p)
+	}
+	return unique
+}
+
+// wavelength returns the leading wavelength of a band group name, e.g. 80 for "80m-40m"
+func wavelength(band string) int {
+	end := 0
+	for end < len(band) && band[end] >= '0' && band[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.Atoi(band[:end])
+	return n
+}
+
+func monthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func laterMonth(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+func value(values map[time.Time]float64, month time.Time) *float64 {
+	if v, ok := values[month]; ok {
+		return &v
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package trends
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"radiocast/internal/models"
+	"radiocast/internal/storage"
+	"radiocast/internal/timeseries"
+)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBuild_Cycle(t *testing.T) {
+	ctx := context.Background()
+	store := timeseries.NewStore(storage.NewMemoryStorageClient())
+
+	// SSN rises by 10 a month from June 2019; SFI is constant
+	var ssn, sfi []timeseries.Point
+	for i := 0; i < 24; i++ {
+		m := month(2019, time.June).AddDate(0, i, 0)
+		ssn = append(ssn, timeseries.Point{Time: m, Value: float64(10 * i)})
+		sfi = append(sfi, timeseries.Point{Time: m, Value: 70})
+	}
+	store.Upsert(ctx, timeseries.MetricSSNMonthly, ssn)
+	store.Upsert(ctx, timeseries.MetricSFIMonthly, sfi)
+
+	predicted := []models.NOAAPredictedCycleResponse{
+		{TimeTag: "2019-01", PredictedSSN: 1},
+		{TimeTag: "2021-07", PredictedSSN: 60, LowSSN: 50, HighSSN: 70, PredictedF107: 90},
+	}
+	trends, err := Build(ctx, store, predicted, time.Now())
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	// December 2019 to the last predicted month, July 2021
+	if len(trends.Cycle) != 20 || !trends.Cycle[0].Month.Equal(CycleStart) {
+		t.Fatalf("Cycle has %d months from %v, want 20 from %v", len(trends.Cycle), trends.Cycle[0].Month, CycleStart)
+	}
+	first := trends.Cycle[0]
+	if first.SSN == nil || *first.SSN != 60 || first.SSNSmoothed == nil || *first.SSNSmoothed != 60 || *first.SFISmoothed != 70 {
+		t.Errorf("December 2019 = %+v, want SSN 60 smoothed to 60 and SFI 70", first)
+	}
+	// May 2021 is the last observed month, so the smoothed series ends six months earlier
+	if m := trends.Cycle[11]; m.SSNSmoothed == nil || *m.SSNSmoothed != 170 {
+		t.Errorf("November 2020 smoothed SSN = %v, want 170", m.SSNSmoothed)
+	}
+	if m := trends.Cycle[12]; m.SSNSmoothed != nil || m.SSN == nil {
+		t.Errorf("December 2020 = %+v, want an observed but no smoothed SSN", m)
+	}
+	last := trends.Cycle[19]
+	if last.SSN != nil || last.PredictedSSN == nil || *last.PredictedSSN != 60 || *last.PredictedSSNHigh != 70 || *last.PredictedSFI != 90 {
+		t.Errorf("July 2021 = %+v, want only the prediction", last)
+	}
+}
+
+func TestBuild_KpAndBands(t *testing.T) {
+	ctx := context.Background()
+	store := timeseries.NewStore(storage.NewMemoryStorageClient())
+
+	day := func(y int, m time.Month, d, h int) time.Time { return time.Date(y, m, d, h, 0, 0, 0, time.UTC) }
+	store.Upsert(ctx, timeseries.MetricKp, []timeseries.Point{
+		{Time: day(2024, time.May, 10, 0), Value: 2.33},
+		{Time: day(2024, time.May, 10, 21), Value: 8.67, Source: "NOAA SWPC"},
+		{Time: day(2024, time.May, 10, 21), Value: 8.67, Source: "N0NBH"}, // same observation, other source
+		{Time: day(2024, time.May, 11, 3), Value: 4.67},
+		{Time: day(2024, time.June, 1, 0), Value: 1},
+		{Time: day(2025, time.March, 1, 0), Value: 3},
+	})
+	store.Upsert(ctx, timeseries.BandMetric("12m-10m", "day"), []timeseries.Point{
+		{Time: day(2025, time.March, 1, 12), Value: 3},
+		{Time: day(2025, time.March, 2, 12), Value: 2},
+		{Time: day(2025, time.April, 1, 12), Value: 4},
+	})
+	store.Upsert(ctx, timeseries.BandMetric("80m-40m", "night"), []timeseries.Point{{Time: day(2025, time.March, 1, 0), Value: 4}})
+	store.Upsert(ctx, timeseries.BandMetric("80m-40m", "day"), []timeseries.Point{{Time: day(2025, time.March, 1, 12), Value: 2}})
+
+	trends, err := Build(ctx, store, nil, time.Now())
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(trends.Cycle) != 0 {
+		t.Errorf("Cycle = %v, want none without solar data", trends.Cycle)
+	}
+
+	if len(trends.KpByYear) != 2 || trends.KpByYear[0].Year != 2024 || trends.KpByYear[0].Samples != 4 {
+		t.Fatalf("KpByYear = %+v, want 2024 with 4 samples and 2025", trends.KpByYear)
+	}
+	if p := trends.KpByYear[0].Percent; p[2] != 25 || p[9] != 25 || p[5] != 25 || p[1] != 25 {
+		t.Errorf("2024 distribution = %v, want 25%% at Kp 1, 2, 5 and 9", p)
+	}
+
+	if len(trends.StormDays) != 3 {
+		t.Fatalf("StormDays = %+v, want May and June 2024 and March 2025", trends.StormDays)
+	}
+	if may := trends.StormDays[0]; may.Days != 2 || may.GLevels[5] != 1 || may.GLevels[1] != 1 {
+		t.Errorf("May 2024 = %+v, want one G5 and one G1 day", may)
+	}
+	if june := trends.StormDays[1]; june.Days != 1 || june.GLevels[0] != 1 {
+		t.Errorf("June 2024 = %+v, want one quiet day", june)
+	}
+
+	if len(trends.Bands) != 3 || trends.Bands[0].Band != "80m-40m" || trends.Bands[0].Period != "day" || trends.Bands[2].Band != "12m-10m" {
+		t.Fatalf("Bands = %+v, want 80m-40m day and night, then 12m-10m", trends.Bands)
+	}
+	if months := trends.Bands[2].Months; len(months) != 2 || months[0].Value != 2.5 || months[0].Samples != 2 || months[1].Value != 4 {
+		t.Errorf("12m-10m months = %+v, want 2.5 in March and 4 in April", months)
+	}
+}
+
+func TestGLevel(t *testing.T) {
+	for kp, want := range map[float64]int{0: 0, 4.33: 0, 4.67: 1, 5.33: 1, 6: 2, 7.67: 4, 9: 5} {
+		if got := GLevel(kp); got != want {
+			t.Errorf("GLevel(%v) = %d, want %d", kp, got, want)
+		}
+	}
+}
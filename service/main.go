@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -85,22 +86,41 @@ func initializeLogger(cfg *config.Config, deploymentMode storage.DeploymentMode)
 		strings.ToUpper(cfg.LogLevel), formatName, deploymentMode)
 }
 
+// parseDeploymentMode validates the -deployment flag
+func parseDeploymentMode(value string) (storage.DeploymentMode, error) {
+	switch value {
+	case "local":
+		return storage.DeploymentLocal, nil
+	case "gcs":
+		return storage.DeploymentGCS, nil
+	case "s3":
+		return storage.DeploymentS3, nil
+	case "memory":
+		return storage.DeploymentMemory, nil
+	default:
+		return "", fmt.Errorf("invalid deployment mode: %s. Use 'local', 'gcs', 's3' or 'memory'", value)
+	}
+}
+
 func main() {
 	ctx := context.Background()
 	
 	// Parse command line flags
-	deploymentFlag := flag.String("deployment", "local", "Deployment mode: local or gcs")
+	deploymentFlag := flag.String("deployment", "local", "Deployment mode: local, gcs, s3 or memory")
 	flag.Parse()
 	
+	// Subcommands take their own flags after the name
+	if flag.Arg(0) == "backfill" {
+		if err := runBackfill(ctx, flag.Args()[1:], *deploymentFlag); err != nil {
+			log.Fatalf("Backfill failed: %v", err)
+		}
+		return
+	}
+	
 	// Validate deployment mode
-	var deploymentMode storage.DeploymentMode
-	switch *deploymentFlag {
-	case "local":
-		deploymentMode = storage.DeploymentLocal
-	case "gcs":
-		deploymentMode = storage.DeploymentGCS
-	default:
-		log.Fatalf("Invalid deployment mode: %s. Use 'local' or 'gcs'", *deploymentFlag)
+	deploymentMode, err := parseDeploymentMode(*deploymentFlag)
+	if err != nil {
+		log.Fatal(err)
 	}
 	
 	// Load configuration
@@ -22,8 +22,8 @@ func TestHealthEndpoint(t *testing.T) {
 		Environment:  "test",
 	}
 
-	// Test server creation with local deployment mode
-	srv, err := server.NewServer(cfg, storage.DeploymentLocal)
+	// Test server creation with in-memory storage so the test leaves no files behind
+	srv, err := server.NewServer(cfg, storage.DeploymentMemory)
 	if err != nil {
 		t.Skip("Skipping test - server creation failed (expected in test environment)")
 	}
@@ -176,6 +176,18 @@ debug_apis() {
 }
 
 
+# Downloads the ECharts bundle served from /static/; the server does not start without it
+fetch_echarts() {
+    local ECHARTS_FILE="internal/static/echarts.min.js"
+    if [ -f "$ECHARTS_FILE" ]; then
+        return
+    fi
+    print_status "Downloading ECharts bundle to $ECHARTS_FILE..."
+    curl -sSfL https://cdn.jsdelivr.net/npm/echarts@5.4.3/dist/echarts.min.js -o "$ECHARTS_FILE" \
+        || curl -sSfL https://unpkg.com/echarts@5.4.3/dist/echarts.min.js -o "$ECHARTS_FILE" \
+        || { rm -f "$ECHARTS_FILE"; print_error "Could not download ECharts into $ECHARTS_FILE"; exit 1; }
+}
+
 run_server() {
     local USE_MOCKUP=false
     
@@ -237,6 +249,8 @@ run_server() {
     print_status "  Converting to HTML with charts"
     print_status "  Validating Chart Data and Band Analysis sections"
     
+    fetch_echarts
+
     # Start server briefly to generate a report
     go run main.go &
     SERVER_PID=$!
@@ -70,7 +70,7 @@ resource "google_cloud_run_v2_service" "radiocast" {
           cpu    = var.cpu_limit
           memory = var.memory_limit
         }
-        cpu_idle = true
+        cpu_idle = !var.cpu_always_allocated
       }
 
       dynamic "startup_probe" {
@@ -60,6 +60,12 @@ variable "cpu_limit" {
   default     = "2"
 }
 
+variable "cpu_always_allocated" {
+  description = "Keep CPU allocated between requests (needed by the DX cluster client, see README)"
+  type        = bool
+  default     = false
+}
+
 variable "memory_limit" {
   description = "Memory limit for Cloud Run service"
   type        = string